@@ -3,21 +3,36 @@ package drydock
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	containeranalysis "cloud.google.com/go/containeranalysis/apiv1"
+	"github.com/googleapis/gax-go/v2"
 	"github.com/hiro-o918/drydock/schemas"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
+	storage "google.golang.org/api/storage/v1"
 	grafeaspb "google.golang.org/genproto/googleapis/grafeas/v1"
 )
 
+// containerAnalysisAPIVersion is the Container Analysis API version findings are read from.
+const containerAnalysisAPIVersion = "v1"
+
 // ArtifactRegistryAnalyzer implements the vulnerability analysis logic.
 type ArtifactRegistryAnalyzer struct {
-	containerAnalysisClient *containeranalysis.Client
+	occurrences occurrenceClient
+	objects     objectReader // downloads SBOMs, nil until EnableSBOMDownload
+	callOpts    []gax.CallOption
+	retrySet    bool
 }
 
+var _ Analyzer = (*ArtifactRegistryAnalyzer)(nil)
+
 // NewArtifactRegistryAnalyzer creates a new analyzer with ADC authentication.
 func NewArtifactRegistryAnalyzer(ctx context.Context, opts ...option.ClientOption) (*ArtifactRegistryAnalyzer, error) {
 	caClient, err := containeranalysis.NewClient(ctx, opts...)
@@ -25,34 +40,63 @@ func NewArtifactRegistryAnalyzer(ctx context.Context, opts ...option.ClientOptio
 		return nil, fmt.Errorf("failed to create Container Analysis client: %w", err)
 	}
 
-	return &ArtifactRegistryAnalyzer{
-		containerAnalysisClient: caClient,
-	}, nil
+	return newArtifactRegistryAnalyzer(gcpOccurrenceClient{caClient}), nil
+}
+
+// EnableSBOMDownload creates the Cloud Storage client FetchSBOM downloads SBOMs with.
+// opts are those of a REST client: Container Analysis endpoints and gRPC options do not apply.
+func (a *ArtifactRegistryAnalyzer) EnableSBOMDownload(ctx context.Context, opts ...option.ClientOption) error {
+	storageService, err := storage.NewService(ctx, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create Cloud Storage client: %w", err)
+	}
+	a.objects = gcsObjectReader{objects: storageService.Objects}
+	return nil
+}
+
+// newArtifactRegistryAnalyzer creates an analyzer on top of the given Container Analysis client.
+func newArtifactRegistryAnalyzer(occurrences occurrenceClient) *ArtifactRegistryAnalyzer {
+	return &ArtifactRegistryAnalyzer{occurrences: occurrences}
+}
+
+// SetRetryPolicy makes the analyzer retry failed API calls according to p.
+// Without it, calls are not retried unless the analyzer is used by a Scanner,
+// which applies its own policy (see WithRetryPolicy).
+func (a *ArtifactRegistryAnalyzer) SetRetryPolicy(p RetryPolicy) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+	a.callOpts = p.callOptions()
+	a.retrySet = true
+	return nil
 }
 
 // Close closes the underlying API client.
 func (a *ArtifactRegistryAnalyzer) Close() error {
-	return a.containerAnalysisClient.Close()
+	return a.occurrences.Close()
 }
 
 // Analyze retrieves and filters vulnerabilities for the specified image digest.
+// Log output goes to the logger attached to ctx (see zerolog.Ctx); nothing is logged otherwise.
 func (a *ArtifactRegistryAnalyzer) Analyze(ctx context.Context, req AnalyzeRequest) (*schemas.AnalyzeResult, error) {
+	start := time.Now()
+	metadata := &schemas.ScanMetadata{}
+
 	// Generate resource URL using ArtifactReference method
 	resourceURL := req.Artifact.ToResourceURL(req.Location)
 
-	// Obtain the Grafeas client from the Container Analysis client.
-	grafeasClient := a.containerAnalysisClient.GetGrafeasClient()
-
-	// Filter specifically for vulnerabilities attached to this resource URL.
+	// Filter specifically for vulnerabilities attached to this resource URL, along with the
+	// discovery occurrence telling whether they are still kept up to date.
 	listReq := &grafeaspb.ListOccurrencesRequest{
 		Parent: fmt.Sprintf("projects/%s", req.Artifact.ProjectID),
-		Filter: fmt.Sprintf(`resourceUrl="%s" AND kind="VULNERABILITY"`, resourceURL),
+		Filter: fmt.Sprintf(`resourceUrl="%s" AND (kind="VULNERABILITY" OR kind="DISCOVERY")`, resourceURL),
 	}
 
-	it := grafeasClient.ListOccurrences(ctx, listReq)
+	it := a.occurrences.ListOccurrences(withFieldMask(ctx, vulnerabilityOccurrenceFields), listReq, a.callOpts...)
 	vulnerabilities := make([]schemas.Vulnerability, 0)
 
 	var scanTime time.Time
+	status := schemas.ScanStatusNotScanned
 
 	for {
 		occ, err := it.Next()
@@ -60,7 +104,21 @@ func (a *ArtifactRegistryAnalyzer) Analyze(ctx context.Context, req AnalyzeReque
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to list occurrences: %w", err)
+			// Keep what was already fetched if the scan is being interrupted mid-pagination;
+			// the result is flagged as truncated instead of being dropped.
+			if metadata.OccurrencesFetched > 0 && ctx.Err() != nil {
+				metadata.Truncated = true
+				metadata.Warnings = append(metadata.Warnings, fmt.Sprintf("listing occurrences stopped early: %v", err))
+				break
+			}
+			return nil, fmt.Errorf("failed to list occurrences: %w", classifyAPIError(err))
+		}
+		metadata.OccurrencesFetched++
+
+		if discovery := occ.GetDiscovery(); discovery != nil {
+			applyDiscovery(metadata, discovery)
+			status = scanStatusOf(discovery)
+			continue
 		}
 
 		if scanTime.IsZero() && occ.GetCreateTime() != nil {
@@ -70,28 +128,123 @@ func (a *ArtifactRegistryAnalyzer) Analyze(ctx context.Context, req AnalyzeReque
 		vuln, err := convertToVulnerability(occ)
 		if err != nil {
 			// Skip occurrences that cannot be converted.
+			metadata.Warnings = append(metadata.Warnings, fmt.Sprintf("skipped occurrence %s: %v", occ.GetName(), err))
 			continue
 		}
 		vulnerabilities = append(vulnerabilities, vuln)
 	}
 
-	filtered := filterBySeverity(vulnerabilities, req.MinSeverity)
+	// Findings prove the image was analyzed, even if its discovery occurrence is missing
+	if status == schemas.ScanStatusNotScanned && len(vulnerabilities) > 0 {
+		status = schemas.ScanStatusFinished
+	}
+	if warning := scanStatusWarning(status); warning != "" {
+		metadata.Warnings = append(metadata.Warnings, warning)
+	}
+
+	filtered := filterBySeverity(zerolog.Ctx(ctx), vulnerabilities, req.MinSeverity)
 
 	// Filter by fixability if requested
 	if req.FixableOnly {
 		filtered = filterFixable(filtered)
 	}
 
+	// Filter by vulnerability ID if requested
+	if len(req.OnlyIDs) > 0 {
+		filtered = filterByID(filtered, req.OnlyIDs, nil)
+	}
+
+	// Suppressed findings are listed separately rather than dropped, so exceptions can be audited
+	filtered, suppressed := schemas.Suppress(filtered, suppressionsOf(req), time.Now())
+
+	annotateDistroStatus(filtered, req.DistroTrackers)
+
+	// Findings that will never be fixed join the suppressed ones once their distro status is known
+	if req.IgnoreUnlikelyFixed {
+		var unlikely []schemas.SuppressedFinding
+		filtered, unlikely = suppressUnlikelyFixed(filtered)
+		suppressed = append(suppressed, unlikely...)
+	}
+
+	// Apply the user-defined expression last, on the already reduced set
+	if req.Filter != nil {
+		var err error
+		filtered, err = req.Filter.Filter(req.Artifact, filtered)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	metadata.DurationMillis = time.Since(start).Milliseconds()
+
 	return &schemas.AnalyzeResult{
 		Artifact:        req.Artifact,
 		ScanTime:        time.Now(),
 		Vulnerabilities: filtered,
 		Summary:         buildSummary(filtered),
+		Suppressed:      suppressed,
+		Remediations:    schemas.BuildRemediations(filtered),
+		Scanner:         &schemas.ScannerInfo{Name: schemas.SourceContainerAnalysis, Version: containerAnalysisAPIVersion},
+		Metadata:        metadata,
+		ScanStatus:      status,
 	}, nil
 }
 
 // Internal Helper Functions
 
+// applyDiscovery records the analysis state of a discovery occurrence in metadata.
+// Container Analysis stops updating the findings of images not pulled for 30 days,
+// which is reported as stale with a warning rather than silently.
+func applyDiscovery(metadata *schemas.ScanMetadata, discovery *grafeaspb.DiscoveryOccurrence) {
+	switch discovery.GetContinuousAnalysis() {
+	case grafeaspb.DiscoveryOccurrence_ACTIVE:
+		metadata.ContinuousAnalysis = "ACTIVE"
+	case grafeaspb.DiscoveryOccurrence_INACTIVE:
+		metadata.ContinuousAnalysis = "INACTIVE"
+	}
+	if t := discovery.GetLastScanTime(); t != nil {
+		metadata.LastAnalysisTime = t.AsTime()
+	}
+	if metadata.ContinuousAnalysis != "INACTIVE" {
+		return
+	}
+	metadata.Stale = true
+	warning := "continuous analysis is inactive (image not pulled for 30 days); findings are not updated"
+	if !metadata.LastAnalysisTime.IsZero() {
+		warning += " since " + metadata.LastAnalysisTime.Format(time.DateOnly)
+	}
+	metadata.Warnings = append(metadata.Warnings, warning)
+}
+
+// scanStatusOf returns the scan status told by a discovery occurrence. Occurrences without an
+// analysis status predate the field; their image was analyzed.
+func scanStatusOf(discovery *grafeaspb.DiscoveryOccurrence) schemas.ScanStatus {
+	switch discovery.GetAnalysisStatus() {
+	case grafeaspb.DiscoveryOccurrence_PENDING, grafeaspb.DiscoveryOccurrence_SCANNING:
+		return schemas.ScanStatusPending
+	case grafeaspb.DiscoveryOccurrence_FINISHED_UNSUPPORTED:
+		return schemas.ScanStatusUnsupported
+	case grafeaspb.DiscoveryOccurrence_FINISHED_FAILED:
+		return schemas.ScanStatusNotScanned
+	default:
+		return schemas.ScanStatusFinished
+	}
+}
+
+// scanStatusWarning explains why an image that was not fully analyzed may have findings missing.
+func scanStatusWarning(status schemas.ScanStatus) string {
+	switch status {
+	case schemas.ScanStatusPending:
+		return "analysis by Container Analysis is not finished; findings may be missing"
+	case schemas.ScanStatusUnsupported:
+		return "image is not supported by Container Analysis (e.g., unsupported OS); it was not analyzed"
+	case schemas.ScanStatusNotScanned:
+		return "image has no analysis by Container Analysis (e.g., pushed before scanning was enabled); it was not analyzed"
+	default:
+		return ""
+	}
+}
+
 func convertToVulnerability(occ *grafeaspb.Occurrence) (schemas.Vulnerability, error) {
 	vulnDetails := occ.GetVulnerability()
 	// Initialize variables for package details
@@ -99,11 +252,12 @@ func convertToVulnerability(occ *grafeaspb.Occurrence) (schemas.Vulnerability, e
 	var installedVer string
 	var fixedVer string
 	var packageType string
+	var issue *grafeaspb.VulnerabilityOccurrence_PackageIssue
 
 	// Extract details from PackageIssue
 	// We primarily use the first issue found to determine package metadata.
 	if issues := vulnDetails.GetPackageIssue(); len(issues) > 0 {
-		issue := issues[0]
+		issue = issues[0]
 		pkgName = issue.AffectedPackage
 
 		// 1. Extract the specific 'package_type' (e.g., "OS", "GO", "MAVEN") if available.
@@ -118,21 +272,56 @@ func convertToVulnerability(occ *grafeaspb.Occurrence) (schemas.Vulnerability, e
 		}
 	}
 
+	cvssVersion, cvssVector, cvssDetails := convertCVSS(vulnDetails)
+
 	vuln := schemas.Vulnerability{
 		ID:               vulnDetails.ShortDescription,
 		Severity:         convertSeverity(vulnDetails.Severity),
 		CVSSScore:        vulnDetails.CvssScore,
-		URLs:             convertUrls(vulnDetails.GetRelatedUrls()),
+		CVSSVersion:      cvssVersion,
+		CVSSVector:       cvssVector,
+		CVSS:             cvssDetails,
+		CWEs:             extractCWEs(vulnDetails),
+		Source:           schemas.SourceContainerAnalysis,
+		References:       convertReferences(vulnDetails.GetRelatedUrls()),
 		Description:      occ.NoteName, // Using NoteName as a fallback for description/identifier
 		PackageType:      packageType,
 		PackageName:      pkgName,
 		InstalledVersion: installedVer,
 		FixedVersion:     fixedVer,
+		FixState:         convertFixState(vulnDetails, issue),
+		PURL:             packagePURL(issue),
+	}
+	// The occurrence is created when the finding is first reported for the image.
+	if ct := occ.GetCreateTime(); ct != nil {
+		vuln.FirstSeen = ct.AsTime()
 	}
 
 	return vuln, nil
 }
 
+// convertFixState derives the fix state from the occurrence, its primary package issue (may be nil),
+// and any VEX remediation attached to it. A vendor's "no fix planned" takes precedence.
+func convertFixState(vuln *grafeaspb.VulnerabilityOccurrence, issue *grafeaspb.VulnerabilityOccurrence_PackageIssue) schemas.FixState {
+	noneAvailable := false
+	for _, r := range vuln.GetVexAssessment().GetRemediations() {
+		switch r.GetRemediationType() {
+		case grafeaspb.VulnerabilityAssessmentNote_Assessment_Remediation_NO_FIX_PLANNED:
+			return schemas.FixStateWontFix
+		case grafeaspb.VulnerabilityAssessmentNote_Assessment_Remediation_NONE_AVAILABLE:
+			noneAvailable = true
+		}
+	}
+
+	if vuln.GetFixAvailable() || issue.GetFixAvailable() || issue.GetFixedVersion().GetName() != "" {
+		return schemas.FixStateFixAvailable
+	}
+	if noneAvailable || issue != nil {
+		return schemas.FixStateNoFixAvailable
+	}
+	return schemas.FixStateUnknown
+}
+
 func convertSeverity(s grafeaspb.Severity) schemas.Severity {
 	switch s {
 	case grafeaspb.Severity_MINIMAL:
@@ -150,40 +339,115 @@ func convertSeverity(s grafeaspb.Severity) schemas.Severity {
 	}
 }
 
-func convertUrls(urls []*grafeaspb.RelatedUrl) []string {
-	result := make([]string, 0, len(urls))
+func convertReferences(urls []*grafeaspb.RelatedUrl) []schemas.Reference {
+	result := make([]schemas.Reference, 0, len(urls))
 	for _, u := range urls {
 		if u != nil {
-			result = append(result, u.Url)
+			result = append(result, schemas.Reference{
+				URL:  u.Url,
+				Type: classifyReference(u.Label, u.Url),
+			})
 		}
 	}
 	return result
 }
 
-func filterBySeverity(vulns []schemas.Vulnerability, min schemas.Severity) []schemas.Vulnerability {
-	if min == schemas.SeverityUnspecified {
-		return vulns
+// advisoryHosts are well-known hosts of security advisories and vulnerability databases.
+var advisoryHosts = []string{
+	"nvd.nist.gov",
+	"cve.mitre.org",
+	"www.cve.org",
+	"osv.dev",
+	"security-tracker.debian.org",
+	"ubuntu.com",
+	"access.redhat.com",
+	"security.alpinelinux.org",
+	"pkg.go.dev",
+}
+
+// classifyReference derives the reference type from the Grafeas RelatedUrl label,
+// falling back to well-known URL shapes when the label is not descriptive.
+func classifyReference(label, rawURL string) schemas.ReferenceType {
+	l := strings.ToLower(label)
+	switch {
+	case strings.Contains(l, "advisory"), strings.Contains(l, "security"), strings.Contains(l, "cve"):
+		return schemas.ReferenceTypeAdvisory
+	case strings.Contains(l, "fix"), strings.Contains(l, "patch"):
+		return schemas.ReferenceTypeFix
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return schemas.ReferenceTypeWeb
+	}
+	if strings.Contains(u.Path, "/commit/") || strings.Contains(u.Path, "/pull/") || strings.Contains(u.Path, "/merge_requests/") {
+		return schemas.ReferenceTypeFix
+	}
+	if strings.HasPrefix(u.Path, "/advisories/") || strings.Contains(u.Path, "/security/") {
+		return schemas.ReferenceTypeAdvisory
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, h := range advisoryHosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return schemas.ReferenceTypeAdvisory
+		}
+	}
+	return schemas.ReferenceTypeWeb
+}
+
+var (
+	cweIDRegex  = regexp.MustCompile(`(?i)\bCWE-(\d+)\b`)
+	cweURLRegex = regexp.MustCompile(`cwe\.mitre\.org/data/definitions/(\d+)\.html`)
+)
+
+// extractCWEs collects CWE identifiers (e.g., "CWE-79") referenced by a vulnerability occurrence.
+// Grafeas has no dedicated CWE field, so they are taken from the NVD-enriched descriptions and
+// related URLs. The result is deduplicated and sorted, or nil if none are found.
+func extractCWEs(vuln *grafeaspb.VulnerabilityOccurrence) []string {
+	seen := make(map[string]struct{})
+	for _, text := range []string{vuln.GetLongDescription(), vuln.GetExtraDetails()} {
+		for _, m := range cweIDRegex.FindAllStringSubmatch(text, -1) {
+			seen["CWE-"+m[1]] = struct{}{}
+		}
+	}
+	for _, u := range vuln.GetRelatedUrls() {
+		for _, m := range cweURLRegex.FindAllStringSubmatch(u.GetUrl(), -1) {
+			seen["CWE-"+m[1]] = struct{}{}
+		}
+		for _, m := range cweIDRegex.FindAllStringSubmatch(u.GetLabel(), -1) {
+			seen["CWE-"+m[1]] = struct{}{}
+		}
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+
+	cwes := make([]string, 0, len(seen))
+	for id := range seen {
+		cwes = append(cwes, id)
 	}
+	slices.SortFunc(cwes, func(a, b string) int {
+		x, _ := strconv.Atoi(strings.TrimPrefix(a, "CWE-"))
+		y, _ := strconv.Atoi(strings.TrimPrefix(b, "CWE-"))
+		return x - y
+	})
+	return cwes
+}
 
-	levels := map[schemas.Severity]int{
-		schemas.SeverityUnspecified: 0,
-		schemas.SeverityMinimal:     1,
-		schemas.SeverityLow:         2,
-		schemas.SeverityMedium:      3,
-		schemas.SeverityHigh:        4,
-		schemas.SeverityCritical:    5,
+func filterBySeverity(log *zerolog.Logger, vulns []schemas.Vulnerability, min schemas.Severity) []schemas.Vulnerability {
+	if min == schemas.SeverityUnspecified {
+		return vulns
 	}
 
-	threshold := levels[min]
 	filtered := make([]schemas.Vulnerability, 0)
 
 	for _, v := range vulns {
 		log.Debug().Str("vulnerability_id", v.ID).
 			Str("severity", string(v.Severity)).
-			Int("severity_level", levels[v.Severity]).
-			Int("threshold_level", threshold).
+			Int("severity_level", v.Severity.Level()).
+			Int("threshold_level", min.Level()).
 			Msg("Evaluating vulnerability for severity filter")
-		if levels[v.Severity] >= threshold {
+		if v.Severity.AtLeast(min) {
 			filtered = append(filtered, v)
 		}
 	}
@@ -194,7 +458,7 @@ func filterFixable(vulns []schemas.Vulnerability) []schemas.Vulnerability {
 	filtered := make([]schemas.Vulnerability, 0)
 
 	for _, v := range vulns {
-		if v.FixedVersion != "" {
+		if v.FixState == schemas.FixStateFixAvailable {
 			filtered = append(filtered, v)
 		}
 	}
@@ -202,15 +466,108 @@ func filterFixable(vulns []schemas.Vulnerability) []schemas.Vulnerability {
 	return filtered
 }
 
+// filterByID keeps vulnerabilities whose ID is in only (when non-empty) and not in skip.
+// IDs are compared case-insensitively.
+func filterByID(vulns []schemas.Vulnerability, only, skip []string) []schemas.Vulnerability {
+	toSet := func(ids []string) map[string]struct{} {
+		set := make(map[string]struct{}, len(ids))
+		for _, id := range ids {
+			set[strings.ToUpper(strings.TrimSpace(id))] = struct{}{}
+		}
+		return set
+	}
+	onlySet, skipSet := toSet(only), toSet(skip)
+
+	filtered := make([]schemas.Vulnerability, 0)
+	for _, v := range vulns {
+		id := strings.ToUpper(v.ID)
+		if _, ok := onlySet[id]; len(onlySet) > 0 && !ok {
+			continue
+		}
+		if _, ok := skipSet[id]; ok {
+			continue
+		}
+		filtered = append(filtered, v)
+	}
+	return filtered
+}
+
+// skipSuppressionSource is the Suppression.Source of the IDs skipped with AnalyzeRequest.SkipIDs.
+const skipSuppressionSource = "skip-cve"
+
+// suppressionsOf returns the suppressions of req, with its skipped IDs first.
+func suppressionsOf(req AnalyzeRequest) []schemas.Suppression {
+	if len(req.SkipIDs) == 0 {
+		return req.Suppressions
+	}
+	suppressions := make([]schemas.Suppression, 0, len(req.SkipIDs)+len(req.Suppressions))
+	for _, id := range req.SkipIDs {
+		suppressions = append(suppressions, schemas.Suppression{ID: id, Source: skipSuppressionSource})
+	}
+	return append(suppressions, req.Suppressions...)
+}
+
+// unlikelyFixedSource is the Suppression.Source of the findings suppressed with AnalyzeRequest.IgnoreUnlikelyFixed.
+const unlikelyFixedSource = "ignore-unlikely-fixed"
+
+// unlikelyFixedReason returns why v is unlikely to ever be fixed, or ok false if a fix may still come.
+// Findings the distro postpones or leaves to a point release (no-dsa) are kept, since they get fixed eventually.
+func unlikelyFixedReason(v schemas.Vulnerability) (reason string, ok bool) {
+	switch {
+	case v.FixState == schemas.FixStateWontFix:
+		return "the vendor will not fix it", true
+	case v.DistroStatus == DistroStatusIgnored:
+		return "the distro will not fix it", true
+	case v.DistroStatus == DistroStatusUnimportant:
+		return "the distro does not consider it a vulnerability of the package", true
+	case v.DistroStatus == DistroStatusEndOfLife:
+		return "the distro no longer supports the package", true
+	default:
+		return "", false
+	}
+}
+
+// suppressUnlikelyFixed splits vulns into the findings to report and those unlikely to ever be fixed.
+func suppressUnlikelyFixed(vulns []schemas.Vulnerability) ([]schemas.Vulnerability, []schemas.SuppressedFinding) {
+	kept := make([]schemas.Vulnerability, 0, len(vulns))
+	var suppressed []schemas.SuppressedFinding
+	for _, v := range vulns {
+		reason, ok := unlikelyFixedReason(v)
+		if !ok {
+			kept = append(kept, v)
+			continue
+		}
+		suppressed = append(suppressed, schemas.SuppressedFinding{
+			Vulnerability: v,
+			Suppression:   schemas.Suppression{ID: v.ID, PackageName: v.PackageName, Reason: reason, Source: unlikelyFixedSource},
+		})
+	}
+	return kept, suppressed
+}
+
 func buildSummary(vulns []schemas.Vulnerability) schemas.VulnerabilitySummary {
 	summary := schemas.VulnerabilitySummary{
-		TotalCount:      len(vulns),
-		CountBySeverity: make(map[schemas.Severity]int),
+		TotalCount:         len(vulns),
+		CountBySeverity:    make(map[schemas.Severity]int),
+		CountByPackageType: make(map[string]int),
+		CountByFixState:    make(map[schemas.FixState]int),
 	}
 
 	for _, v := range vulns {
 		summary.CountBySeverity[v.Severity]++
-		if v.FixedVersion != "" {
+
+		packageType := v.PackageType
+		if packageType == "" {
+			packageType = schemas.PackageTypeUnknown
+		}
+		summary.CountByPackageType[packageType]++
+
+		fixState := v.FixState
+		if fixState == "" {
+			fixState = schemas.FixStateUnknown
+		}
+		summary.CountByFixState[fixState]++
+		if fixState == schemas.FixStateFixAvailable {
 			summary.FixableCount++
 		}
 	}
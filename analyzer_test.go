@@ -2,11 +2,14 @@ package drydock_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hiro-o918/drydock"
 	"github.com/hiro-o918/drydock/schemas"
+	"github.com/rs/zerolog"
 	grafeaspb "google.golang.org/genproto/googleapis/grafeas/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestConvertToVulnerability(t *testing.T) {
@@ -43,11 +46,59 @@ func TestConvertToVulnerability(t *testing.T) {
 				ID:               "CVE-2023-0001",
 				Severity:         schemas.SeverityCritical,
 				CVSSScore:        9.8,
-				URLs:             []string{"https://cve.mitre.org/example"},
+				References:       []schemas.Reference{{URL: "https://cve.mitre.org/example", Type: schemas.ReferenceTypeAdvisory}},
 				Description:      "projects/ops/notes/CVE-2023-0001",
 				PackageName:      "openssl",
 				InstalledVersion: "1.1.1 (Kind: NORMAL)",
 				FixedVersion:     "1.1.1t",
+				FixState:         schemas.FixStateFixAvailable,
+				Source:           schemas.SourceContainerAnalysis,
+			},
+		},
+		"should populate CVSS version, vector and sub-scores when CVSSv3 data is present": {
+			input: &grafeaspb.Occurrence{
+				NoteName: "projects/ops/notes/CVE-2023-0002",
+				Details: &grafeaspb.Occurrence_Vulnerability{
+					Vulnerability: &grafeaspb.VulnerabilityOccurrence{
+						ShortDescription: "CVE-2023-0002",
+						Severity:         grafeaspb.Severity_HIGH,
+						CvssScore:        7.5,
+						CvssVersion:      grafeaspb.CVSSVersion_CVSS_VERSION_3,
+						Cvssv3: &grafeaspb.CVSS{
+							BaseScore:             7.5,
+							ExploitabilityScore:   3.9,
+							ImpactScore:           3.6,
+							AttackVector:          grafeaspb.CVSS_ATTACK_VECTOR_NETWORK,
+							AttackComplexity:      grafeaspb.CVSS_ATTACK_COMPLEXITY_LOW,
+							PrivilegesRequired:    grafeaspb.CVSS_PRIVILEGES_REQUIRED_NONE,
+							UserInteraction:       grafeaspb.CVSS_USER_INTERACTION_NONE,
+							Scope:                 grafeaspb.CVSS_SCOPE_UNCHANGED,
+							ConfidentialityImpact: grafeaspb.CVSS_IMPACT_NONE,
+							IntegrityImpact:       grafeaspb.CVSS_IMPACT_NONE,
+							AvailabilityImpact:    grafeaspb.CVSS_IMPACT_HIGH,
+						},
+						PackageIssue: []*grafeaspb.VulnerabilityOccurrence_PackageIssue{
+							{AffectedPackage: "zlib"},
+						},
+					},
+				},
+			},
+			want: schemas.Vulnerability{
+				ID:          "CVE-2023-0002",
+				Severity:    schemas.SeverityHigh,
+				CVSSScore:   7.5,
+				CVSSVersion: "3",
+				CVSSVector:  "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H",
+				CVSS: &schemas.CVSSDetails{
+					BaseScore:           7.5,
+					ExploitabilityScore: 3.9,
+					ImpactScore:         3.6,
+				},
+				References:  []schemas.Reference{},
+				Description: "projects/ops/notes/CVE-2023-0002",
+				PackageName: "zlib",
+				FixState:    schemas.FixStateNoFixAvailable,
+				Source:      schemas.SourceContainerAnalysis,
 			},
 		},
 	}
@@ -66,6 +117,140 @@ func TestConvertToVulnerability(t *testing.T) {
 	}
 }
 
+func TestExtractCWEs(t *testing.T) {
+	tests := map[string]struct {
+		input *grafeaspb.VulnerabilityOccurrence
+		want  []string
+	}{
+		"should return nil when no CWE is referenced": {
+			input: &grafeaspb.VulnerabilityOccurrence{LongDescription: "A buffer overflow in foo."},
+			want:  nil,
+		},
+		"should extract CWE IDs from descriptions and related URLs": {
+			input: &grafeaspb.VulnerabilityOccurrence{
+				LongDescription: "NIST: CWE-787 out-of-bounds write",
+				ExtraDetails:    "cwe-125",
+				RelatedUrls: []*grafeaspb.RelatedUrl{
+					{Url: "https://cwe.mitre.org/data/definitions/20.html"},
+					{Url: "https://example.com", Label: "CWE-787"},
+				},
+			},
+			want: []string{"CWE-20", "CWE-125", "CWE-787"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := drydock.ExportExtractCWEs(tt.input)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ExtractCWEs() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConvertFixState(t *testing.T) {
+	vex := func(types ...grafeaspb.VulnerabilityAssessmentNote_Assessment_Remediation_RemediationType) *grafeaspb.VulnerabilityOccurrence_VexAssessment {
+		a := &grafeaspb.VulnerabilityOccurrence_VexAssessment{}
+		for _, typ := range types {
+			a.Remediations = append(a.Remediations, &grafeaspb.VulnerabilityAssessmentNote_Assessment_Remediation{RemediationType: typ})
+		}
+		return a
+	}
+
+	tests := map[string]struct {
+		vuln  *grafeaspb.VulnerabilityOccurrence
+		issue *grafeaspb.VulnerabilityOccurrence_PackageIssue
+		want  schemas.FixState
+	}{
+		"should return UNKNOWN when there is no package issue": {
+			vuln: &grafeaspb.VulnerabilityOccurrence{},
+			want: schemas.FixStateUnknown,
+		},
+		"should return FIX_AVAILABLE when the package issue has a fixed version": {
+			vuln:  &grafeaspb.VulnerabilityOccurrence{},
+			issue: &grafeaspb.VulnerabilityOccurrence_PackageIssue{FixedVersion: &grafeaspb.Version{Name: "1.2.3"}},
+			want:  schemas.FixStateFixAvailable,
+		},
+		"should return FIX_AVAILABLE when the occurrence reports a fix": {
+			vuln:  &grafeaspb.VulnerabilityOccurrence{FixAvailable: true},
+			issue: &grafeaspb.VulnerabilityOccurrence_PackageIssue{},
+			want:  schemas.FixStateFixAvailable,
+		},
+		"should return NO_FIX_AVAILABLE when the package issue has no fix": {
+			vuln:  &grafeaspb.VulnerabilityOccurrence{},
+			issue: &grafeaspb.VulnerabilityOccurrence_PackageIssue{FixedVersion: &grafeaspb.Version{Kind: grafeaspb.Version_MAXIMUM}},
+			want:  schemas.FixStateNoFixAvailable,
+		},
+		"should return NO_FIX_AVAILABLE when VEX says none is available": {
+			vuln: &grafeaspb.VulnerabilityOccurrence{
+				VexAssessment: vex(grafeaspb.VulnerabilityAssessmentNote_Assessment_Remediation_NONE_AVAILABLE),
+			},
+			want: schemas.FixStateNoFixAvailable,
+		},
+		"should return WONT_FIX when VEX says no fix is planned": {
+			vuln: &grafeaspb.VulnerabilityOccurrence{
+				FixAvailable:  true,
+				VexAssessment: vex(grafeaspb.VulnerabilityAssessmentNote_Assessment_Remediation_NO_FIX_PLANNED),
+			},
+			want: schemas.FixStateWontFix,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := drydock.ExportConvertFixState(tt.vuln, tt.issue); got != tt.want {
+				t.Errorf("ConvertFixState() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyReference(t *testing.T) {
+	tests := map[string]struct {
+		label string
+		url   string
+		want  schemas.ReferenceType
+	}{
+		"should classify by advisory label": {
+			label: "Vendor Advisory",
+			url:   "https://example.com/notice",
+			want:  schemas.ReferenceTypeAdvisory,
+		},
+		"should classify by patch label": {
+			label: "Patch",
+			url:   "https://example.com/download",
+			want:  schemas.ReferenceTypeFix,
+		},
+		"should classify commit links as fixes": {
+			url:  "https://github.com/openssl/openssl/commit/abc123",
+			want: schemas.ReferenceTypeFix,
+		},
+		"should classify known advisory hosts": {
+			label: "More Info",
+			url:   "https://security-tracker.debian.org/tracker/CVE-2023-0001",
+			want:  schemas.ReferenceTypeAdvisory,
+		},
+		"should classify GitHub advisories": {
+			url:  "https://github.com/advisories/GHSA-xxxx-yyyy-zzzz",
+			want: schemas.ReferenceTypeAdvisory,
+		},
+		"should fall back to web": {
+			label: "More Info",
+			url:   "https://example.com/blog/post",
+			want:  schemas.ReferenceTypeWeb,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := drydock.ExportClassifyReference(tt.label, tt.url); got != tt.want {
+				t.Errorf("ClassifyReference() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFilterBySeverity(t *testing.T) {
 	// Shared input slice for filtering tests
 	inputVulns := []schemas.Vulnerability{
@@ -75,6 +260,8 @@ func TestFilterBySeverity(t *testing.T) {
 		{ID: "CRIT-1", Severity: schemas.SeverityCritical},
 	}
 
+	logger := zerolog.Nop()
+
 	tests := map[string]struct {
 		minSeverity schemas.Severity
 		want        []schemas.Vulnerability
@@ -118,7 +305,7 @@ func TestFilterBySeverity(t *testing.T) {
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			got := drydock.ExportFilterBySeverity(inputVulns, tt.minSeverity)
+			got := drydock.ExportFilterBySeverity(&logger, inputVulns, tt.minSeverity)
 
 			if diff := cmp.Diff(tt.want, got); diff != "" {
 				t.Errorf("FilterBySeverity() mismatch (-want +got):\n%s", diff)
@@ -134,31 +321,31 @@ func TestFilterFixable(t *testing.T) {
 	}{
 		"should return only fixable vulnerabilities": {
 			input: []schemas.Vulnerability{
-				{ID: "CVE-1", FixedVersion: "1.0.0"},
-				{ID: "CVE-2", FixedVersion: ""},
-				{ID: "CVE-3", FixedVersion: "2.0.0"},
-				{ID: "CVE-4", FixedVersion: ""},
+				{ID: "CVE-1", FixState: schemas.FixStateFixAvailable},
+				{ID: "CVE-2", FixState: schemas.FixStateNoFixAvailable},
+				{ID: "CVE-3", FixState: schemas.FixStateFixAvailable},
+				{ID: "CVE-4", FixState: schemas.FixStateNoFixAvailable},
 			},
 			want: []schemas.Vulnerability{
-				{ID: "CVE-1", FixedVersion: "1.0.0"},
-				{ID: "CVE-3", FixedVersion: "2.0.0"},
+				{ID: "CVE-1", FixState: schemas.FixStateFixAvailable},
+				{ID: "CVE-3", FixState: schemas.FixStateFixAvailable},
 			},
 		},
 		"should return empty list when no vulnerabilities have fixes": {
 			input: []schemas.Vulnerability{
-				{ID: "CVE-1", FixedVersion: ""},
-				{ID: "CVE-2", FixedVersion: ""},
+				{ID: "CVE-1", FixState: schemas.FixStateNoFixAvailable},
+				{ID: "CVE-2", FixState: schemas.FixStateNoFixAvailable},
 			},
 			want: []schemas.Vulnerability{},
 		},
 		"should return all vulnerabilities when all have fixes": {
 			input: []schemas.Vulnerability{
-				{ID: "CVE-1", FixedVersion: "1.0.0"},
-				{ID: "CVE-2", FixedVersion: "2.0.0"},
+				{ID: "CVE-1", FixState: schemas.FixStateFixAvailable},
+				{ID: "CVE-2", FixState: schemas.FixStateFixAvailable},
 			},
 			want: []schemas.Vulnerability{
-				{ID: "CVE-1", FixedVersion: "1.0.0"},
-				{ID: "CVE-2", FixedVersion: "2.0.0"},
+				{ID: "CVE-1", FixState: schemas.FixStateFixAvailable},
+				{ID: "CVE-2", FixState: schemas.FixStateFixAvailable},
 			},
 		},
 		"should return empty list when input is empty": {
@@ -178,6 +365,90 @@ func TestFilterFixable(t *testing.T) {
 	}
 }
 
+func TestFilterByID(t *testing.T) {
+	input := []schemas.Vulnerability{
+		{ID: "CVE-2024-0001"},
+		{ID: "CVE-2024-0002"},
+		{ID: "CVE-2024-0003"},
+	}
+
+	tests := map[string]struct {
+		only []string
+		skip []string
+		want []schemas.Vulnerability
+	}{
+		"should keep only listed IDs when only is set": {
+			only: []string{"CVE-2024-0002", "CVE-2024-9999"},
+			want: []schemas.Vulnerability{{ID: "CVE-2024-0002"}},
+		},
+		"should drop skipped IDs": {
+			skip: []string{"CVE-2024-0001"},
+			want: []schemas.Vulnerability{{ID: "CVE-2024-0002"}, {ID: "CVE-2024-0003"}},
+		},
+		"should apply skip on top of only": {
+			only: []string{"CVE-2024-0001", "CVE-2024-0002"},
+			skip: []string{"CVE-2024-0002"},
+			want: []schemas.Vulnerability{{ID: "CVE-2024-0001"}},
+		},
+		"should match IDs case-insensitively": {
+			only: []string{" cve-2024-0003 "},
+			want: []schemas.Vulnerability{{ID: "CVE-2024-0003"}},
+		},
+		"should return all vulnerabilities when no IDs are given": {
+			want: input,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := drydock.ExportFilterByID(input, tt.only, tt.skip)
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("FilterByID() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSuppressUnlikelyFixed(t *testing.T) {
+	fixable := schemas.Vulnerability{ID: "CVE-1", PackageName: "openssl", FixState: schemas.FixStateFixAvailable}
+	wontFix := schemas.Vulnerability{ID: "CVE-2", PackageName: "linux", FixState: schemas.FixStateWontFix}
+	ignored := schemas.Vulnerability{ID: "CVE-3", PackageName: "glibc", FixState: schemas.FixStateNoFixAvailable, DistroStatus: drydock.DistroStatusIgnored}
+	noDSA := schemas.Vulnerability{ID: "CVE-4", PackageName: "curl", FixState: schemas.FixStateNoFixAvailable, DistroStatus: drydock.DistroStatusNoDSA}
+
+	tests := map[string]struct {
+		input          []schemas.Vulnerability
+		wantKept       []schemas.Vulnerability
+		wantSuppressed []schemas.SuppressedFinding
+	}{
+		"should suppress wont-fix findings and those the distro ignores": {
+			input:    []schemas.Vulnerability{fixable, wontFix, ignored},
+			wantKept: []schemas.Vulnerability{fixable},
+			wantSuppressed: []schemas.SuppressedFinding{
+				{Vulnerability: wontFix, Suppression: schemas.Suppression{ID: "CVE-2", PackageName: "linux", Reason: "the vendor will not fix it", Source: "ignore-unlikely-fixed"}},
+				{Vulnerability: ignored, Suppression: schemas.Suppression{ID: "CVE-3", PackageName: "glibc", Reason: "the distro will not fix it", Source: "ignore-unlikely-fixed"}},
+			},
+		},
+		"should keep findings the distro fixes eventually": {
+			input:    []schemas.Vulnerability{noDSA},
+			wantKept: []schemas.Vulnerability{noDSA},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			kept, suppressed := drydock.ExportSuppressUnlikelyFixed(tt.input)
+
+			if diff := cmp.Diff(tt.wantKept, kept); diff != "" {
+				t.Errorf("kept mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantSuppressed, suppressed); diff != "" {
+				t.Errorf("suppressed mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestBuildSummary(t *testing.T) {
 	tests := map[string]struct {
 		input []schemas.Vulnerability
@@ -185,25 +456,39 @@ func TestBuildSummary(t *testing.T) {
 	}{
 		"should calculate correct counts when mixed fixable and non-fixable vulnerabilities exist": {
 			input: []schemas.Vulnerability{
-				{Severity: schemas.SeverityHigh, FixedVersion: "1.0.1"}, // Fixable
-				{Severity: schemas.SeverityHigh, FixedVersion: ""},      // Not Fixable
-				{Severity: schemas.SeverityMedium, FixedVersion: "2.0"}, // Fixable
+				{Severity: schemas.SeverityHigh, FixState: schemas.FixStateFixAvailable, PackageType: "OS"}, // Fixable
+				{Severity: schemas.SeverityHigh, FixState: schemas.FixStateWontFix, PackageType: "GO"},      // Not Fixable
+				{Severity: schemas.SeverityMedium, FixState: schemas.FixStateFixAvailable},                  // Fixable
+				{Severity: schemas.SeverityLow}, // Unknown
 			},
 			want: schemas.VulnerabilitySummary{
-				TotalCount:   3,
+				TotalCount:   4,
 				FixableCount: 2,
 				CountBySeverity: map[schemas.Severity]int{
 					schemas.SeverityHigh:   2,
 					schemas.SeverityMedium: 1,
+					schemas.SeverityLow:    1,
+				},
+				CountByPackageType: map[string]int{
+					"OS":                       1,
+					"GO":                       1,
+					schemas.PackageTypeUnknown: 2,
+				},
+				CountByFixState: map[schemas.FixState]int{
+					schemas.FixStateFixAvailable: 2,
+					schemas.FixStateWontFix:      1,
+					schemas.FixStateUnknown:      1,
 				},
 			},
 		},
 		"should return zero counts when input list is empty": {
 			input: []schemas.Vulnerability{},
 			want: schemas.VulnerabilitySummary{
-				TotalCount:      0,
-				FixableCount:    0,
-				CountBySeverity: map[schemas.Severity]int{},
+				TotalCount:         0,
+				FixableCount:       0,
+				CountBySeverity:    map[schemas.Severity]int{},
+				CountByPackageType: map[string]int{},
+				CountByFixState:    map[schemas.FixState]int{},
 			},
 		},
 	}
@@ -218,3 +503,46 @@ func TestBuildSummary(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyDiscovery(t *testing.T) {
+	lastScan := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := map[string]struct {
+		input *grafeaspb.DiscoveryOccurrence
+		want  *schemas.ScanMetadata
+	}{
+		"should record an active analysis": {
+			input: &grafeaspb.DiscoveryOccurrence{
+				ContinuousAnalysis: grafeaspb.DiscoveryOccurrence_ACTIVE,
+				LastScanTime:       timestamppb.New(lastScan),
+			},
+			want: &schemas.ScanMetadata{ContinuousAnalysis: "ACTIVE", LastAnalysisTime: lastScan},
+		},
+		"should flag an inactive analysis as stale with a warning": {
+			input: &grafeaspb.DiscoveryOccurrence{
+				ContinuousAnalysis: grafeaspb.DiscoveryOccurrence_INACTIVE,
+				LastScanTime:       timestamppb.New(lastScan),
+			},
+			want: &schemas.ScanMetadata{
+				ContinuousAnalysis: "INACTIVE",
+				LastAnalysisTime:   lastScan,
+				Stale:              true,
+				Warnings:           []string{"continuous analysis is inactive (image not pulled for 30 days); findings are not updated since 2024-01-02"},
+			},
+		},
+		"should leave an unspecified state empty": {
+			input: &grafeaspb.DiscoveryOccurrence{},
+			want:  &schemas.ScanMetadata{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := &schemas.ScanMetadata{}
+			drydock.ExportApplyDiscovery(got, tt.input)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("applyDiscovery() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
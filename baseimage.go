@@ -0,0 +1,181 @@
+package drydock
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hiro-o918/drydock/schemas"
+	"google.golang.org/api/iterator"
+	grafeaspb "google.golang.org/genproto/googleapis/grafeas/v1"
+)
+
+// BaseImage returns the URL of the base image the artifact was built on, as detected by Container Analysis
+// from the image layers (IMAGE occurrences). It returns an empty string when no base image is known.
+func (a *ArtifactRegistryAnalyzer) BaseImage(ctx context.Context, artifact schemas.ArtifactReference, location string) (string, error) {
+	listReq := &grafeaspb.ListOccurrencesRequest{
+		Parent: fmt.Sprintf("projects/%s", artifact.ProjectID),
+		Filter: fmt.Sprintf(`resourceUrl="%s" AND kind="IMAGE"`, artifact.ToResourceURL(location)),
+	}
+
+	var images []*grafeaspb.ImageOccurrence
+	it := a.occurrences.ListOccurrences(withFieldMask(ctx, imageOccurrenceFields), listReq, a.callOpts...)
+	for {
+		occ, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to list image occurrences: %w", classifyAPIError(err))
+		}
+		if img := occ.GetImage(); img != nil {
+			images = append(images, img)
+		}
+	}
+	return closestBaseImage(images), nil
+}
+
+// closestBaseImage returns the base image differing from the image by the fewest layers.
+func closestBaseImage(images []*grafeaspb.ImageOccurrence) string {
+	var closest *grafeaspb.ImageOccurrence
+	for _, img := range images {
+		if img.GetBaseResourceUrl() == "" {
+			continue
+		}
+		if closest == nil || img.GetDistance() < closest.GetDistance() {
+			closest = img
+		}
+	}
+	return closest.GetBaseResourceUrl()
+}
+
+// baseImageAdvisor compares the base images of analyzed images with their newest versions.
+// Advice is cached by base image, since many images usually share a few bases.
+type baseImageAdvisor struct {
+	resolver *ImageResolver
+	analyzer *ArtifactRegistryAnalyzer
+
+	mu    sync.Mutex
+	cache map[string]*schemas.BaseImageAdvice
+}
+
+// newBaseImageAdvisor creates an advisor using the scanner components.
+func newBaseImageAdvisor(resolver *ImageResolver, analyzer *ArtifactRegistryAnalyzer) *baseImageAdvisor {
+	return &baseImageAdvisor{
+		resolver: resolver,
+		analyzer: analyzer,
+		cache:    make(map[string]*schemas.BaseImageAdvice),
+	}
+}
+
+// advise returns the base image advice of target, or nil if its base image is unknown.
+// Base images outside Artifact Registry are reported without a recommendation.
+func (b *baseImageAdvisor) advise(ctx context.Context, target ImageTarget) (*schemas.BaseImageAdvice, error) {
+	baseURL, err := b.analyzer.BaseImage(ctx, target.Artifact, target.Location)
+	if err != nil || baseURL == "" {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	advice, ok := b.cache[baseURL]
+	b.mu.Unlock()
+	if ok {
+		return advice, nil
+	}
+
+	advice, err = b.compare(ctx, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	b.cache[baseURL] = advice
+	b.mu.Unlock()
+	return advice, nil
+}
+
+// compare finds the newest version of the base image and compares the findings of both.
+func (b *baseImageAdvisor) compare(ctx context.Context, baseURL string) (*schemas.BaseImageAdvice, error) {
+	current := strings.TrimPrefix(baseURL, "https://")
+	ref, err := schemas.ParseArtifactURI(current)
+	location, inRegistry := strings.CutSuffix(ref.Host, "-docker.pkg.dev")
+	if err != nil || !inRegistry || ref.Digest == nil {
+		return &schemas.BaseImageAdvice{Current: current, Message: "base image is not in Artifact Registry; newer versions were not checked"}, nil
+	}
+
+	repoName := fmt.Sprintf("projects/%s/locations/%s/repositories/%s", ref.ProjectID, location, ref.RepositoryID)
+	var newest *ImageTarget
+	for target, err := range b.resolver.repositoryLatestImages(ctx, repoName, &resolveConfig{}) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list versions of base image %s: %w", current, err)
+		}
+		if target.Artifact.ImageName == ref.ImageName {
+			newest = &target
+			break
+		}
+	}
+	if newest == nil || newest.Artifact.Digest == nil || *newest.Artifact.Digest == *ref.Digest {
+		return &schemas.BaseImageAdvice{Current: current, Message: "base image is up to date"}, nil
+	}
+
+	before, err := b.analyzer.Analyze(ctx, AnalyzeRequest{Artifact: ref, Location: location})
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze base image %s: %w", current, err)
+	}
+	after, err := b.analyzer.Analyze(ctx, AnalyzeRequest{Artifact: newest.Artifact, Location: newest.Location})
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze base image %s: %w", newest.Artifact, err)
+	}
+	return buildBaseImageAdvice(current, newest.Artifact.String(), *before, *after), nil
+}
+
+// buildBaseImageAdvice compares the findings of the current base image with those of a newer one.
+func buildBaseImageAdvice(current, recommended string, before, after schemas.AnalyzeResult) *schemas.BaseImageAdvice {
+	advice := &schemas.BaseImageAdvice{Current: current}
+	// Both results are versions of the same image, so the diff has at most one entry
+	for _, d := range schemas.DiffResults([]schemas.AnalyzeResult{before}, []schemas.AnalyzeResult{after}) {
+		advice.RemovedBySeverity = countBySeverity(d.Removed)
+		advice.AddedBySeverity = countBySeverity(d.Added)
+	}
+
+	removed := formatSeverityCounts(advice.RemovedBySeverity)
+	if removed == "" {
+		advice.Message = fmt.Sprintf("%s is newer but would not remove any finding", recommended)
+		return advice
+	}
+	advice.Recommended = recommended
+	advice.Message = fmt.Sprintf("rebuilding on %s would remove %s findings", recommended, removed)
+	if added := formatSeverityCounts(advice.AddedBySeverity); added != "" {
+		advice.Message += fmt.Sprintf(" (and add %s)", added)
+	}
+	return advice
+}
+
+// countBySeverity counts vulnerabilities per severity, returning nil for none.
+func countBySeverity(vulns []schemas.Vulnerability) map[schemas.Severity]int {
+	if len(vulns) == 0 {
+		return nil
+	}
+	counts := make(map[schemas.Severity]int)
+	for _, v := range vulns {
+		counts[v.Severity]++
+	}
+	return counts
+}
+
+// formatSeverityCounts renders counts from the most severe, e.g. "2 CRITICAL / 5 HIGH".
+func formatSeverityCounts(counts map[schemas.Severity]int) string {
+	severities := make([]schemas.Severity, 0, len(counts))
+	for s := range counts {
+		severities = append(severities, s)
+	}
+	slices.SortFunc(severities, func(a, b schemas.Severity) int { return schemas.CompareSeverity(b, a) })
+
+	parts := make([]string, 0, len(severities))
+	for _, s := range severities {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[s], s))
+	}
+	return strings.Join(parts, " / ")
+}
@@ -0,0 +1,85 @@
+package drydock_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+	grafeaspb "google.golang.org/genproto/googleapis/grafeas/v1"
+)
+
+func TestClosestBaseImage(t *testing.T) {
+	tests := map[string]struct {
+		images []*grafeaspb.ImageOccurrence
+		want   string
+	}{
+		"should pick the base image with the fewest differing layers": {
+			images: []*grafeaspb.ImageOccurrence{
+				{BaseResourceUrl: "https://us-docker.pkg.dev/p/base/debian@sha256:a", Distance: 5},
+				{BaseResourceUrl: "https://us-docker.pkg.dev/p/base/node@sha256:b", Distance: 2},
+				{Distance: 1},
+			},
+			want: "https://us-docker.pkg.dev/p/base/node@sha256:b",
+		},
+		"should return nothing without occurrences": {
+			images: nil,
+			want:   "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := drydock.ExportClosestBaseImage(tt.images); got != tt.want {
+				t.Errorf("closestBaseImage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildBaseImageAdvice(t *testing.T) {
+	artifact := schemas.ArtifactReference{Host: "us-docker.pkg.dev", ProjectID: "p", RepositoryID: "base", ImageName: "debian"}
+	result := func(vulns ...schemas.Vulnerability) schemas.AnalyzeResult {
+		return schemas.AnalyzeResult{Artifact: artifact, Vulnerabilities: vulns}
+	}
+	critical := schemas.Vulnerability{ID: "CVE-1", PackageName: "openssl", Severity: schemas.SeverityCritical}
+	high := schemas.Vulnerability{ID: "CVE-2", PackageName: "zlib", Severity: schemas.SeverityHigh}
+	high2 := schemas.Vulnerability{ID: "CVE-3", PackageName: "curl", Severity: schemas.SeverityHigh}
+	low := schemas.Vulnerability{ID: "CVE-4", PackageName: "bash", Severity: schemas.SeverityLow}
+
+	tests := map[string]struct {
+		before schemas.AnalyzeResult
+		after  schemas.AnalyzeResult
+		want   *schemas.BaseImageAdvice
+	}{
+		"should recommend a base image removing findings": {
+			before: result(critical, high, high2),
+			after:  result(low),
+			want: &schemas.BaseImageAdvice{
+				Current:           "current",
+				Recommended:       "newer",
+				RemovedBySeverity: map[schemas.Severity]int{schemas.SeverityCritical: 1, schemas.SeverityHigh: 2},
+				AddedBySeverity:   map[schemas.Severity]int{schemas.SeverityLow: 1},
+				Message:           "rebuilding on newer would remove 1 CRITICAL / 2 HIGH findings (and add 1 LOW)",
+			},
+		},
+		"should not recommend a base image removing nothing": {
+			before: result(high),
+			after:  result(high, low),
+			want: &schemas.BaseImageAdvice{
+				Current:         "current",
+				AddedBySeverity: map[schemas.Severity]int{schemas.SeverityLow: 1},
+				Message:         "newer is newer but would not remove any finding",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := drydock.ExportBuildBaseImageAdvice("current", "newer", tt.before, tt.after)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("buildBaseImageAdvice() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
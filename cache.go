@@ -0,0 +1,176 @@
+package drydock
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/rs/zerolog"
+)
+
+// Cache stores analysis results between scans (see WithCache). Implementations only keep bytes;
+// expiry is checked by the scanner, and ttl is a hint for backends that evict entries themselves.
+// FileCache, RedisCache and GCSCache implement it; a shared backend lets CI runners reuse each
+// other's analyses. The scanner closes caches implementing io.Closer.
+type Cache interface {
+	// Get returns the value stored under key, or ok false if there is none
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+
+	// Set stores value under key, for at least ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// FileCache keeps cache entries as files in a local directory.
+type FileCache struct {
+	dir string
+}
+
+var _ Cache = (*FileCache)(nil)
+
+// NewFileCache returns a cache keeping its entries in dir, which is created on the first Set.
+// Expired entries are overwritten, not removed.
+func NewFileCache(dir string) *FileCache {
+	return &FileCache{dir: dir}
+}
+
+// Get implements Cache.
+func (c *FileCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// Set implements Cache. The file is replaced atomically, so concurrent readers never see half an entry.
+func (c *FileCache) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, "."+key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(value); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(c.dir, key))
+}
+
+// cacheEntry is a cached analysis result with its expiry.
+type cacheEntry struct {
+	Expires time.Time              `json:"expires"`
+	Result  *schemas.AnalyzeResult `json:"result"`
+}
+
+// cachingAnalyzer answers analyses from a cache, and stores those it has to run.
+type cachingAnalyzer struct {
+	next  Analyzer
+	cache Cache
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// Analyze implements Analyzer. Cache failures are logged and fall back to the next analyzer.
+// Truncated results are not cached, since they miss findings.
+func (a *cachingAnalyzer) Analyze(ctx context.Context, req AnalyzeRequest) (*schemas.AnalyzeResult, error) {
+	log := zerolog.Ctx(ctx)
+	key, ok := analysisCacheKey(req)
+	if !ok {
+		return a.next.Analyze(ctx, req)
+	}
+
+	data, found, err := a.cache.Get(ctx, key)
+	if err != nil {
+		log.Warn().Err(err).Str("image", req.Artifact.String()).Msg("Reading the analysis cache failed")
+	}
+	if found {
+		var entry cacheEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			log.Warn().Err(err).Str("image", req.Artifact.String()).Msg("Ignoring invalid analysis cache entry")
+		} else if entry.Result != nil && a.now().Before(entry.Expires) {
+			log.Debug().Str("image", req.Artifact.String()).Msg("Analysis answered from cache")
+			return entry.Result, nil
+		}
+	}
+
+	result, err := a.next.Analyze(ctx, req)
+	if err != nil || (result.Metadata != nil && result.Metadata.Truncated) {
+		return result, err
+	}
+	data, err = json.Marshal(cacheEntry{Expires: a.now().Add(a.ttl), Result: result})
+	if err == nil {
+		err = a.cache.Set(ctx, key, data, a.ttl)
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("image", req.Artifact.String()).Msg("Writing the analysis cache failed")
+	}
+	return result, nil
+}
+
+// Close closes the next analyzer and the cache, if they need closing.
+func (a *cachingAnalyzer) Close() error {
+	var errs []error
+	for _, c := range []any{a.next, a.cache} {
+		if closer, ok := c.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// analysisCacheKey returns the cache key of the analysis of req: a hash of the image digest and of
+// every setting changing the result. Images without a digest are not cached, since their content
+// changes, and neither are requests with distro trackers, whose data cannot be fingerprinted.
+func analysisCacheKey(req AnalyzeRequest) (string, bool) {
+	if req.Artifact.Digest == nil || len(req.DistroTrackers) > 0 {
+		return "", false
+	}
+	filter := ""
+	if req.Filter != nil {
+		filter = req.Filter.String()
+	}
+	fingerprint, err := json.Marshal(struct {
+		Version      int
+		Resource     string
+		MinSeverity  schemas.Severity
+		FixableOnly  bool
+		OnlyIDs      []string
+		SkipIDs      []string
+		Suppressions []schemas.Suppression
+		Filter       string
+		Unlikely     bool `json:",omitempty"`
+	}{
+		Version:      1,
+		Resource:     req.Artifact.ToResourceURL(req.Location),
+		MinSeverity:  req.MinSeverity,
+		FixableOnly:  req.FixableOnly,
+		OnlyIDs:      slices.Sorted(slices.Values(req.OnlyIDs)),
+		SkipIDs:      slices.Sorted(slices.Values(req.SkipIDs)),
+		Suppressions: req.Suppressions,
+		Filter:       filter,
+		Unlikely:     req.IgnoreUnlikelyFixed,
+	})
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(fingerprint)
+	return "analysis-" + hex.EncodeToString(sum[:]), true
+}
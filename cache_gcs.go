@@ -0,0 +1,70 @@
+package drydock
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	storage "google.golang.org/api/storage/v1"
+)
+
+// GCSCache keeps cache entries as objects in a Cloud Storage bucket, so that scanners on many
+// machines share them. Each object gets its expiry as custom time: a lifecycle rule on
+// "days since custom time" deletes expired entries.
+type GCSCache struct {
+	objects *storage.ObjectsService
+	bucket  string
+	prefix  string
+	now     func() time.Time
+}
+
+var _ Cache = (*GCSCache)(nil)
+
+// NewGCSCache returns a cache in bucket, naming its objects prefix followed by the key (e.g. "drydock/").
+func NewGCSCache(ctx context.Context, bucket, prefix string, opts ...option.ClientOption) (*GCSCache, error) {
+	if bucket == "" {
+		return nil, errors.New("a Cloud Storage bucket is required")
+	}
+	service, err := storage.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Storage client: %w", err)
+	}
+	return &GCSCache{objects: service.Objects, bucket: bucket, prefix: prefix, now: time.Now}, nil
+}
+
+// Get implements Cache.
+func (c *GCSCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	resp, err := c.objects.Get(c.bucket, c.prefix+key).Context(ctx).Download()
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read gs://%s/%s%s: %w", c.bucket, c.prefix, key, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read gs://%s/%s%s: %w", c.bucket, c.prefix, key, err)
+	}
+	return data, true, nil
+}
+
+// Set implements Cache.
+func (c *GCSCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	object := &storage.Object{
+		Name:        c.prefix + key,
+		ContentType: "application/json",
+		CustomTime:  c.now().Add(ttl).UTC().Format(time.RFC3339),
+	}
+	if _, err := c.objects.Insert(c.bucket, object).Media(bytes.NewReader(value)).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to write gs://%s/%s: %w", c.bucket, object.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package drydock_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/drydocktest"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+func TestScanner_WithCache(t *testing.T) {
+	pinned := drydocktest.Target("us-central1-docker.pkg.dev/p/repo/api@sha256:" + strings.Repeat("a", 64))
+	tagged := drydocktest.Target("us-central1-docker.pkg.dev/p/repo/worker:v1")
+	critical := schemas.Vulnerability{ID: "CVE-1", Severity: schemas.SeverityCritical, PackageName: "openssl"}
+	canned := schemas.AnalyzeResult{Artifact: pinned.Artifact, Vulnerabilities: []schemas.Vulnerability{critical}}
+	byImage := cmpopts.SortSlices(func(a, b schemas.AnalyzeResult) bool {
+		return a.Artifact.ImageName < b.Artifact.ImageName
+	})
+
+	tests := map[string]struct {
+		ttl          time.Duration
+		minSeverity  schemas.Severity
+		wantAnalyzed []string
+	}{
+		"should answer digests from the cache and analyze images without one": {
+			ttl:          time.Hour,
+			minSeverity:  schemas.SeverityHigh,
+			wantAnalyzed: []string{"worker"},
+		},
+		"should analyze again after settings change": {
+			ttl:          time.Hour,
+			minSeverity:  schemas.SeverityCritical,
+			wantAnalyzed: []string{"api", "worker"},
+		},
+		"should analyze again once entries expire": {
+			ttl:          time.Nanosecond,
+			minSeverity:  schemas.SeverityHigh,
+			wantAnalyzed: []string{"api", "worker"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			cache := drydock.NewFileCache(t.TempDir())
+			scan := func(minSeverity schemas.Severity) (*drydocktest.Analyzer, []schemas.AnalyzeResult) {
+				analyzer := drydocktest.NewAnalyzer(canned)
+				exporter := &drydocktest.Exporter{}
+				scanner, err := drydock.NewScanner(ctx, "us-central1",
+					drydock.WithProjectID("p"),
+					drydock.WithResolver(drydocktest.NewResolver(pinned, tagged)),
+					drydock.WithAnalyzer(analyzer),
+					drydock.WithExporter(exporter),
+					drydock.WithCache(cache, tt.ttl),
+				)
+				if err != nil {
+					t.Fatalf("NewScanner() error = %v", err)
+				}
+				defer func() { _ = scanner.Close() }()
+				if err := scanner.Scan(ctx, drydock.ScanOptions{MinSeverity: minSeverity}); err != nil {
+					t.Fatalf("Scan() error = %v", err)
+				}
+				return analyzer, exporter.Results()
+			}
+
+			_, first := scan(schemas.SeverityHigh)
+			time.Sleep(time.Millisecond)
+			analyzer, second := scan(tt.minSeverity)
+
+			var analyzed []string
+			for _, req := range analyzer.Requests() {
+				analyzed = append(analyzed, req.Artifact.ImageName)
+			}
+			if diff := cmp.Diff(tt.wantAnalyzed, analyzed, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+				t.Errorf("analyzed images mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(first, second, cmpopts.EquateEmpty(), byImage, cmpopts.IgnoreFields(schemas.AnalyzeResult{}, "ScanTime")); diff != "" {
+				t.Errorf("results of the second scan mismatch (-first +second):\n%s", diff)
+			}
+		})
+	}
+}
+
+// fakeRedis is a Redis server keeping strings in memory, for GET, SET (with PX), AUTH and SELECT.
+type fakeRedis struct {
+	password string
+
+	mu       sync.Mutex
+	values   map[string]string
+	commands []string
+}
+
+// serve answers the connections of l until it is closed.
+func (f *fakeRedis) serve(l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+	authed := f.password == ""
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.commands = append(f.commands, strings.Join(args, " "))
+		var reply string
+		switch {
+		case args[0] == "AUTH":
+			authed = args[len(args)-1] == f.password
+			reply = "+OK\r\n"
+			if !authed {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			reply = "-NOAUTH Authentication required.\r\n"
+		case args[0] == "SELECT":
+			reply = "+OK\r\n"
+		case args[0] == "GET":
+			if v, ok := f.values[args[1]]; ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+			} else {
+				reply = "$-1\r\n"
+			}
+		case args[0] == "SET":
+			f.values[args[1]] = args[2]
+			reply = "+OK\r\n"
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		f.mu.Unlock()
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand reads a command sent as an array of bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	readLine := func() (string, error) {
+		line, err := r.ReadString('\n')
+		return strings.TrimSuffix(line, "\r\n"), err
+	}
+	line, err := readLine()
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(line, "*"))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		if _, err := readLine(); err != nil {
+			return nil, err
+		}
+		if args[i], err = readLine(); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+func TestRedisCache(t *testing.T) {
+	tests := map[string]struct {
+		password     string
+		url          func(addr string) string
+		wantErr      bool
+		wantCommands []string
+	}{
+		"should store and read values": {
+			url: func(addr string) string { return "redis://" + addr },
+			wantCommands: []string{
+				"GET k", "SET k v PX 60000", "GET k",
+			},
+		},
+		"should authenticate and select the database": {
+			password: "secret",
+			url:      func(addr string) string { return "redis://:secret@" + addr + "/2" },
+			wantCommands: []string{
+				"AUTH secret", "SELECT 2", "GET k", "SET k v PX 60000", "GET k",
+			},
+		},
+		"should report authentication failures": {
+			password: "secret",
+			url:      func(addr string) string { return "redis://:wrong@" + addr },
+			wantErr:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Skipf("cannot listen: %v", err)
+			}
+			defer func() { _ = l.Close() }()
+			server := &fakeRedis{password: tt.password, values: make(map[string]string)}
+			go server.serve(l)
+
+			cache, err := drydock.NewRedisCache(tt.url(l.Addr().String()))
+			if err != nil {
+				t.Fatalf("NewRedisCache() error = %v", err)
+			}
+			defer func() { _ = cache.Close() }()
+			ctx := context.Background()
+
+			_, found, err := cache.Get(ctx, "k")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Get() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil || found {
+				t.Fatalf("Get() of a missing key = %v, %v, want a miss", found, err)
+			}
+			if err := cache.Set(ctx, "k", []byte("v"), time.Minute); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+			got, found, err := cache.Get(ctx, "k")
+			if err != nil || !found || string(got) != "v" {
+				t.Errorf("Get() = %q, %v, %v, want %q", got, found, err, "v")
+			}
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			if diff := cmp.Diff(tt.wantCommands, server.commands); diff != "" {
+				t.Errorf("commands mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package drydock
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	artifactregistry "cloud.google.com/go/artifactregistry/apiv1"
+	"cloud.google.com/go/artifactregistry/apiv1/artifactregistrypb"
+	containeranalysis "cloud.google.com/go/containeranalysis/apiv1"
+	"github.com/googleapis/gax-go/v2"
+	storage "google.golang.org/api/storage/v1"
+	locationpb "google.golang.org/genproto/googleapis/cloud/location"
+	grafeaspb "google.golang.org/genproto/googleapis/grafeas/v1"
+	"google.golang.org/grpc/metadata"
+)
+
+// fieldMaskHeader is the gRPC metadata of the X-Goog-FieldMask system parameter, which restricts
+// responses to the listed fields. The list calls have no read mask of their own.
+const fieldMaskHeader = "x-goog-fieldmask"
+
+// Fields of the list responses consumed by drydock. Occurrences in particular carry much that is
+// discarded (e.g. attestation envelopes, remediation text), and are listed once per scanned image.
+// Keep them in sync with newCandidateImage, convertToVulnerability, applyDiscovery, BaseImage and FetchSBOM.
+var (
+	dockerImageFields = []string{
+		"docker_images.uri",
+		"docker_images.tags",
+		"docker_images.image_size_bytes",
+		"docker_images.upload_time",
+		"docker_images.media_type",
+		"docker_images.build_time",
+		"docker_images.update_time",
+		"next_page_token",
+	}
+	vulnerabilityOccurrenceFields = []string{
+		"occurrences.name",
+		"occurrences.note_name",
+		"occurrences.create_time",
+		"occurrences.vulnerability",
+		"occurrences.discovery",
+		"next_page_token",
+	}
+	imageOccurrenceFields = []string{
+		"occurrences.image",
+		"next_page_token",
+	}
+	sbomReferenceOccurrenceFields = []string{
+		"occurrences.sbom_reference.payload.predicate",
+		"next_page_token",
+	}
+)
+
+// withFieldMask returns a context whose calls request only the fields listed.
+func withFieldMask(ctx context.Context, fields []string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, fieldMaskHeader, strings.Join(fields, ","))
+}
+
+// The interfaces below are the parts of the Google Cloud clients used by ImageResolver and
+// ArtifactRegistryAnalyzer. They let tests exercise pagination, grouping and conversion against
+// fakes instead of the APIs. Iterators return iterator.Done after the last item, like the real ones.
+
+// artifactRegistryClient is the part of the Artifact Registry API used by ImageResolver.
+type artifactRegistryClient interface {
+	ListLocations(ctx context.Context, req *locationpb.ListLocationsRequest, opts ...gax.CallOption) locationIterator
+	ListRepositories(ctx context.Context, req *artifactregistrypb.ListRepositoriesRequest, opts ...gax.CallOption) repositoryIterator
+	ListDockerImages(ctx context.Context, req *artifactregistrypb.ListDockerImagesRequest, opts ...gax.CallOption) dockerImageIterator
+	GetTag(ctx context.Context, req *artifactregistrypb.GetTagRequest, opts ...gax.CallOption) (*artifactregistrypb.Tag, error)
+	GetDockerImage(ctx context.Context, req *artifactregistrypb.GetDockerImageRequest, opts ...gax.CallOption) (*artifactregistrypb.DockerImage, error)
+	Close() error
+}
+
+// locationIterator iterates over the pages of a ListLocations call.
+type locationIterator interface {
+	Next() (*locationpb.Location, error)
+}
+
+// repositoryIterator iterates over the pages of a ListRepositories call.
+type repositoryIterator interface {
+	Next() (*artifactregistrypb.Repository, error)
+}
+
+// dockerImageIterator iterates over the pages of a ListDockerImages call.
+type dockerImageIterator interface {
+	Next() (*artifactregistrypb.DockerImage, error)
+}
+
+// occurrenceClient is the part of the Container Analysis (Grafeas) API used by ArtifactRegistryAnalyzer.
+type occurrenceClient interface {
+	ListOccurrences(ctx context.Context, req *grafeaspb.ListOccurrencesRequest, opts ...gax.CallOption) occurrenceIterator
+	Close() error
+}
+
+// occurrenceIterator iterates over the pages of a ListOccurrences call.
+type occurrenceIterator interface {
+	Next() (*grafeaspb.Occurrence, error)
+}
+
+// objectReader is the part of the Cloud Storage API used by ArtifactRegistryAnalyzer to download SBOMs.
+type objectReader interface {
+	ReadObject(ctx context.Context, bucket, object string) (io.ReadCloser, error)
+}
+
+// gcpArtifactRegistryClient adapts the Artifact Registry client to artifactRegistryClient.
+type gcpArtifactRegistryClient struct {
+	*artifactregistry.Client
+}
+
+func (c gcpArtifactRegistryClient) ListLocations(ctx context.Context, req *locationpb.ListLocationsRequest, opts ...gax.CallOption) locationIterator {
+	return c.Client.ListLocations(ctx, req, opts...)
+}
+
+func (c gcpArtifactRegistryClient) ListRepositories(ctx context.Context, req *artifactregistrypb.ListRepositoriesRequest, opts ...gax.CallOption) repositoryIterator {
+	return c.Client.ListRepositories(ctx, req, opts...)
+}
+
+func (c gcpArtifactRegistryClient) ListDockerImages(ctx context.Context, req *artifactregistrypb.ListDockerImagesRequest, opts ...gax.CallOption) dockerImageIterator {
+	return c.Client.ListDockerImages(ctx, req, opts...)
+}
+
+// gcpOccurrenceClient adapts the Container Analysis client to occurrenceClient.
+type gcpOccurrenceClient struct {
+	*containeranalysis.Client
+}
+
+func (c gcpOccurrenceClient) ListOccurrences(ctx context.Context, req *grafeaspb.ListOccurrencesRequest, opts ...gax.CallOption) occurrenceIterator {
+	return c.GetGrafeasClient().ListOccurrences(ctx, req, opts...)
+}
+
+// gcsObjectReader adapts the Cloud Storage objects service to objectReader.
+type gcsObjectReader struct {
+	objects *storage.ObjectsService
+}
+
+func (r gcsObjectReader) ReadObject(ctx context.Context, bucket, object string) (io.ReadCloser, error) {
+	resp, err := r.objects.Get(bucket, object).Context(ctx).Download()
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
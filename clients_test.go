@@ -0,0 +1,673 @@
+package drydock_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/artifactregistry/apiv1/artifactregistrypb"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/gax-go/v2"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+	"google.golang.org/api/iterator"
+	locationpb "google.golang.org/genproto/googleapis/cloud/location"
+	grafeaspb "google.golang.org/genproto/googleapis/grafeas/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeIterator yields items, then err if set, then iterator.Done.
+type fakeIterator[T any] struct {
+	items []T
+	err   error
+}
+
+func (it *fakeIterator[T]) Next() (T, error) {
+	var zero T
+	if len(it.items) > 0 {
+		item := it.items[0]
+		it.items = it.items[1:]
+		return item, nil
+	}
+	if it.err != nil {
+		return zero, it.err
+	}
+	return zero, iterator.Done
+}
+
+// fakeArtifactRegistry serves locations, repositories and images from memory.
+type fakeArtifactRegistry struct {
+	locations []string
+	repos     []*artifactregistrypb.Repository
+	repoErrs  map[string]error                             // by location name
+	images    map[string][]*artifactregistrypb.DockerImage // by repository name
+	imageErrs map[string]error                             // by repository name
+	tags      map[string]*artifactregistrypb.Tag           // by tag name
+}
+
+func (f *fakeArtifactRegistry) ListLocations(_ context.Context, req *locationpb.ListLocationsRequest, _ ...gax.CallOption) drydock.ExportLocationIterator {
+	var locations []*locationpb.Location
+	for _, id := range f.locations {
+		locations = append(locations, &locationpb.Location{Name: req.GetName() + "/locations/" + id, LocationId: id})
+	}
+	return &fakeIterator[*locationpb.Location]{items: locations}
+}
+
+func (f *fakeArtifactRegistry) ListRepositories(_ context.Context, req *artifactregistrypb.ListRepositoriesRequest, _ ...gax.CallOption) drydock.ExportRepositoryIterator {
+	var repos []*artifactregistrypb.Repository
+	for _, repo := range f.repos {
+		if strings.HasPrefix(repo.GetName(), req.GetParent()+"/") {
+			repos = append(repos, repo)
+		}
+	}
+	return &fakeIterator[*artifactregistrypb.Repository]{items: repos, err: f.repoErrs[req.GetParent()]}
+}
+
+func (f *fakeArtifactRegistry) ListDockerImages(_ context.Context, req *artifactregistrypb.ListDockerImagesRequest, _ ...gax.CallOption) drydock.ExportDockerImageIterator {
+	return &fakeIterator[*artifactregistrypb.DockerImage]{items: f.images[req.GetParent()], err: f.imageErrs[req.GetParent()]}
+}
+
+func (f *fakeArtifactRegistry) GetTag(_ context.Context, req *artifactregistrypb.GetTagRequest, _ ...gax.CallOption) (*artifactregistrypb.Tag, error) {
+	if t, ok := f.tags[req.GetName()]; ok {
+		return t, nil
+	}
+	return nil, status.Error(codes.NotFound, "tag not found")
+}
+
+func (f *fakeArtifactRegistry) GetDockerImage(_ context.Context, req *artifactregistrypb.GetDockerImageRequest, _ ...gax.CallOption) (*artifactregistrypb.DockerImage, error) {
+	for _, images := range f.images {
+		for _, img := range images {
+			if strings.HasSuffix(req.GetName(), "@"+img.GetUri()[strings.LastIndex(img.GetUri(), "@")+1:]) {
+				return img, nil
+			}
+		}
+	}
+	return nil, status.Error(codes.NotFound, "image not found")
+}
+
+func (f *fakeArtifactRegistry) Close() error { return nil }
+
+const fakeRepo = "projects/p/locations/us-central1/repositories/repo"
+
+func fakeDigest(c string) string {
+	return "sha256:" + strings.Repeat(c, 64)
+}
+
+func dockerImage(image, digest string, updated time.Time, tags ...string) *artifactregistrypb.DockerImage {
+	return &artifactregistrypb.DockerImage{
+		Uri:        "us-central1-docker.pkg.dev/p/repo/" + image + "@" + digest,
+		Tags:       tags,
+		UpdateTime: timestamppb.New(updated),
+	}
+}
+
+func newFakeRegistry() *fakeArtifactRegistry {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &fakeArtifactRegistry{
+		repos: []*artifactregistrypb.Repository{
+			{Name: fakeRepo, Format: artifactregistrypb.Repository_DOCKER},
+			{Name: "projects/p/locations/us-central1/repositories/npm", Format: artifactregistrypb.Repository_NPM},
+		},
+		images: map[string][]*artifactregistrypb.DockerImage{
+			fakeRepo: {
+				dockerImage("api", fakeDigest("c"), day.AddDate(0, 0, 3), "v3"),
+				dockerImage("api", fakeDigest("b"), day.AddDate(0, 0, 2), "latest", "v2"),
+				dockerImage("api", fakeDigest("a"), day.AddDate(0, 0, 1), "v1"),
+				dockerImage("worker", fakeDigest("e"), day.AddDate(0, 0, 5)),
+				dockerImage("worker", fakeDigest("d"), day.AddDate(0, 0, 4), "v1"),
+			},
+		},
+		tags: map[string]*artifactregistrypb.Tag{
+			fakeRepo + "/packages/api/tags/v1": {Name: "v1", Version: fakeRepo + "/packages/api/versions/" + fakeDigest("a")},
+		},
+	}
+}
+
+// targetLines summarizes targets as "URI tag", sorted.
+func targetLines(t *testing.T, targets func(yield func(drydock.ImageTarget, error) bool)) ([]string, []error) {
+	t.Helper()
+	var lines []string
+	var errs []error
+	for target, err := range targets {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		tag := ""
+		if target.Artifact.Tag != nil {
+			tag = *target.Artifact.Tag
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", target.URI, tag))
+	}
+	slices.Sort(lines)
+	return lines, errs
+}
+
+func TestImageResolver_AllLatestImages(t *testing.T) {
+	tests := map[string]struct {
+		registry func() *fakeArtifactRegistry
+		opts     []drydock.ResolveOption
+		want     []string
+		wantErrs int
+	}{
+		"should list repositories concurrently": {
+			registry: func() *fakeArtifactRegistry {
+				f := newFakeRegistry()
+				for _, repo := range []string{"other", "third"} {
+					name := "projects/p/locations/us-central1/repositories/" + repo
+					f.repos = append(f.repos, &artifactregistrypb.Repository{Name: name, Format: artifactregistrypb.Repository_DOCKER})
+					f.images[name] = []*artifactregistrypb.DockerImage{{
+						Uri:  "us-central1-docker.pkg.dev/p/" + repo + "/app@" + fakeDigest("f"),
+						Tags: []string{"latest"},
+					}}
+				}
+				return f
+			},
+			opts: []drydock.ResolveOption{drydock.ResolveConcurrency(3)},
+			want: []string{
+				"us-central1-docker.pkg.dev/p/other/app@" + fakeDigest("f") + " latest",
+				"us-central1-docker.pkg.dev/p/repo/api@" + fakeDigest("b") + " latest",
+				"us-central1-docker.pkg.dev/p/repo/worker@" + fakeDigest("e") + " ",
+				"us-central1-docker.pkg.dev/p/third/app@" + fakeDigest("f") + " latest",
+			},
+		},
+		"should select the latest tag, or else the newest digest, of each image": {
+			registry: newFakeRegistry,
+			want: []string{
+				"us-central1-docker.pkg.dev/p/repo/api@" + fakeDigest("b") + " latest",
+				"us-central1-docker.pkg.dev/p/repo/worker@" + fakeDigest("e") + " ",
+			},
+		},
+		"should only consider the newest digests of each image": {
+			registry: func() *fakeArtifactRegistry {
+				day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+				f := newFakeRegistry()
+				f.images[fakeRepo] = nil
+				for i, c := range "abcdef" {
+					tags := []string{fmt.Sprintf("v%d", 6-i)}
+					if c == 'f' {
+						tags = append(tags, "latest")
+					}
+					f.images[fakeRepo] = append(f.images[fakeRepo], dockerImage("api", fakeDigest(string(c)), day.AddDate(0, 0, 6-i), tags...))
+				}
+				return f
+			},
+			want: []string{"us-central1-docker.pkg.dev/p/repo/api@" + fakeDigest("a") + " v6"},
+		},
+		"should consider as many digests of each image as requested": {
+			registry: func() *fakeArtifactRegistry {
+				day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+				f := newFakeRegistry()
+				f.images[fakeRepo] = nil
+				for i, c := range "abcdef" {
+					tags := []string{fmt.Sprintf("v%d", 6-i)}
+					if c == 'f' {
+						tags = append(tags, "latest")
+					}
+					f.images[fakeRepo] = append(f.images[fakeRepo], dockerImage("api", fakeDigest(string(c)), day.AddDate(0, 0, 6-i), tags...))
+				}
+				return f
+			},
+			opts: []drydock.ResolveOption{drydock.ResolveMaxCandidates(6), drydock.ResolvePageSize(2)},
+			want: []string{"us-central1-docker.pkg.dev/p/repo/api@" + fakeDigest("f") + " v1"},
+		},
+		"should select the newest digests of each image": {
+			registry: newFakeRegistry,
+			opts:     []drydock.ResolveOption{drydock.DigestsPerImage(2)},
+			want: []string{
+				"us-central1-docker.pkg.dev/p/repo/api@" + fakeDigest("b") + " latest",
+				"us-central1-docker.pkg.dev/p/repo/api@" + fakeDigest("c") + " v3",
+				"us-central1-docker.pkg.dev/p/repo/worker@" + fakeDigest("d") + " v1",
+				"us-central1-docker.pkg.dev/p/repo/worker@" + fakeDigest("e") + " ",
+			},
+		},
+		"should yield the images settled before a repository fails mid-pagination": {
+			registry: func() *fakeArtifactRegistry {
+				f := newFakeRegistry()
+				f.imageErrs = map[string]error{fakeRepo: status.Error(codes.PermissionDenied, "denied")}
+				return f
+			},
+			// api is settled by its "latest" tag before the failure; worker is not
+			want:     []string{"us-central1-docker.pkg.dev/p/repo/api@" + fakeDigest("b") + " latest"},
+			wantErrs: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resolver := drydock.ExportNewImageResolver(tt.registry())
+			got, errs := targetLines(t, resolver.AllLatestImages(context.Background(), "p", "us-central1", tt.opts...))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("AllLatestImages() mismatch (-want +got):\n%s", diff)
+			}
+			if len(errs) != tt.wantErrs {
+				t.Errorf("errors = %v, want %d", errs, tt.wantErrs)
+			}
+			for _, err := range errs {
+				if !errors.Is(err, drydock.ErrPermissionDenied) {
+					t.Errorf("error = %v, want ErrPermissionDenied", err)
+				}
+			}
+		})
+	}
+}
+
+func TestImageResolver_AllLocations(t *testing.T) {
+	f := newFakeRegistry()
+	f.locations = []string{"asia-northeast1", "us-central1"}
+	name := "projects/p/locations/asia-northeast1/repositories/tokyo"
+	f.repos = append(f.repos, &artifactregistrypb.Repository{Name: name, Format: artifactregistrypb.Repository_DOCKER})
+	f.images[name] = []*artifactregistrypb.DockerImage{{
+		Uri:  "asia-northeast1-docker.pkg.dev/p/tokyo/app@" + fakeDigest("f"),
+		Tags: []string{"latest"},
+	}}
+	resolver := drydock.ExportNewImageResolver(f)
+
+	locations, err := resolver.ListLocations(context.Background(), "p")
+	if err != nil {
+		t.Fatalf("ListLocations() error = %v", err)
+	}
+	if diff := cmp.Diff(f.locations, locations); diff != "" {
+		t.Errorf("ListLocations() mismatch (-want +got):\n%s", diff)
+	}
+
+	got, errs := targetLines(t, resolver.AllLatestImages(context.Background(), "p", drydock.AllLocations))
+	if len(errs) > 0 {
+		t.Fatalf("AllLatestImages() errors = %v", errs)
+	}
+	want := []string{
+		"asia-northeast1-docker.pkg.dev/p/tokyo/app@" + fakeDigest("f") + " latest",
+		"us-central1-docker.pkg.dev/p/repo/api@" + fakeDigest("b") + " latest",
+		"us-central1-docker.pkg.dev/p/repo/worker@" + fakeDigest("e") + " ",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("AllLatestImages() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestImageResolver_AllLocations_PartialErrors(t *testing.T) {
+	f := newFakeRegistry()
+	f.locations = []string{"asia-northeast1", "us-central1"}
+	f.repoErrs = map[string]error{"projects/p/locations/asia-northeast1": status.Error(codes.PermissionDenied, "denied")}
+	resolver := drydock.ExportNewImageResolver(f)
+
+	got, errs := targetLines(t, resolver.AllLatestImages(context.Background(), "p", drydock.AllLocations))
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "location asia-northeast1") {
+		t.Errorf("AllLatestImages() errors = %v, want the asia-northeast1 error", errs)
+	}
+	want := []string{
+		"us-central1-docker.pkg.dev/p/repo/api@" + fakeDigest("b") + " latest",
+		"us-central1-docker.pkg.dev/p/repo/worker@" + fakeDigest("e") + " ",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("AllLatestImages() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestImageResolver_AllImages(t *testing.T) {
+	resolver := drydock.ExportNewImageResolver(newFakeRegistry())
+	got, errs := targetLines(t, resolver.AllImages(context.Background(), "p", "us-central1"))
+	if len(errs) > 0 {
+		t.Fatalf("AllImages() errors = %v", errs)
+	}
+	if len(got) != 5 {
+		t.Errorf("AllImages() yielded %d digests, want 5:\n%s", len(got), strings.Join(got, "\n"))
+	}
+}
+
+func TestImageResolver_ResolveImage(t *testing.T) {
+	api := schemas.ArtifactReference{Host: "us-central1-docker.pkg.dev", ProjectID: "p", RepositoryID: "repo", ImageName: "api"}
+	byTag := api
+	byTag.Tag = utils.Ptr("v1")
+	byDigest := api
+	byDigest.Digest = utils.Ptr(fakeDigest("c"))
+	missing := api
+	missing.Tag = utils.Ptr("v9")
+
+	tests := map[string]struct {
+		ref        schemas.ArtifactReference
+		wantDigest string
+		wantErr    bool
+	}{
+		"should resolve a tag to its digest": {
+			ref: byTag, wantDigest: fakeDigest("a"),
+		},
+		"should look a digest up directly": {
+			ref: byDigest, wantDigest: fakeDigest("c"),
+		},
+		"should fail on a missing tag": {
+			ref: missing, wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resolver := drydock.ExportNewImageResolver(newFakeRegistry())
+			got, err := resolver.ResolveImage(context.Background(), tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if *got.Artifact.Digest != tt.wantDigest {
+				t.Errorf("digest = %s, want %s", *got.Artifact.Digest, tt.wantDigest)
+			}
+			if got.Location != "us-central1" {
+				t.Errorf("location = %s, want us-central1", got.Location)
+			}
+		})
+	}
+}
+
+// fakeOccurrences serves occurrences from memory and records the filters it receives.
+type fakeOccurrences struct {
+	mu          sync.Mutex
+	occurrences []*grafeaspb.Occurrence
+	err         error
+	filters     []string
+	fieldMasks  []string
+}
+
+func (f *fakeOccurrences) ListOccurrences(ctx context.Context, req *grafeaspb.ListOccurrencesRequest, _ ...gax.CallOption) drydock.ExportOccurrenceIterator {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.filters = append(f.filters, req.GetFilter())
+	md, _ := metadata.FromOutgoingContext(ctx)
+	f.fieldMasks = append(f.fieldMasks, md.Get("x-goog-fieldmask")...)
+	return &fakeIterator[*grafeaspb.Occurrence]{items: f.occurrences, err: f.err}
+}
+
+func (f *fakeOccurrences) Close() error { return nil }
+
+func vulnerabilityOccurrence(id string, severity grafeaspb.Severity) *grafeaspb.Occurrence {
+	return &grafeaspb.Occurrence{
+		Details: &grafeaspb.Occurrence_Vulnerability{Vulnerability: &grafeaspb.VulnerabilityOccurrence{
+			ShortDescription: id,
+			Severity:         severity,
+			PackageIssue:     []*grafeaspb.VulnerabilityOccurrence_PackageIssue{{AffectedPackage: "openssl"}},
+		}},
+	}
+}
+
+func TestArtifactRegistryAnalyzer_Analyze(t *testing.T) {
+	artifact := schemas.ArtifactReference{
+		Host: "us-central1-docker.pkg.dev", ProjectID: "p", RepositoryID: "repo", ImageName: "api",
+		Digest: utils.Ptr(fakeDigest("a")),
+	}
+	discovery := &grafeaspb.Occurrence{
+		Details: &grafeaspb.Occurrence_Discovery{Discovery: &grafeaspb.DiscoveryOccurrence{
+			ContinuousAnalysis: grafeaspb.DiscoveryOccurrence_ACTIVE,
+		}},
+	}
+
+	tests := map[string]struct {
+		client     *fakeOccurrences
+		cancel     bool
+		wantIDs    []string
+		wantMeta   *schemas.ScanMetadata
+		wantStatus schemas.ScanStatus
+		wantErr    error
+		wantFilter string
+	}{
+		"should convert and filter vulnerabilities, and read the discovery occurrence": {
+			client: &fakeOccurrences{occurrences: []*grafeaspb.Occurrence{
+				vulnerabilityOccurrence("CVE-1", grafeaspb.Severity_CRITICAL),
+				discovery,
+				vulnerabilityOccurrence("CVE-2", grafeaspb.Severity_LOW),
+			}},
+			wantIDs:    []string{"CVE-1"},
+			wantMeta:   &schemas.ScanMetadata{OccurrencesFetched: 3, ContinuousAnalysis: "ACTIVE"},
+			wantStatus: schemas.ScanStatusFinished,
+			wantFilter: `resourceUrl="https://us-central1-docker.pkg.dev/p/repo/api@` + fakeDigest("a") + `" AND (kind="VULNERABILITY" OR kind="DISCOVERY")`,
+		},
+		"should classify API errors": {
+			client:  &fakeOccurrences{err: status.Error(codes.PermissionDenied, "denied")},
+			wantErr: drydock.ErrPermissionDenied,
+		},
+		"should keep the fetched occurrences when interrupted mid-pagination": {
+			client: &fakeOccurrences{
+				occurrences: []*grafeaspb.Occurrence{vulnerabilityOccurrence("CVE-1", grafeaspb.Severity_CRITICAL)},
+				err:         context.Canceled,
+			},
+			cancel:  true,
+			wantIDs: []string{"CVE-1"},
+			wantMeta: &schemas.ScanMetadata{
+				OccurrencesFetched: 1,
+				Truncated:          true,
+				Warnings:           []string{"listing occurrences stopped early: context canceled"},
+			},
+			wantStatus: schemas.ScanStatusFinished,
+		},
+		"should report an image without occurrences as not scanned": {
+			client: &fakeOccurrences{},
+			wantMeta: &schemas.ScanMetadata{
+				Warnings: []string{"image has no analysis by Container Analysis (e.g., pushed before scanning was enabled); it was not analyzed"},
+			},
+			wantStatus: schemas.ScanStatusNotScanned,
+		},
+		"should report an analysis in progress as pending": {
+			client: &fakeOccurrences{occurrences: []*grafeaspb.Occurrence{{
+				Details: &grafeaspb.Occurrence_Discovery{Discovery: &grafeaspb.DiscoveryOccurrence{
+					AnalysisStatus: grafeaspb.DiscoveryOccurrence_SCANNING,
+				}},
+			}}},
+			wantMeta: &schemas.ScanMetadata{
+				OccurrencesFetched: 1,
+				Warnings:           []string{"analysis by Container Analysis is not finished; findings may be missing"},
+			},
+			wantStatus: schemas.ScanStatusPending,
+		},
+		"should report an unsupported image": {
+			client: &fakeOccurrences{occurrences: []*grafeaspb.Occurrence{{
+				Details: &grafeaspb.Occurrence_Discovery{Discovery: &grafeaspb.DiscoveryOccurrence{
+					AnalysisStatus: grafeaspb.DiscoveryOccurrence_FINISHED_UNSUPPORTED,
+				}},
+			}}},
+			wantMeta: &schemas.ScanMetadata{
+				OccurrencesFetched: 1,
+				Warnings:           []string{"image is not supported by Container Analysis (e.g., unsupported OS); it was not analyzed"},
+			},
+			wantStatus: schemas.ScanStatusUnsupported,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+			analyzer := drydock.ExportNewArtifactRegistryAnalyzer(tt.client)
+
+			got, err := analyzer.Analyze(ctx, drydock.AnalyzeRequest{Artifact: artifact, Location: "us-central1", MinSeverity: schemas.SeverityHigh})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Analyze() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+
+			var ids []string
+			for _, v := range got.Vulnerabilities {
+				ids = append(ids, v.ID)
+			}
+			if diff := cmp.Diff(tt.wantIDs, ids); diff != "" {
+				t.Errorf("vulnerability IDs mismatch (-want +got):\n%s", diff)
+			}
+			got.Metadata.DurationMillis = 0
+			if diff := cmp.Diff(tt.wantMeta, got.Metadata); diff != "" {
+				t.Errorf("metadata mismatch (-want +got):\n%s", diff)
+			}
+			if got.ScanStatus != tt.wantStatus {
+				t.Errorf("ScanStatus = %s, want %s", got.ScanStatus, tt.wantStatus)
+			}
+			if tt.wantFilter != "" && tt.client.filters[0] != tt.wantFilter {
+				t.Errorf("filter = %s, want %s", tt.client.filters[0], tt.wantFilter)
+			}
+		})
+	}
+}
+
+func TestFieldMasks(t *testing.T) {
+	tests := map[string]struct {
+		response proto.Message
+		fields   []string
+	}{
+		"should request existing fields of listed docker images": {
+			response: &artifactregistrypb.ListDockerImagesResponse{},
+			fields:   drydock.ExportDockerImageFields,
+		},
+		"should request existing fields of listed vulnerability occurrences": {
+			response: &grafeaspb.ListOccurrencesResponse{},
+			fields:   drydock.ExportVulnerabilityOccurrenceFields,
+		},
+		"should request existing fields of listed image occurrences": {
+			response: &grafeaspb.ListOccurrencesResponse{},
+			fields:   drydock.ExportImageOccurrenceFields,
+		},
+		"should request existing fields of listed SBOM reference occurrences": {
+			response: &grafeaspb.ListOccurrencesResponse{},
+			fields:   drydock.ExportSBOMReferenceOccurrenceFields,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, field := range tt.fields {
+				if !hasFieldPath(tt.response.ProtoReflect().Descriptor(), field) {
+					t.Errorf("field %q does not exist in %s", field, tt.response.ProtoReflect().Descriptor().FullName())
+				}
+			}
+			if !slices.Contains(tt.fields, "next_page_token") {
+				t.Errorf("field mask %v drops the next page token", tt.fields)
+			}
+		})
+	}
+}
+
+// hasFieldPath reports whether the dotted path of field names exists in the message. Unlike
+// fieldmaskpb, it traverses repeated fields, as the X-Goog-FieldMask system parameter does.
+func hasFieldPath(desc protoreflect.MessageDescriptor, path string) bool {
+	for name := range strings.SplitSeq(path, ".") {
+		if desc == nil {
+			return false
+		}
+		field := desc.Fields().ByName(protoreflect.Name(name))
+		if field == nil {
+			return false
+		}
+		desc = field.Message()
+	}
+	return true
+}
+
+func TestArtifactRegistryAnalyzer_Analyze_FieldMask(t *testing.T) {
+	client := &fakeOccurrences{occurrences: []*grafeaspb.Occurrence{vulnerabilityOccurrence("CVE-1", grafeaspb.Severity_CRITICAL)}}
+	analyzer := drydock.ExportNewArtifactRegistryAnalyzer(client)
+	artifact := schemas.ArtifactReference{
+		Host: "us-central1-docker.pkg.dev", ProjectID: "p", RepositoryID: "repo", ImageName: "api",
+		Digest: utils.Ptr(fakeDigest("a")),
+	}
+	if _, err := analyzer.Analyze(t.Context(), drydock.AnalyzeRequest{Artifact: artifact, Location: "us-central1"}); err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	want := []string{strings.Join(drydock.ExportVulnerabilityOccurrenceFields, ",")}
+	if diff := cmp.Diff(want, client.fieldMasks); diff != "" {
+		t.Errorf("field masks mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// fakeObjects serves Cloud Storage objects from memory, by "bucket/object".
+type fakeObjects map[string]string
+
+func (f fakeObjects) ReadObject(_ context.Context, bucket, object string) (io.ReadCloser, error) {
+	data, ok := f[bucket+"/"+object]
+	if !ok {
+		return nil, errors.New("object not found")
+	}
+	return io.NopCloser(strings.NewReader(data)), nil
+}
+
+func sbomReferenceOccurrence(location string) *grafeaspb.Occurrence {
+	return &grafeaspb.Occurrence{
+		Details: &grafeaspb.Occurrence_SbomReference{SbomReference: &grafeaspb.SBOMReferenceOccurrence{
+			Payload: &grafeaspb.SbomReferenceIntotoPayload{
+				Predicate: &grafeaspb.SbomReferenceIntotoPredicate{Location: location, MimeType: "application/spdx+json"},
+			},
+		}},
+	}
+}
+
+func TestArtifactRegistryAnalyzer_FetchSBOM(t *testing.T) {
+	artifact := schemas.ArtifactReference{
+		Host: "us-central1-docker.pkg.dev", ProjectID: "p", RepositoryID: "repo", ImageName: "api",
+		Digest: utils.Ptr(fakeDigest("a")),
+	}
+	objects := fakeObjects{"bucket/sboms/api.spdx.json": `{"spdxVersion": "SPDX-2.3"}`}
+
+	tests := map[string]struct {
+		client     *fakeOccurrences
+		artifact   schemas.ArtifactReference
+		want       *drydock.SBOMDocument
+		wantErr    error
+		wantFilter string
+	}{
+		"should download the referenced SBOM": {
+			client:   &fakeOccurrences{occurrences: []*grafeaspb.Occurrence{sbomReferenceOccurrence("gs://bucket/sboms/api.spdx.json")}},
+			artifact: artifact,
+			want: &drydock.SBOMDocument{
+				Reference: schemas.SBOMReference{URI: "gs://bucket/sboms/api.spdx.json", MediaType: "application/spdx+json"},
+				Data:      []byte(`{"spdxVersion": "SPDX-2.3"}`),
+			},
+			wantFilter: `resourceUrl="https://us-central1-docker.pkg.dev/p/repo/api@` + fakeDigest("a") + `" AND kind="SBOM_REFERENCE"`,
+		},
+		"should report images without SBOM": {
+			client:     &fakeOccurrences{},
+			artifact:   artifact,
+			wantErr:    drydock.ErrSBOMNotFound,
+			wantFilter: `resourceUrl="https://us-central1-docker.pkg.dev/p/repo/api@` + fakeDigest("a") + `" AND kind="SBOM_REFERENCE"`,
+		},
+		"should classify API errors": {
+			client:     &fakeOccurrences{err: status.Error(codes.PermissionDenied, "denied")},
+			artifact:   artifact,
+			wantErr:    drydock.ErrPermissionDenied,
+			wantFilter: `resourceUrl="https://us-central1-docker.pkg.dev/p/repo/api@` + fakeDigest("a") + `" AND kind="SBOM_REFERENCE"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			analyzer := drydock.ExportNewArtifactRegistryAnalyzer(tt.client)
+			drydock.ExportSetObjectReader(analyzer, objects)
+
+			got, err := analyzer.FetchSBOM(t.Context(), tt.artifact)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FetchSBOM() error = %v, want %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("FetchSBOM() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff([]string{tt.wantFilter}, tt.client.filters); diff != "" {
+				t.Errorf("filters mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// ColorMode controls whether output is colorized.
+type ColorMode string
+
+const (
+	// ColorAuto colorizes output written to a terminal, unless NO_COLOR is set.
+	ColorAuto ColorMode = "auto"
+	// ColorAlways always colorizes output.
+	ColorAlways ColorMode = "always"
+	// ColorNever never colorizes output.
+	ColorNever ColorMode = "never"
+)
+
+// String implements the flag.Value interface.
+func (m *ColorMode) String() string {
+	return string(*m)
+}
+
+// Set implements the flag.Value interface.
+func (m *ColorMode) Set(value string) error {
+	normalized := ColorMode(strings.ToLower(strings.TrimSpace(value)))
+	switch normalized {
+	case ColorAuto, ColorAlways, ColorNever:
+		*m = normalized
+		return nil
+	default:
+		return fmt.Errorf("invalid color mode: %s (allowed: auto, always, never)", value)
+	}
+}
+
+// enabled reports whether output written to w should be colorized.
+// In auto mode, the NO_COLOR environment variable (https://no-color.org) disables colors.
+func (m ColorMode) enabled(w io.Writer) bool {
+	switch m {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && (isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd()))
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestColorMode_Enabled(t *testing.T) {
+	tests := map[string]struct {
+		mode    ColorMode
+		noColor string
+		want    bool
+	}{
+		"should always colorize with always": {
+			mode:    ColorAlways,
+			noColor: "1",
+			want:    true,
+		},
+		"should never colorize with never": {
+			mode: ColorNever,
+			want: false,
+		},
+		"should not colorize a non-terminal in auto mode": {
+			mode: ColorAuto,
+			want: false,
+		},
+		"should honor NO_COLOR in auto mode": {
+			mode:    ColorAuto,
+			noColor: "1",
+			want:    false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			if got := tt.mode.enabled(&bytes.Buffer{}); got != tt.want {
+				t.Errorf("enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFlags_Color(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		want    ColorMode
+		wantErr bool
+	}{
+		"should default to auto": {
+			args: []string{"-l", "us-central1"},
+			want: ColorAuto,
+		},
+		"should parse --color": {
+			args: []string{"-l", "us-central1", "--color", "always"},
+			want: ColorAlways,
+		},
+		"should parse --no-color": {
+			args: []string{"-l", "us-central1", "--no-color"},
+			want: ColorNever,
+		},
+		"should keep the mode with --no-color=false": {
+			args: []string{"-l", "us-central1", "--color", "always", "--no-color=false"},
+			want: ColorAlways,
+		},
+		"should reject invalid --no-color values": {
+			args:    []string{"-l", "us-central1", "--no-color=maybe"},
+			wantErr: true,
+		},
+		"should reject unknown modes": {
+			args:    []string{"-l", "us-central1", "--color", "rainbow"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := parseFlags(tt.args, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cfg.Color != tt.want {
+				t.Errorf("parseFlags() Color = %v, want %v", cfg.Color, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// commandScan is the subcommand that scans a location and exports the findings.
+const commandScan = "scan"
+
+// command is a drydock subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string, stdout, stderr io.Writer) error
+}
+
+// commands returns the subcommands in the order they are listed in the usage.
+func commands() []command {
+	return []command{
+		{name: commandScan, summary: "Scan the images of a location for vulnerabilities (default)", run: runScan},
+		{name: commandList, summary: "List repositories, images, tags and digests (no vulnerability data)", run: runList},
+		{name: commandStale, summary: "Report untagged and long-unchanged digests with their vulnerability counts, as cleanup candidates", run: runStale},
+		{name: commandPlan, summary: "Resolve targets and estimate API calls without analyzing images", run: runPlanCommand},
+		{name: commandReport, summary: "Re-export JSON reports of previous scans in another format (offline)", run: runReport},
+		{name: commandDiff, summary: "Compare two JSON reports and print the changed findings (offline)", run: runDiff},
+		{name: commandExplain, summary: "Show the images, packages, scores, fixes and advisories of one vulnerability", run: runExplain},
+		{name: commandFixPR, summary: "Open GitHub pull requests fixing the findings of JSON reports", run: runFixPR},
+		{name: commandServe, summary: "Scan images pushed to Artifact Registry, as notified by Eventarc", run: runServe},
+		{name: commandConfig, summary: "Validate the configuration (file and flags) of a scan without running it", run: runConfig},
+		{name: commandSchema, summary: "Print the JSON Schema of the JSON output", run: func(_ context.Context, _ []string, stdout, _ io.Writer) error {
+			return runSchema(stdout)
+		}},
+		{name: commandVersion, summary: "Print the version", run: func(_ context.Context, _ []string, stdout, _ io.Writer) error {
+			return runVersion(stdout)
+		}},
+	}
+}
+
+// lookupCommand returns the subcommand with the given name.
+func lookupCommand(name string) (command, bool) {
+	for _, c := range commands() {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// printUsage lists the subcommands.
+func printUsage(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "Drydock - Artifact Registry Vulnerability Scanner")
+	_, _ = fmt.Fprintln(w, "")
+	_, _ = fmt.Fprintln(w, "Usage: drydock <command> [flags]")
+	_, _ = fmt.Fprintln(w, "")
+	_, _ = fmt.Fprintln(w, "Commands:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range commands() {
+		_, _ = fmt.Fprintf(tw, "  %s\t%s\n", c.name, c.summary)
+	}
+	_ = tw.Flush()
+	_, _ = fmt.Fprintln(w, "")
+	_, _ = fmt.Fprintln(w, "Run `drydock <command> -h` for the flags of a command.")
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRun_Commands(t *testing.T) {
+	tests := map[string]struct {
+		args       []string
+		wantErr    bool
+		wantStdout string
+		wantStderr string
+	}{
+		"should print the usage without arguments": {
+			args:       nil,
+			wantStderr: "Commands:",
+		},
+		"should print the usage for help": {
+			args:       []string{"help"},
+			wantStderr: "serve",
+		},
+		"should print the version": {
+			args:       []string{"version"},
+			wantStdout: "drydock ",
+		},
+		"should print the flags of a command": {
+			args:       []string{"scan", "-h"},
+			wantStderr: "Usage: drydock scan [flags]",
+		},
+		"should reject unknown commands": {
+			args:    []string{"frobnicate"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := run(context.Background(), tt.args, &stdout, &stderr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Errorf("stdout = %q, want it to contain %q", stdout.String(), tt.wantStdout)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestParseCommandFlags_Scoping(t *testing.T) {
+	tests := map[string]struct {
+		command string
+		args    []string
+		wantErr bool
+	}{
+		"should accept finding flags for scan": {
+			command: commandScan,
+			args:    []string{"-l", "us-central1", "-s", "LOW", "-o", "csv"},
+		},
+		"should reject finding flags for plan": {
+			command: commandPlan,
+			args:    []string{"-l", "us-central1", "-s", "LOW"},
+			wantErr: true,
+		},
+		"should accept the listen address for serve": {
+			command: commandServe,
+			args:    []string{"-l", "us-central1", "--addr", ":9090", "--fixable"},
+		},
+		"should reject the listen address for scan": {
+			command: commandScan,
+			args:    []string{"-l", "us-central1", "--addr", ":9090"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseCommandFlags(tt.command, tt.args, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseCommandFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
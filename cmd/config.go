@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// commandConfig is the subcommand that works with configuration files.
+const commandConfig = "config"
+
+// configEnv names the environment variable holding the default configuration file.
+const configEnv = "DRYDOCK_CONFIG"
+
+// configCommands are the subcommands whose flags may be set in a configuration file.
+var configCommands = []string{commandScan, commandPlan, commandList, commandStale, commandExplain, commandServe}
+
+// addConfigFlags registers the --config flag shared by all commands.
+func addConfigFlags(fs *flag.FlagSet, cfg *Config) {
+	// --config
+	fs.StringVar(&cfg.ConfigFile, "config", "", "JSON file of flag values, keyed by long flag name; flags on the command line take precedence (default: $"+configEnv+")")
+}
+
+// applyConfigFile sets the flags of fs that were not given on the command line from a JSON configuration file.
+// Keys are long flag names; lists set repeatable flags once per element.
+// Keys of flags that belong to other commands are ignored, so a single file can serve every command.
+// All problems of the file are reported at once.
+func applyConfigFile(fs *flag.FlagSet, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration file: %w", err)
+	}
+
+	var values map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&values); err != nil {
+		return fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+		if name := aliasOf(f); name != "" {
+			explicit[name] = true
+		}
+	})
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		if key == "config" || !knownConfigKey(key) {
+			errs = append(errs, fmt.Errorf("unknown key %q", key))
+			continue
+		}
+		f := fs.Lookup(key)
+		if f == nil || explicit[key] || explicit[aliasOf(f)] {
+			continue
+		}
+		items, ok := values[key].([]any)
+		if !ok {
+			items = []any{values[key]}
+		}
+		for _, item := range items {
+			value, err := configValue(item)
+			if err == nil {
+				err = fs.Set(key, value)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("key %q: %w", key, err))
+				break
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration file %s: %w", path, errors.Join(errs...))
+	}
+	return nil
+}
+
+// aliasOf returns the name of the flag f is an alias of, as documented in its usage ("(alias for --name)").
+func aliasOf(f *flag.Flag) string {
+	_, after, ok := strings.Cut(f.Usage, "(alias for --")
+	if !ok {
+		return ""
+	}
+	end := strings.IndexAny(after, " )")
+	if end < 0 {
+		return after
+	}
+	return after[:end]
+}
+
+// knownConfigKey reports whether key is a flag of any command that reads the configuration file.
+func knownConfigKey(key string) bool {
+	for _, command := range configCommands {
+		if newCommandFlagSet(command, newConfig(), io.Discard).Lookup(key) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// configValue converts a JSON scalar to its flag representation.
+func configValue(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return fmt.Sprint(v), nil
+	case json.Number:
+		return v.String(), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v (want a string, number, boolean or list of them)", v)
+	}
+}
+
+// addConfigValidateFlags registers the flags of `drydock config validate`.
+func addConfigValidateFlags(fs *flag.FlagSet, cfg *Config) {
+	// --check-connectivity
+	fs.BoolVar(&cfg.CheckConnectivity, "check-connectivity", false, "Also check that the output file is writable, and that Artifact Registry and the destination of the report (Confluence, ServiceNow, S3, BigQuery, Cloud Storage, GitHub) can be reached with the configured credentials")
+}
+
+// runConfig dispatches the config subcommands.
+func runConfig(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 || args[0] != "validate" {
+		_, _ = fmt.Fprintln(stderr, "Usage: drydock config validate [--config file] [--check-connectivity] [flags]")
+		_, _ = fmt.Fprintln(stderr, "  Validate the configuration of a scan without running it")
+		if len(args) == 0 {
+			return flag.ErrHelp
+		}
+		return fmt.Errorf("unknown config command %q", args[0])
+	}
+	return runConfigValidate(ctx, args[1:], stdout, stderr)
+}
+
+// runConfigValidate parses and validates the effective scan configuration (file and flags) without scanning.
+// With --check-connectivity, the output file, the registry and the destination of the report are checked too.
+func runConfigValidate(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 && os.Getenv(configEnv) == "" {
+		return fmt.Errorf("config validate requires --config or $%s", configEnv)
+	}
+	cfg, err := parseCommandFlags(commandConfig, args, stderr)
+	if err != nil {
+		return err
+	}
+	setupGlobalLogger(stderr, cfg.LogLevel, cfg.LogFormat, cfg.Color)
+
+	if cfg.CheckConnectivity {
+		if err := checkConnectivity(ctx, cfg, stdout, stderr); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintln(stdout, "Configuration is valid.")
+	return err
+}
+
+// checkConnectivity checks the destinations the scan would use, printing one line per check.
+func checkConnectivity(ctx context.Context, cfg *Config, stdout, stderr io.Writer) error {
+	var errs []error
+	report := func(name string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			_, _ = fmt.Fprintf(stdout, "FAIL  %s: %v\n", name, err)
+			return
+		}
+		_, _ = fmt.Fprintf(stdout, "ok    %s\n", name)
+	}
+
+	if cfg.OutputFile != "" {
+		path, err := expandOutputPath(cfg.OutputFile, cfg, time.Now())
+		if err == nil {
+			err = checkWritable(path)
+		}
+		report("output file "+path, err)
+	}
+	report("artifact registry", checkRegistry(ctx, cfg, stderr))
+	if flags := cfg.destinationFlags(); len(flags) > 0 && flags[0] != "`--output-file`" {
+		report("destination "+strings.Trim(flags[0], "`"), checkDestination(ctx, cfg))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("connectivity check failed: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// destinationChecker is implemented by the exporters of remote destinations, which can be
+// checked without exporting anything.
+type destinationChecker interface {
+	Check(ctx context.Context) error
+}
+
+// checkDestination checks that the integration the report is sent to answers and accepts the credentials,
+// or that the badge directory is writable.
+func checkDestination(ctx context.Context, cfg *Config) error {
+	if cfg.BadgeDir != "" {
+		return checkWritable(filepath.Join(cfg.BadgeDir, "badge.json"))
+	}
+	exp, err := newDestination(ctx, cfg, io.Discard)
+	if err != nil {
+		return err
+	}
+	if checker, ok := exp.(destinationChecker); ok {
+		return checker.Check(ctx)
+	}
+	return nil
+}
+
+// checkWritable checks that a file can be created at path, without creating missing directories.
+func checkWritable(path string) error {
+	dir := filepath.Dir(path)
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	f, err := os.CreateTemp(dir, ".drydock-check-*")
+	if err != nil {
+		return fmt.Errorf("not writable: %w", err)
+	}
+	_ = f.Close()
+	return os.Remove(f.Name())
+}
+
+// checkRegistry checks that the credentials can list the images of the location.
+func checkRegistry(ctx context.Context, cfg *Config, stderr io.Writer) error {
+	scanner, err := newScanner(ctx, cfg, stderr, io.Discard)
+	if err != nil {
+		return err
+	}
+	defer closeScanner(scanner)
+
+	for _, err := range scanner.ListImages(ctx) {
+		// The first image (or error) is enough to know the API answers
+		return err
+	}
+	log.Debug().Msg("No images found in the location")
+	return nil
+}
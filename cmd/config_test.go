@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+func TestParseCommandFlags_ConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig := func(content string) string {
+		path := filepath.Join(dir, strings.ReplaceAll(t.Name(), "/", "_")+".json")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := map[string]struct {
+		config  string
+		args    []string
+		want    *Config
+		wantErr string
+	}{
+		"should read flag values from the file": {
+			config: `{"location": "us-central1", "min-severity": "LOW", "fixable": true, "concurrency": 10, "only-cve": ["CVE-1", "CVE-2"]}`,
+			want: &Config{
+				Location: "us-central1", MinSeverity: schemas.SeverityLow, FixableOnly: true, Concurrency: 10,
+				OnlyCVEs: []string{"CVE-1", "CVE-2"},
+			},
+		},
+		"should prefer flags given on the command line": {
+			config: `{"location": "us-central1", "min-severity": "LOW", "concurrency": 10}`,
+			args:   []string{"-s", "CRITICAL"},
+			want:   &Config{Location: "us-central1", MinSeverity: schemas.SeverityCritical, Concurrency: 10},
+		},
+		"should ignore keys of other commands": {
+			config: `{"location": "us-central1", "addr": ":9090"}`,
+			want:   &Config{Location: "us-central1", MinSeverity: schemas.SeverityHigh, Concurrency: 5},
+		},
+		"should report every invalid key": {
+			config:  `{"location": "us-central1", "min-severity": "SEVERE", "locaton": "x"}`,
+			wantErr: `key "min-severity"`,
+		},
+		"should report unknown keys": {
+			config:  `{"location": "us-central1", "locaton": "x"}`,
+			wantErr: `unknown key "locaton"`,
+		},
+		"should reject nested configuration files": {
+			config:  `{"location": "us-central1", "config": "other.json"}`,
+			wantErr: `unknown key "config"`,
+		},
+		"should reject malformed files": {
+			config:  `{"location": `,
+			wantErr: "invalid configuration file",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := writeConfig(tt.config)
+			got, err := parseCommandFlags(commandScan, append([]string{"--config", path}, tt.args...), io.Discard)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseCommandFlags() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCommandFlags() error = %v", err)
+			}
+			gotSubset := &Config{
+				Location: got.Location, MinSeverity: got.MinSeverity, FixableOnly: got.FixableOnly,
+				Concurrency: got.Concurrency, OnlyCVEs: got.OnlyCVEs,
+			}
+			if diff := cmp.Diff(tt.want, gotSubset); diff != "" {
+				t.Errorf("parseCommandFlags() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseCommandFlags_ConfigEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "drydock.json")
+	if err := os.WriteFile(path, []byte(`{"location": "asia-northeast1"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(configEnv, path)
+
+	got, err := parseCommandFlags(commandScan, nil, io.Discard)
+	if err != nil {
+		t.Fatalf("parseCommandFlags() error = %v", err)
+	}
+	if got.Location != "asia-northeast1" {
+		t.Errorf("Location = %q, want %q", got.Location, "asia-northeast1")
+	}
+}
+
+func TestRun_ConfigValidate(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.json")
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(valid, []byte(`{"location": "us-central1", "output-format": "csv"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(invalid, []byte(`{"output-format": "xml"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args       []string
+		wantErr    bool
+		wantStdout string
+	}{
+		"should accept a valid configuration": {
+			args:       []string{"validate", "--config", valid},
+			wantStdout: "Configuration is valid.",
+		},
+		"should reject an invalid configuration": {
+			args:    []string{"validate", "--config", invalid},
+			wantErr: true,
+		},
+		"should require a location in the file or the flags": {
+			args:    []string{"validate", "--config", filepath.Join(dir, "missing.json")},
+			wantErr: true,
+		},
+		"should require a configuration": {
+			args:    []string{"validate"},
+			wantErr: true,
+		},
+		"should reject unknown subcommands": {
+			args:    []string{"show"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			err := run(context.Background(), append([]string{commandConfig}, tt.args...), &stdout, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Errorf("stdout = %q, want it to contain %q", stdout.String(), tt.wantStdout)
+			}
+		})
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := map[string]struct {
+		path    string
+		wantErr bool
+	}{
+		"should accept files in existing directories": {
+			path: filepath.Join(dir, "report.json"),
+		},
+		"should accept files in directories that will be created": {
+			path: filepath.Join(dir, "reports", "nightly", "report.json"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := checkWritable(tt.path); (err != nil) != tt.wantErr {
+				t.Errorf("checkWritable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "reports")); !os.IsNotExist(err) {
+				t.Errorf("checkWritable() created directories")
+			}
+		})
+	}
+}
+
+func TestCheckDestination(t *testing.T) {
+	tests := map[string]struct {
+		status  int
+		wantErr bool
+	}{
+		"should accept a destination answering": {status: http.StatusOK},
+		"should report rejected credentials":    {status: http.StatusUnauthorized, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var paths []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.Method+" "+r.URL.Path)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"result": []}`))
+			}))
+			defer srv.Close()
+
+			cfg := &Config{ServiceNowURL: srv.URL}
+			err := checkDestination(context.Background(), cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkDestination() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			// Nothing is written to the destination
+			if diff := cmp.Diff([]string{"GET /api/now/table/sn_vul_vulnerable_item"}, paths); diff != "" {
+				t.Errorf("requests mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// commandDiff is the subcommand that compares two JSON reports.
+const commandDiff = "diff"
+
+// DiffFormat is the output format of the diff command.
+type DiffFormat string
+
+const (
+	// DiffFormatTable prints one aligned line per change.
+	DiffFormatTable DiffFormat = "table"
+	// DiffFormatMarkdown prints one Markdown table per image.
+	DiffFormatMarkdown DiffFormat = "markdown"
+	// DiffFormatJSON prints the changes as JSON.
+	DiffFormatJSON DiffFormat = "json"
+)
+
+// String implements the flag.Value interface.
+func (f *DiffFormat) String() string {
+	return string(*f)
+}
+
+// Set implements the flag.Value interface.
+func (f *DiffFormat) Set(value string) error {
+	normalized := DiffFormat(strings.ToLower(strings.TrimSpace(value)))
+	switch normalized {
+	case DiffFormatTable, DiffFormatMarkdown, DiffFormatJSON:
+		*f = normalized
+		return nil
+	default:
+		return fmt.Errorf("invalid diff format: %s (allowed: table, markdown, json)", value)
+	}
+}
+
+// runDiff prints the findings added, removed and changed between two JSON reports.
+// It works offline: no API is called.
+func runDiff(_ context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("drydock "+commandDiff, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	format := DiffFormatTable
+	fs.Var(&format, "output-format", "Output format (table, markdown, json) (default: table)")
+	fs.Var(&format, "o", "Output format (alias for --output-format)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: drydock diff [flags] <before.json> <after.json>")
+		_, _ = fmt.Fprintln(stderr, "  Compare two JSON reports and print the added, removed and changed findings per image")
+		_, _ = fmt.Fprintln(stderr, "")
+		fs.PrintDefaults()
+	}
+
+	files, err := parseInterleaved(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(files) != 2 {
+		fs.Usage()
+		return errors.New("diff requires exactly two report files")
+	}
+
+	before, err := readReport(files[0])
+	if err != nil {
+		return err
+	}
+	after, err := readReport(files[1])
+	if err != nil {
+		return err
+	}
+
+	diffs := schemas.DiffResults(before, after)
+	switch format {
+	case DiffFormatJSON:
+		return writeDiffJSON(stdout, diffs)
+	case DiffFormatMarkdown:
+		return writeDiffMarkdown(stdout, diffs)
+	default:
+		return writeDiffTable(stdout, diffs)
+	}
+}
+
+// readReport reads a JSON report file.
+func readReport(path string) ([]schemas.AnalyzeResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open report: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	results, err := schemas.ReadResults(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report %s: %w", path, err)
+	}
+	return results, nil
+}
+
+// diffRow is one change of a diff, as printed in tables.
+type diffRow struct {
+	change  string
+	vuln    schemas.Vulnerability
+	details string
+}
+
+// diffRows flattens the changes of an image.
+func diffRows(d schemas.ImageDiff) []diffRow {
+	var rows []diffRow
+	for _, v := range d.Added {
+		rows = append(rows, diffRow{change: "added", vuln: v})
+	}
+	for _, v := range d.Removed {
+		rows = append(rows, diffRow{change: "removed", vuln: v})
+	}
+	for _, c := range d.Changed {
+		rows = append(rows, diffRow{change: "changed", vuln: c.After, details: changeDetails(c)})
+	}
+	return rows
+}
+
+// changeDetails describes the changed fields, e.g. "severity: HIGH -> CRITICAL".
+func changeDetails(c schemas.VulnerabilityChange) string {
+	var parts []string
+	for _, field := range c.Fields {
+		var before, after any
+		switch field {
+		case "severity":
+			before, after = c.Before.Severity, c.After.Severity
+		case "installedVersion":
+			before, after = c.Before.InstalledVersion, c.After.InstalledVersion
+		case "fixedVersion":
+			before, after = c.Before.FixedVersion, c.After.FixedVersion
+		case "fixState":
+			before, after = c.Before.FixState, c.After.FixState
+		case "cvssScore":
+			before, after = c.Before.CVSSScore, c.After.CVSSScore
+		default:
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s: %v -> %v", field, orDash(before), orDash(after)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// orDash prints empty values as "-".
+func orDash(v any) any {
+	if fmt.Sprint(v) == "" {
+		return "-"
+	}
+	return v
+}
+
+// writeDiffTable prints one aligned line per change.
+func writeDiffTable(w io.Writer, diffs []schemas.ImageDiff) error {
+	if len(diffs) == 0 {
+		_, err := fmt.Fprintln(w, "No changes.")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "IMAGE\tCHANGE\tID\tPACKAGE\tSEVERITY\tDETAILS")
+	for _, d := range diffs {
+		for _, r := range diffRows(d) {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				d.Image, r.change, r.vuln.ID, r.vuln.PackageName, r.vuln.Severity, r.details)
+		}
+	}
+	return tw.Flush()
+}
+
+// writeDiffMarkdown prints one section with a table per image.
+func writeDiffMarkdown(w io.Writer, diffs []schemas.ImageDiff) error {
+	var b strings.Builder
+	b.WriteString("# Vulnerability changes\n\n")
+	if len(diffs) == 0 {
+		b.WriteString("No changes.\n")
+	}
+	for _, d := range diffs {
+		fmt.Fprintf(&b, "## %s\n\n", d.Image)
+		fmt.Fprintf(&b, "%d added, %d removed, %d changed\n\n", len(d.Added), len(d.Removed), len(d.Changed))
+		b.WriteString("| Change | ID | Package | Severity | Details |\n")
+		b.WriteString("|--------|----|---------|----------|---------|\n")
+		for _, r := range diffRows(d) {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+				r.change, r.vuln.ID, r.vuln.PackageName, r.vuln.Severity, r.details)
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeDiffJSON prints the diffs as a JSON array.
+func writeDiffJSON(w io.Writer, diffs []schemas.ImageDiff) error {
+	if diffs == nil {
+		diffs = []schemas.ImageDiff{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(diffs)
+}
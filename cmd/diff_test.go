@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_Diff(t *testing.T) {
+	dir := t.TempDir()
+	before := filepath.Join(dir, "before.json")
+	after := filepath.Join(dir, "after.json")
+	writeFile := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(before, `[{"artifact":{"host":"us-docker.pkg.dev","projectID":"p","repositoryID":"r","imageName":"app"},
+		"vulnerabilities":[{"id":"CVE-1","packageName":"openssl","severity":"HIGH"}]}]`)
+	writeFile(after, `[{"artifact":{"host":"us-docker.pkg.dev","projectID":"p","repositoryID":"r","imageName":"app"},
+		"vulnerabilities":[{"id":"CVE-1","packageName":"openssl","severity":"CRITICAL"},{"id":"CVE-2","packageName":"zlib","severity":"LOW"}]}]`)
+
+	tests := map[string]struct {
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		"should print a table by default": {
+			args: []string{before, after},
+			want: []string{"IMAGE", "added    CVE-2", "changed  CVE-1", "severity: HIGH -> CRITICAL"},
+		},
+		"should print markdown": {
+			args: []string{before, after, "-o", "markdown"},
+			want: []string{"## us-docker.pkg.dev/p/r/app", "1 added, 0 removed, 1 changed", "| added | CVE-2 | zlib | LOW |  |"},
+		},
+		"should print JSON": {
+			args: []string{"--output-format", "json", before, after},
+			want: []string{`"image": "us-docker.pkg.dev/p/r/app"`, `"fields": [`},
+		},
+		"should report no changes": {
+			args: []string{before, before},
+			want: []string{"No changes."},
+		},
+		"should require two files": {
+			args:    []string{before},
+			wantErr: true,
+		},
+		"should fail on missing files": {
+			args:    []string{before, filepath.Join(dir, "missing.json")},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := run(context.Background(), append([]string{"diff"}, tt.args...), &out, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output does not contain %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/rs/zerolog/log"
+)
+
+// commandExplain is the subcommand that shows everything known about one vulnerability.
+const commandExplain = "explain"
+
+// ExplainFormat is the output format of the explain command.
+type ExplainFormat string
+
+const (
+	// ExplainFormatText prints the details followed by a table of the affected images.
+	ExplainFormatText ExplainFormat = "text"
+	// ExplainFormatJSON prints the explanation as JSON.
+	ExplainFormatJSON ExplainFormat = "json"
+)
+
+// String implements the flag.Value interface.
+func (f *ExplainFormat) String() string {
+	return string(*f)
+}
+
+// Set implements the flag.Value interface.
+func (f *ExplainFormat) Set(value string) error {
+	normalized := ExplainFormat(strings.ToLower(strings.TrimSpace(value)))
+	switch normalized {
+	case ExplainFormatText, ExplainFormatJSON:
+		*f = normalized
+		return nil
+	default:
+		return fmt.Errorf("invalid explain format: %s (allowed: text, json)", value)
+	}
+}
+
+// addExplainFlags registers the flags of the explain command.
+func addExplainFlags(fs *flag.FlagSet, cfg *Config) {
+	// --report (repeatable)
+	fs.Func("report", "Read findings from this JSON report instead of querying the APIs (repeatable)", func(s string) error {
+		cfg.Reports = append(cfg.Reports, s)
+		return nil
+	})
+
+	// --output-format / -o
+	fs.Var(&cfg.ExplainFormat, "output-format", "Output format (text, json) (default: text)")
+	fs.Var(&cfg.ExplainFormat, "o", "Output format (alias for --output-format)")
+}
+
+// explanation gathers what the findings say about one vulnerability.
+type explanation struct {
+	ID          string               `json:"id"`
+	Severity    schemas.Severity     `json:"severity"`
+	CVSSScore   float32              `json:"cvssScore"`
+	CVSSVersion string               `json:"cvssVersion,omitempty"`
+	CVSSVector  string               `json:"cvssVector,omitempty"`
+	CVSS        *schemas.CVSSDetails `json:"cvss,omitempty"`
+	Description string               `json:"description,omitempty"`
+	CWEs        []string             `json:"cwes,omitempty"`
+	References  []schemas.Reference  `json:"references,omitempty"`
+	Affected    []affectedPackage    `json:"affected"`
+}
+
+// affectedPackage is one package of one image affected by the vulnerability.
+type affectedPackage struct {
+	Image            schemas.ArtifactReference `json:"image"`
+	PackageName      string                    `json:"packageName"`
+	InstalledVersion string                    `json:"installedVersion"`
+	FixedVersion     string                    `json:"fixedVersion,omitempty"`
+	FixState         schemas.FixState          `json:"fixState,omitempty"`
+}
+
+// runExplain shows the images and packages affected by a vulnerability, with its scores, fixes and advisories.
+// Findings come from stored reports (--report) or from a live query of the location.
+func runExplain(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	cfg, err := parseCommandFlags(commandExplain, args, stderr)
+	if err != nil {
+		return err
+	}
+	setupGlobalLogger(stderr, cfg.LogLevel, cfg.LogFormat, cfg.Color)
+
+	var results []schemas.AnalyzeResult
+	if len(cfg.Reports) > 0 {
+		results, err = readReports(cfg.Reports)
+	} else {
+		results, err = queryVulnerability(ctx, cfg, stderr)
+	}
+	if err != nil {
+		return err
+	}
+
+	e := explain(results, cfg.CVE)
+	switch cfg.ExplainFormat {
+	case ExplainFormatJSON:
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(e)
+	default:
+		return writeExplainText(stdout, e)
+	}
+}
+
+// queryVulnerability scans the location for the vulnerability only, at every severity.
+func queryVulnerability(ctx context.Context, cfg *Config, stderr io.Writer) ([]schemas.AnalyzeResult, error) {
+	cfg.OnlyCVEs = []string{cfg.CVE}
+	cfg.OutputFormat = drydock.OutputFormatJSON
+
+	scanner, err := newScanner(ctx, cfg, stderr, io.Discard)
+	if err != nil {
+		return nil, err
+	}
+	defer closeScanner(scanner)
+
+	log.Info().Str("id", cfg.CVE).Msg("Looking for affected images...")
+	results, err := scanner.ScanResults(ctx, drydock.ScanOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("scan failed: %w", err)
+	}
+	return results, nil
+}
+
+// readReports reads and merges JSON report files.
+func readReports(paths []string) ([]schemas.AnalyzeResult, error) {
+	var results []schemas.AnalyzeResult
+	for _, path := range paths {
+		r, err := readReport(path)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r...)
+	}
+	return results, nil
+}
+
+// explain collects the findings of the vulnerability id across results.
+// Scores and advisories are taken from the most severe finding; references are merged.
+func explain(results []schemas.AnalyzeResult, id string) explanation {
+	e := explanation{ID: id, Severity: schemas.SeverityUnspecified, Affected: []affectedPackage{}}
+	var best *schemas.Vulnerability
+	seenRefs := make(map[string]bool)
+	for _, r := range results {
+		for _, v := range r.Vulnerabilities {
+			if !strings.EqualFold(v.ID, id) {
+				continue
+			}
+			e.Affected = append(e.Affected, affectedPackage{
+				Image:            r.Artifact,
+				PackageName:      v.PackageName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+				FixState:         v.FixState,
+			})
+			if best == nil || schemas.CompareSeverity(v.Severity, best.Severity) > 0 ||
+				(v.Severity == best.Severity && v.CVSSScore > best.CVSSScore) {
+				best = &v
+			}
+			for _, ref := range v.References {
+				if !seenRefs[ref.URL] {
+					seenRefs[ref.URL] = true
+					e.References = append(e.References, ref)
+				}
+			}
+			for _, cwe := range v.CWEs {
+				if !slices.Contains(e.CWEs, cwe) {
+					e.CWEs = append(e.CWEs, cwe)
+				}
+			}
+		}
+	}
+	if best != nil {
+		e.ID = best.ID
+		e.Severity = best.Severity
+		e.CVSSScore = best.CVSSScore
+		e.CVSSVersion = best.CVSSVersion
+		e.CVSSVector = best.CVSSVector
+		e.CVSS = best.CVSS
+		e.Description = best.Description
+	}
+	slices.SortFunc(e.Affected, func(a, b affectedPackage) int {
+		return strings.Compare(a.Image.String()+" "+a.PackageName, b.Image.String()+" "+b.PackageName)
+	})
+	return e
+}
+
+// writeExplainText prints the vulnerability details followed by the affected images.
+func writeExplainText(w io.Writer, e explanation) error {
+	if len(e.Affected) == 0 {
+		_, err := fmt.Fprintf(w, "%s: no affected images found.\n", e.ID)
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", e.ID)
+	fmt.Fprintf(&b, "Severity:  %s\n", e.Severity)
+	if e.CVSSScore > 0 {
+		fmt.Fprintf(&b, "CVSS:      %.1f", e.CVSSScore)
+		if e.CVSSVersion != "" {
+			fmt.Fprintf(&b, " (v%s)", e.CVSSVersion)
+		}
+		b.WriteString("\n")
+	}
+	if e.CVSSVector != "" {
+		fmt.Fprintf(&b, "Vector:    %s\n", e.CVSSVector)
+	}
+	if len(e.CWEs) > 0 {
+		fmt.Fprintf(&b, "CWEs:      %s\n", strings.Join(e.CWEs, ", "))
+	}
+	if e.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", e.Description)
+	}
+	if _, err := fmt.Fprintln(w, b.String()); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "IMAGE\tPACKAGE\tINSTALLED\tFIXED")
+	for _, a := range e.Affected {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", a.Image, a.PackageName, a.InstalledVersion, orDash(a.FixedVersion))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(e.References) > 0 {
+		var refs strings.Builder
+		refs.WriteString("\nReferences:\n")
+		for _, ref := range e.References {
+			if ref.Type != "" {
+				fmt.Fprintf(&refs, "  [%s] %s\n", ref.Type, ref.URL)
+			} else {
+				fmt.Fprintf(&refs, "  %s\n", ref.URL)
+			}
+		}
+		if _, err := io.WriteString(w, refs.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errExplainArgs is returned when explain is not given exactly one vulnerability ID.
+var errExplainArgs = errors.New("explain requires exactly one vulnerability ID, e.g. drydock explain CVE-2024-1234")
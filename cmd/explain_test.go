@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+func TestParseCommandFlags_Explain(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		wantCVE string
+		wantErr bool
+	}{
+		"should read the vulnerability ID": {
+			args:    []string{"CVE-2024-1234", "--report", "results.json"},
+			wantCVE: "CVE-2024-1234",
+		},
+		"should accept a live query with a location": {
+			args:    []string{"-l", "us-central1", "CVE-2024-1234"},
+			wantCVE: "CVE-2024-1234",
+		},
+		"should require a location or a report": {
+			args:    []string{"CVE-2024-1234"},
+			wantErr: true,
+		},
+		"should require exactly one ID": {
+			args:    []string{"--report", "results.json", "CVE-1", "CVE-2"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseCommandFlags(commandExplain, tt.args, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCommandFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.CVE != tt.wantCVE {
+				t.Errorf("CVE = %q, want %q", got.CVE, tt.wantCVE)
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	app := schemas.ArtifactReference{Host: "us-docker.pkg.dev", ProjectID: "p", RepositoryID: "r", ImageName: "app"}
+	web := schemas.ArtifactReference{Host: "us-docker.pkg.dev", ProjectID: "p", RepositoryID: "r", ImageName: "web"}
+	results := []schemas.AnalyzeResult{
+		{Artifact: web, Vulnerabilities: []schemas.Vulnerability{{
+			ID: "CVE-1", PackageName: "openssl", InstalledVersion: "1.0", Severity: schemas.SeverityMedium, CVSSScore: 5.0,
+			References: []schemas.Reference{{URL: "https://nvd.nist.gov/vuln/detail/CVE-1", Type: schemas.ReferenceTypeAdvisory}},
+		}}},
+		{Artifact: app, Vulnerabilities: []schemas.Vulnerability{
+			{
+				ID: "CVE-1", PackageName: "openssl", InstalledVersion: "1.0", FixedVersion: "1.1", Severity: schemas.SeverityHigh, CVSSScore: 7.5,
+				Description: "bad", CWEs: []string{"CWE-79"},
+				References: []schemas.Reference{{URL: "https://nvd.nist.gov/vuln/detail/CVE-1", Type: schemas.ReferenceTypeAdvisory}},
+			},
+			{ID: "CVE-2", PackageName: "zlib"},
+		}},
+	}
+
+	tests := map[string]struct {
+		id   string
+		want explanation
+	}{
+		"should collect the affected images and keep the most severe details": {
+			id: "cve-1",
+			want: explanation{
+				ID: "CVE-1", Severity: schemas.SeverityHigh, CVSSScore: 7.5, Description: "bad", CWEs: []string{"CWE-79"},
+				References: []schemas.Reference{{URL: "https://nvd.nist.gov/vuln/detail/CVE-1", Type: schemas.ReferenceTypeAdvisory}},
+				Affected: []affectedPackage{
+					{Image: app, PackageName: "openssl", InstalledVersion: "1.0", FixedVersion: "1.1"},
+					{Image: web, PackageName: "openssl", InstalledVersion: "1.0"},
+				},
+			},
+		},
+		"should return no affected images for unknown IDs": {
+			id:   "CVE-9",
+			want: explanation{ID: "CVE-9", Severity: schemas.SeverityUnspecified, Affected: []affectedPackage{}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := explain(results, tt.id)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("explain() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRun_Explain(t *testing.T) {
+	report := filepath.Join(t.TempDir(), "results.json")
+	content := `[{"artifact":{"host":"us-docker.pkg.dev","projectID":"p","repositoryID":"r","imageName":"app"},
+		"vulnerabilities":[{"id":"CVE-1","packageName":"openssl","installedVersion":"1.0","fixedVersion":"1.1","severity":"HIGH","cvssScore":7.5,
+		"references":[{"url":"https://example.com/CVE-1","type":"ADVISORY"}]}]}]`
+	if err := os.WriteFile(report, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args []string
+		want []string
+	}{
+		"should print text": {
+			args: []string{"CVE-1", "--report", report},
+			want: []string{"Severity:  HIGH", "CVSS:      7.5", "us-docker.pkg.dev/p/r/app  openssl  1.0        1.1", "[ADVISORY] https://example.com/CVE-1"},
+		},
+		"should print JSON": {
+			args: []string{"CVE-1", "--report", report, "-o", "json"},
+			want: []string{`"fixedVersion": "1.1"`, `"severity": "HIGH"`},
+		},
+		"should say when nothing is affected": {
+			args: []string{"CVE-2", "--report", report},
+			want: []string{"CVE-2: no affected images found."},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := run(context.Background(), append([]string{commandExplain}, tt.args...), &out, io.Discard); err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output does not contain %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
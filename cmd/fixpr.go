@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hiro-o918/drydock/fixpr"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// commandFixPR is the subcommand that opens pull requests fixing the findings of JSON reports.
+const commandFixPR = "fix-pr"
+
+// githubTokenEnv names the environment variable holding the GitHub token.
+const githubTokenEnv = "GITHUB_TOKEN"
+
+// runFixPR opens a GitHub pull request per mapped image with fixable findings,
+// bumping the base image and package pins of its Dockerfile.
+func runFixPR(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("drydock "+commandFixPR, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	minSeverity := schemas.SeverityCritical
+	var mappingFile string
+	var dryRun bool
+	github := &fixpr.GitHub{Token: os.Getenv(githubTokenEnv)}
+	fs.StringVar(&mappingFile, "mapping", "", `JSON file mapping images to their source, e.g. {"us-docker.pkg.dev/p/r/api": {"repository": "org/api", "dockerfile": "Dockerfile"}} (required)`)
+	fs.Var(&minSeverity, "min-severity", "Open pull requests for images with a fixable finding of at least this severity (default: CRITICAL)")
+	fs.Var(&minSeverity, "s", "Severity (alias for --min-severity)")
+	fs.BoolVar(&dryRun, "dry-run", false, "Print the pull requests that would be opened without calling GitHub")
+	fs.StringVar(&github.BaseURL, "github-url", fixpr.DefaultGitHubURL, "GitHub API endpoint (for GitHub Enterprise)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: drydock fix-pr --mapping mapping.json [flags] <report.json> [...]")
+		_, _ = fmt.Fprintln(stderr, "  Open GitHub pull requests bumping base images and package pins for fixable findings ($"+githubTokenEnv+")")
+		_, _ = fmt.Fprintln(stderr, "")
+		fs.PrintDefaults()
+	}
+
+	files, err := parseInterleaved(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 || mappingFile == "" {
+		fs.Usage()
+		return errors.New("fix-pr requires --mapping and at least one report file")
+	}
+	if github.Token == "" && !dryRun {
+		return fmt.Errorf("fix-pr requires a GitHub token in $%s", githubTokenEnv)
+	}
+
+	f, err := os.Open(mappingFile)
+	if err != nil {
+		return fmt.Errorf("failed to open mapping: %w", err)
+	}
+	mapping, err := fixpr.ReadMapping(f)
+	_ = f.Close()
+	if err != nil {
+		return err
+	}
+	results, err := readReports(files)
+	if err != nil {
+		return err
+	}
+
+	fixes := fixpr.Plan(results, mapping, minSeverity)
+	if len(fixes) == 0 {
+		_, err := fmt.Fprintln(stdout, "No fixes to propose.")
+		return err
+	}
+
+	var errs []error
+	for _, fix := range fixes {
+		if dryRun {
+			_, _ = fmt.Fprintf(stdout, "%-13s %s  %s:%s (%s)\n", "would open", fix.Image(), fix.Source.Repository, fix.Source.Dockerfile, fix.Branch())
+			continue
+		}
+		pr, err := github.Open(ctx, fix, minSeverity)
+		switch {
+		case errors.Is(err, fixpr.ErrNothingToChange):
+			_, _ = fmt.Fprintf(stdout, "%-13s %s  %s: %v\n", "skipped", fix.Image(), fix.Source.Repository, err)
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", fix.Image(), err))
+			_, _ = fmt.Fprintf(stdout, "%-13s %s  %s: %v\n", "failed", fix.Image(), fix.Source.Repository, err)
+		case pr.Existing:
+			_, _ = fmt.Fprintf(stdout, "%-13s %s  %s\n", "already open", fix.Image(), pr.URL)
+		default:
+			_, _ = fmt.Fprintf(stdout, "%-13s %s  %s\n", "opened", fix.Image(), pr.URL)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to open some pull requests: %w", errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_FixPR(t *testing.T) {
+	dir := t.TempDir()
+	report := filepath.Join(dir, "results.json")
+	mapping := filepath.Join(dir, "mapping.json")
+	writeFile := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(report, `[{"artifact":{"host":"us-docker.pkg.dev","projectID":"p","repositoryID":"r","imageName":"api","digest":"sha256:0123456789abcdef"},
+		"vulnerabilities":[{"id":"CVE-1","packageName":"openssl","severity":"CRITICAL","fixState":"FIX_AVAILABLE","fixedVersion":"1.1.1t"}],
+		"remediations":[{"packageManager":"apt","packageName":"openssl","installedVersion":"1.1.1n","fixedVersion":"1.1.1t","command":"apt-get install --only-upgrade openssl=1.1.1t","vulnerabilityIDs":["CVE-1"]}]}]`)
+	writeFile(mapping, `{"us-docker.pkg.dev/p/r/api": {"repository": "org/api", "dockerfile": "build/Dockerfile"}}`)
+	otherMapping := filepath.Join(dir, "other.json")
+	writeFile(otherMapping, `{"us-docker.pkg.dev/p/r/web": {"repository": "org/web"}}`)
+
+	tests := map[string]struct {
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		"should list the pull requests of a dry run": {
+			args: []string{"--mapping", mapping, "--dry-run", report},
+			want: "would open    us-docker.pkg.dev/p/r/api  org/api:build/Dockerfile (drydock/fix-api-0123456789ab)",
+		},
+		"should report when there is nothing to fix": {
+			args: []string{"--mapping", otherMapping, "--dry-run", report},
+			want: "No fixes to propose.",
+		},
+		"should require a mapping": {
+			args:    []string{"--dry-run", report},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := run(context.Background(), append([]string{commandFixPR}, tt.args...), &out, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("output does not contain %q:\n%s", tt.want, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/rs/zerolog/log"
+)
+
+// commandList is the subcommand that lists the images of a location without reading vulnerabilities.
+const commandList = "list"
+
+// ListFormat is the output format of the list command.
+type ListFormat string
+
+const (
+	// ListFormatTable prints an aligned table.
+	ListFormatTable ListFormat = "table"
+	// ListFormatJSON prints a JSON array.
+	ListFormatJSON ListFormat = "json"
+	// ListFormatCSV prints comma-separated values with a header row.
+	ListFormatCSV ListFormat = "csv"
+)
+
+// String implements the flag.Value interface.
+func (f *ListFormat) String() string {
+	return string(*f)
+}
+
+// Set implements the flag.Value interface.
+func (f *ListFormat) Set(value string) error {
+	normalized := ListFormat(strings.ToLower(strings.TrimSpace(value)))
+	switch normalized {
+	case ListFormatTable, ListFormatJSON, ListFormatCSV:
+		*f = normalized
+		return nil
+	default:
+		return fmt.Errorf("invalid list format: %s (allowed: table, json, csv)", value)
+	}
+}
+
+// addListFlags registers the flags of the list command.
+func addListFlags(fs *flag.FlagSet, cfg *Config) {
+	// --repository / --image
+	fs.StringVar(&cfg.RepositoryFilter, "repository", "", "Only list repositories matching this glob pattern, e.g. 'prod-*'")
+	fs.StringVar(&cfg.ImageFilter, "image", "", "Only list images matching this glob pattern, e.g. 'api/*'")
+
+	// --tagged
+	fs.BoolVar(&cfg.TaggedOnly, "tagged", false, "Only list digests that have at least one tag")
+
+	// --output-format / -o
+	fs.Var(&cfg.ListFormat, "output-format", "Output format (table, json, csv) (default: table)")
+	fs.Var(&cfg.ListFormat, "o", "Output format (alias for --output-format)")
+}
+
+// listEntry is one image digest in the list output.
+type listEntry struct {
+	Location string                    `json:"location"`
+	Artifact schemas.ArtifactReference `json:"artifact"`
+	Image    *schemas.ImageMetadata    `json:"image,omitempty"`
+}
+
+// runList lists repositories, images, tags and digests using Artifact Registry only.
+func runList(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	cfg, scanner, err := setupScanner(ctx, commandList, args, stdout, stderr)
+	if err != nil {
+		return err
+	}
+	defer closeScanner(scanner)
+
+	log.Info().Msg("Listing images...")
+
+	var entries []listEntry
+	var errs error
+	for target, err := range scanner.ListImages(ctx) {
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		if !matchesListFilters(cfg, target) {
+			continue
+		}
+		entries = append(entries, listEntry{Location: target.Location, Artifact: target.Artifact, Image: target.Image})
+	}
+
+	var werr error
+	switch cfg.ListFormat {
+	case ListFormatJSON:
+		werr = writeListJSON(stdout, entries)
+	case ListFormatCSV:
+		werr = writeListCSV(stdout, entries)
+	default:
+		werr = writeListTable(stdout, entries)
+	}
+	if werr != nil {
+		return fmt.Errorf("failed to write image list: %w", werr)
+	}
+	if errs != nil {
+		return fmt.Errorf("list completed with partial errors:\n%w", errs)
+	}
+	return nil
+}
+
+// matchesListFilters reports whether the image passes the --repository, --image and --tagged filters.
+func matchesListFilters(cfg *Config, target drydock.ImageTarget) bool {
+	if cfg.RepositoryFilter != "" {
+		if ok, _ := path.Match(cfg.RepositoryFilter, target.Artifact.RepositoryID); !ok {
+			return false
+		}
+	}
+	if cfg.ImageFilter != "" {
+		if ok, _ := path.Match(cfg.ImageFilter, target.Artifact.ImageName); !ok {
+			return false
+		}
+	}
+	if cfg.TaggedOnly && (target.Image == nil || len(target.Image.Tags) == 0) {
+		return false
+	}
+	return true
+}
+
+// listRow returns the printable columns of an entry.
+func listRow(e listEntry) []string {
+	digest := ""
+	if e.Artifact.Digest != nil {
+		digest = *e.Artifact.Digest
+	}
+	var tags, updated, size string
+	if e.Image != nil {
+		tags = strings.Join(e.Image.Tags, ",")
+		if !e.Image.UpdateTime.IsZero() {
+			updated = e.Image.UpdateTime.UTC().Format(time.RFC3339)
+		}
+		size = strconv.FormatInt(e.Image.SizeBytes, 10)
+	}
+	return []string{e.Artifact.RepositoryID, e.Artifact.ImageName, digest, tags, updated, size}
+}
+
+var listHeader = []string{"REPOSITORY", "IMAGE", "DIGEST", "TAGS", "UPDATED", "SIZE"}
+
+// writeListTable prints an aligned table.
+func writeListTable(w io.Writer, entries []listEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, strings.Join(listHeader, "\t"))
+	for _, e := range entries {
+		_, _ = fmt.Fprintln(tw, strings.Join(listRow(e), "\t"))
+	}
+	return tw.Flush()
+}
+
+// writeListCSV prints the entries as CSV with a header row.
+func writeListCSV(w io.Writer, entries []listEntry) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, len(listHeader))
+	for i, h := range listHeader {
+		header[i] = strings.ToLower(h)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := cw.Write(listRow(e)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeListJSON prints the entries as a JSON array.
+func writeListJSON(w io.Writer, entries []listEntry) error {
+	if entries == nil {
+		entries = []listEntry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
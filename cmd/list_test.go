@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestParseCommandFlags_List(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		want    *Config
+		wantErr bool
+	}{
+		"should default to a table": {
+			args: []string{"-l", "us-central1"},
+			want: &Config{ListFormat: ListFormatTable},
+		},
+		"should parse filters and format": {
+			args: []string{"-l", "us-central1", "--repository", "prod-*", "--image", "api/*", "--tagged", "-o", "csv"},
+			want: &Config{RepositoryFilter: "prod-*", ImageFilter: "api/*", TaggedOnly: true, ListFormat: ListFormatCSV},
+		},
+		"should reject unknown formats": {
+			args:    []string{"-l", "us-central1", "-o", "yaml"},
+			wantErr: true,
+		},
+		"should reject finding flags": {
+			args:    []string{"-l", "us-central1", "--fixable"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseCommandFlags(commandList, tt.args, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCommandFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			gotSubset := &Config{RepositoryFilter: got.RepositoryFilter, ImageFilter: got.ImageFilter, TaggedOnly: got.TaggedOnly, ListFormat: got.ListFormat}
+			if diff := cmp.Diff(tt.want, gotSubset); diff != "" {
+				t.Errorf("parseCommandFlags() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMatchesListFilters(t *testing.T) {
+	target := drydock.ImageTarget{
+		Artifact: schemas.ArtifactReference{RepositoryID: "prod-images", ImageName: "api/server"},
+		Image:    &schemas.ImageMetadata{Tags: []string{"v1"}},
+	}
+	untagged := drydock.ImageTarget{
+		Artifact: schemas.ArtifactReference{RepositoryID: "prod-images", ImageName: "api/server"},
+		Image:    &schemas.ImageMetadata{},
+	}
+
+	tests := map[string]struct {
+		cfg    *Config
+		target drydock.ImageTarget
+		want   bool
+	}{
+		"should match without filters": {
+			cfg: &Config{}, target: target, want: true,
+		},
+		"should match repository and image globs": {
+			cfg: &Config{RepositoryFilter: "prod-*", ImageFilter: "api/*"}, target: target, want: true,
+		},
+		"should exclude other repositories": {
+			cfg: &Config{RepositoryFilter: "dev-*"}, target: target, want: false,
+		},
+		"should exclude other images": {
+			cfg: &Config{ImageFilter: "web"}, target: target, want: false,
+		},
+		"should exclude untagged digests when tagged only": {
+			cfg: &Config{TaggedOnly: true}, target: untagged, want: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := matchesListFilters(tt.cfg, tt.target); got != tt.want {
+				t.Errorf("matchesListFilters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteList(t *testing.T) {
+	entries := []listEntry{{
+		Location: "us-central1",
+		Artifact: schemas.ArtifactReference{
+			Host: "us-central1-docker.pkg.dev", ProjectID: "p", RepositoryID: "repo", ImageName: "app",
+			Digest: utils.Ptr("sha256:abc"),
+		},
+		Image: &schemas.ImageMetadata{
+			Tags:       []string{"latest", "v1"},
+			UpdateTime: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			SizeBytes:  1024,
+		},
+	}}
+
+	tests := map[string]struct {
+		write func(io.Writer, []listEntry) error
+		want  []string
+	}{
+		"should print a table": {
+			write: writeListTable,
+			want:  []string{"REPOSITORY", "repo        app    sha256:abc  latest,v1  2024-01-02T03:04:05Z  1024"},
+		},
+		"should print CSV": {
+			write: writeListCSV,
+			want:  []string{"repository,image,digest,tags,updated,size\n", `repo,app,sha256:abc,"latest,v1",2024-01-02T03:04:05Z,1024`},
+		},
+		"should print JSON": {
+			write: writeListJSON,
+			want:  []string{`"location": "us-central1"`, `"digest": "sha256:abc"`, `"sizeBytes": 1024`},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := tt.write(&out, entries); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output does not contain %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
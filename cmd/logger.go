@@ -1,26 +1,65 @@
 package main
 
 import (
+	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
+// LogFormat represents the output format of log messages.
+type LogFormat string
+
+const (
+	// LogFormatConsole writes human-friendly, colorized log lines.
+	LogFormatConsole LogFormat = "console"
+	// LogFormatJSON writes one JSON object per line, suitable for log sinks such as Cloud Logging.
+	LogFormatJSON LogFormat = "json"
+)
+
+// String implements the flag.Value interface.
+func (f *LogFormat) String() string {
+	return string(*f)
+}
+
+// Set implements the flag.Value interface.
+func (f *LogFormat) Set(value string) error {
+	normalized := LogFormat(strings.ToLower(strings.TrimSpace(value)))
+	switch normalized {
+	case LogFormatConsole, LogFormatJSON:
+		*f = normalized
+		return nil
+	default:
+		return fmt.Errorf("invalid log format: %s (allowed: console, json)", value)
+	}
+}
+
+// parseLogLevel converts a level name (e.g., "debug", "warn") into a zerolog.Level.
+func parseLogLevel(s string) (zerolog.Level, error) {
+	level, err := zerolog.ParseLevel(strings.ToLower(strings.TrimSpace(s)))
+	if err != nil || level == zerolog.NoLevel {
+		return zerolog.NoLevel, fmt.Errorf("invalid log level: %s (allowed: trace, debug, info, warn, error, fatal, panic, disabled)", s)
+	}
+	return level, nil
+}
+
 // setupGlobalLogger configures the global zerolog logger for CLI usage.
-func setupGlobalLogger(w io.Writer, debug bool) {
+func setupGlobalLogger(w io.Writer, level zerolog.Level, format LogFormat, color ColorMode) {
 	// 1. Configure Log Level
-	level := zerolog.InfoLevel
-	if debug {
-		level = zerolog.DebugLevel
-	}
 	zerolog.SetGlobalLevel(level)
 
-	// 2. Configure Output Format (Human-friendly ConsoleWriter)
-	output := zerolog.ConsoleWriter{
-		Out:        w,
-		TimeFormat: time.Kitchen, // e.g., "3:04PM"
+	// 2. Configure Output Format
+	output := w
+	if format != LogFormatJSON {
+		// Human-friendly ConsoleWriter
+		output = zerolog.ConsoleWriter{
+			Out:        w,
+			TimeFormat: time.Kitchen, // e.g., "3:04PM"
+			NoColor:    !color.enabled(w),
+		}
 	}
 
 	// 3. Set Global Logger
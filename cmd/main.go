@@ -6,86 +6,562 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"golang.org/x/oauth2"
 	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
 )
 
 func main() {
 	ctx := context.Background()
 
-	// Trap Ctrl+C (SIGINT)
-	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	// Trap Ctrl+C (SIGINT) and SIGTERM so partial results can be flushed
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
 	// We use stderr for logging to keep stdout clean for data output.
 	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
-		log.Error().Err(err).Msg("Application execution failed")
+		event := log.Error().Err(err)
+		if hint := drydock.ErrorHint(err); hint != "" {
+			event = event.Str("hint", hint)
+		}
+		event.Msg("Application execution failed")
 		os.Exit(1)
 	}
 }
 
-// run orchestrates the application components.
+// run dispatches the arguments to a subcommand.
+// Flags without a subcommand (e.g. `drydock -l us-central1`) run the scan command.
 func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
 	// Preliminary Logger Setup (in case of early errors)
-	setupGlobalLogger(stderr, false)
+	setupGlobalLogger(stderr, zerolog.InfoLevel, LogFormatConsole, ColorAuto)
+
+	if len(args) == 0 {
+		printUsage(stderr)
+		return nil
+	}
+
+	name := args[0]
+	switch {
+	case name == "help" || name == "-h" || name == "-help" || name == "--help":
+		printUsage(stderr)
+		return nil
+	case len(name) > 0 && name[0] == '-':
+		name = commandScan
+	default:
+		args = args[1:]
+	}
+
+	c, ok := lookupCommand(name)
+	if !ok {
+		printUsage(stderr)
+		return fmt.Errorf("unknown command %q", name)
+	}
+
+	err := c.run(ctx, args, stdout, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
 
-	// 1. Parse Configuration
-	cfg, err := parseFlags(args, stderr)
+// runScan discovers the images of a location, analyzes them, and exports the results.
+func runScan(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	cfg, err := parseCommandFlags(commandScan, args, stderr)
+	if err != nil {
+		return err
+	}
+	setupGlobalLogger(stderr, cfg.LogLevel, cfg.LogFormat, cfg.Color)
+	stopProfiling, err := startProfiling(cfg)
 	if err != nil {
-		if errors.Is(err, flag.ErrHelp) {
-			return nil
-		}
 		return err
 	}
+	defer func() {
+		if err := stopProfiling(); err != nil {
+			log.Warn().Err(err).Msg("Failed to stop profiling")
+		}
+	}()
 
-	// 2. Setup Logger
-	setupGlobalLogger(stderr, cfg.Debug)
+	// Explicit images bypass discovery; they also tell the project and location to use
+	var images []schemas.ArtifactReference
+	for _, uri := range cfg.Images {
+		ref, err := schemas.ParseArtifactURI(uri)
+		if err != nil {
+			return err
+		}
+		if !ref.IsArtifactRegistry() {
+			return fmt.Errorf("%s: only Artifact Registry images can be scanned", uri)
+		}
+		images = append(images, ref)
+	}
+	if len(images) > 0 {
+		if cfg.ProjectID == "" {
+			cfg.ProjectID = images[0].ProjectID
+		}
+		if cfg.Location == "" {
+			cfg.Location = strings.TrimSuffix(images[0].Host, "-docker.pkg.dev")
+		}
+	}
 
-	log.Debug().Interface("config", cfg).Msg("Configuration loaded")
-	log.Info().Str("project", cfg.ProjectID).Str("location", cfg.Location).Msg("Initializing scanner...")
+	// The report goes to a file only once it is complete, so readers never see half of it
+	if cfg.OutputFile, err = expandOutputPath(cfg.OutputFile, cfg, time.Now()); err != nil {
+		return err
+	}
+	var out io.Writer = stdout
+	var file *atomicFile
+	if cfg.OutputFile != "" {
+		file, err = createAtomicFile(cfg.OutputFile)
+		if err != nil {
+			return err
+		}
+		defer file.Discard()
+		out = file
+	}
 
-	// 3. Setup Infrastructure (Clients)
-	clientOpts := []option.ClientOption{}
-	if cfg.ProjectID != "" {
-		clientOpts = append(clientOpts, option.WithQuotaProject(cfg.ProjectID))
+	outs := []io.Writer{out}
+	if cfg.Tee {
+		outs = append(outs, stdout)
+	}
+	scanner, err := newScanner(ctx, cfg, stderr, outs...)
+	if err != nil {
+		return err
+	}
+	defer closeScanner(scanner)
+
+	// A single artifact selected by flags, in the scanned project and location
+	if cfg.Image != "" {
+		images = append(images, schemas.ArtifactReference{
+			Host:         scanner.Location() + "-docker.pkg.dev",
+			ProjectID:    scanner.ProjectID(),
+			RepositoryID: cfg.Repository,
+			ImageName:    cfg.Image,
+			Tag:          utils.ToPtr(cfg.Tag),
+			Digest:       utils.ToPtr(cfg.Digest),
+		})
 	}
 
-	// 4. Execution Phase
 	log.Info().Msg("Starting vulnerability scan...")
+	var scanErr error
+	if len(images) > 0 {
+		scanErr = scanner.ScanImages(ctx, images, cfg.scanOptions())
+	} else {
+		scanErr = scanner.Scan(ctx, cfg.scanOptions())
+	}
+
+	// Partial results of a failed or interrupted scan are still worth keeping
+	if file != nil && (scanErr == nil || file.Written()) {
+		if err := file.Commit(); err != nil {
+			return errors.Join(scanErr, err)
+		}
+		log.Info().Str("path", cfg.OutputFile).Msg("Report written")
+	}
+	if scanErr != nil {
+		return fmt.Errorf("scan failed: %w", scanErr)
+	}
+
+	log.Info().Msg("Vulnerability scan completed successfully")
+	return nil
+}
+
+// setupScanner parses the flags of command, configures logging, and creates the scanner.
+func setupScanner(ctx context.Context, command string, args []string, stdout, stderr io.Writer) (*Config, *drydock.Scanner, error) {
+	cfg, err := parseCommandFlags(command, args, stderr)
+	if err != nil {
+		return nil, nil, err
+	}
+	setupGlobalLogger(stderr, cfg.LogLevel, cfg.LogFormat, cfg.Color)
+
+	scanner, err := newScanner(ctx, cfg, stderr, stdout)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, scanner, nil
+}
+
+// newScanner creates a scanner from the configuration, exporting results to out.
+// The progress stream, if enabled, goes to stderr.
+// The report is written to every writer of outs.
+func newScanner(ctx context.Context, cfg *Config, stderr io.Writer, outs ...io.Writer) (*drydock.Scanner, error) {
+	exp, err := newExporter(ctx, cfg, outs...)
+	if err != nil {
+		return nil, err
+	}
+	return newScannerWithExporter(ctx, cfg, stderr, exp)
+}
+
+// newScannerWithExporter creates a scanner from the configuration, exporting results to exp.
+func newScannerWithExporter(ctx context.Context, cfg *Config, stderr io.Writer, exp drydock.Exporter) (_ *drydock.Scanner, err error) {
+	logged := *cfg
+	logged.Proxy = redactURL(cfg.Proxy)
+	logged.Cache = redactURL(cfg.Cache)
+	log.Debug().Interface("config", logged).Msg("Configuration loaded")
+	log.Info().Str("project", cfg.ProjectID).Str("location", cfg.Location).Msg("Initializing scanner...")
 
 	// Create scanner options
 	var scannerOpts []drydock.ScannerOption
 	if cfg.ProjectID != "" {
 		scannerOpts = append(scannerOpts, drydock.WithProjectID(cfg.ProjectID))
 	}
+	if cfg.NoMetadata {
+		scannerOpts = append(scannerOpts, drydock.WithoutMetadataServer())
+	}
+	if cfg.RecordDir != "" {
+		scannerOpts = append(scannerOpts, drydock.WithRecording(cfg.RecordDir))
+	}
+	if cfg.ReplayDir != "" {
+		scannerOpts = append(scannerOpts, drydock.WithReplay(cfg.ReplayDir))
+	}
+	if cfg.QuotaProject != "" {
+		scannerOpts = append(scannerOpts, drydock.WithQuotaProject(cfg.QuotaProject))
+	}
+	scannerOpts = append(scannerOpts, drydock.WithLogger(&log.Logger))
+	if cfg.Proxy != "" {
+		scannerOpts = append(scannerOpts, drydock.WithProxy(cfg.Proxy))
+	}
+	var cacheOpts []option.ClientOption
+	if cfg.CredentialsFile != "" {
+		creds, err := drydock.LoadCredentialsFile(ctx, cfg.CredentialsFile)
+		if err != nil {
+			return nil, err
+		}
+		scannerOpts = append(scannerOpts, drydock.WithCredentials(creds))
+		cacheOpts = append(cacheOpts, option.WithCredentials(creds))
+	}
+	if cfg.Cache != "" {
+		cache, err := newCache(ctx, cfg.Cache, cacheOpts...)
+		if err != nil {
+			return nil, err
+		}
+		// The scanner owns the cache once built; until then, close it on failure
+		if closer, ok := cache.(io.Closer); ok {
+			defer func() {
+				if err != nil {
+					_ = closer.Close()
+				}
+			}()
+		}
+		scannerOpts = append(scannerOpts, drydock.WithCache(cache, cfg.CacheTTL))
+	}
 	scannerOpts = append(scannerOpts, drydock.WithConcurrency(cfg.Concurrency))
-	scannerOpts = append(scannerOpts, drydock.WithClientOptions(clientOpts...))
-	scannerOpts = append(scannerOpts, drydock.WithOutputFormat(cfg.OutputFormat, stdout))
+	scannerOpts = append(scannerOpts, drydock.WithDiscoveryConcurrency(cfg.DiscoveryConcurrency))
+	scannerOpts = append(scannerOpts, drydock.WithDigestsPerImage(cfg.DigestsPerImage))
+	if cfg.MaxCandidates > 0 {
+		scannerOpts = append(scannerOpts, drydock.WithMaxCandidates(cfg.MaxCandidates))
+	}
+	if cfg.ListPageSize > 0 {
+		scannerOpts = append(scannerOpts, drydock.WithListPageSize(cfg.ListPageSize))
+	}
+	retry := drydock.DefaultRetryPolicy()
+	retry.MaxAttempts = cfg.MaxAttempts
+	scannerOpts = append(scannerOpts, drydock.WithRetryPolicy(retry))
+	if cfg.Connection != (drydock.ConnectionPolicy{}) {
+		scannerOpts = append(scannerOpts, drydock.WithConnectionPolicy(cfg.Connection))
+	}
+	if len(cfg.OnlyCVEs) > 0 {
+		scannerOpts = append(scannerOpts, drydock.WithOnlyVulnerabilities(cfg.OnlyCVEs...))
+	}
+	if len(cfg.SkipCVEs) > 0 {
+		scannerOpts = append(scannerOpts, drydock.WithSkipVulnerabilities(cfg.SkipCVEs...))
+	}
+	if len(cfg.CloudRunRegions) > 0 {
+		scannerOpts = append(scannerOpts, drydock.WithCloudRunDiscovery(cfg.CloudRunRegions...))
+	}
+	if len(cfg.KubernetesPods) > 0 {
+		scannerOpts = append(scannerOpts, drydock.WithDeploymentSource(cfg.KubernetesPods...))
+	}
+	if cfg.DeployedOnly {
+		scannerOpts = append(scannerOpts, drydock.WithDeployedOnly())
+	}
+	if len(cfg.Suppressions) > 0 {
+		scannerOpts = append(scannerOpts, drydock.WithSuppressions(cfg.Suppressions...))
+	}
+	if cfg.IgnoreUnlikelyFixed {
+		scannerOpts = append(scannerOpts, drydock.WithIgnoreUnlikelyFixed())
+	}
+	if cfg.Filter != "" {
+		scannerOpts = append(scannerOpts, drydock.WithFilter(cfg.Filter))
+	}
+	if len(cfg.Priorities) > 0 {
+		scannerOpts = append(scannerOpts, drydock.WithRepositoryPriority(cfg.Priorities...))
+	}
+	if cfg.Adaptive {
+		scannerOpts = append(scannerOpts, drydock.WithAdaptiveConcurrency())
+	}
+	if cfg.Reproducible {
+		scannerOpts = append(scannerOpts, drydock.WithReproducibleOutput())
+	}
+	if cfg.BaseImageAdvice {
+		scannerOpts = append(scannerOpts, drydock.WithBaseImageAdvice())
+	}
+	for ref, graph := range cfg.SBOMs {
+		scannerOpts = append(scannerOpts, drydock.WithSBOM(ref, graph))
+	}
+	if cfg.SBOMDir != "" {
+		scannerOpts = append(scannerOpts, drydock.WithSBOMDownload(cfg.SBOMDir))
+	}
+	if cfg.SBOMOnly {
+		scannerOpts = append(scannerOpts, drydock.WithSBOMOnly())
+	}
+	if cfg.DebianTracker != "" {
+		log.Info().Str("source", cfg.DebianTracker).Msg("Loading the Debian security tracker...")
+		tracker, err := drydock.LoadDebianSecurityTracker(ctx, cfg.DebianTracker)
+		if err != nil {
+			return nil, err
+		}
+		scannerOpts = append(scannerOpts, drydock.WithDistroTracker(tracker))
+	}
+	if cfg.RefreshStale {
+		scannerOpts = append(scannerOpts, drydock.WithStaleRefresh())
+	}
+	if cfg.History != "" {
+		scannerOpts = append(scannerOpts, drydock.WithHistory(drydock.NewFileHistoryStore(cfg.History)))
+	}
+	if cfg.SLA != nil {
+		scannerOpts = append(scannerOpts, drydock.WithSLA(cfg.SLA))
+	}
+	if cfg.FailOnSLABreach {
+		scannerOpts = append(scannerOpts, drydock.WithFailOnSLABreach())
+	}
+	if cfg.FailOn != "" {
+		scannerOpts = append(scannerOpts, drydock.WithScanPolicy(drydock.ScanPolicy{FailOnSeverity: cfg.FailOn}))
+	}
+	scannerOpts = append(scannerOpts, drydock.WithExporter(exp))
+	scannerOpts = append(scannerOpts, drydock.WithExportBatchSize(cfg.BatchSize))
+	if cfg.Stream {
+		scannerOpts = append(scannerOpts, drydock.WithStreamingExport())
+	}
+	scannerOpts = append(scannerOpts, drydock.WithResultSpill(cfg.SpillThreshold, cfg.SpillDir))
+	if cfg.Progress == ProgressFormatJSON {
+		scannerOpts = append(scannerOpts, drydock.WithProgress(newJSONProgress(stderr)))
+	}
+	if cfg.Summary {
+		scannerOpts = append(scannerOpts, drydock.WithSummary(newSummaryPrinter(stderr)))
+	}
 
 	// Initialize scanner with location and options
 	scanner, err := drydock.NewScanner(ctx, cfg.Location, scannerOpts...)
 	if err != nil {
-		return fmt.Errorf("failed to initialize scanner: %w", err)
+		return nil, fmt.Errorf("failed to initialize scanner: %w", err)
 	}
-	defer func() {
-		if err := scanner.Close(); err != nil {
-			log.Warn().Err(err).Msg("Failed to close scanner resources")
+	return scanner, nil
+}
+
+// newExporter returns the exporter of the report: an integration, or the output format written to every writer of outs.
+// With a baseline, only the findings new since the baseline are exported.
+func newExporter(ctx context.Context, cfg *Config, outs ...io.Writer) (drydock.Exporter, error) {
+	exp, err := newDestination(ctx, cfg, outs...)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case cfg.NewSince != "":
+		exp = drydock.NewDeltaExporter(exp, drydock.ReportBaseline(cfg.NewSince))
+	case cfg.NewOnly:
+		exp = drydock.NewDeltaExporter(exp, drydock.HistoryBaseline(drydock.NewFileHistoryStore(cfg.History)))
+	}
+	return exp, nil
+}
+
+// newDestination returns the exporter sending the report to its destination: an integration,
+// or the output format written to every writer of outs.
+func newDestination(ctx context.Context, cfg *Config, outs ...io.Writer) (drydock.Exporter, error) {
+	var exp drydock.Exporter
+	switch {
+	case cfg.ConfluenceURL != "":
+		exp = &exporter.ConfluenceExporter{
+			BaseURL:  cfg.ConfluenceURL,
+			Space:    cfg.ConfluenceSpace,
+			ParentID: cfg.ConfluenceParent,
+			Title:    cfg.ConfluenceTitle,
+			User:     os.Getenv(confluenceUserEnv),
+			Token:    os.Getenv(confluenceTokenEnv),
 		}
-	}()
+	case cfg.ServiceNowURL != "":
+		exp = &exporter.ServiceNowExporter{
+			BaseURL:     cfg.ServiceNowURL,
+			User:        os.Getenv(serviceNowUserEnv),
+			Password:    os.Getenv(serviceNowPasswordEnv),
+			CITable:     cfg.ServiceNowCITable,
+			Concurrency: cfg.ExportConcurrency,
+		}
+	case cfg.S3Bucket != "":
+		exp = newS3Exporter(cfg)
+	case cfg.BigQueryTable != "":
+		project, dataset, table, err := parseBigQueryTable(cfg.BigQueryTable)
+		if err != nil {
+			return nil, err
+		}
+		client, err := newGoogleHTTPClient(ctx, cfg, exporter.BigQueryScope)
+		if err != nil {
+			return nil, err
+		}
+		exp = &exporter.BigQueryExporter{Project: project, Dataset: dataset, Table: table, HTTPClient: client}
+	case cfg.GCSBucket != "":
+		client, err := newGoogleHTTPClient(ctx, cfg, exporter.GCSScope)
+		if err != nil {
+			return nil, err
+		}
+		exp = &exporter.GCSExporter{
+			Bucket:     cfg.GCSBucket,
+			Name:       cfg.GCSObject,
+			Gzip:       cfg.GCSGzip,
+			Metadata:   gcsMetadata(cfg),
+			HTTPClient: client,
+		}
+	case cfg.BadgeDir != "":
+		exp = exporter.NewBadgeExporter(io.MultiWriter(outs...), cfg.BadgeDir)
+	case cfg.BackstageMapping != nil:
+		exp = exporter.NewBackstageExporter(io.MultiWriter(outs...), cfg.BackstageMapping)
+	case cfg.GitHubIssue != "":
+		repository := os.Getenv("GITHUB_REPOSITORY")
+		if repository == "" {
+			return nil, errors.New("flag `--github-issue` requires $GITHUB_REPOSITORY")
+		}
+		e := exporter.NewGitHubActionsExporter(io.MultiWriter(outs...))
+		e.Repository = repository
+		e.IssueTitle = cfg.GitHubIssue
+		e.Token = os.Getenv(githubTokenEnv)
+		e.BaseURL = os.Getenv("GITHUB_API_URL")
+		exp = e
+	case len(outs) > 1:
+		tee := make([]exporter.Exporter, len(outs))
+		for i, out := range outs {
+			e, err := drydock.NewExporter(cfg.OutputFormat, out)
+			if err != nil {
+				return nil, err
+			}
+			tee[i] = e
+		}
+		t := exporter.NewTee(tee...)
+		t.Concurrency = cfg.ExportConcurrency
+		exp = t
+	default:
+		var err error
+		if exp, err = drydock.NewExporter(cfg.OutputFormat, outs[0]); err != nil {
+			return nil, err
+		}
+	}
+	return exp, nil
+}
+
+// newGoogleHTTPClient returns an HTTP client authenticated like the scan, for exporters calling Google Cloud APIs.
+func newGoogleHTTPClient(ctx context.Context, cfg *Config, scope string) (*http.Client, error) {
+	// With a proxy, API and token requests both go through it: tokens are fetched
+	// with the HTTP client of the context the credentials are created with
+	var base *http.Transport
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		base = http.DefaultTransport.(*http.Transport).Clone()
+		base.Proxy = http.ProxyURL(proxyURL)
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: base})
+	}
 
-	minSeverity, err := parseSeverity(cfg.MinSeverity)
+	opts := []option.ClientOption{option.WithScopes(scope)}
+	if cfg.CredentialsFile != "" {
+		creds, err := drydock.LoadCredentialsFile(ctx, cfg.CredentialsFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, option.WithCredentials(creds))
+	}
+	if cfg.QuotaProject != "" {
+		opts = append(opts, option.WithQuotaProject(cfg.QuotaProject))
+	}
+	if base == nil {
+		client, _, err := htransport.NewClient(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Google Cloud HTTP client: %w", err)
+		}
+		return client, nil
+	}
+	transport, err := htransport.NewTransport(ctx, base, opts...)
 	if err != nil {
-		return fmt.Errorf("invalid minimum severity: %w", err)
+		return nil, fmt.Errorf("failed to create Google Cloud HTTP client: %w", err)
 	}
+	return &http.Client{Transport: transport}, nil
+}
 
-	if err := scanner.Scan(ctx, minSeverity, cfg.FixableOnly); err != nil {
-		return fmt.Errorf("scan failed: %w", err)
+// gcsMetadata returns the scan parameters recorded on the objects uploaded to Cloud Storage.
+func gcsMetadata(cfg *Config) map[string]string {
+	return map[string]string{
+		"location":        cfg.Location,
+		"min-severity":    string(cfg.MinSeverity),
+		"fixable-only":    strconv.FormatBool(cfg.FixableOnly),
+		"drydock-version": buildVersion(),
 	}
+}
 
-	log.Info().Msg("Vulnerability scan completed successfully")
-	return nil
+// newS3Exporter returns an exporter uploading the report, rendered in the output format, to the S3 bucket.
+func newS3Exporter(cfg *Config) *exporter.S3Exporter {
+	key := cfg.S3Key
+	if key == "" {
+		key = strings.TrimSuffix(exporter.DefaultS3Key, ".json") + "." + string(cfg.OutputFormat)
+	}
+	format := cfg.OutputFormat
+	return &exporter.S3Exporter{
+		Endpoint:        cfg.S3Endpoint,
+		Region:          cfg.S3Region,
+		Bucket:          cfg.S3Bucket,
+		Key:             key,
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		Render: func(ctx context.Context, w io.Writer, results []schemas.AnalyzeResult) error {
+			e, err := drydock.NewExporter(format, w)
+			if err != nil {
+				return err
+			}
+			return e.Export(ctx, results)
+		},
+	}
+}
+
+// newCache returns the analysis cache at rawURL: a Redis server (redis:// or rediss://),
+// a Cloud Storage bucket and prefix (gs://bucket/prefix), or else a local directory.
+func newCache(ctx context.Context, rawURL string, opts ...option.ClientOption) (drydock.Cache, error) {
+	switch {
+	case strings.HasPrefix(rawURL, "redis://"), strings.HasPrefix(rawURL, "rediss://"):
+		return drydock.NewRedisCache(rawURL)
+	case strings.HasPrefix(rawURL, "gs://"):
+		bucket, prefix, _ := strings.Cut(strings.TrimPrefix(rawURL, "gs://"), "/")
+		if prefix != "" && !strings.HasSuffix(prefix, "/") {
+			prefix += "/"
+		}
+		return drydock.NewGCSCache(ctx, bucket, prefix, opts...)
+	default:
+		return drydock.NewFileCache(strings.TrimPrefix(rawURL, "file://")), nil
+	}
+}
+
+// redactURL returns rawURL with the password of its userinfo masked, for logging.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
+	}
+	return u.Redacted()
+}
+
+// closeScanner releases the scanner resources, logging failures.
+func closeScanner(scanner *drydock.Scanner) {
+	if err := scanner.Close(); err != nil {
+		log.Warn().Err(err).Msg("Failed to close scanner resources")
+	}
 }
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// outputPathData are the fields of an output file template, e.g. "reports/{{.Project}}-{{.Date}}.json".
+type outputPathData struct {
+	Date     string // e.g., 2024-01-02, in UTC
+	Time     string // e.g., 150405, in UTC
+	Project  string // as given by flags, empty if detected
+	Location string
+	Format   string // output format, e.g. json
+}
+
+// expandOutputPath fills in the template of an output file path for a scan started at now.
+// Paths without actions are returned as is.
+func expandOutputPath(path string, cfg *Config, now time.Time) (string, error) {
+	if !strings.Contains(path, "{{") {
+		return path, nil
+	}
+	tmpl, err := template.New("output-file").Parse(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid output file template: %w", err)
+	}
+	now = now.UTC()
+	var b strings.Builder
+	if err := tmpl.Execute(&b, outputPathData{
+		Date:     now.Format(time.DateOnly),
+		Time:     now.Format("150405"),
+		Project:  cfg.ProjectID,
+		Location: cfg.Location,
+		Format:   string(cfg.OutputFormat),
+	}); err != nil {
+		return "", fmt.Errorf("invalid output file template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// atomicFile is a report file that only appears at its path once complete.
+// Writes go to a temporary file in the same directory, which Commit renames into place.
+type atomicFile struct {
+	path    string
+	tmp     *os.File
+	written int64
+}
+
+// createAtomicFile creates the parent directories of path and a temporary file next to it.
+func createAtomicFile(path string) (*atomicFile, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	return &atomicFile{path: path, tmp: tmp}, nil
+}
+
+// Write implements io.Writer.
+func (f *atomicFile) Write(p []byte) (int, error) {
+	n, err := f.tmp.Write(p)
+	f.written += int64(n)
+	return n, err
+}
+
+// Written reports whether anything has been written.
+func (f *atomicFile) Written() bool {
+	return f.written > 0
+}
+
+// Commit flushes the temporary file and moves it to the final path.
+func (f *atomicFile) Commit() error {
+	if err := f.tmp.Sync(); err != nil {
+		f.Discard()
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := f.tmp.Close(); err != nil {
+		_ = os.Remove(f.tmp.Name())
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	// CreateTemp uses 0600; reports are regular files
+	if err := os.Chmod(f.tmp.Name(), 0o644); err != nil {
+		_ = os.Remove(f.tmp.Name())
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := os.Rename(f.tmp.Name(), f.path); err != nil {
+		_ = os.Remove(f.tmp.Name())
+		return fmt.Errorf("failed to move output file into place: %w", err)
+	}
+	return nil
+}
+
+// Discard removes the temporary file, leaving any existing file at the path untouched.
+func (f *atomicFile) Discard() {
+	_ = f.tmp.Close()
+	_ = os.Remove(f.tmp.Name())
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAtomicFile(t *testing.T) {
+	tests := map[string]struct {
+		commit   bool
+		existing string
+		want     string
+	}{
+		"should create the file with parent directories on commit": {
+			commit: true,
+			want:   "report",
+		},
+		"should replace an existing file on commit": {
+			commit:   true,
+			existing: "old",
+			want:     "report",
+		},
+		"should keep an existing file when discarded": {
+			commit:   false,
+			existing: "old",
+			want:     "old",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "nested", "out", "report.json")
+			if tt.existing != "" {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.existing), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			f, err := createAtomicFile(path)
+			if err != nil {
+				t.Fatalf("createAtomicFile() error = %v", err)
+			}
+			if _, err := f.Write([]byte("report")); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if _, err := os.Stat(path); tt.existing == "" && !os.IsNotExist(err) {
+				t.Errorf("file exists before commit: %v", err)
+			}
+			if tt.commit {
+				if err := f.Commit(); err != nil {
+					t.Fatalf("Commit() error = %v", err)
+				}
+			}
+			f.Discard()
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("file content = %q, want %q", got, tt.want)
+			}
+			entries, err := os.ReadDir(filepath.Dir(path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Errorf("temporary files left behind: %v", entries)
+			}
+		})
+	}
+}
+
+func TestExpandOutputPath(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("JST", 9*60*60))
+	cfg := &Config{ProjectID: "my-project", Location: "us-central1", OutputFormat: "csv"}
+
+	tests := map[string]struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		"should keep paths without template actions": {
+			path: "report.json",
+			want: "report.json",
+		},
+		"should fill in the date, time and scan settings in UTC": {
+			path: "reports/{{.Project}}/{{.Location}}-{{.Date}}T{{.Time}}.{{.Format}}",
+			want: "reports/my-project/us-central1-2024-01-01T180405.csv",
+		},
+		"should reject unknown fields": {
+			path:    "report-{{.Unknown}}.json",
+			wantErr: true,
+		},
+		"should reject malformed templates": {
+			path:    "report-{{.Date.json",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := expandOutputPath(tt.path, cfg, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expandOutputPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("expandOutputPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
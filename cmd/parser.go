@@ -5,131 +5,812 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/exporter"
 	"github.com/hiro-o918/drydock/schemas"
+	"github.com/rs/zerolog"
 )
 
 // Config holds the application configuration.
 type Config struct {
-	ProjectID    string
-	Location     string
-	MinSeverity  string
-	FixableOnly  bool
-	OutputFormat drydock.OutputFormat
-	Concurrency  uint8
-	Debug        bool
+	ProjectID            string
+	Location             string
+	QuotaProject         string
+	CredentialsFile      string
+	Proxy                string
+	NoMetadata           bool
+	RecordDir            string // directory API responses are recorded to
+	ReplayDir            string // directory API responses are replayed from
+	MinSeverity          schemas.Severity
+	FixableOnly          bool
+	Filter               string
+	OnlyCVEs             []string
+	SkipCVEs             []string
+	Suppressions         []schemas.Suppression
+	IgnoreUnlikelyFixed  bool // suppress findings the vendor or distro will not fix
+	OutputFormat         drydock.OutputFormat
+	Concurrency          int // images analyzed at once
+	DiscoveryConcurrency int // repositories listed at once
+	DigestsPerImage      int // most recent digests analyzed per image
+	MaxCandidates        int // most recent digests considered per image, 0 for the default
+	ListPageSize         int // items per page of list calls, 0 for the API default
+	ExportConcurrency    int // destinations and records written at once
+	Adaptive             bool
+	BaseImageAdvice      bool
+	RefreshStale         bool
+	SLA                  schemas.SLAPolicy
+	FailOnSLABreach      bool
+	FailOn               schemas.Severity                    // severity at or above which any finding fails the run
+	History              string                              // file tracking findings across runs
+	NewSince             string                              // report whose findings are not exported again
+	NewOnly              bool                                // export only the findings new since the last run of History
+	DebianTracker        string                              // file or URL of the Debian security tracker export
+	SBOMs                map[string]*drydock.DependencyGraph // by digest or repository/image
+	SBOMDir              string                              // directory the SBOMs of the images are downloaded to
+	SBOMOnly             bool                                // download SBOMs instead of analyzing vulnerabilities
+	CloudRunRegions      []string
+	KubernetesPods       []drydock.DeploymentSource
+	DeployedOnly         bool
+	MaxAttempts          int
+	Cache                string                   // directory or URL of the analysis cache
+	CacheTTL             time.Duration            // how long cached analyses are reused
+	Connection           drydock.ConnectionPolicy // gRPC connection tuning
+	Priorities           []string
+	BatchSize            int
+	Stream               bool   // export each result as it completes
+	SpillThreshold       int    // results held in memory before spilling to disk
+	SpillDir             string // directory of the spill files
+	Addr                 string
+	Dashboard            bool   // serve the web dashboard under /dashboard/
+	Pprof                string // address serving net/http/pprof
+	CPUProfile           string // file of the CPU profile of the run
+	MemProfile           string // file of the heap profile at the end of the run
+	OutputFile           string
+	Reproducible         bool                      // sorted output with a single scan time, for diffing
+	Tee                  bool                      // also write the report to stdout with OutputFile
+	BadgeDir             string                    // directory of per-image badges
+	BackstageMapping     exporter.BackstageMapping // images to Backstage entities, with -o backstage
+	ConfluenceURL        string                    // Confluence endpoint to publish the report to
+	ConfluenceSpace      string
+	ConfluenceParent     string
+	ConfluenceTitle      string
+	ServiceNowURL        string // ServiceNow instance to export vulnerable items to
+	ServiceNowCITable    string
+	S3Bucket             string // bucket to upload the report to
+	S3Key                string
+	S3Endpoint           string
+	S3Region             string
+	BigQueryTable        string // PROJECT.DATASET.TABLE to stream findings into
+	GCSBucket            string // bucket to upload the result of each image to
+	GCSObject            string
+	GCSGzip              bool
+	GitHubIssue          string   // title of the GitHub issue tracking the findings, with -o github
+	Images               []string // explicit images to scan instead of discovering them
+	Repository           string
+	Image                string
+	Tag                  string
+	Digest               string
+	Progress             ProgressFormat
+	Summary              bool // print a summary of the scan on stderr
+	Color                ColorMode
+	RepositoryFilter     string
+	ImageFilter          string
+	TaggedOnly           bool
+	ListFormat           ListFormat
+	StaleDays            int      // days without update after which a digest is stale
+	NoVulnerabilities    bool     // stale: do not read vulnerability counts
+	CVE                  string   // vulnerability to explain
+	Reports              []string // stored reports to read instead of querying the APIs
+	ExplainFormat        ExplainFormat
+	ConfigFile           string
+	CheckConnectivity    bool
+	Debug                bool
+	LogLevel             zerolog.Level
+	LogFormat            LogFormat
+}
+
+// Environment variables holding the Confluence credentials.
+const (
+	confluenceUserEnv  = "CONFLUENCE_USER"
+	confluenceTokenEnv = "CONFLUENCE_TOKEN"
+)
+
+// Environment variables holding the ServiceNow credentials.
+const (
+	serviceNowUserEnv     = "SERVICENOW_USER"
+	serviceNowPasswordEnv = "SERVICENOW_PASSWORD"
+)
+
+// scanOptions returns the options of each scan; the other filters are set on the scanner.
+func (c *Config) scanOptions() drydock.ScanOptions {
+	return drydock.ScanOptions{MinSeverity: c.MinSeverity, FixableOnly: c.FixableOnly}
 }
 
 // Validate checks if the configuration is valid.
 func (c *Config) Validate() error {
-	if c.Location == "" {
+	if c.Location == "" && len(c.Images) == 0 && len(c.Reports) == 0 {
 		return errors.New("flag `-l`, `--location` is required")
 	}
+	if c.Image != "" && c.Repository == "" {
+		return errors.New("flag `--image` requires `--repository`")
+	}
+	if c.Image == "" && (c.Repository != "" || c.Tag != "" || c.Digest != "") {
+		return errors.New("flags `--repository`, `--tag` and `--digest` require `--image`")
+	}
+	if c.Image != "" && c.Location == drydock.AllLocations {
+		return errors.New("flag `--image` requires a single `--location`, not `all`")
+	}
+	if c.Image != "" && len(c.Images) > 0 {
+		return errors.New("flag `--image` cannot be combined with image arguments")
+	}
+	if c.Digest != "" {
+		if err := schemas.ValidateDigest(c.Digest); err != nil {
+			return fmt.Errorf("flag `--digest`: %w", err)
+		}
+	}
+	if c.RecordDir != "" && c.ReplayDir != "" {
+		return errors.New("flags `--record` and `--replay` cannot be combined")
+	}
+	if c.SBOMOnly && c.SBOMDir == "" {
+		return errors.New("flag `--sbom-only` requires `--sbom-dir`")
+	}
+	if c.NewOnly && c.History == "" {
+		return errors.New("flag `--new-only` requires `--history`")
+	}
+	if c.NewOnly && c.NewSince != "" {
+		return errors.New("flag `--new-only` cannot be combined with `--new-since`")
+	}
+	if _, err := expandOutputPath(c.OutputFile, c, time.Now()); err != nil {
+		return fmt.Errorf("flag `--output-file`: %w", err)
+	}
+	if c.Tee && c.OutputFile == "" {
+		return errors.New("flag `--tee` requires `--output-file`")
+	}
+	if c.ConfluenceURL != "" && c.ConfluenceSpace == "" {
+		return errors.New("flag `--confluence-url` requires `--confluence-space`")
+	}
+	if c.BigQueryTable != "" {
+		if _, _, _, err := parseBigQueryTable(c.BigQueryTable); err != nil {
+			return err
+		}
+	}
+	if flags := c.destinationFlags(); len(flags) > 1 {
+		return fmt.Errorf("flags %s cannot be combined: the report goes to a single destination", strings.Join(flags, ", "))
+	}
+	if c.BadgeDir != "" && c.OutputFormat != drydock.OutputFormatBadge {
+		return errors.New("flag `--badge-dir` requires `-o badge`")
+	}
+	if c.BackstageMapping != nil && c.OutputFormat != drydock.OutputFormatBackstage {
+		return errors.New("flag `--backstage-mapping` requires `-o backstage`")
+	}
+	if c.GitHubIssue != "" && c.OutputFormat != drydock.OutputFormatGitHub {
+		return errors.New("flag `--github-issue` requires `-o github`")
+	}
+	if c.MaxAttempts < 1 {
+		return errors.New("flag `--max-attempts` must be at least 1")
+	}
+	if c.BatchSize < 0 {
+		return errors.New("flag `--export-batch-size` must not be negative")
+	}
+	if c.Stream && c.BatchSize > 0 {
+		return errors.New("flag `--stream` conflicts with `--export-batch-size`")
+	}
+	if c.SpillThreshold < 0 {
+		return errors.New("flag `--spill-threshold` must not be negative")
+	}
+	if c.SpillDir != "" && c.SpillThreshold == 0 {
+		return errors.New("flag `--spill-dir` requires `--spill-threshold`")
+	}
+	if c.DeployedOnly && len(c.CloudRunRegions) == 0 && len(c.KubernetesPods) == 0 {
+		return errors.New("flag `--deployed-only` requires `--cloud-run-region` or `--k8s-pods`")
+	}
+	if c.StaleDays < 0 {
+		return errors.New("flag `--older-than` must not be negative")
+	}
+	if c.DigestsPerImage < 1 {
+		return fmt.Errorf("flag `--digests-per-image` must be positive, got %d", c.DigestsPerImage)
+	}
+	if c.MaxCandidates < 0 || c.ListPageSize < 0 {
+		return errors.New("flags `--max-candidates` and `--page-size` must not be negative")
+	}
+	if c.FailOn != "" && !c.FailOn.AtLeast(c.MinSeverity) {
+		return fmt.Errorf("flag `--fail-on` %s is below `--min-severity` %s, whose findings are never counted", c.FailOn, c.MinSeverity)
+	}
+	if c.FailOnSLABreach && c.SLA == nil {
+		return errors.New("flag `--fail-on-sla-breach` requires `--sla`")
+	}
 	// OutputFormat validation is handled during flag parsing, so it's not needed here.
 	return nil
 }
 
-// parseFlags handles CLI argument parsing and returns a validated Config.
+// destinationFlags returns the flags of the destinations the report is sent to.
+// Badges, the Backstage document and the GitHub summary are written to the output,
+// so --output-file is only a destination of its own for the other formats.
+func (c *Config) destinationFlags() []string {
+	var flags []string
+	for _, d := range []struct {
+		flag string
+		set  bool
+	}{
+		{"`--confluence-url`", c.ConfluenceURL != ""},
+		{"`--servicenow-url`", c.ServiceNowURL != ""},
+		{"`--s3-bucket`", c.S3Bucket != ""},
+		{"`--bigquery-table`", c.BigQueryTable != ""},
+		{"`--gcs-bucket`", c.GCSBucket != ""},
+		{"`--badge-dir`", c.BadgeDir != ""},
+		{"`--backstage-mapping`", c.BackstageMapping != nil},
+		{"`--github-issue`", c.GitHubIssue != ""},
+	} {
+		if d.set {
+			flags = append(flags, d.flag)
+		}
+	}
+	if c.OutputFile != "" && c.BadgeDir == "" && c.BackstageMapping == nil && c.GitHubIssue == "" {
+		flags = append(flags, "`--output-file`")
+	}
+	return flags
+}
+
+// parseFlags parses the flags of the scan command and returns a validated Config.
 func parseFlags(args []string, stderr io.Writer) (*Config, error) {
-	if len(args) == 0 {
-		args = []string{"-h"}
+	return parseCommandFlags(commandScan, args, stderr)
+}
+
+// parseCommandFlags parses the flags of the given subcommand and returns a validated Config.
+// Each subcommand only accepts the flag groups it uses.
+func parseCommandFlags(command string, args []string, stderr io.Writer) (*Config, error) {
+	cfg := newConfig()
+	fs := newCommandFlagSet(command, cfg, stderr)
+
+	configPath := os.Getenv(configEnv)
+	if len(args) == 0 && configPath == "" {
+		fs.Usage()
+		return nil, flag.ErrHelp
+	}
+	positional, err := parseInterleaved(fs, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Values of the configuration file apply to the flags not given on the command line
+	if cfg.ConfigFile != "" {
+		configPath = cfg.ConfigFile
+	}
+	if configPath != "" {
+		if err := applyConfigFile(fs, configPath); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case command == commandScan:
+		cfg.Images = positional
+	case command == commandExplain:
+		if len(positional) != 1 {
+			fs.Usage()
+			return nil, errExplainArgs
+		}
+		cfg.CVE = positional[0]
+	case len(positional) > 0:
+		fs.Usage()
+		return nil, fmt.Errorf("unexpected argument: %s", positional[0])
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fs.Usage()
+		return nil, fmt.Errorf("configuration error: %w", err)
+	}
+
+	// --debug is kept as a shortcut for --log-level debug
+	if cfg.Debug && cfg.LogLevel > zerolog.DebugLevel {
+		cfg.LogLevel = zerolog.DebugLevel
 	}
-	fs := flag.NewFlagSet("drydock", flag.ContinueOnError)
+
+	return cfg, nil
+}
+
+// newConfig returns a Config holding the default values.
+func newConfig() *Config {
+	return &Config{
+		MinSeverity:          schemas.SeverityHigh,
+		OutputFormat:         drydock.OutputFormatJSON,
+		Concurrency:          5, // Default concurrency level
+		DiscoveryConcurrency: 1,
+		DigestsPerImage:      1,
+		ExportConcurrency:    1,
+		MaxAttempts:          drydock.DefaultRetryPolicy().MaxAttempts,
+		LogLevel:             zerolog.InfoLevel,
+		LogFormat:            LogFormatConsole,
+		Progress:             ProgressFormatNone,
+		Color:                ColorAuto,
+		ListFormat:           ListFormatTable,
+		ExplainFormat:        ExplainFormatText,
+	}
+}
+
+// newCommandFlagSet registers the flags of the given subcommand, storing their values in cfg.
+func newCommandFlagSet(command string, cfg *Config, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("drydock "+command, flag.ContinueOnError)
 	fs.SetOutput(stderr)
 
-	cfg := &Config{
-		OutputFormat: drydock.OutputFormatJSON,
-		Concurrency:  5, // Default concurrency level
+	addTargetFlags(fs, cfg)
+	switch command {
+	case commandScan:
+		addArtifactFlags(fs, cfg)
+		addDeploymentFlags(fs, cfg)
+		addFindingFlags(fs, cfg)
+		addOutputFlags(fs, cfg)
+		addPprofFlags(fs, cfg)
+		addProfileFlags(fs, cfg)
+	case commandServe:
+		addFindingFlags(fs, cfg)
+		addServeFlags(fs, cfg)
+		addPprofFlags(fs, cfg)
+	case commandList:
+		addListFlags(fs, cfg)
+	case commandStale:
+		addStaleFlags(fs, cfg)
+	case commandExplain:
+		addExplainFlags(fs, cfg)
+	case commandConfig:
+		addArtifactFlags(fs, cfg)
+		addDeploymentFlags(fs, cfg)
+		addFindingFlags(fs, cfg)
+		addOutputFlags(fs, cfg)
+		addConfigValidateFlags(fs, cfg)
 	}
+	addLogFlags(fs, cfg)
+	addConfigFlags(fs, cfg)
 
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Drydock - Artifact Registry Vulnerability Scanner")
+		_, _ = fmt.Fprintln(stderr, "")
+		switch command {
+		case commandScan:
+			_, _ = fmt.Fprintf(stderr, "Usage: drydock %s [flags] [image ...]\n", command)
+		case commandExplain:
+			_, _ = fmt.Fprintf(stderr, "Usage: drydock %s [flags] <vulnerability-id>\n", command)
+		case commandConfig:
+			_, _ = fmt.Fprintf(stderr, "Usage: drydock %s validate [flags]\n", command)
+		default:
+			_, _ = fmt.Fprintf(stderr, "Usage: drydock %s [flags]\n", command)
+		}
+		if c, ok := lookupCommand(command); ok {
+			_, _ = fmt.Fprintf(stderr, "  %s\n", c.summary)
+		}
+		_, _ = fmt.Fprintln(stderr, "")
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// addTargetFlags registers the flags selecting what to scan and how to reach the APIs.
+func addTargetFlags(fs *flag.FlagSet, cfg *Config) {
 	// --project / -p
 	fs.StringVar(&cfg.ProjectID, "project", "", "GCP project ID (required)")
 	fs.StringVar(&cfg.ProjectID, "p", "", "Project ID (alias for --project)")
 
 	// --location / -l
-	fs.StringVar(&cfg.Location, "location", "", "Artifact Registry location (required)")
+	fs.StringVar(&cfg.Location, "location", "", "Artifact Registry location, or \"all\" for every location of the project (required)")
 	fs.StringVar(&cfg.Location, "l", "", "Location (alias for --location)")
 
+	// --quota-project
+	fs.StringVar(&cfg.QuotaProject, "quota-project", "", "Project charged for API quota and billing (default: the scanned project)")
+
+	// --credentials-file
+	fs.StringVar(&cfg.CredentialsFile, "credentials-file", "", "Path to a service account key or external_account (Workload Identity Federation) JSON file (default: Application Default Credentials)")
+
+	// --proxy
+	fs.StringVar(&cfg.Proxy, "proxy", "", "HTTP(S) proxy for Google API traffic, e.g. http://proxy:3128 (default: HTTPS_PROXY / NO_PROXY)")
+
+	// --no-metadata-server
+	fs.BoolVar(&cfg.NoMetadata, "no-metadata-server", false, "Do not probe the GCP metadata server when detecting the project ID (avoids a timeout outside GCP)")
+
+	// --record / --replay
+	fs.StringVar(&cfg.RecordDir, "record", "", "Record Artifact Registry and Container Analysis responses to this directory")
+	fs.StringVar(&cfg.ReplayDir, "replay", "", "Answer Artifact Registry and Container Analysis calls from responses recorded with --record, offline")
+
+	// --cache / --cache-ttl
+	fs.StringVar(&cfg.Cache, "cache", "", "Reuse analyses of unchanged digests from a cache: a directory, redis://[:password@]host:port/db, or gs://bucket/prefix")
+	fs.DurationVar(&cfg.CacheTTL, "cache-ttl", 24*time.Hour, "How long cached analyses are reused, e.g. 6h")
+
+	// --max-attempts
+	fs.IntVar(&cfg.MaxAttempts, "max-attempts", cfg.MaxAttempts, "Attempts per API call, retrying transient and quota errors with backoff (1 disables retries)")
+
+	// --grpc-*
+	fs.DurationVar(&cfg.Connection.KeepaliveTime, "grpc-keepalive", 0, "Ping idle gRPC connections after this duration, e.g. 5m, to detect connections dropped by proxies or NATs (at least 10s; default: off)")
+	fs.DurationVar(&cfg.Connection.KeepaliveTimeout, "grpc-keepalive-timeout", 0, "Close gRPC connections whose keepalive ping is not acknowledged in time (default: 20s)")
+	fs.IntVar(&cfg.Connection.MaxReceiveMessageSize, "grpc-max-message-size", 0, "Largest gRPC response accepted, in bytes (default: 2 GiB)")
+	fs.IntVar(&cfg.Connection.PoolSize, "grpc-pool-size", 0, "gRPC connections per API client, across which calls are balanced (default: the client library's)")
+
+	// --concurrency / -c
+	parseConcurrency := concurrencyFlag(&cfg.Concurrency)
+	fs.Func("concurrency", "Number of images analyzed at once (default: 5)", parseConcurrency)
+	fs.Func("c", "Concurrency (alias for --concurrency)", parseConcurrency)
+
+	// --discovery-concurrency
+	fs.Func("discovery-concurrency", "Number of repositories listed at once (default: 1)", concurrencyFlag(&cfg.DiscoveryConcurrency))
+
+	// --digests-per-image
+	fs.IntVar(&cfg.DigestsPerImage, "digests-per-image", 1, "Number of most recent digests analyzed per image, instead of only the latest one")
+
+	// --max-candidates / --page-size
+	fs.IntVar(&cfg.MaxCandidates, "max-candidates", 0, fmt.Sprintf("Number of most recent digests of each image searched for the latest tag (default: %d)", drydock.MaxCandidates))
+	fs.IntVar(&cfg.ListPageSize, "page-size", 0, "Number of repositories and images requested per page while listing (default: API default)")
+
+	// --priority (repeatable, comma-separated)
+	fs.Func("priority", "Glob pattern of repositories to scan first, e.g. 'prod-*' (repeatable, comma-separated)", func(s string) error {
+		cfg.Priorities = append(cfg.Priorities, splitList(s)...)
+		return nil
+	})
+
+	// --adaptive-concurrency
+	fs.BoolVar(&cfg.Adaptive, "adaptive-concurrency", false, "Reduce concurrency automatically when API quota is exhausted")
+}
+
+// addArtifactFlags registers the flags selecting a single artifact of the project to scan.
+func addArtifactFlags(fs *flag.FlagSet, cfg *Config) {
+	// --repository / --image
+	fs.StringVar(&cfg.Repository, "repository", "", "Repository of the image to scan (with --image)")
+	fs.StringVar(&cfg.Image, "image", "", "Scan only this image, e.g. my-service/worker (requires --repository)")
+
+	// --tag / --digest
+	fs.StringVar(&cfg.Tag, "tag", "", "Tag of the image to scan (default: latest)")
+	fs.StringVar(&cfg.Digest, "digest", "", "Digest of the image to scan, e.g. sha256:... (takes precedence over --tag)")
+}
+
+// addDeploymentFlags registers the flags correlating images with the workloads running them.
+func addDeploymentFlags(fs *flag.FlagSet, cfg *Config) {
+	// --cloud-run-region (repeatable, comma-separated)
+	fs.Func("cloud-run-region", "Find the images serving the Cloud Run services of the project in these regions (repeatable, comma-separated)", func(s string) error {
+		cfg.CloudRunRegions = append(cfg.CloudRunRegions, splitList(s)...)
+		return nil
+	})
+
+	// --k8s-pods (repeatable)
+	fs.Func("k8s-pods", "Find the images of running pods in the output of `kubectl get pods -A -o json`, as FILE or CLUSTER=FILE (repeatable)", func(s string) error {
+		source, err := readKubernetesPods(s)
+		if err != nil {
+			return err
+		}
+		cfg.KubernetesPods = append(cfg.KubernetesPods, source)
+		return nil
+	})
+
+	// --deployed-only
+	fs.BoolVar(&cfg.DeployedOnly, "deployed-only", false, "Only scan images running in a discovered workload (requires --cloud-run-region or --k8s-pods)")
+}
+
+// addFindingFlags registers the flags selecting which vulnerabilities are reported.
+func addFindingFlags(fs *flag.FlagSet, cfg *Config) {
 	// --min-severity / -s
-	fs.StringVar(&cfg.MinSeverity, "min-severity", "HIGH", "Minimum severity level")
-	fs.StringVar(&cfg.MinSeverity, "s", "HIGH", "Severity (alias for --min-severity)")
+	fs.Var(&cfg.MinSeverity, "min-severity", "Minimum severity level (MINIMAL, LOW, MEDIUM, HIGH, CRITICAL)")
+	fs.Var(&cfg.MinSeverity, "s", "Severity (alias for --min-severity)")
 
 	// --fixable-only / -f
 	fs.BoolVar(&cfg.FixableOnly, "fixable", false, "Only show vulnerabilities that have a fix available")
 
-	// --output-format / -o
-	fs.Var(&cfg.OutputFormat, "output-format", "Output format (json, csv, tsv)")
-	fs.Var(&cfg.OutputFormat, "o", "Output format (alias for --output-format)")
+	// --only-cve / --skip-cve (repeatable, comma-separated)
+	fs.Func("only-cve", "Only report these vulnerability IDs, e.g. CVE-2024-1234 (repeatable, comma-separated)", func(s string) error {
+		cfg.OnlyCVEs = append(cfg.OnlyCVEs, splitList(s)...)
+		return nil
+	})
+	fs.Func("skip-cve", "Never report these vulnerability IDs; they are listed as suppressed (repeatable, comma-separated)", func(s string) error {
+		cfg.SkipCVEs = append(cfg.SkipCVEs, splitList(s)...)
+		return nil
+	})
 
-	// --concurrency / -c
-	fs.Func("concurrency", "Number of concurrent scans (default: 5)", func(s string) error {
-		var n uint64
-		_, err := fmt.Sscanf(s, "%d", &n)
+	// --suppressions (repeatable)
+	fs.Func("suppressions", "JSON file of accepted findings (id, packageName, reason, by, until), listed as suppressed (repeatable)", func(s string) error {
+		suppressions, err := readSuppressions(s)
 		if err != nil {
-			return fmt.Errorf("invalid concurrency value: %w", err)
+			return err
 		}
-		if n < 1 {
-			return fmt.Errorf("concurrency must be at least 1")
+		cfg.Suppressions = append(cfg.Suppressions, suppressions...)
+		return nil
+	})
+
+	// --ignore-unlikely-fixed
+	fs.BoolVar(&cfg.IgnoreUnlikelyFixed, "ignore-unlikely-fixed", false, "List findings the vendor will not fix (WONT_FIX), or that --debian-tracker reports as ignored, unimportant or end-of-life, as suppressed")
+
+	// --base-image-advice
+	fs.BoolVar(&cfg.BaseImageAdvice, "base-image-advice", false, "Compare the findings of each image's base image with the newest version of that base image (extra API calls)")
+
+	// --sbom (repeatable)
+	fs.Func("sbom", "CycloneDX or SPDX JSON SBOM of an image, as DIGEST=FILE or REPOSITORY/IMAGE=FILE, to report the dependency path of language package findings (repeatable)", func(s string) error {
+		ref, graph, err := readSBOM(s)
+		if err != nil {
+			return err
 		}
-		if n > 255 {
-			return fmt.Errorf("concurrency must be at most 255")
+		if cfg.SBOMs == nil {
+			cfg.SBOMs = make(map[string]*drydock.DependencyGraph)
 		}
-		cfg.Concurrency = uint8(n)
+		cfg.SBOMs[ref] = graph
 		return nil
 	})
-	fs.Func("c", "Concurrency (alias for --concurrency)", func(s string) error {
-		var n uint64
-		_, err := fmt.Sscanf(s, "%d", &n)
+
+	// --sbom-dir
+	fs.StringVar(&cfg.SBOMDir, "sbom-dir", "", "Download the SBOM Artifact Analysis generated for each image (SPDX JSON) to this directory, alongside the report")
+
+	// --sbom-only
+	fs.BoolVar(&cfg.SBOMOnly, "sbom-only", false, "Download SBOMs without analyzing vulnerabilities (requires --sbom-dir)")
+
+	// --debian-tracker
+	fs.StringVar(&cfg.DebianTracker, "debian-tracker", "", "Annotate Debian findings with their status in the Debian security tracker (no-dsa, ignored, ...), from a file or URL of its JSON export, e.g. "+drydock.DebianSecurityTrackerURL)
+
+	// --refresh-stale
+	fs.BoolVar(&cfg.RefreshStale, "refresh-stale", false, "Pull images whose continuous analysis stopped (not pulled for 30 days) so their findings are updated for later scans")
+
+	// --sla / --fail-on-sla-breach
+	fs.Func("sla", "Days allowed to fix findings by severity since first seen, e.g. CRITICAL=7d,HIGH=30d", func(s string) error {
+		policy, err := schemas.ParseSLAPolicy(s)
 		if err != nil {
-			return fmt.Errorf("invalid concurrency value: %w", err)
+			return err
 		}
-		if n < 1 {
-			return fmt.Errorf("concurrency must be at least 1")
-		}
-		if n > 255 {
-			return fmt.Errorf("concurrency must be at most 255")
+		cfg.SLA = policy
+		return nil
+	})
+	fs.BoolVar(&cfg.FailOnSLABreach, "fail-on-sla-breach", false, "Exit with an error when any finding is past its SLA (requires --sla)")
+
+	// --fail-on
+	fs.Var(&cfg.FailOn, "fail-on", "Exit with an error when any finding is at or above this severity (MINIMAL, LOW, MEDIUM, HIGH, CRITICAL)")
+
+	// --history
+	fs.StringVar(&cfg.History, "history", "", "JSON file tracking findings across runs: stamps firstSeen/lastSeen and reports resolved findings (created if missing)")
+
+	// --new-since / --new-only
+	fs.StringVar(&cfg.NewSince, "new-since", "", "Export only the findings that are not in this JSON report of a previous scan (all of them if it is missing)")
+	fs.BoolVar(&cfg.NewOnly, "new-only", false, "Export only the findings that are new since the previous run recorded in --history")
+
+	// --filter
+	fs.StringVar(&cfg.Filter, "filter", "", "CEL expression selecting vulnerabilities, e.g. 'vuln.cvssScore >= 7.0'")
+}
+
+// addOutputFlags registers the flags controlling how results are exported.
+func addOutputFlags(fs *flag.FlagSet, cfg *Config) {
+	// --output-format / -o
+	fs.Var(&cfg.OutputFormat, "output-format", fmt.Sprintf("Output format (%s)", strings.Join(formatNames(), ", ")))
+	fs.Var(&cfg.OutputFormat, "o", "Output format (alias for --output-format)")
+
+	// --output-file / -O
+	fs.StringVar(&cfg.OutputFile, "output-file", "", "Write the report to this file instead of stdout (created atomically, with parent directories); a template such as report-{{.Date}}.json")
+	fs.StringVar(&cfg.OutputFile, "output", "", "Output file (alias for --output-file)")
+	fs.StringVar(&cfg.OutputFile, "O", "", "Output file (alias for --output-file)")
+
+	// --reproducible
+	fs.BoolVar(&cfg.Reproducible, "reproducible", false, "Produce identical reports for unchanged data: images and findings sorted, one scan time, no durations")
+
+	// --tee
+	fs.BoolVar(&cfg.Tee, "tee", false, "With --output-file, also print the report on stdout")
+
+	// --confluence-*
+	fs.StringVar(&cfg.ConfluenceURL, "confluence-url", "", "Publish the report as a Confluence page instead of writing it, e.g. https://example.atlassian.net/wiki ($"+confluenceUserEnv+", $"+confluenceTokenEnv+")")
+	fs.StringVar(&cfg.ConfluenceSpace, "confluence-space", "", "Key of the Confluence space of the page")
+	fs.StringVar(&cfg.ConfluenceParent, "confluence-parent", "", "ID of the Confluence page to create the page under (default: the space root)")
+	fs.StringVar(&cfg.ConfluenceTitle, "confluence-title", "Vulnerability report", "Title of the Confluence page, updated on each run")
+
+	// --servicenow-*
+	fs.StringVar(&cfg.ServiceNowURL, "servicenow-url", "", "Create or update ServiceNow Vulnerable Items instead of writing the report, e.g. https://example.service-now.com ($"+serviceNowUserEnv+", $"+serviceNowPasswordEnv+")")
+	fs.StringVar(&cfg.ServiceNowCITable, "servicenow-ci-table", "cmdb_ci", "ServiceNow table of the configuration items named after the images")
+
+	// --s3-*
+	fs.StringVar(&cfg.S3Bucket, "s3-bucket", "", "Upload the report to this S3-compatible bucket instead of writing it ($AWS_ACCESS_KEY_ID, $AWS_SECRET_ACCESS_KEY, $AWS_SESSION_TOKEN)")
+	fs.StringVar(&cfg.S3Key, "s3-key", "", "Object key template, with {{.Project}}, {{.Location}}, {{.Date}} and {{.Timestamp}} (default: drydock/{{.Project}}/{{.Date}}/{{.Timestamp}}.<format>)")
+	fs.StringVar(&cfg.S3Endpoint, "s3-endpoint", "", "S3-compatible endpoint, e.g. http://minio:9000 (default: AWS S3 in the region)")
+	fs.StringVar(&cfg.S3Region, "s3-region", os.Getenv("AWS_REGION"), "Bucket region (default: $AWS_REGION, or us-east-1)")
+
+	// --bigquery-table
+	fs.StringVar(&cfg.BigQueryTable, "bigquery-table", "", "Stream one row per finding into this BigQuery table, as PROJECT.DATASET.TABLE, instead of writing the report (created, partitioned by scan date, if missing)")
+
+	// --gcs-*
+	fs.StringVar(&cfg.GCSBucket, "gcs-bucket", "", "Upload the JSON result of each image to this Cloud Storage bucket instead of writing the report")
+	fs.StringVar(&cfg.GCSObject, "gcs-object", exporter.DefaultGCSObjectName, "Object name template, with {{.Project}}, {{.Location}}, {{.Repository}}, {{.Image}}, {{.Digest}}, {{.Date}} and {{.Timestamp}}")
+	fs.BoolVar(&cfg.GCSGzip, "gcs-gzip", false, "Compress the uploaded objects with gzip")
+
+	// --github-issue
+	fs.StringVar(&cfg.GitHubIssue, "github-issue", "", "With -o github, also file the report as an issue with this title in $GITHUB_REPOSITORY, refreshed by later runs and closed once clean ($"+githubTokenEnv+")")
+
+	// --badge-dir
+	fs.StringVar(&cfg.BadgeDir, "badge-dir", "", "With -o badge, also write one badge per image to <dir>/<host>/<project>/<repository>/<image>.json")
+
+	// --backstage-mapping
+	fs.Func("backstage-mapping", `With -o backstage, JSON file mapping images (or glob patterns) to entities, e.g. {"us-docker.pkg.dev/p/r/api": "component:default/api"} (default: component named after the image)`, func(s string) error {
+		mapping, err := readBackstageMapping(s)
+		if err != nil {
+			return err
 		}
-		cfg.Concurrency = uint8(n)
+		cfg.BackstageMapping = mapping
 		return nil
 	})
 
+	// --progress
+	fs.Var(&cfg.Progress, "progress", "Progress stream on stderr: none, json (one event per line) (default: none)")
+
+	// --summary
+	fs.BoolVar(&cfg.Summary, "summary", true, "Print a summary of the scan (images, findings by severity, worst images) on stderr at the end; --summary=false disables it")
+
+	// --export-batch-size
+	fs.IntVar(&cfg.BatchSize, "export-batch-size", 0, "Export results in batches of N as they complete to keep memory bounded (0: export all at the end)")
+
+	// --stream
+	fs.BoolVar(&cfg.Stream, "stream", false, "Export each result as soon as its image is analyzed, keeping memory flat however many images are scanned")
+
+	// --export-concurrency
+	fs.Func("export-concurrency", "Number of outputs, and of ServiceNow records, written at once (default: 1)", concurrencyFlag(&cfg.ExportConcurrency))
+
+	// --spill-threshold
+	fs.IntVar(&cfg.SpillThreshold, "spill-threshold", 0, "Move results to temporary files whenever N are held in memory, and export them at the end (0: keep all in memory)")
+
+	// --spill-dir
+	fs.StringVar(&cfg.SpillDir, "spill-dir", "", "Directory of the files of --spill-threshold (default: the system temporary directory)")
+}
+
+// addServeFlags registers the flags of the serve command.
+func addServeFlags(fs *flag.FlagSet, cfg *Config) {
+	// --addr
+	fs.StringVar(&cfg.Addr, "addr", defaultServeAddr(), "Address to listen on for CloudEvents (default: :$PORT or :8080)")
+
+	// --dashboard
+	fs.BoolVar(&cfg.Dashboard, "dashboard", false, "Serve a web dashboard of the scans under /dashboard/ (trends require --history)")
+
+	// --output-format / -o
+	fs.Var(&cfg.OutputFormat, "output-format", fmt.Sprintf("Output format of each event's results (%s)", strings.Join(formatNames(), ", ")))
+	fs.Var(&cfg.OutputFormat, "o", "Output format (alias for --output-format)")
+}
+
+// addLogFlags registers the logging flags shared by all commands.
+func addLogFlags(fs *flag.FlagSet, cfg *Config) {
 	// --debug / -d
 	fs.BoolVar(&cfg.Debug, "debug", false, "Enable debug logging")
 	fs.BoolVar(&cfg.Debug, "d", false, "Debug (alias for --debug)")
 
-	fs.Usage = func() {
-		_, _ = fmt.Fprintln(stderr, "Drydock - Artifact Registry Vulnerability Scanner")
-		fs.PrintDefaults()
+	// --log-level
+	fs.Func("log-level", "Log level (trace, debug, info, warn, error) (default: info)", func(s string) error {
+		level, err := parseLogLevel(s)
+		if err != nil {
+			return err
+		}
+		cfg.LogLevel = level
+		return nil
+	})
+
+	// --log-format
+	fs.Var(&cfg.LogFormat, "log-format", "Log format (console, json) (default: console)")
+
+	// --color / --no-color
+	fs.Var(&cfg.Color, "color", "Colorize output: auto (terminals only, honoring NO_COLOR), always, never (default: auto)")
+	fs.BoolFunc("no-color", "Disable colors (alias for --color never)", func(s string) error {
+		disable, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		if disable {
+			cfg.Color = ColorNever
+		}
+		return nil
+	})
+}
+
+// concurrencyFlag returns the parser of a concurrency flag storing its value in n.
+func concurrencyFlag(n *int) func(string) error {
+	return func(s string) error {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid concurrency value: %w", err)
+		}
+		if v < 1 || v > drydock.MaxConcurrency {
+			return fmt.Errorf("concurrency must be between 1 and %d", drydock.MaxConcurrency)
+		}
+		*n = v
+		return nil
+	}
+}
+
+// parseInterleaved parses args with fs, allowing flags to follow positional arguments
+// (as in `drydock scan IMAGE -s LOW`), and returns the positional arguments.
+// Everything after a "--" terminator is positional.
+func parseInterleaved(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		// Parse consumes the terminator, and stops before any other positional argument
+		if consumed := len(args) - fs.NArg(); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, fs.Args()...), nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// formatNames returns the registered output format names for help messages.
+func formatNames() []string {
+	var names []string
+	for _, f := range drydock.RegisteredFormats() {
+		names = append(names, string(f))
 	}
+	return names
+}
 
-	if err := fs.Parse(args); err != nil {
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// readSuppressions reads the suppressions file at path.
+func readSuppressions(path string) ([]schemas.Suppression, error) {
+	f, err := os.Open(path)
+	if err != nil {
 		return nil, err
 	}
+	defer func() { _ = f.Close() }()
+	return schemas.ReadSuppressions(f, path)
+}
 
-	if err := cfg.Validate(); err != nil {
-		fs.Usage()
-		return nil, fmt.Errorf("configuration error: %w", err)
+// readSBOM reads an SBOM given as REF=FILE.
+func readSBOM(arg string) (string, *drydock.DependencyGraph, error) {
+	ref, path, ok := strings.Cut(arg, "=")
+	if !ok || ref == "" || path == "" {
+		return "", nil, fmt.Errorf("invalid SBOM %q: expected DIGEST=FILE or REPOSITORY/IMAGE=FILE", arg)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer func() { _ = f.Close() }()
+	graph, err := drydock.ReadSBOM(f)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %w", path, err)
 	}
+	return ref, graph, nil
+}
 
-	return cfg, nil
+// readBackstageMapping reads the Backstage mapping file at path.
+func readBackstageMapping(path string) (exporter.BackstageMapping, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return exporter.ReadBackstageMapping(f)
+}
+
+// readKubernetesPods reads a pod list given as FILE or CLUSTER=FILE.
+func readKubernetesPods(arg string) (*drydock.KubernetesPodsSource, error) {
+	cluster, path, ok := strings.Cut(arg, "=")
+	if !ok {
+		cluster, path = "", arg
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return drydock.NewKubernetesPodsSource(f, cluster)
 }
 
-func parseSeverity(s string) (schemas.Severity, error) {
-	s = strings.ToUpper(strings.TrimSpace(s))
-	switch s {
-	case "MINIMAL":
-		return schemas.SeverityMinimal, nil
-	case "LOW":
-		return schemas.SeverityLow, nil
-	case "MEDIUM":
-		return schemas.SeverityMedium, nil
-	case "HIGH":
-		return schemas.SeverityHigh, nil
-	case "CRITICAL":
-		return schemas.SeverityCritical, nil
-	default:
-		return "", fmt.Errorf("invalid severity level: %s (allowed: MINIMAL, LOW, MEDIUM, HIGH, CRITICAL)", s)
+// parseBigQueryTable splits a PROJECT.DATASET.TABLE table name.
+func parseBigQueryTable(s string) (project, dataset, table string, err error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 || slices.Contains(parts, "") {
+		return "", "", "", fmt.Errorf("flag `--bigquery-table` must be PROJECT.DATASET.TABLE, got %q", s)
 	}
+	return parts[0], parts[1], parts[2], nil
 }
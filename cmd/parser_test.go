@@ -1,56 +1,490 @@
 package main
 
 import (
+	"flag"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hiro-o918/drydock/schemas"
 )
 
-func TestParseSeverity(t *testing.T) {
+func TestParseFlags_MinSeverity(t *testing.T) {
 	tests := map[string]struct {
-		input   string
+		args    []string
 		want    schemas.Severity
 		wantErr bool
 	}{
-		"should parse MINIMAL severity": {
-			input: "MINIMAL",
-			want:  schemas.SeverityMinimal,
+		"should default to HIGH when severity is not given": {
+			args: []string{"-l", "us-central1"},
+			want: schemas.SeverityHigh,
 		},
-		"should parse LOW severity": {
-			input: "LOW",
-			want:  schemas.SeverityLow,
+		"should parse --min-severity case-insensitively": {
+			args: []string{"-l", "us-central1", "--min-severity", "critical"},
+			want: schemas.SeverityCritical,
 		},
-		"should parse MEDIUM severity": {
-			input: "MEDIUM",
-			want:  schemas.SeverityMedium,
+		"should parse the -s alias": {
+			args: []string{"-l", "us-central1", "-s", "LOW"},
+			want: schemas.SeverityLow,
 		},
-		"should parse HIGH severity": {
-			input: "HIGH",
-			want:  schemas.SeverityHigh,
+		"should return error when severity is invalid": {
+			args:    []string{"-l", "us-central1", "-s", "INVALID"},
+			wantErr: true,
 		},
-		"should parse CRITICAL severity": {
-			input: "CRITICAL",
-			want:  schemas.SeverityCritical,
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := parseFlags(tt.args, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseFlags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && cfg.MinSeverity != tt.want {
+				t.Errorf("parseFlags() MinSeverity = %v, want %v", cfg.MinSeverity, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFlags_Images(t *testing.T) {
+	const image = "us-central1-docker.pkg.dev/proj/repo/img:v1"
+
+	tests := map[string]struct {
+		args         []string
+		wantImages   []string
+		wantSeverity schemas.Severity
+		wantErr      bool
+	}{
+		"should not require a location with explicit images": {
+			args:         []string{image},
+			wantImages:   []string{image},
+			wantSeverity: schemas.SeverityHigh,
 		},
-		"should return error when severity is invalid": {
-			input:   "INVALID",
+		"should accept flags after the images": {
+			args:         []string{image, "-s", "LOW", image + "2"},
+			wantImages:   []string{image, image + "2"},
+			wantSeverity: schemas.SeverityLow,
+		},
+		"should accept flags before the images": {
+			args:         []string{"-s", "CRITICAL", image},
+			wantImages:   []string{image},
+			wantSeverity: schemas.SeverityCritical,
+		},
+		"should require a location without images": {
+			args:    []string{"-s", "LOW"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := parseFlags(tt.args, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.wantImages, cfg.Images); diff != "" {
+				t.Errorf("parseFlags() Images mismatch (-want +got):\n%s", diff)
+			}
+			if cfg.MinSeverity != tt.wantSeverity {
+				t.Errorf("parseFlags() MinSeverity = %v, want %v", cfg.MinSeverity, tt.wantSeverity)
+			}
+		})
+	}
+}
+
+func TestParseFlags_Artifact(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	tests := map[string]struct {
+		args    []string
+		wantErr bool
+	}{
+		"should accept an image by tag": {
+			args: []string{"-l", "us-central1", "--repository", "r", "--image", "app", "--tag", "v1.2.3"},
+		},
+		"should accept an image by digest": {
+			args: []string{"-l", "us-central1", "--repository", "r", "--image", "app", "--digest", digest},
+		},
+		"should require a repository with an image": {
+			args:    []string{"-l", "us-central1", "--image", "app"},
+			wantErr: true,
+		},
+		"should require an image with a tag": {
+			args:    []string{"-l", "us-central1", "--tag", "v1.2.3"},
+			wantErr: true,
+		},
+		"should accept a sha512 digest": {
+			args: []string{"-l", "us-central1", "--repository", "r", "--image", "app", "--digest", "sha512:" + strings.Repeat("b", 128)},
+		},
+		"should reject malformed digests": {
+			args:    []string{"-l", "us-central1", "--repository", "r", "--image", "app", "--digest", "sha256:abc"},
+			wantErr: true,
+		},
+		"should reject an image combined with image arguments": {
+			args:    []string{"--repository", "r", "--image", "app", "us-central1-docker.pkg.dev/p/r/app:v1"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseFlags(tt.args, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseFlags_SLA(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		want    schemas.SLAPolicy
+		wantErr bool
+	}{
+		"should parse per-severity windows": {
+			args: []string{"-l", "us-central1", "--sla", "CRITICAL=7d,HIGH=30d", "--fail-on-sla-breach"},
+			want: schemas.SLAPolicy{schemas.SeverityCritical: 7 * 24 * time.Hour, schemas.SeverityHigh: 30 * 24 * time.Hour},
+		},
+		"should reject invalid windows": {
+			args:    []string{"-l", "us-central1", "--sla", "CRITICAL=soon"},
+			wantErr: true,
+		},
+		"should require --sla with --fail-on-sla-breach": {
+			args:    []string{"-l", "us-central1", "--fail-on-sla-breach"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := parseFlags(tt.args, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, cfg.SLA); diff != "" {
+				t.Errorf("SLA mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseFlags_FailOn(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		want    schemas.Severity
+		wantErr bool
+	}{
+		"should parse the severity case-insensitively": {
+			args: []string{"-l", "us-central1", "--fail-on", "critical"},
+			want: schemas.SeverityCritical,
+		},
+		"should reject invalid severities": {
+			args:    []string{"-l", "us-central1", "--fail-on", "urgent"},
+			wantErr: true,
+		},
+		"should reject a severity below the minimum severity": {
+			args:    []string{"-l", "us-central1", "-s", "HIGH", "--fail-on", "MEDIUM"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := parseFlags(tt.args, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if cfg.FailOn != tt.want {
+				t.Errorf("FailOn = %s, want %s", cfg.FailOn, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFlags_Suppressions(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "suppressions.json")
+	if err := os.WriteFile(valid, []byte(`[{"id": "CVE-2024-1234", "reason": "not reachable", "by": "alice"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`[{"reason": "no id"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args    []string
+		want    []schemas.Suppression
+		wantErr bool
+	}{
+		"should read the suppressions file": {
+			args: []string{"-l", "us-central1", "--suppressions", valid},
+			want: []schemas.Suppression{{ID: "CVE-2024-1234", Reason: "not reachable", By: "alice", Source: valid}},
+		},
+		"should reject invalid suppressions": {
+			args:    []string{"-l", "us-central1", "--suppressions", invalid},
+			wantErr: true,
+		},
+		"should reject missing files": {
+			args:    []string{"-l", "us-central1", "--suppressions", filepath.Join(dir, "missing.json")},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := parseFlags(tt.args, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, cfg.Suppressions); diff != "" {
+				t.Errorf("Suppressions mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseFlags_Deployments(t *testing.T) {
+	pods := filepath.Join(t.TempDir(), "pods.json")
+	if err := os.WriteFile(pods, []byte(`{"items": []}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args        []string
+		wantRegions []string
+		wantPods    int
+		wantErr     bool
+	}{
+		"should collect Cloud Run regions": {
+			args:        []string{"-l", "us-central1", "--cloud-run-region", "us-central1,asia-northeast1", "--deployed-only"},
+			wantRegions: []string{"us-central1", "asia-northeast1"},
+		},
+		"should read pod lists with or without a cluster name": {
+			args:     []string{"-l", "us-central1", "--k8s-pods", pods, "--k8s-pods", "gke-prod=" + pods, "--deployed-only"},
+			wantPods: 2,
+		},
+		"should reject missing pod lists": {
+			args:    []string{"-l", "us-central1", "--k8s-pods", pods + ".missing"},
+			wantErr: true,
+		},
+		"should require a deployment source with --deployed-only": {
+			args:    []string{"-l", "us-central1", "--deployed-only"},
 			wantErr: true,
 		},
-		"should return error when severity is empty": {
-			input:   "",
+		"should reject --record combined with --replay": {
+			args:    []string{"-l", "us-central1", "--record", "a", "--replay", "b"},
+			wantErr: true,
+		},
+		"should require --history with --new-only": {
+			args:    []string{"-l", "us-central1", "--new-only"},
+			wantErr: true,
+		},
+		"should reject --new-only combined with --new-since": {
+			args:    []string{"-l", "us-central1", "--history", "h.json", "--new-only", "--new-since", "r.json"},
 			wantErr: true,
 		},
 	}
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			got, err := parseSeverity(tt.input)
+			cfg, err := parseFlags(tt.args, io.Discard)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("parseSeverity() error = %v, wantErr %v", err, tt.wantErr)
+				t.Fatalf("parseFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
 				return
 			}
-			if !tt.wantErr && got != tt.want {
-				t.Errorf("parseSeverity() = %v, want %v", got, tt.want)
+			if diff := cmp.Diff(tt.wantRegions, cfg.CloudRunRegions); diff != "" {
+				t.Errorf("CloudRunRegions mismatch (-want +got):\n%s", diff)
+			}
+			if len(cfg.KubernetesPods) != tt.wantPods {
+				t.Errorf("len(KubernetesPods) = %d, want %d", len(cfg.KubernetesPods), tt.wantPods)
+			}
+		})
+	}
+}
+
+func TestParseFlags_SBOM(t *testing.T) {
+	sbom := filepath.Join(t.TempDir(), "sbom.json")
+	if err := os.WriteFile(sbom, []byte(`{"bomFormat": "CycloneDX"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args     []string
+		wantRefs []string
+		wantErr  bool
+	}{
+		"should read SBOMs by digest and by image": {
+			args:     []string{"-l", "us-central1", "--sbom", "sha256:abc=" + sbom, "--sbom", "repo/app=" + sbom},
+			wantRefs: []string{"repo/app", "sha256:abc"},
+		},
+		"should reject an SBOM without a reference": {
+			args:    []string{"-l", "us-central1", "--sbom", sbom},
+			wantErr: true,
+		},
+		"should reject missing files": {
+			args:    []string{"-l", "us-central1", "--sbom", "repo/app=" + sbom + ".missing"},
+			wantErr: true,
+		},
+		"should require --sbom-dir with --sbom-only": {
+			args:    []string{"-l", "us-central1", "--sbom-only"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := parseFlags(tt.args, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			refs := slices.Sorted(maps.Keys(cfg.SBOMs))
+			if diff := cmp.Diff(tt.wantRefs, refs); diff != "" {
+				t.Errorf("SBOM refs mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseFlags_BigQueryTable(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		wantErr bool
+	}{
+		"should accept a fully qualified table": {
+			args: []string{"-l", "us-central1", "--bigquery-table", "p.security.findings"},
+		},
+		"should reject a table without project": {
+			args:    []string{"-l", "us-central1", "--bigquery-table", "security.findings"},
+			wantErr: true,
+		},
+		"should reject --bigquery-table combined with --output-file": {
+			args:    []string{"-l", "us-central1", "--bigquery-table", "p.security.findings", "-O", "report.json"},
+			wantErr: true,
+		},
+		"should reject --gcs-bucket combined with --bigquery-table": {
+			args:    []string{"-l", "us-central1", "--bigquery-table", "p.security.findings", "--gcs-bucket", "reports"},
+			wantErr: true,
+		},
+		"should accept --github-issue with -o github": {
+			args: []string{"-l", "us-central1", "-o", "github", "--github-issue", "Vulnerabilities"},
+		},
+		"should reject --github-issue without -o github": {
+			args:    []string{"-l", "us-central1", "--github-issue", "Vulnerabilities"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseFlags(tt.args, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseFlags_Destinations(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		wantErr bool
+	}{
+		"should reject --confluence-url combined with --output-file": {
+			args:    []string{"-l", "us-central1", "--confluence-url", "https://example.atlassian.net/wiki", "--confluence-space", "SEC", "-O", "report.json"},
+			wantErr: true,
+		},
+		"should reject --servicenow-url combined with --output-file": {
+			args:    []string{"-l", "us-central1", "--servicenow-url", "https://example.service-now.com", "-O", "report.json"},
+			wantErr: true,
+		},
+		"should reject --confluence-url combined with --github-issue": {
+			args:    []string{"-l", "us-central1", "-o", "github", "--github-issue", "Vulnerabilities", "--confluence-url", "https://example.atlassian.net/wiki", "--confluence-space", "SEC"},
+			wantErr: true,
+		},
+		"should reject --servicenow-url combined with --badge-dir": {
+			args:    []string{"-l", "us-central1", "-o", "badge", "--badge-dir", "badges", "--servicenow-url", "https://example.service-now.com"},
+			wantErr: true,
+		},
+		"should accept --badge-dir writing the badge to --output-file": {
+			args: []string{"-l", "us-central1", "-o", "badge", "--badge-dir", "badges", "-O", "badges/project.json"},
+		},
+		"should accept --github-issue with --output-file": {
+			args: []string{"-l", "us-central1", "-o", "github", "--github-issue", "Vulnerabilities", "-O", "annotations.txt"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseFlags(tt.args, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseInterleaved(t *testing.T) {
+	tests := map[string]struct {
+		args           []string
+		wantPositional []string
+		wantVerbose    bool
+	}{
+		"should parse flags after positional arguments": {
+			args:           []string{"a.json", "-v", "b.json"},
+			wantPositional: []string{"a.json", "b.json"},
+			wantVerbose:    true,
+		},
+		"should treat everything after -- as positional": {
+			args:           []string{"a.json", "--", "-v", "b.json"},
+			wantPositional: []string{"a.json", "-v", "b.json"},
+		},
+		"should parse flags before --": {
+			args:           []string{"-v", "--", "-v"},
+			wantPositional: []string{"-v"},
+			wantVerbose:    true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			verbose := fs.Bool("v", false, "")
+			got, err := parseInterleaved(fs, tt.args)
+			if err != nil {
+				t.Fatalf("parseInterleaved() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.wantPositional, got); diff != "" {
+				t.Errorf("parseInterleaved() mismatch (-want +got):\n%s", diff)
+			}
+			if *verbose != tt.wantVerbose {
+				t.Errorf("verbose = %v, want %v", *verbose, tt.wantVerbose)
 			}
 		})
 	}
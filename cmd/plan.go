@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/hiro-o918/drydock"
+	"github.com/rs/zerolog/log"
+)
+
+// commandPlan is the subcommand that previews a scan without analyzing images.
+const commandPlan = "plan"
+
+// runPlanCommand parses the flags of the plan command and prints the plan.
+func runPlanCommand(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	_, scanner, err := setupScanner(ctx, commandPlan, args, stdout, stderr)
+	if err != nil {
+		return err
+	}
+	defer closeScanner(scanner)
+
+	return runPlan(ctx, scanner, stdout)
+}
+
+// runPlan resolves scan targets and prints the plan to w.
+func runPlan(ctx context.Context, scanner *drydock.Scanner, w io.Writer) error {
+	log.Info().Msg("Resolving scan targets...")
+
+	plan, err := scanner.Plan(ctx)
+	if plan != nil {
+		if werr := writePlan(w, plan); werr != nil {
+			return fmt.Errorf("failed to write scan plan: %w", werr)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("plan failed: %w", err)
+	}
+	return nil
+}
+
+// writePlan renders a human-readable scan plan.
+func writePlan(w io.Writer, plan *drydock.ScanPlan) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintf(tw, "Scan plan for project %s (%s)\n\n", plan.ProjectID, plan.Location)
+
+	_, _ = fmt.Fprintf(tw, "REPOSITORY\tIMAGES\n")
+	for _, repo := range plan.Repositories {
+		_, _ = fmt.Fprintf(tw, "%s\t%d\n", repo.Name, repo.ImageCount)
+	}
+	_, _ = fmt.Fprintf(tw, "\nRepositories:\t%d\n", len(plan.Repositories))
+	_, _ = fmt.Fprintf(tw, "Images to analyze:\t%d\n\n", plan.ImageCount)
+
+	calls := plan.EstimatedAPICalls
+	_, _ = fmt.Fprintf(tw, "Estimated API calls (excluding pagination):\n")
+	if calls.ListLocations > 0 {
+		_, _ = fmt.Fprintf(tw, "  artifactregistry ListLocations\t%d\n", calls.ListLocations)
+	}
+	_, _ = fmt.Fprintf(tw, "  artifactregistry ListRepositories\t%d\n", calls.ListRepositories)
+	_, _ = fmt.Fprintf(tw, "  artifactregistry ListDockerImages\t%d\n", calls.ListDockerImages)
+	_, _ = fmt.Fprintf(tw, "  containeranalysis ListOccurrences\t%d\n", calls.ListOccurrences)
+	_, _ = fmt.Fprintf(tw, "  Total\t%d\n", calls.Total)
+
+	return tw.Flush()
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	httppprof "net/http/pprof"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// addPprofFlags registers the flag serving the runtime profiles, for long and one-shot runs alike.
+func addPprofFlags(fs *flag.FlagSet, cfg *Config) {
+	// --pprof
+	fs.StringVar(&cfg.Pprof, "pprof", "", "Serve net/http/pprof profiles on this address (e.g. localhost:6060) while running")
+}
+
+// addProfileFlags registers the flags writing profiles of a one-shot run to files.
+func addProfileFlags(fs *flag.FlagSet, cfg *Config) {
+	// --cpu-profile
+	fs.StringVar(&cfg.CPUProfile, "cpu-profile", "", "Write a CPU profile of the run to this file")
+
+	// --mem-profile
+	fs.StringVar(&cfg.MemProfile, "mem-profile", "", "Write a heap profile to this file at the end of the run")
+}
+
+// pprofHandler serves the runtime profiles under /debug/pprof/, as net/http/pprof does on the
+// default mux, which drydock does not use.
+func pprofHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", httppprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", httppprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", httppprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", httppprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", httppprof.Trace)
+	return mux
+}
+
+// startProfiling starts the profiling enabled by the flags. The returned function stops it,
+// writing the profiles to their files; it must be called once the run is over.
+// Profiles are served on their own address, so that they are never exposed with the CloudEvents
+// endpoint of the serve command.
+func startProfiling(cfg *Config) (func() error, error) {
+	var srv *http.Server
+	if cfg.Pprof != "" {
+		srv = &http.Server{
+			Addr:              cfg.Pprof,
+			Handler:           pprofHandler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Warn().Err(err).Str("addr", cfg.Pprof).Msg("Failed to serve profiles")
+			}
+		}()
+		log.Info().Str("addr", cfg.Pprof).Msg("Serving profiles under /debug/pprof/")
+	}
+
+	var cpu *os.File
+	if cfg.CPUProfile != "" {
+		f, err := os.Create(cfg.CPUProfile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		cpu = f
+	}
+
+	return func() error {
+		var errs error
+		if srv != nil {
+			errs = errors.Join(errs, srv.Close())
+		}
+		if cpu != nil {
+			pprof.StopCPUProfile()
+			if err := cpu.Close(); err != nil {
+				errs = errors.Join(errs, fmt.Errorf("failed to write CPU profile: %w", err))
+			}
+		}
+		if cfg.MemProfile != "" {
+			errs = errors.Join(errs, writeHeapProfile(cfg.MemProfile))
+		}
+		return errs
+	}, nil
+}
+
+// writeHeapProfile writes the profile of the memory in use to path.
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create heap profile: %w", err)
+	}
+	// Up-to-date statistics of the memory in use
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write heap profile: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write heap profile: %w", err)
+	}
+	return nil
+}
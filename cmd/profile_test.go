@@ -0,0 +1,65 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStartProfiling(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &Config{
+		CPUProfile: filepath.Join(dir, "cpu.pprof"),
+		MemProfile: filepath.Join(dir, "mem.pprof"),
+	}
+
+	stop, err := startProfiling(cfg)
+	if err != nil {
+		t.Fatalf("startProfiling() error = %v", err)
+	}
+	if err := stop(); err != nil {
+		t.Fatalf("stop() error = %v", err)
+	}
+
+	for _, path := range []string{cfg.CPUProfile, cfg.MemProfile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("profile not written: %v", err)
+		}
+		if info.Size() == 0 {
+			t.Errorf("profile %s is empty", path)
+		}
+	}
+}
+
+func TestPprofHandler(t *testing.T) {
+	tests := map[string]struct {
+		path string
+		want int
+	}{
+		"should serve the index of profiles": {
+			path: "/debug/pprof/",
+			want: http.StatusOK,
+		},
+		"should serve a named profile": {
+			path: "/debug/pprof/goroutine?debug=1",
+			want: http.StatusOK,
+		},
+		"should serve nothing else": {
+			path: "/",
+			want: http.StatusNotFound,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			pprofHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/hiro-o918/drydock"
+)
+
+// ProgressFormat is the format of the progress stream.
+type ProgressFormat string
+
+const (
+	// ProgressFormatNone disables the progress stream.
+	ProgressFormatNone ProgressFormat = "none"
+	// ProgressFormatJSON writes one JSON progress event per line (NDJSON).
+	ProgressFormatJSON ProgressFormat = "json"
+)
+
+// String implements the flag.Value interface.
+func (f *ProgressFormat) String() string {
+	return string(*f)
+}
+
+// Set implements the flag.Value interface.
+func (f *ProgressFormat) Set(value string) error {
+	normalized := ProgressFormat(strings.ToLower(strings.TrimSpace(value)))
+	switch normalized {
+	case ProgressFormatNone, ProgressFormatJSON:
+		*f = normalized
+		return nil
+	default:
+		return fmt.Errorf("invalid progress format: %s (allowed: none, json)", value)
+	}
+}
+
+// newJSONProgress returns a progress function writing each event to w as a line of JSON.
+func newJSONProgress(w io.Writer) drydock.ProgressFunc {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	return func(event drydock.ProgressEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = enc.Encode(event)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+)
+
+func TestProgressFormat_Set(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    ProgressFormat
+		wantErr bool
+	}{
+		"should accept json": {
+			input: "JSON",
+			want:  ProgressFormatJSON,
+		},
+		"should accept none": {
+			input: "none",
+			want:  ProgressFormatNone,
+		},
+		"should reject unknown format": {
+			input:   "bar",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got ProgressFormat
+			err := got.Set(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Set() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Set() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewJSONProgress(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	events := []drydock.ProgressEvent{
+		{Type: drydock.ProgressResolved, Time: now, Image: "img"},
+		{Type: drydock.ProgressCompleted, Time: now, Image: "img", Vulnerabilities: 3},
+		{Type: drydock.ProgressFailed, Time: now, Image: "img", Error: errors.New("denied").Error()},
+	}
+
+	var buf bytes.Buffer
+	progress := newJSONProgress(&buf)
+	for _, e := range events {
+		progress(e)
+	}
+
+	var got []drydock.ProgressEvent
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var e drydock.ProgressEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("line is not a JSON object: %q: %v", scanner.Text(), err)
+		}
+		got = append(got, e)
+	}
+	if diff := cmp.Diff(events, got); diff != "" {
+		t.Errorf("progress events mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// commandReport is the subcommand that re-exports stored JSON reports in another format.
+const commandReport = "report"
+
+// runReport reads JSON reports from previous scans and exports them again in the chosen format.
+// It works offline: no API is called.
+func runReport(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("drydock "+commandReport, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	format := drydock.OutputFormatJSON
+	var outputFile string
+	fs.Var(&format, "output-format", fmt.Sprintf("Output format (%s)", strings.Join(formatNames(), ", ")))
+	fs.Var(&format, "o", "Output format (alias for --output-format)")
+	fs.StringVar(&outputFile, "output-file", "", "Write the report to this file instead of stdout (created atomically, with parent directories)")
+	fs.StringVar(&outputFile, "O", "", "Output file (alias for --output-file)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: drydock report [flags] <report.json> [...]")
+		_, _ = fmt.Fprintln(stderr, "  Re-export JSON reports of previous scans in another format (use - to read stdin)")
+		_, _ = fmt.Fprintln(stderr, "")
+		fs.PrintDefaults()
+	}
+
+	files, err := parseInterleaved(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fs.Usage()
+		return errors.New("report requires at least one report file")
+	}
+
+	var results []schemas.AnalyzeResult
+	for _, path := range files {
+		var r []schemas.AnalyzeResult
+		if path == "-" {
+			r, err = schemas.ReadResults(os.Stdin)
+			if err != nil {
+				err = fmt.Errorf("failed to read report from stdin: %w", err)
+			}
+		} else {
+			r, err = readReport(path)
+		}
+		if err != nil {
+			return err
+		}
+		results = append(results, r...)
+	}
+
+	var out io.Writer = stdout
+	var file *atomicFile
+	if outputFile != "" {
+		file, err = createAtomicFile(outputFile)
+		if err != nil {
+			return err
+		}
+		defer file.Discard()
+		out = file
+	}
+
+	exporter, err := drydock.NewExporter(format, out)
+	if err != nil {
+		return err
+	}
+	if err := exporter.Export(ctx, results); err != nil {
+		return fmt.Errorf("failed to export results: %w", err)
+	}
+	if file != nil {
+		return file.Commit()
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_Report(t *testing.T) {
+	dir := t.TempDir()
+	report := filepath.Join(dir, "report.json")
+	content := `[{"artifact":{"host":"us-docker.pkg.dev","projectID":"p","repositoryID":"r","imageName":"app"},
+		"vulnerabilities":[{"id":"CVE-1","packageName":"openssl","severity":"HIGH"}]}]`
+	if err := os.WriteFile(report, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		"should re-export as CSV": {
+			args: []string{report, "-o", "csv"},
+			want: []string{"CVE-1", "openssl"},
+		},
+		"should re-export as JSON by default": {
+			args: []string{report},
+			want: []string{`"schemaVersion": "1"`, `"id": "CVE-1"`},
+		},
+		"should require a report": {
+			args:    nil,
+			wantErr: true,
+		},
+		"should reject unknown formats": {
+			args:    []string{report, "-o", "docx"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := run(context.Background(), append([]string{"report"}, tt.args...), &out, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output does not contain %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
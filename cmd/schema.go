@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// commandSchema is the subcommand that prints the JSON Schema of the JSON output.
+const commandSchema = "schema"
+
+// runSchema writes the embedded JSON Schema to w.
+func runSchema(w io.Writer) error {
+	if _, err := w.Write(schemas.JSONSchema); err != nil {
+		return fmt.Errorf("failed to write schema: %w", err)
+	}
+	return nil
+}
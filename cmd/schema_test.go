@@ -0,0 +1,24 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+func TestRun_Schema(t *testing.T) {
+	var out bytes.Buffer
+	if err := run(context.Background(), []string{"schema"}, &out, io.Discard); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatalf("schema output is not valid JSON: %v", err)
+	}
+	if doc["$schema"] == nil {
+		t.Errorf("schema output has no $schema keyword: %s", out.String())
+	}
+}
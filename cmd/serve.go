@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/server"
+	"github.com/rs/zerolog/log"
+)
+
+// commandServe is the subcommand that scans images as Artifact Registry notifies pushes.
+const commandServe = "serve"
+
+// defaultServeAddr listens on $PORT, as set by Cloud Run, or 8080.
+func defaultServeAddr() string {
+	if port := os.Getenv("PORT"); port != "" {
+		return ":" + port
+	}
+	return ":8080"
+}
+
+// dashboardPath is where the dashboard is served, if enabled.
+const dashboardPath = "/dashboard/"
+
+// runServe serves CloudEvents from Eventarc and scans each pushed image, exporting the results to stdout.
+// With --dashboard, the results are also shown on a web dashboard.
+func runServe(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	cfg, err := parseCommandFlags(commandServe, args, stderr)
+	if err != nil {
+		return err
+	}
+	setupGlobalLogger(stderr, cfg.LogLevel, cfg.LogFormat, cfg.Color)
+	stopProfiling, err := startProfiling(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := stopProfiling(); err != nil {
+			log.Warn().Err(err).Msg("Failed to stop profiling")
+		}
+	}()
+
+	exp, err := newExporter(ctx, cfg, stdout)
+	if err != nil {
+		return err
+	}
+	var dashboard *server.Dashboard
+	if cfg.Dashboard {
+		var history drydock.HistoryStore
+		if cfg.History != "" {
+			history = drydock.NewFileHistoryStore(cfg.History)
+		}
+		dashboard = server.NewDashboard(history)
+		exp = exporter.NewTee(exp, dashboard)
+	}
+	scanner, err := newScannerWithExporter(ctx, cfg, stderr, exp)
+	if err != nil {
+		return err
+	}
+	defer closeScanner(scanner)
+
+	// Results of concurrent events must not interleave on stdout
+	var mu sync.Mutex
+	events := server.NewCloudEventHandler(func(ctx context.Context, target drydock.ImageTarget) error {
+		mu.Lock()
+		defer mu.Unlock()
+		err := scanner.ScanTargets(ctx, []drydock.ImageTarget{target}, cfg.scanOptions())
+		if err != nil && dashboard != nil {
+			dashboard.RecordFailure(target.Artifact, err)
+		}
+		return err
+	})
+
+	var handler http.Handler = events
+	if dashboard != nil {
+		mux := http.NewServeMux()
+		mux.Handle("/", events)
+		mux.Handle(dashboardPath, http.StripPrefix(strings.TrimSuffix(dashboardPath, "/"), dashboard))
+		handler = mux
+		log.Info().Str("path", dashboardPath).Msg("Serving dashboard")
+	}
+
+	srv := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Warn().Err(err).Msg("Failed to shut down server")
+		}
+	}()
+
+	log.Info().Str("addr", cfg.Addr).Msg("Serving CloudEvents...")
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/rs/zerolog/log"
+)
+
+// commandStale is the subcommand that reports untagged and stale images as cleanup candidates.
+const commandStale = "stale"
+
+// Reasons an image is a cleanup candidate
+const (
+	staleReasonUntagged = "untagged"
+	staleReasonOld      = "not-updated"
+)
+
+// addStaleFlags registers the flags of the stale command.
+func addStaleFlags(fs *flag.FlagSet, cfg *Config) {
+	// --repository / --image
+	fs.StringVar(&cfg.RepositoryFilter, "repository", "", "Only report repositories matching this glob pattern, e.g. 'prod-*'")
+	fs.StringVar(&cfg.ImageFilter, "image", "", "Only report images matching this glob pattern, e.g. 'api/*'")
+
+	// --older-than
+	fs.IntVar(&cfg.StaleDays, "older-than", 90, "Report digests not updated (pushed or re-tagged) in this many days")
+
+	// --no-vulnerabilities
+	fs.BoolVar(&cfg.NoVulnerabilities, "no-vulnerabilities", false, "Do not read vulnerability counts (Artifact Registry only)")
+
+	// --output-format / -o
+	fs.Var(&cfg.ListFormat, "output-format", "Output format (table, json, csv) (default: table)")
+	fs.Var(&cfg.ListFormat, "o", "Output format (alias for --output-format)")
+}
+
+// staleEntry is one cleanup candidate in the stale output.
+type staleEntry struct {
+	Location string                    `json:"location"`
+	Artifact schemas.ArtifactReference `json:"artifact"`
+	Image    *schemas.ImageMetadata    `json:"image,omitempty"`
+
+	// Reasons lists why the digest is a candidate: "untagged" and/or "not-updated"
+	Reasons []string `json:"reasons"`
+
+	// AgeDays is the number of days since the digest was last updated
+	AgeDays int `json:"ageDays"`
+
+	// Summary holds the vulnerability counts of the digest, unless they were not read
+	Summary *schemas.VulnerabilitySummary `json:"summary,omitempty"`
+
+	target drydock.ImageTarget
+}
+
+// runStale reports the untagged digests and those not updated in --older-than days, with their vulnerability counts.
+func runStale(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	cfg, err := parseCommandFlags(commandStale, args, stderr)
+	if err != nil {
+		return err
+	}
+	setupGlobalLogger(stderr, cfg.LogLevel, cfg.LogFormat, cfg.Color)
+
+	// Vulnerability counts are read at every severity, as JSON results
+	cfg.MinSeverity = schemas.SeverityUnspecified
+	cfg.OutputFormat = drydock.OutputFormatJSON
+	var buf bytes.Buffer
+	scanner, err := newScanner(ctx, cfg, stderr, &buf)
+	if err != nil {
+		return err
+	}
+	defer closeScanner(scanner)
+
+	log.Info().Int("older_than_days", cfg.StaleDays).Msg("Looking for stale images...")
+
+	var targets []drydock.ImageTarget
+	var errs error
+	for target, err := range scanner.ListImages(ctx) {
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		if matchesListFilters(cfg, target) {
+			targets = append(targets, target)
+		}
+	}
+	entries := findStale(targets, time.Now(), time.Duration(cfg.StaleDays)*24*time.Hour)
+
+	if !cfg.NoVulnerabilities && len(entries) > 0 {
+		candidates := make([]drydock.ImageTarget, 0, len(entries))
+		for _, e := range entries {
+			candidates = append(candidates, e.target)
+		}
+		if err := scanner.ScanTargets(ctx, candidates, drydock.ScanOptions{}); err != nil {
+			errs = errors.Join(errs, err)
+		}
+		results, err := schemas.ReadResults(&buf)
+		if err != nil {
+			return fmt.Errorf("failed to read scan results: %w", err)
+		}
+		addStaleSummaries(entries, results)
+	}
+
+	var werr error
+	switch cfg.ListFormat {
+	case ListFormatJSON:
+		werr = writeStaleJSON(stdout, entries)
+	case ListFormatCSV:
+		werr = writeStaleCSV(stdout, entries)
+	default:
+		werr = writeStaleTable(stdout, entries)
+	}
+	if werr != nil {
+		return fmt.Errorf("failed to write stale images: %w", werr)
+	}
+	if errs != nil {
+		return fmt.Errorf("stale report completed with partial errors:\n%w", errs)
+	}
+	return nil
+}
+
+// findStale returns the targets that are untagged or were last updated more than olderThan before now,
+// oldest first. Targets without registry metadata cannot be judged and are left out.
+func findStale(targets []drydock.ImageTarget, now time.Time, olderThan time.Duration) []staleEntry {
+	var entries []staleEntry
+	for _, t := range targets {
+		if t.Image == nil {
+			continue
+		}
+		var reasons []string
+		if len(t.Image.Tags) == 0 {
+			reasons = append(reasons, staleReasonUntagged)
+		}
+		age := now.Sub(t.Image.UpdateTime)
+		if !t.Image.UpdateTime.IsZero() && age > olderThan {
+			reasons = append(reasons, staleReasonOld)
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+		entry := staleEntry{Location: t.Location, Artifact: t.Artifact, Image: t.Image, Reasons: reasons, target: t}
+		if !t.Image.UpdateTime.IsZero() {
+			entry.AgeDays = int(age / (24 * time.Hour))
+		}
+		entries = append(entries, entry)
+	}
+	slices.SortStableFunc(entries, func(a, b staleEntry) int {
+		return cmp.Compare(b.AgeDays, a.AgeDays)
+	})
+	return entries
+}
+
+// addStaleSummaries attaches the vulnerability summary of each entry's digest from results.
+func addStaleSummaries(entries []staleEntry, results []schemas.AnalyzeResult) {
+	summaries := make(map[string]schemas.VulnerabilitySummary, len(results))
+	for _, r := range results {
+		if r.Artifact.Digest != nil {
+			summaries[*r.Artifact.Digest] = r.Summary
+		}
+	}
+	for i := range entries {
+		if entries[i].Artifact.Digest == nil {
+			continue
+		}
+		if s, ok := summaries[*entries[i].Artifact.Digest]; ok {
+			entries[i].Summary = &s
+		}
+	}
+}
+
+var staleHeader = []string{"REPOSITORY", "IMAGE", "DIGEST", "TAGS", "UPDATED", "AGE (DAYS)", "REASONS", "CRITICAL", "HIGH", "TOTAL"}
+
+// staleRow returns the printable columns of an entry. Counts are empty when they were not read.
+func staleRow(e staleEntry) []string {
+	row := listRow(listEntry{Location: e.Location, Artifact: e.Artifact, Image: e.Image})
+	row = append(row[:5], strconv.Itoa(e.AgeDays), strings.Join(e.Reasons, ","))
+	if e.Summary == nil {
+		return append(row, "", "", "")
+	}
+	return append(row,
+		strconv.Itoa(e.Summary.CountBySeverity[schemas.SeverityCritical]),
+		strconv.Itoa(e.Summary.CountBySeverity[schemas.SeverityHigh]),
+		strconv.Itoa(e.Summary.TotalCount),
+	)
+}
+
+// writeStaleTable prints an aligned table.
+func writeStaleTable(w io.Writer, entries []staleEntry) error {
+	if len(entries) == 0 {
+		_, err := fmt.Fprintln(w, "No stale images found.")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, strings.Join(staleHeader, "\t"))
+	for _, e := range entries {
+		_, _ = fmt.Fprintln(tw, strings.Join(staleRow(e), "\t"))
+	}
+	return tw.Flush()
+}
+
+// writeStaleCSV prints the entries as CSV with a header row.
+func writeStaleCSV(w io.Writer, entries []staleEntry) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, len(staleHeader))
+	for i, h := range staleHeader {
+		header[i] = strings.ToLower(h)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := cw.Write(staleRow(e)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeStaleJSON prints the entries as a JSON array.
+func writeStaleJSON(w io.Writer, entries []staleEntry) error {
+	if entries == nil {
+		entries = []staleEntry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestParseCommandFlags_Stale(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		want    *Config
+		wantErr bool
+	}{
+		"should default to 90 days and a table": {
+			args: []string{"-l", "us-central1"},
+			want: &Config{StaleDays: 90, ListFormat: ListFormatTable},
+		},
+		"should parse the age, filters and format": {
+			args: []string{"-l", "us-central1", "--older-than", "30", "--repository", "prod-*", "--no-vulnerabilities", "-o", "json"},
+			want: &Config{StaleDays: 30, RepositoryFilter: "prod-*", NoVulnerabilities: true, ListFormat: ListFormatJSON},
+		},
+		"should reject a negative age": {
+			args:    []string{"-l", "us-central1", "--older-than", "-1"},
+			wantErr: true,
+		},
+		"should reject the tagged filter": {
+			args:    []string{"-l", "us-central1", "--tagged"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseCommandFlags(commandStale, tt.args, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCommandFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			gotSubset := &Config{StaleDays: got.StaleDays, RepositoryFilter: got.RepositoryFilter, NoVulnerabilities: got.NoVulnerabilities, ListFormat: got.ListFormat}
+			if diff := cmp.Diff(tt.want, gotSubset); diff != "" {
+				t.Errorf("parseCommandFlags() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFindStale(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	target := func(digest string, updated time.Time, tags ...string) drydock.ImageTarget {
+		return drydock.ImageTarget{
+			Artifact: schemas.ArtifactReference{RepositoryID: "repo", ImageName: "app", Digest: utils.Ptr(digest)},
+			Image:    &schemas.ImageMetadata{Tags: tags, UpdateTime: updated},
+		}
+	}
+	fresh := target("sha256:fresh", now.AddDate(0, 0, -1), "latest")
+	old := target("sha256:old", now.AddDate(0, 0, -100), "v1")
+	dangling := target("sha256:dangling", now.AddDate(0, 0, -10))
+	abandoned := target("sha256:abandoned", now.AddDate(0, 0, -200))
+
+	tests := map[string]struct {
+		targets []drydock.ImageTarget
+		want    []staleEntry
+	}{
+		"should report untagged and old digests, oldest first": {
+			targets: []drydock.ImageTarget{fresh, dangling, old, abandoned},
+			want: []staleEntry{
+				{Artifact: abandoned.Artifact, Image: abandoned.Image, Reasons: []string{staleReasonUntagged, staleReasonOld}, AgeDays: 200, target: abandoned},
+				{Artifact: old.Artifact, Image: old.Image, Reasons: []string{staleReasonOld}, AgeDays: 100, target: old},
+				{Artifact: dangling.Artifact, Image: dangling.Image, Reasons: []string{staleReasonUntagged}, AgeDays: 10, target: dangling},
+			},
+		},
+		"should skip targets without metadata": {
+			targets: []drydock.ImageTarget{{Artifact: schemas.ArtifactReference{ImageName: "app"}}},
+			want:    nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := findStale(tt.targets, now, 90*24*time.Hour)
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(staleEntry{}), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("findStale() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWriteStale(t *testing.T) {
+	entries := []staleEntry{{
+		Location: "us-central1",
+		Artifact: schemas.ArtifactReference{
+			Host: "us-central1-docker.pkg.dev", ProjectID: "p", RepositoryID: "repo", ImageName: "app",
+			Digest: utils.Ptr("sha256:abc"),
+		},
+		Image:   &schemas.ImageMetadata{UpdateTime: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		Reasons: []string{staleReasonUntagged, staleReasonOld},
+		AgeDays: 150,
+	}}
+	addStaleSummaries(entries, []schemas.AnalyzeResult{{
+		Artifact: entries[0].Artifact,
+		Summary:  schemas.VulnerabilitySummary{TotalCount: 5, CountBySeverity: map[schemas.Severity]int{schemas.SeverityCritical: 1, schemas.SeverityHigh: 2}},
+	}})
+
+	tests := map[string]struct {
+		write func(io.Writer, []staleEntry) error
+		want  []string
+	}{
+		"should print a table": {
+			write: writeStaleTable,
+			want:  []string{"REASONS", "untagged,not-updated", "150"},
+		},
+		"should print CSV": {
+			write: writeStaleCSV,
+			want:  []string{"repository,image,digest,tags,updated,age (days),reasons,critical,high,total\n", `repo,app,sha256:abc,,2024-01-02T03:04:05Z,150,"untagged,not-updated",1,2,5`},
+		},
+		"should print JSON": {
+			write: writeStaleJSON,
+			want:  []string{`"ageDays": 150`, `"totalCount": 5`},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := tt.write(&out, entries); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output does not contain %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
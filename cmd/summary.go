@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// summarySeverities are the severities listed in the summary, most severe first.
+var summarySeverities = []schemas.Severity{
+	schemas.SeverityCritical, schemas.SeverityHigh, schemas.SeverityMedium,
+	schemas.SeverityLow, schemas.SeverityMinimal, schemas.SeverityUnspecified,
+}
+
+// newSummaryPrinter returns a summary function writing the summary of each scan to w, for humans.
+func newSummaryPrinter(w io.Writer) drydock.SummaryFunc {
+	var mu sync.Mutex
+	return func(summary drydock.ScanSummary) {
+		mu.Lock()
+		defer mu.Unlock()
+		writeSummary(w, summary)
+	}
+}
+
+// writeSummary writes the summary of a scan as aligned text.
+func writeSummary(w io.Writer, summary drydock.ScanSummary) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	title := "Scan summary"
+	if summary.Interrupted {
+		title += " (interrupted)"
+	}
+	_, _ = fmt.Fprintln(tw, title)
+	images := fmt.Sprintf("%d scanned, %d failed", summary.Images, summary.Failed)
+	if summary.NotAnalyzed > 0 {
+		images += fmt.Sprintf(", %d not analyzed", summary.NotAnalyzed)
+	}
+	_, _ = fmt.Fprintf(tw, "  Images:\t%s\n", images)
+	findings := fmt.Sprintf("%d", summary.Vulnerabilities)
+	if counts := severityCounts(summary.CountBySeverity); counts != "" {
+		findings += " (" + counts + ")"
+	}
+	_, _ = fmt.Fprintf(tw, "  Findings:\t%s, %d fixable\n", findings, summary.Fixable)
+	_, _ = fmt.Fprintf(tw, "  Duration:\t%s\n", summary.Duration.Round(time.Millisecond))
+	if len(summary.Worst) > 0 {
+		_, _ = fmt.Fprintln(tw, "  Worst images:")
+		for _, image := range summary.Worst {
+			_, _ = fmt.Fprintf(tw, "    %s\t%s\n", image.Image, severityCounts(image.Summary.CountBySeverity))
+		}
+	}
+	_ = tw.Flush()
+}
+
+// severityCounts formats the non-zero counts by severity, most severe first, e.g. "CRITICAL 1, LOW 3".
+func severityCounts(counts map[schemas.Severity]int) string {
+	var parts []string
+	for _, severity := range summarySeverities {
+		if n := counts[severity]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", severity, n))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
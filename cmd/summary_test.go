@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+func TestWriteSummary(t *testing.T) {
+	tests := map[string]struct {
+		summary drydock.ScanSummary
+		want    string
+	}{
+		"should list the totals and the worst images": {
+			summary: drydock.ScanSummary{
+				Images:          3,
+				Failed:          1,
+				Vulnerabilities: 6,
+				CountBySeverity: map[schemas.Severity]int{schemas.SeverityLow: 5, schemas.SeverityCritical: 1},
+				Fixable:         3,
+				Duration:        1500 * time.Millisecond,
+				Worst: []drydock.ImageSummary{
+					{Image: "api", Summary: schemas.VulnerabilitySummary{CountBySeverity: map[schemas.Severity]int{schemas.SeverityCritical: 1, schemas.SeverityLow: 2}}},
+					{Image: "web", Summary: schemas.VulnerabilitySummary{CountBySeverity: map[schemas.Severity]int{schemas.SeverityLow: 3}}},
+				},
+			},
+			want: "Scan summary\n" +
+				"  Images:    3 scanned, 1 failed\n" +
+				"  Findings:  6 (CRITICAL 1, LOW 5), 3 fixable\n" +
+				"  Duration:  1.5s\n" +
+				"  Worst images:\n" +
+				"    api  CRITICAL 1, LOW 2\n" +
+				"    web  LOW 3\n",
+		},
+		"should mark interrupted scans without findings": {
+			summary: drydock.ScanSummary{Images: 0, Duration: time.Second, Interrupted: true},
+			want: "Scan summary (interrupted)\n" +
+				"  Images:    0 scanned, 0 failed\n" +
+				"  Findings:  0, 0 fixable\n" +
+				"  Duration:  1s\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeSummary(&buf, tt.summary)
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("writeSummary() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
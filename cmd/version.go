@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+)
+
+// commandVersion is the subcommand that prints the version.
+const commandVersion = "version"
+
+// version is set at build time (-ldflags "-X main.version=...").
+var version = ""
+
+// buildVersion returns the version set at build time, or the module version for `go install` builds.
+func buildVersion() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+// runVersion writes the version to w.
+func runVersion(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "drydock %s\n", buildVersion()); err != nil {
+		return fmt.Errorf("failed to write version: %w", err)
+	}
+	return nil
+}
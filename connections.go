@@ -0,0 +1,142 @@
+package drydock
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// minKeepaliveTime is the shortest keepalive interval gRPC allows.
+const minKeepaliveTime = 10 * time.Second
+
+// ConnectionPolicy tunes the gRPC connections to Artifact Registry and Container Analysis.
+// Zero fields keep the defaults of the client libraries.
+type ConnectionPolicy struct {
+	// KeepaliveTime pings the server after this much time without activity, so that connections
+	// dropped by NATs, proxies or load balancers while idle are detected before the next call
+	// rather than failing it. At least 10s; Google front ends may close connections pinged more
+	// often than every few minutes while no call is in flight.
+	KeepaliveTime time.Duration
+
+	// KeepaliveTimeout closes the connection when a ping is not acknowledged in time (gRPC default: 20s)
+	KeepaliveTimeout time.Duration
+
+	// MaxReceiveMessageSize is the largest response accepted, in bytes (client library default: 2 GiB)
+	MaxReceiveMessageSize int
+
+	// PoolSize is the number of connections of each client, across which calls are balanced
+	PoolSize int
+}
+
+// validate checks that the policy can be applied.
+func (p ConnectionPolicy) validate() error {
+	if p.KeepaliveTime != 0 && p.KeepaliveTime < minKeepaliveTime {
+		return errors.New("keepalive time must be at least 10s")
+	}
+	if p.KeepaliveTimeout < 0 {
+		return errors.New("keepalive timeout must not be negative")
+	}
+	if p.KeepaliveTimeout > 0 && p.KeepaliveTime == 0 {
+		return errors.New("keepalive timeout requires a keepalive time")
+	}
+	if p.MaxReceiveMessageSize < 0 {
+		return errors.New("max receive message size must not be negative")
+	}
+	if p.PoolSize < 0 {
+		return errors.New("pool size must not be negative")
+	}
+	return nil
+}
+
+// clientOptions returns the options applying the policy to gRPC clients.
+func (p ConnectionPolicy) clientOptions() []option.ClientOption {
+	var opts []option.ClientOption
+	if p.KeepaliveTime > 0 {
+		opts = append(opts, option.WithGRPCDialOption(grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    p.KeepaliveTime,
+			Timeout: p.KeepaliveTimeout,
+			// Keep connections of long-running servers alive between scans too
+			PermitWithoutStream: true,
+		})))
+	}
+	if p.MaxReceiveMessageSize > 0 {
+		opts = append(opts, option.WithGRPCDialOption(grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(p.MaxReceiveMessageSize))))
+	}
+	if p.PoolSize > 0 {
+		opts = append(opts, option.WithGRPCConnectionPool(p.PoolSize))
+	}
+	return opts
+}
+
+// clientManager provides the options of the Google API clients created by a scanner, so that
+// they share one set of credentials: Application Default Credentials are looked up once, and
+// every client gets the same token source, so tokens are fetched and refreshed once for all of them
+// instead of once per client.
+//
+// Connections themselves cannot be shared, as Artifact Registry, Container Analysis and Cloud Run
+// are served from different endpoints; each of them gets a single client per scanner.
+type clientManager struct {
+	// base are the options of every client
+	base []option.ClientOption
+
+	// shareADC looks up Application Default Credentials for the clients, when they would
+	// otherwise each look them up
+	shareADC bool
+
+	// grpc are the options of gRPC clients only
+	grpc []option.ClientOption
+
+	// findCredentials looks up Application Default Credentials
+	findCredentials func(ctx context.Context, scopes ...string) (*google.Credentials, error)
+
+	// proxy, if set, is the proxy the token requests of the shared credentials are sent through
+	proxy *url.URL
+
+	once sync.Once
+	opts []option.ClientOption
+}
+
+// newClientManager returns a manager of clients created with opts, and the gRPC clients with
+// the connection policy. With shareADC, the clients share Application Default Credentials,
+// looked up on the first client.
+func newClientManager(opts []option.ClientOption, connection ConnectionPolicy, shareADC bool) *clientManager {
+	return &clientManager{
+		base:            opts,
+		grpc:            connection.clientOptions(),
+		shareADC:        shareADC,
+		findCredentials: google.FindDefaultCredentials,
+	}
+}
+
+// options returns the options of a new client.
+func (m *clientManager) options(ctx context.Context) []option.ClientOption {
+	m.once.Do(func() {
+		m.opts = m.base
+		if !m.shareADC {
+			return
+		}
+		if m.proxy != nil {
+			ctx = proxyContext(ctx, m.proxy)
+		}
+		creds, err := m.findCredentials(ctx, cloudPlatformScope)
+		if err != nil {
+			// Leave the lookup to each client, which reports the error when it is created
+			return
+		}
+		m.opts = append([]option.ClientOption{option.WithCredentials(creds)}, m.base...)
+	})
+	return m.opts
+}
+
+// grpcOptions returns the options of a new gRPC client.
+func (m *clientManager) grpcOptions(ctx context.Context) []option.ClientOption {
+	opts := m.options(ctx)
+	return append(opts[:len(opts):len(opts)], m.grpc...)
+}
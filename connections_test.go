@@ -0,0 +1,92 @@
+package drydock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hiro-o918/drydock"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+)
+
+func TestClientManager_Options(t *testing.T) {
+	base := []option.ClientOption{option.WithQuotaProject("p")}
+	creds := &google.Credentials{ProjectID: "p"}
+
+	tests := map[string]struct {
+		shareADC   bool
+		findErr    error
+		wantLookup int
+		wantLen    int
+	}{
+		"should look up ADC once for every client": {
+			shareADC:   true,
+			wantLookup: 1,
+			wantLen:    len(base) + 1,
+		},
+		"should leave the lookup to the clients when it fails": {
+			shareADC:   true,
+			findErr:    errors.New("could not find default credentials"),
+			wantLookup: 1,
+			wantLen:    len(base),
+		},
+		"should not look up ADC for configured clients": {
+			shareADC:   false,
+			wantLookup: 0,
+			wantLen:    len(base),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			lookups := 0
+			find := func(_ context.Context, scopes ...string) (*google.Credentials, error) {
+				lookups++
+				if len(scopes) != 1 || scopes[0] != "https://www.googleapis.com/auth/cloud-platform" {
+					t.Errorf("scopes = %v", scopes)
+				}
+				return creds, tt.findErr
+			}
+			opts := drydock.ExportClientOptions(context.Background(), base, tt.shareADC, find, 3)
+			if lookups != tt.wantLookup {
+				t.Errorf("lookups = %d, want %d", lookups, tt.wantLookup)
+			}
+			for i, o := range opts {
+				if len(o) != tt.wantLen {
+					t.Errorf("client %d got %d options, want %d", i, len(o), tt.wantLen)
+				}
+			}
+		})
+	}
+}
+
+func TestConnectionPolicy_ClientOptions(t *testing.T) {
+	tests := map[string]struct {
+		policy  drydock.ConnectionPolicy
+		wantLen int
+	}{
+		"should keep the library defaults": {
+			policy:  drydock.ConnectionPolicy{},
+			wantLen: 0,
+		},
+		"should set keepalive, message size and pool size": {
+			policy: drydock.ConnectionPolicy{
+				KeepaliveTime:         time.Minute,
+				KeepaliveTimeout:      10 * time.Second,
+				MaxReceiveMessageSize: 64 << 20,
+				PoolSize:              4,
+			},
+			wantLen: 3,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := drydock.ExportConnectionClientOptions(tt.policy); len(got) != tt.wantLen {
+				t.Errorf("clientOptions() returned %d options, want %d", len(got), tt.wantLen)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package drydock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"golang.org/x/oauth2/google"
+)
+
+// cloudPlatformScope is the OAuth scope requested for explicitly configured credentials.
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// supportedCredentialTypes are the credential file types accepted by LoadCredentialsFile.
+var supportedCredentialTypes = map[string]bool{
+	"service_account":                  true,
+	"authorized_user":                  true,
+	"external_account":                 true, // Workload Identity Federation
+	"external_account_authorized_user": true,
+	"impersonated_service_account":     true,
+}
+
+// LoadCredentialsFile reads Google credentials from a JSON file: a service account key,
+// a gcloud user credentials file, or an external_account (Workload Identity Federation) configuration.
+// Use it with WithCredentials in environments where Application Default Credentials are not set up.
+func LoadCredentialsFile(ctx context.Context, path string) (*google.Credentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+	return parseCredentialsJSON(ctx, data)
+}
+
+// parseCredentialsJSON validates the credential type before handing the JSON to the oauth2 library,
+// which would otherwise also accept OAuth client configurations that cannot be used non-interactively.
+func parseCredentialsJSON(ctx context.Context, data []byte) (*google.Credentials, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("invalid credentials file: %w", err)
+	}
+	if !supportedCredentialTypes[header.Type] {
+		return nil, fmt.Errorf("unsupported credentials type: %q", header.Type)
+	}
+
+	creds, err := google.CredentialsFromJSON(ctx, data, cloudPlatformScope)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials file: %w", err)
+	}
+	return creds, nil
+}
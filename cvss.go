@@ -0,0 +1,147 @@
+package drydock
+
+import (
+	"strings"
+
+	"github.com/hiro-o918/drydock/schemas"
+	grafeaspb "google.golang.org/genproto/googleapis/grafeas/v1"
+)
+
+// CVSS versions reported in schemas.Vulnerability.CVSSVersion.
+const (
+	cvssVersion2 = "2"
+	cvssVersion3 = "3"
+)
+
+// convertCVSS extracts the CVSS version, vector string and sub-scores from a vulnerability occurrence.
+// The CVSS block matching the occurrence's CVSS version is used; if the version is unspecified,
+// CVSS v3 data is preferred over v2.
+func convertCVSS(vuln *grafeaspb.VulnerabilityOccurrence) (version string, vector string, details *schemas.CVSSDetails) {
+	var cvss *grafeaspb.CVSS
+	switch vuln.GetCvssVersion() {
+	case grafeaspb.CVSSVersion_CVSS_VERSION_3:
+		cvss, version = vuln.GetCvssv3(), cvssVersion3
+	case grafeaspb.CVSSVersion_CVSS_VERSION_2:
+		cvss, version = vuln.GetCvssV2(), cvssVersion2
+	default:
+		if vuln.GetCvssv3() != nil {
+			cvss, version = vuln.GetCvssv3(), cvssVersion3
+		} else if vuln.GetCvssV2() != nil {
+			cvss, version = vuln.GetCvssV2(), cvssVersion2
+		}
+	}
+	if cvss == nil {
+		return "", "", nil
+	}
+
+	if version == cvssVersion3 {
+		vector = cvssV3Vector(cvss)
+	} else {
+		vector = cvssV2Vector(cvss)
+	}
+
+	return version, vector, &schemas.CVSSDetails{
+		BaseScore:           cvss.GetBaseScore(),
+		ExploitabilityScore: cvss.GetExploitabilityScore(),
+		ImpactScore:         cvss.GetImpactScore(),
+	}
+}
+
+// cvssV3Vector reconstructs a CVSS v3.1 vector string (e.g., "CVSS:3.1/AV:N/AC:L/...").
+// Grafeas does not report the minor version, so v3.1 notation is assumed.
+// It returns an empty string if any base metric is missing.
+func cvssV3Vector(c *grafeaspb.CVSS) string {
+	metrics := []struct {
+		name  string
+		value string
+	}{
+		{"AV", map[grafeaspb.CVSS_AttackVector]string{
+			grafeaspb.CVSS_ATTACK_VECTOR_NETWORK:  "N",
+			grafeaspb.CVSS_ATTACK_VECTOR_ADJACENT: "A",
+			grafeaspb.CVSS_ATTACK_VECTOR_LOCAL:    "L",
+			grafeaspb.CVSS_ATTACK_VECTOR_PHYSICAL: "P",
+		}[c.GetAttackVector()]},
+		{"AC", map[grafeaspb.CVSS_AttackComplexity]string{
+			grafeaspb.CVSS_ATTACK_COMPLEXITY_LOW:  "L",
+			grafeaspb.CVSS_ATTACK_COMPLEXITY_HIGH: "H",
+		}[c.GetAttackComplexity()]},
+		{"PR", map[grafeaspb.CVSS_PrivilegesRequired]string{
+			grafeaspb.CVSS_PRIVILEGES_REQUIRED_NONE: "N",
+			grafeaspb.CVSS_PRIVILEGES_REQUIRED_LOW:  "L",
+			grafeaspb.CVSS_PRIVILEGES_REQUIRED_HIGH: "H",
+		}[c.GetPrivilegesRequired()]},
+		{"UI", map[grafeaspb.CVSS_UserInteraction]string{
+			grafeaspb.CVSS_USER_INTERACTION_NONE:     "N",
+			grafeaspb.CVSS_USER_INTERACTION_REQUIRED: "R",
+		}[c.GetUserInteraction()]},
+		{"S", map[grafeaspb.CVSS_Scope]string{
+			grafeaspb.CVSS_SCOPE_UNCHANGED: "U",
+			grafeaspb.CVSS_SCOPE_CHANGED:   "C",
+		}[c.GetScope()]},
+		{"C", cvssV3Impact(c.GetConfidentialityImpact())},
+		{"I", cvssV3Impact(c.GetIntegrityImpact())},
+		{"A", cvssV3Impact(c.GetAvailabilityImpact())},
+	}
+
+	parts := []string{"CVSS:3.1"}
+	for _, m := range metrics {
+		if m.value == "" {
+			return ""
+		}
+		parts = append(parts, m.name+":"+m.value)
+	}
+	return strings.Join(parts, "/")
+}
+
+func cvssV3Impact(i grafeaspb.CVSS_Impact) string {
+	return map[grafeaspb.CVSS_Impact]string{
+		grafeaspb.CVSS_IMPACT_HIGH: "H",
+		grafeaspb.CVSS_IMPACT_LOW:  "L",
+		grafeaspb.CVSS_IMPACT_NONE: "N",
+	}[i]
+}
+
+// cvssV2Vector reconstructs a CVSS v2 base vector string (e.g., "AV:N/AC:L/Au:N/C:P/I:P/A:P").
+// It returns an empty string if any base metric is missing.
+func cvssV2Vector(c *grafeaspb.CVSS) string {
+	metrics := []struct {
+		name  string
+		value string
+	}{
+		{"AV", map[grafeaspb.CVSS_AttackVector]string{
+			grafeaspb.CVSS_ATTACK_VECTOR_NETWORK:  "N",
+			grafeaspb.CVSS_ATTACK_VECTOR_ADJACENT: "A",
+			grafeaspb.CVSS_ATTACK_VECTOR_LOCAL:    "L",
+		}[c.GetAttackVector()]},
+		{"AC", map[grafeaspb.CVSS_AttackComplexity]string{
+			grafeaspb.CVSS_ATTACK_COMPLEXITY_LOW:    "L",
+			grafeaspb.CVSS_ATTACK_COMPLEXITY_MEDIUM: "M",
+			grafeaspb.CVSS_ATTACK_COMPLEXITY_HIGH:   "H",
+		}[c.GetAttackComplexity()]},
+		{"Au", map[grafeaspb.CVSS_Authentication]string{
+			grafeaspb.CVSS_AUTHENTICATION_MULTIPLE: "M",
+			grafeaspb.CVSS_AUTHENTICATION_SINGLE:   "S",
+			grafeaspb.CVSS_AUTHENTICATION_NONE:     "N",
+		}[c.GetAuthentication()]},
+		{"C", cvssV2Impact(c.GetConfidentialityImpact())},
+		{"I", cvssV2Impact(c.GetIntegrityImpact())},
+		{"A", cvssV2Impact(c.GetAvailabilityImpact())},
+	}
+
+	parts := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		if m.value == "" {
+			return ""
+		}
+		parts = append(parts, m.name+":"+m.value)
+	}
+	return strings.Join(parts, "/")
+}
+
+func cvssV2Impact(i grafeaspb.CVSS_Impact) string {
+	return map[grafeaspb.CVSS_Impact]string{
+		grafeaspb.CVSS_IMPACT_NONE:     "N",
+		grafeaspb.CVSS_IMPACT_PARTIAL:  "P",
+		grafeaspb.CVSS_IMPACT_COMPLETE: "C",
+	}[i]
+}
@@ -0,0 +1,113 @@
+package drydock_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+	grafeaspb "google.golang.org/genproto/googleapis/grafeas/v1"
+)
+
+func TestConvertCVSS(t *testing.T) {
+	v2 := &grafeaspb.CVSS{
+		BaseScore:             5.0,
+		ExploitabilityScore:   10.0,
+		ImpactScore:           2.9,
+		AttackVector:          grafeaspb.CVSS_ATTACK_VECTOR_NETWORK,
+		AttackComplexity:      grafeaspb.CVSS_ATTACK_COMPLEXITY_MEDIUM,
+		Authentication:        grafeaspb.CVSS_AUTHENTICATION_NONE,
+		ConfidentialityImpact: grafeaspb.CVSS_IMPACT_PARTIAL,
+		IntegrityImpact:       grafeaspb.CVSS_IMPACT_NONE,
+		AvailabilityImpact:    grafeaspb.CVSS_IMPACT_COMPLETE,
+	}
+	v3 := &grafeaspb.CVSS{
+		BaseScore:             9.8,
+		ExploitabilityScore:   3.9,
+		ImpactScore:           5.9,
+		AttackVector:          grafeaspb.CVSS_ATTACK_VECTOR_NETWORK,
+		AttackComplexity:      grafeaspb.CVSS_ATTACK_COMPLEXITY_LOW,
+		PrivilegesRequired:    grafeaspb.CVSS_PRIVILEGES_REQUIRED_NONE,
+		UserInteraction:       grafeaspb.CVSS_USER_INTERACTION_NONE,
+		Scope:                 grafeaspb.CVSS_SCOPE_UNCHANGED,
+		ConfidentialityImpact: grafeaspb.CVSS_IMPACT_HIGH,
+		IntegrityImpact:       grafeaspb.CVSS_IMPACT_HIGH,
+		AvailabilityImpact:    grafeaspb.CVSS_IMPACT_HIGH,
+	}
+
+	type want struct {
+		version string
+		vector  string
+		details *schemas.CVSSDetails
+	}
+
+	tests := map[string]struct {
+		input *grafeaspb.VulnerabilityOccurrence
+		want  want
+	}{
+		"should return empty values when no CVSS data is present": {
+			input: &grafeaspb.VulnerabilityOccurrence{},
+			want:  want{},
+		},
+		"should use CVSSv3 data when the version is 3": {
+			input: &grafeaspb.VulnerabilityOccurrence{
+				CvssVersion: grafeaspb.CVSSVersion_CVSS_VERSION_3,
+				Cvssv3:      v3,
+				CvssV2:      v2,
+			},
+			want: want{
+				version: "3",
+				vector:  "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+				details: &schemas.CVSSDetails{BaseScore: 9.8, ExploitabilityScore: 3.9, ImpactScore: 5.9},
+			},
+		},
+		"should use CVSSv2 data when the version is 2": {
+			input: &grafeaspb.VulnerabilityOccurrence{
+				CvssVersion: grafeaspb.CVSSVersion_CVSS_VERSION_2,
+				Cvssv3:      v3,
+				CvssV2:      v2,
+			},
+			want: want{
+				version: "2",
+				vector:  "AV:N/AC:M/Au:N/C:P/I:N/A:C",
+				details: &schemas.CVSSDetails{BaseScore: 5.0, ExploitabilityScore: 10.0, ImpactScore: 2.9},
+			},
+		},
+		"should prefer CVSSv3 data when the version is unspecified": {
+			input: &grafeaspb.VulnerabilityOccurrence{Cvssv3: v3, CvssV2: v2},
+			want: want{
+				version: "3",
+				vector:  "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+				details: &schemas.CVSSDetails{BaseScore: 9.8, ExploitabilityScore: 3.9, ImpactScore: 5.9},
+			},
+		},
+		"should fall back to CVSSv2 data when the version is unspecified and v3 is missing": {
+			input: &grafeaspb.VulnerabilityOccurrence{CvssV2: v2},
+			want: want{
+				version: "2",
+				vector:  "AV:N/AC:M/Au:N/C:P/I:N/A:C",
+				details: &schemas.CVSSDetails{BaseScore: 5.0, ExploitabilityScore: 10.0, ImpactScore: 2.9},
+			},
+		},
+		"should omit the vector when a base metric is unspecified": {
+			input: &grafeaspb.VulnerabilityOccurrence{
+				CvssVersion: grafeaspb.CVSSVersion_CVSS_VERSION_3,
+				Cvssv3:      &grafeaspb.CVSS{BaseScore: 7.0, AttackVector: grafeaspb.CVSS_ATTACK_VECTOR_LOCAL},
+			},
+			want: want{
+				version: "3",
+				details: &schemas.CVSSDetails{BaseScore: 7.0},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			version, vector, details := drydock.ExportConvertCVSS(tt.input)
+			got := want{version: version, vector: vector, details: details}
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("ConvertCVSS() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package drydock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// BaselineSource loads the findings known before a scan (see NewDeltaExporter).
+type BaselineSource func(ctx context.Context) (*schemas.Baseline, error)
+
+// ReportBaseline returns a source reading the baseline from a JSON report of a previous scan.
+// A missing report is an empty baseline, so that the first run reports every finding.
+func ReportBaseline(path string) BaselineSource {
+	return func(_ context.Context) (*schemas.Baseline, error) {
+		f, err := os.Open(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return schemas.NewBaseline(nil), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open baseline: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		results, err := schemas.ReadResults(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read baseline %s: %w", path, err)
+		}
+		return schemas.NewBaseline(results), nil
+	}
+}
+
+// HistoryBaseline returns a source reading the baseline from a history store: the open findings of the
+// previous runs. Used with WithHistory on the same store, it loads the history before the scan saves it.
+func HistoryBaseline(store HistoryStore) BaselineSource {
+	return func(ctx context.Context) (*schemas.Baseline, error) {
+		history, err := store.Load(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load baseline: %w", err)
+		}
+		return history.Baseline(), nil
+	}
+}
+
+// DeltaExporter exports only the findings that are not in a baseline, through another exporter
+// in whatever format it writes, so that alerting integrations notify about changes rather than
+// re-sending every known finding. Images without new findings are left out, and the summaries
+// count only the new findings.
+type DeltaExporter struct {
+	next   Exporter
+	source BaselineSource
+
+	// baseline is loaded by Export or Begin
+	baseline *schemas.Baseline
+
+	// buffered are the results streamed since Begin, if next does not support streaming
+	buffered []schemas.AnalyzeResult
+}
+
+var _ StreamExporter = (*DeltaExporter)(nil)
+
+// NewDeltaExporter creates an exporter passing the new findings, compared to the baseline of source, to next.
+func NewDeltaExporter(next Exporter, source BaselineSource) *DeltaExporter {
+	return &DeltaExporter{
+		next:   next,
+		source: source,
+	}
+}
+
+// Export exports the new findings of results
+func (e *DeltaExporter) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
+	baseline, err := e.source(ctx)
+	if err != nil {
+		return err
+	}
+	var delta []schemas.AnalyzeResult
+	for _, r := range results {
+		if r, ok := deltaResult(baseline, r); ok {
+			delta = append(delta, r)
+		}
+	}
+	return e.next.Export(ctx, delta)
+}
+
+// Begin loads the baseline and begins streaming on the next exporter, if it supports streaming
+func (e *DeltaExporter) Begin(ctx context.Context) error {
+	baseline, err := e.source(ctx)
+	if err != nil {
+		return err
+	}
+	e.baseline = baseline
+	e.buffered = nil
+	if s, ok := e.next.(StreamExporter); ok {
+		return s.Begin(ctx)
+	}
+	return nil
+}
+
+// ExportOne exports the new findings of a single result
+func (e *DeltaExporter) ExportOne(ctx context.Context, result schemas.AnalyzeResult) error {
+	if e.baseline == nil {
+		return errors.New("delta exporter: ExportOne called before Begin")
+	}
+	result, ok := deltaResult(e.baseline, result)
+	if !ok {
+		return nil
+	}
+	if s, ok := e.next.(StreamExporter); ok {
+		return s.ExportOne(ctx, result)
+	}
+	e.buffered = append(e.buffered, result)
+	return nil
+}
+
+// End ends streaming on the next exporter, or exports the buffered results to it
+func (e *DeltaExporter) End(ctx context.Context) error {
+	if s, ok := e.next.(StreamExporter); ok {
+		return s.End(ctx)
+	}
+	buffered := e.buffered
+	e.buffered = nil
+	return e.next.Export(ctx, buffered)
+}
+
+// deltaResult returns result with only its new findings, or false if it has none.
+func deltaResult(baseline *schemas.Baseline, result schemas.AnalyzeResult) (schemas.AnalyzeResult, bool) {
+	vulns := baseline.New(result)
+	if len(vulns) == 0 {
+		return result, false
+	}
+	result.Vulnerabilities = vulns
+	result.Summary = buildSummary(vulns)
+	for _, v := range vulns {
+		if v.SLABreached {
+			result.Summary.SLABreachCount++
+		}
+	}
+	// Resolved and suppressed findings are not news either
+	result.Resolved = nil
+	result.Suppressed = nil
+	ids := make(map[string]bool, len(vulns))
+	for _, v := range vulns {
+		ids[v.ID] = true
+	}
+	result.Remediations = slices.DeleteFunc(slices.Clone(result.Remediations), func(r schemas.Remediation) bool {
+		return !slices.ContainsFunc(r.VulnerabilityIDs, func(id string) bool { return ids[id] })
+	})
+	return result, true
+}
@@ -0,0 +1,182 @@
+package drydock_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+// collectExporter records exported results, optionally as a StreamExporter.
+type collectExporter struct {
+	results []schemas.AnalyzeResult
+	began   bool
+	ended   bool
+}
+
+func (e *collectExporter) Export(_ context.Context, results []schemas.AnalyzeResult) error {
+	e.results = append(e.results, results...)
+	return nil
+}
+
+type collectStreamExporter struct{ collectExporter }
+
+func (e *collectStreamExporter) Begin(_ context.Context) error { e.began = true; return nil }
+
+func (e *collectStreamExporter) ExportOne(_ context.Context, result schemas.AnalyzeResult) error {
+	e.results = append(e.results, result)
+	return nil
+}
+
+func (e *collectStreamExporter) End(_ context.Context) error { e.ended = true; return nil }
+
+func TestDeltaExporter(t *testing.T) {
+	ctx := context.Background()
+	artifact := func(image string) schemas.ArtifactReference {
+		return schemas.ArtifactReference{
+			Host: "us-docker.pkg.dev", ProjectID: "p", RepositoryID: "r", ImageName: image,
+			Tag: utils.Ptr("latest"), Digest: utils.Ptr("sha256:" + image),
+		}
+	}
+	openssl := schemas.Vulnerability{ID: "CVE-1", PackageName: "openssl", Severity: schemas.SeverityHigh}
+	curl := schemas.Vulnerability{ID: "CVE-2", PackageName: "curl", Severity: schemas.SeverityCritical, SLABreached: true}
+	baseline := []schemas.AnalyzeResult{
+		{Artifact: artifact("app"), Vulnerabilities: []schemas.Vulnerability{openssl}},
+		{Artifact: artifact("worker"), Vulnerabilities: []schemas.Vulnerability{openssl}},
+	}
+	results := []schemas.AnalyzeResult{
+		{
+			Artifact:        artifact("app"),
+			Vulnerabilities: []schemas.Vulnerability{openssl, curl},
+			Summary:         schemas.VulnerabilitySummary{TotalCount: 2, SLABreachCount: 1},
+			Remediations: []schemas.Remediation{
+				{PackageName: "openssl", VulnerabilityIDs: []string{"CVE-1"}},
+				{PackageName: "curl", VulnerabilityIDs: []string{"CVE-2"}},
+			},
+			Resolved: []schemas.Vulnerability{{ID: "CVE-9", PackageName: "zlib"}},
+		},
+		{Artifact: artifact("worker"), Vulnerabilities: []schemas.Vulnerability{openssl}},
+	}
+	want := []schemas.AnalyzeResult{
+		{
+			Artifact:        artifact("app"),
+			Vulnerabilities: []schemas.Vulnerability{curl},
+			Summary: schemas.VulnerabilitySummary{
+				TotalCount:         1,
+				SLABreachCount:     1,
+				CountBySeverity:    map[schemas.Severity]int{schemas.SeverityCritical: 1},
+				CountByPackageType: map[string]int{schemas.PackageTypeUnknown: 1},
+				CountByFixState:    map[schemas.FixState]int{schemas.FixStateUnknown: 1},
+			},
+			Remediations: []schemas.Remediation{
+				{PackageName: "curl", VulnerabilityIDs: []string{"CVE-2"}},
+			},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp, err := drydock.NewExporter(drydock.OutputFormatJSON, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := exp.Export(ctx, baseline); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should export only new findings", func(t *testing.T) {
+		next := &collectExporter{}
+		if err := drydock.NewDeltaExporter(next, drydock.ReportBaseline(path)).Export(ctx, results); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		if diff := cmp.Diff(want, next.results); diff != "" {
+			t.Errorf("Export() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should stream new findings to a stream exporter", func(t *testing.T) {
+		next := &collectStreamExporter{}
+		delta := drydock.NewDeltaExporter(next, drydock.ReportBaseline(path))
+		if err := delta.Begin(ctx); err != nil {
+			t.Fatalf("Begin() error = %v", err)
+		}
+		for _, r := range results {
+			if err := delta.ExportOne(ctx, r); err != nil {
+				t.Fatalf("ExportOne() error = %v", err)
+			}
+		}
+		if err := delta.End(ctx); err != nil {
+			t.Fatalf("End() error = %v", err)
+		}
+		if !next.began || !next.ended {
+			t.Errorf("Begin/End not forwarded: began = %v, ended = %v", next.began, next.ended)
+		}
+		if diff := cmp.Diff(want, next.results); diff != "" {
+			t.Errorf("streamed results mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should buffer streamed findings for a non-stream exporter", func(t *testing.T) {
+		next := &collectExporter{}
+		delta := drydock.NewDeltaExporter(next, drydock.ReportBaseline(path))
+		if err := delta.Begin(ctx); err != nil {
+			t.Fatalf("Begin() error = %v", err)
+		}
+		for _, r := range results {
+			if err := delta.ExportOne(ctx, r); err != nil {
+				t.Fatalf("ExportOne() error = %v", err)
+			}
+		}
+		if len(next.results) != 0 {
+			t.Errorf("results exported before End: %d", len(next.results))
+		}
+		if err := delta.End(ctx); err != nil {
+			t.Fatalf("End() error = %v", err)
+		}
+		if diff := cmp.Diff(want, next.results); diff != "" {
+			t.Errorf("buffered results mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should export every finding when the baseline report is missing", func(t *testing.T) {
+		next := &collectExporter{}
+		source := drydock.ReportBaseline(filepath.Join(t.TempDir(), "missing.json"))
+		if err := drydock.NewDeltaExporter(next, source).Export(ctx, results[1:]); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		if diff := cmp.Diff(results[1:], next.results, cmpopts.IgnoreFields(schemas.AnalyzeResult{}, "Summary")); diff != "" {
+			t.Errorf("Export() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should use the open findings of a history as baseline", func(t *testing.T) {
+		store := drydock.NewFileHistoryStore(filepath.Join(t.TempDir(), "history.json"))
+		var history schemas.History
+		for _, r := range baseline {
+			history.Observe(&r, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+		}
+		if err := store.Save(ctx, &history); err != nil {
+			t.Fatal(err)
+		}
+		next := &collectExporter{}
+		if err := drydock.NewDeltaExporter(next, drydock.HistoryBaseline(store)).Export(ctx, results); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		if diff := cmp.Diff(want, next.results); diff != "" {
+			t.Errorf("Export() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
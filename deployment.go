@@ -0,0 +1,265 @@
+package drydock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/hiro-o918/drydock/schemas"
+	"google.golang.org/api/option"
+	run "google.golang.org/api/run/v1"
+	htransport "google.golang.org/api/transport/http"
+)
+
+// Deployment is an image digest running in a workload.
+type Deployment struct {
+	// Digest is the digest of the running image (e.g., "sha256:...")
+	Digest string
+
+	// Workload is the workload running it
+	Workload schemas.Workload
+}
+
+// DeploymentSource discovers the images running in workloads (see WithDeploymentSource).
+type DeploymentSource interface {
+	Deployments(ctx context.Context) ([]Deployment, error)
+}
+
+// discoverDeployments indexes the workloads of all sources by image digest.
+// Errors of every source are joined.
+func discoverDeployments(ctx context.Context, sources []DeploymentSource) (map[string][]schemas.Workload, error) {
+	index := make(map[string][]schemas.Workload)
+	var errs []error
+	for _, src := range sources {
+		deployments, err := src.Deployments(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, d := range deployments {
+			if !slices.Contains(index[d.Digest], d.Workload) {
+				index[d.Digest] = append(index[d.Digest], d.Workload)
+			}
+		}
+	}
+	return index, errors.Join(errs...)
+}
+
+// digestOf returns the digest of an image reference such as "us-docker.pkg.dev/p/r/app@sha256:...",
+// or "" if it is not pinned by digest.
+func digestOf(image string) string {
+	_, digest, ok := strings.Cut(image, "@")
+	if !ok || schemas.ValidateDigest(digest) != nil {
+		return ""
+	}
+	return digest
+}
+
+// ============================================================================
+// Cloud Run
+// ============================================================================
+
+// cloudRunIngressAnnotation holds the ingress setting of a Cloud Run service; when absent, it is "all".
+const cloudRunIngressAnnotation = "run.googleapis.com/ingress"
+
+// CloudRunSource discovers the images serving traffic in the Cloud Run services of a project.
+type CloudRunSource struct {
+	client    *http.Client
+	service   *run.APIService
+	projectID string
+	regions   []string
+}
+
+var _ DeploymentSource = (*CloudRunSource)(nil)
+
+// NewCloudRunSource creates a source for the Cloud Run services of projectID in the given regions.
+func NewCloudRunSource(ctx context.Context, projectID string, regions []string, opts ...option.ClientOption) (*CloudRunSource, error) {
+	if len(regions) == 0 {
+		return nil, errors.New("at least one Cloud Run region is required")
+	}
+	// The HTTP client is created here, so that Close can release its connections
+	client, _, err := htransport.NewClient(ctx, append([]option.ClientOption{option.WithScopes(cloudPlatformScope)}, opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Run client: %w", err)
+	}
+	service, err := run.NewService(ctx, append(slices.Clone(opts), option.WithHTTPClient(client))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Run client: %w", err)
+	}
+	return &CloudRunSource{client: client, service: service, projectID: projectID, regions: regions}, nil
+}
+
+// Close releases the connections of the Cloud Run client.
+func (s *CloudRunSource) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// Deployments implements DeploymentSource. Only revisions receiving traffic are reported.
+func (s *CloudRunSource) Deployments(ctx context.Context) ([]Deployment, error) {
+	var deployments []Deployment
+	for _, region := range s.regions {
+		parent := fmt.Sprintf("projects/%s/locations/%s", s.projectID, region)
+
+		digests, err := s.revisionDigests(ctx, parent)
+		if err != nil {
+			return nil, fmt.Errorf("listing Cloud Run revisions in %s: %w", region, classifyAPIError(err))
+		}
+
+		var next string
+		for {
+			call := s.service.Projects.Locations.Services.List(parent).Context(ctx)
+			if next != "" {
+				call = call.Continue(next)
+			}
+			resp, err := call.Do()
+			if err != nil {
+				return nil, fmt.Errorf("listing Cloud Run services in %s: %w", region, classifyAPIError(err))
+			}
+			for _, svc := range resp.Items {
+				deployments = append(deployments, serviceDeployments(svc, region, digests)...)
+			}
+			if resp.Metadata == nil || resp.Metadata.Continue == "" {
+				break
+			}
+			next = resp.Metadata.Continue
+		}
+	}
+	return deployments, nil
+}
+
+// revisionDigests maps the revisions under parent to the digests of their images.
+func (s *CloudRunSource) revisionDigests(ctx context.Context, parent string) (map[string]string, error) {
+	digests := make(map[string]string)
+	var next string
+	for {
+		call := s.service.Projects.Locations.Revisions.List(parent).Context(ctx)
+		if next != "" {
+			call = call.Continue(next)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, rev := range resp.Items {
+			if rev.Metadata == nil || rev.Status == nil {
+				continue
+			}
+			if digest := digestOf(rev.Status.ImageDigest); digest != "" {
+				digests[rev.Metadata.Name] = digest
+			}
+		}
+		if resp.Metadata == nil || resp.Metadata.Continue == "" {
+			return digests, nil
+		}
+		next = resp.Metadata.Continue
+	}
+}
+
+// serviceDeployments returns the images of the revisions of svc that receive traffic.
+func serviceDeployments(svc *run.Service, region string, digests map[string]string) []Deployment {
+	if svc.Metadata == nil || svc.Status == nil {
+		return nil
+	}
+	ingress := svc.Metadata.Annotations[cloudRunIngressAnnotation]
+	workload := schemas.Workload{
+		Platform:       schemas.PlatformCloudRun,
+		Name:           svc.Metadata.Name,
+		Location:       region,
+		InternetFacing: ingress == "" || ingress == "all",
+	}
+
+	var deployments []Deployment
+	for _, t := range svc.Status.Traffic {
+		digest, ok := digests[t.RevisionName]
+		if t.Percent <= 0 || !ok {
+			continue
+		}
+		deployments = append(deployments, Deployment{Digest: digest, Workload: workload})
+	}
+	return deployments
+}
+
+// ============================================================================
+// Kubernetes
+// ============================================================================
+
+// KubernetesPodsSource reports the images of the pods in a pod list, such as the output of
+// `kubectl get pods --all-namespaces -o json` run against a GKE cluster.
+type KubernetesPodsSource struct {
+	deployments []Deployment
+}
+
+var _ DeploymentSource = (*KubernetesPodsSource)(nil)
+
+// kubernetesPodList is the part of a Kubernetes PodList used to find running images.
+type kubernetesPodList struct {
+	Items []struct {
+		Metadata struct {
+			Name            string            `json:"name"`
+			Namespace       string            `json:"namespace"`
+			Labels          map[string]string `json:"labels"`
+			OwnerReferences []struct {
+				Kind       string `json:"kind"`
+				Name       string `json:"name"`
+				Controller bool   `json:"controller"`
+			} `json:"ownerReferences"`
+		} `json:"metadata"`
+		Status struct {
+			Phase             string `json:"phase"`
+			ContainerStatuses []struct {
+				ImageID string `json:"imageID"`
+			} `json:"containerStatuses"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// NewKubernetesPodsSource reads a JSON pod list; cluster names the cluster it comes from, if known.
+// Pods are reported under their controlling workload (e.g., "default/Deployment/api"), and images
+// only when the container runtime reports their digest. Internet exposure is not known from pods.
+func NewKubernetesPodsSource(r io.Reader, cluster string) (*KubernetesPodsSource, error) {
+	var list kubernetesPodList
+	if err := json.NewDecoder(r).Decode(&list); err != nil {
+		return nil, fmt.Errorf("invalid pod list: %w", err)
+	}
+
+	var deployments []Deployment
+	for _, pod := range list.Items {
+		if pod.Status.Phase != "Running" {
+			continue
+		}
+		kind, name := "Pod", pod.Metadata.Name
+		for _, owner := range pod.Metadata.OwnerReferences {
+			if owner.Controller {
+				kind, name = owner.Kind, owner.Name
+			}
+		}
+		// ReplicaSets of Deployments are named after the Deployment and the pod template hash
+		if hash := pod.Metadata.Labels["pod-template-hash"]; kind == "ReplicaSet" && hash != "" {
+			if deployment, ok := strings.CutSuffix(name, "-"+hash); ok {
+				kind, name = "Deployment", deployment
+			}
+		}
+		workload := schemas.Workload{
+			Platform: schemas.PlatformKubernetes,
+			Name:     pod.Metadata.Namespace + "/" + kind + "/" + name,
+			Location: cluster,
+		}
+		for _, c := range pod.Status.ContainerStatuses {
+			if digest := digestOf(c.ImageID); digest != "" {
+				deployments = append(deployments, Deployment{Digest: digest, Workload: workload})
+			}
+		}
+	}
+	return &KubernetesPodsSource{deployments: deployments}, nil
+}
+
+// Deployments implements DeploymentSource.
+func (s *KubernetesPodsSource) Deployments(context.Context) ([]Deployment, error) {
+	return s.deployments, nil
+}
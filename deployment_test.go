@@ -0,0 +1,138 @@
+package drydock_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+	"google.golang.org/api/option"
+)
+
+func TestNewKubernetesPodsSource(t *testing.T) {
+	sha512 := "sha512:" + strings.Repeat("b", 128)
+	pods := `{"items": [
+		{
+			"metadata": {
+				"name": "api-7d9f8-x2x", "namespace": "prod", "labels": {"pod-template-hash": "7d9f8"},
+				"ownerReferences": [{"kind": "ReplicaSet", "name": "api-7d9f8", "controller": true}]
+			},
+			"status": {"phase": "Running", "containerStatuses": [
+				{"imageID": "docker-pullable://us-docker.pkg.dev/p/r/api@` + fakeDigest("a") + `"},
+				{"imageID": "sha256:config-digest-only"}
+			]}
+		},
+		{
+			"metadata": {"name": "job-1", "namespace": "batch", "ownerReferences": [{"kind": "Job", "name": "nightly", "controller": true}]},
+			"status": {"phase": "Running", "containerStatuses": [{"imageID": "us-docker.pkg.dev/p/r/job@` + sha512 + `"}]}
+		},
+		{
+			"metadata": {"name": "done", "namespace": "batch"},
+			"status": {"phase": "Succeeded", "containerStatuses": [{"imageID": "us-docker.pkg.dev/p/r/job@` + fakeDigest("c") + `"}]}
+		}
+	]}`
+
+	source, err := drydock.NewKubernetesPodsSource(strings.NewReader(pods), "gke-prod")
+	if err != nil {
+		t.Fatalf("NewKubernetesPodsSource() error = %v", err)
+	}
+	got, err := source.Deployments(context.Background())
+	if err != nil {
+		t.Fatalf("Deployments() error = %v", err)
+	}
+
+	want := []drydock.Deployment{
+		{Digest: fakeDigest("a"), Workload: schemas.Workload{Platform: schemas.PlatformKubernetes, Name: "prod/Deployment/api", Location: "gke-prod"}},
+		{Digest: sha512, Workload: schemas.Workload{Platform: schemas.PlatformKubernetes, Name: "batch/Job/nightly", Location: "gke-prod"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Deployments() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCloudRunSource_Deployments(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/projects/p/locations/us-central1/revisions":
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": []any{
+				map[string]any{"metadata": map[string]any{"name": "api-001"}, "status": map[string]any{"imageDigest": "us-docker.pkg.dev/p/r/api@" + fakeDigest("0")}},
+				map[string]any{"metadata": map[string]any{"name": "api-002"}, "status": map[string]any{"imageDigest": "us-docker.pkg.dev/p/r/api@" + fakeDigest("1")}},
+				map[string]any{"metadata": map[string]any{"name": "worker-001"}, "status": map[string]any{"imageDigest": "us-docker.pkg.dev/p/r/worker@" + fakeDigest("2")}},
+			}})
+		case "/v1/projects/p/locations/us-central1/services":
+			if r.URL.Query().Get("continue") == "" {
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"items": []any{map[string]any{
+						"metadata": map[string]any{"name": "api"},
+						"status": map[string]any{"traffic": []any{
+							map[string]any{"revisionName": "api-002", "percent": 100},
+							map[string]any{"revisionName": "api-001", "percent": 0},
+						}},
+					}},
+					"metadata": map[string]any{"continue": "page2"},
+				})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": []any{map[string]any{
+				"metadata": map[string]any{"name": "worker", "annotations": map[string]any{"run.googleapis.com/ingress": "internal"}},
+				"status":   map[string]any{"traffic": []any{map[string]any{"revisionName": "worker-001", "percent": 100}}},
+			}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	source, err := drydock.NewCloudRunSource(ctx, "p", []string{"us-central1"},
+		option.WithEndpoint(srv.URL+"/"), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewCloudRunSource() error = %v", err)
+	}
+	defer func() { _ = source.Close() }()
+	got, err := source.Deployments(ctx)
+	if err != nil {
+		t.Fatalf("Deployments() error = %v", err)
+	}
+
+	want := []drydock.Deployment{
+		{Digest: fakeDigest("1"), Workload: schemas.Workload{Platform: schemas.PlatformCloudRun, Name: "api", Location: "us-central1", InternetFacing: true}},
+		{Digest: fakeDigest("2"), Workload: schemas.Workload{Platform: schemas.PlatformCloudRun, Name: "worker", Location: "us-central1"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Deployments() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+type staticDeployments []drydock.Deployment
+
+func (s staticDeployments) Deployments(context.Context) ([]drydock.Deployment, error) {
+	return s, nil
+}
+
+func TestDiscoverDeployments(t *testing.T) {
+	api := schemas.Workload{Platform: schemas.PlatformCloudRun, Name: "api", InternetFacing: true}
+	pod := schemas.Workload{Platform: schemas.PlatformKubernetes, Name: "prod/Deployment/api"}
+
+	got, err := drydock.ExportDiscoverDeployments(context.Background(), []drydock.DeploymentSource{
+		staticDeployments{{Digest: "sha256:a", Workload: api}, {Digest: "sha256:a", Workload: api}},
+		staticDeployments{{Digest: "sha256:a", Workload: pod}, {Digest: "sha256:b", Workload: pod}},
+	})
+	if err != nil {
+		t.Fatalf("discoverDeployments() error = %v", err)
+	}
+
+	want := map[string][]schemas.Workload{
+		"sha256:a": {api, pod},
+		"sha256:b": {pod},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("discoverDeployments() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,158 @@
+package drydock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// DistroTracker looks up the status a Linux distribution gives to a vulnerability of one of its packages
+// in its security tracker. Findings are annotated with it (see schemas.Vulnerability.DistroStatus) before
+// the CEL filter runs, so that e.g. `vuln.distroStatus != "ignored"` drops what the distro will not fix.
+type DistroTracker interface {
+	// Status returns the distro's status of v, or an empty string if the tracker does not know it.
+	Status(v schemas.Vulnerability) string
+}
+
+// Statuses reported by DebianSecurityTracker
+const (
+	DistroStatusOpen         = "open"         // affected, a fix is expected
+	DistroStatusResolved     = "resolved"     // fixed in the release
+	DistroStatusNoDSA        = "no-dsa"       // minor issue, fixed in a point release at best
+	DistroStatusIgnored      = "ignored"      // the distro will not fix it
+	DistroStatusPostponed    = "postponed"    // fix deferred to a later update
+	DistroStatusUnimportant  = "unimportant"  // not considered a vulnerability of the package
+	DistroStatusEndOfLife    = "end-of-life"  // the package is no longer supported
+	DistroStatusUndetermined = "undetermined" // not yet assessed
+)
+
+// DebianSecurityTrackerURL is the JSON export of the Debian security tracker.
+const DebianSecurityTrackerURL = "https://security-tracker.debian.org/tracker/data/json"
+
+// debianReleases maps Debian versions, as found in purl distro qualifiers, to release code names.
+var debianReleases = map[string]string{
+	"9":  "stretch",
+	"10": "buster",
+	"11": "bullseye",
+	"12": "bookworm",
+	"13": "trixie",
+	"14": "forky",
+}
+
+// DebianSecurityTracker reports the status of vulnerabilities of Debian packages
+// from the JSON export of the Debian security tracker.
+type DebianSecurityTracker struct {
+	// statuses maps source package → vulnerability ID → release code name → status
+	statuses map[string]map[string]map[string]string
+}
+
+// debianTrackerRelease is the per-release entry of the Debian security tracker export.
+type debianTrackerRelease struct {
+	Status      string `json:"status"`
+	Urgency     string `json:"urgency"`
+	NoDSA       string `json:"nodsa"`
+	NoDSAReason string `json:"nodsa_reason"`
+}
+
+// ReadDebianSecurityTracker reads the JSON export of the Debian security tracker (see DebianSecurityTrackerURL).
+func ReadDebianSecurityTracker(r io.Reader) (*DebianSecurityTracker, error) {
+	var data map[string]map[string]struct {
+		Releases map[string]debianTrackerRelease `json:"releases"`
+	}
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode Debian security tracker data: %w", err)
+	}
+
+	t := &DebianSecurityTracker{statuses: make(map[string]map[string]map[string]string, len(data))}
+	for pkg, vulns := range data {
+		byID := make(map[string]map[string]string, len(vulns))
+		for id, vuln := range vulns {
+			byRelease := make(map[string]string, len(vuln.Releases))
+			for release, r := range vuln.Releases {
+				byRelease[release] = r.status()
+			}
+			byID[id] = byRelease
+		}
+		t.statuses[pkg] = byID
+	}
+	return t, nil
+}
+
+// LoadDebianSecurityTracker reads the Debian security tracker export from a file, or downloads it
+// if source is an http(s) URL such as DebianSecurityTrackerURL.
+func LoadDebianSecurityTracker(ctx context.Context, source string) (*DebianSecurityTracker, error) {
+	if !strings.HasPrefix(source, "https://") && !strings.HasPrefix(source, "http://") {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open Debian security tracker data: %w", err)
+		}
+		defer f.Close()
+		return ReadDebianSecurityTracker(f)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download Debian security tracker data: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download Debian security tracker data: %s", resp.Status)
+	}
+	return ReadDebianSecurityTracker(resp.Body)
+}
+
+// Status returns the status of v in the Debian release of its package, for Debian OS packages.
+func (t *DebianSecurityTracker) Status(v schemas.Vulnerability) string {
+	distro, version, ok := strings.Cut(purlDistro(v.PURL), "-")
+	if !ok || distro != "debian" || !strings.HasPrefix(v.PURL, "pkg:deb/") {
+		return ""
+	}
+	release, ok := debianReleases[version]
+	if !ok {
+		return ""
+	}
+	return t.statuses[v.PackageName][v.ID][release]
+}
+
+// status condenses a tracker entry into one status, the most specific reason first.
+func (r debianTrackerRelease) status() string {
+	if r.Status != "open" {
+		return r.Status // "resolved" or "undetermined"
+	}
+	switch {
+	case r.NoDSAReason == "ignored":
+		return DistroStatusIgnored
+	case r.NoDSAReason == "postponed":
+		return DistroStatusPostponed
+	case r.NoDSA != "":
+		return DistroStatusNoDSA
+	case r.Urgency == "unimportant":
+		return DistroStatusUnimportant
+	case r.Urgency == "end-of-life":
+		return DistroStatusEndOfLife
+	default:
+		return DistroStatusOpen
+	}
+}
+
+// annotateDistroStatus sets the distro status of each vulnerability from the first tracker knowing it.
+func annotateDistroStatus(vulns []schemas.Vulnerability, trackers []DistroTracker) {
+	for i := range vulns {
+		for _, t := range trackers {
+			if status := t.Status(vulns[i]); status != "" {
+				vulns[i].DistroStatus = status
+				break
+			}
+		}
+	}
+}
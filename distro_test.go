@@ -0,0 +1,105 @@
+package drydock_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+const debianTrackerJSON = `{
+  "openssl": {
+    "CVE-2024-0001": {"releases": {"bookworm": {"status": "open", "urgency": "low", "nodsa": "Minor issue", "nodsa_reason": ""}}},
+    "CVE-2024-0002": {"releases": {"bookworm": {"status": "open", "urgency": "low", "nodsa": "Minor issue", "nodsa_reason": "ignored"}}},
+    "CVE-2024-0003": {"releases": {"bookworm": {"status": "open", "urgency": "low", "nodsa": "Minor issue", "nodsa_reason": "postponed"}}},
+    "CVE-2024-0004": {"releases": {"bookworm": {"status": "open", "urgency": "unimportant"}}},
+    "CVE-2024-0005": {"releases": {"bookworm": {"status": "open", "urgency": "medium"}, "bullseye": {"status": "resolved", "urgency": "medium"}}}
+  }
+}`
+
+func TestDebianSecurityTracker_Status(t *testing.T) {
+	tracker, err := drydock.ReadDebianSecurityTracker(strings.NewReader(debianTrackerJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vuln := func(id, purl string) schemas.Vulnerability {
+		return schemas.Vulnerability{ID: id, PackageName: "openssl", PURL: purl}
+	}
+	const bookworm = "pkg:deb/debian/openssl@3.0.11-1~deb12u2?distro=debian-12"
+
+	tests := map[string]struct {
+		input schemas.Vulnerability
+		want  string
+	}{
+		"should report no-dsa": {
+			input: vuln("CVE-2024-0001", bookworm), want: drydock.DistroStatusNoDSA,
+		},
+		"should report ignored": {
+			input: vuln("CVE-2024-0002", bookworm), want: drydock.DistroStatusIgnored,
+		},
+		"should report postponed": {
+			input: vuln("CVE-2024-0003", bookworm), want: drydock.DistroStatusPostponed,
+		},
+		"should report unimportant": {
+			input: vuln("CVE-2024-0004", bookworm), want: drydock.DistroStatusUnimportant,
+		},
+		"should report open": {
+			input: vuln("CVE-2024-0005", bookworm), want: drydock.DistroStatusOpen,
+		},
+		"should use the release of the package": {
+			input: vuln("CVE-2024-0005", "pkg:deb/debian/openssl@1.1.1w-0+deb11u1?distro=debian-11"), want: drydock.DistroStatusResolved,
+		},
+		"should not know releases missing from the tracker": {
+			input: vuln("CVE-2024-0001", "pkg:deb/debian/openssl@1.1.1w-0+deb11u1?distro=debian-11"), want: "",
+		},
+		"should ignore Ubuntu packages": {
+			input: vuln("CVE-2024-0001", "pkg:deb/ubuntu/openssl@3.0.2?distro=ubuntu-22.04"), want: "",
+		},
+		"should ignore packages without a distro": {
+			input: vuln("CVE-2024-0001", "pkg:deb/debian/openssl@3.0.11"), want: "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tracker.Status(tt.input); got != tt.want {
+				t.Errorf("Status() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeTracker reports the same status for every vulnerability of one package.
+type fakeTracker struct {
+	pkg    string
+	status string
+}
+
+func (f fakeTracker) Status(v schemas.Vulnerability) string {
+	if v.PackageName == f.pkg {
+		return f.status
+	}
+	return ""
+}
+
+func TestAnnotateDistroStatus(t *testing.T) {
+	vulns := []schemas.Vulnerability{{ID: "CVE-1", PackageName: "openssl"}, {ID: "CVE-2", PackageName: "zlib"}, {ID: "CVE-3", PackageName: "bash"}}
+	trackers := []drydock.DistroTracker{
+		fakeTracker{pkg: "openssl", status: drydock.DistroStatusIgnored},
+		fakeTracker{pkg: "openssl", status: drydock.DistroStatusOpen},
+		fakeTracker{pkg: "zlib", status: drydock.DistroStatusNoDSA},
+	}
+
+	drydock.ExportAnnotateDistroStatus(vulns, trackers)
+
+	want := []schemas.Vulnerability{
+		{ID: "CVE-1", PackageName: "openssl", DistroStatus: drydock.DistroStatusIgnored},
+		{ID: "CVE-2", PackageName: "zlib", DistroStatus: drydock.DistroStatusNoDSA},
+		{ID: "CVE-3", PackageName: "bash"},
+	}
+	if diff := cmp.Diff(want, vulns); diff != "" {
+		t.Errorf("annotateDistroStatus() mismatch (-want +got):\n%s", diff)
+	}
+}
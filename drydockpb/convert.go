@@ -0,0 +1,404 @@
+package drydockpb
+
+import (
+	"strings"
+	"time"
+
+	"github.com/hiro-o918/drydock/schemas"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FromSchema converts an analysis result into its protobuf representation.
+func FromSchema(r schemas.AnalyzeResult) *AnalyzeResult {
+	out := &AnalyzeResult{
+		SchemaVersion:   schemas.SchemaVersion,
+		Artifact:        fromArtifact(r.Artifact),
+		Vulnerabilities: make([]*Vulnerability, 0, len(r.Vulnerabilities)),
+		Summary:         fromSummary(r.Summary),
+		Partial:         r.Partial,
+		Deployed:        r.Deployed,
+	}
+	out.ScanTime = fromTime(r.ScanTime)
+	for _, v := range r.Vulnerabilities {
+		out.Vulnerabilities = append(out.Vulnerabilities, fromVulnerability(v))
+	}
+	for _, v := range r.Resolved {
+		out.Resolved = append(out.Resolved, fromVulnerability(v))
+	}
+	for _, w := range r.Workloads {
+		out.Workloads = append(out.Workloads, &Workload{
+			Platform:       w.Platform,
+			Name:           w.Name,
+			Location:       w.Location,
+			InternetFacing: w.InternetFacing,
+		})
+	}
+	for _, s := range r.Suppressed {
+		out.Suppressed = append(out.Suppressed, &SuppressedFinding{
+			Vulnerability: fromVulnerability(s.Vulnerability),
+			Suppression: &Suppression{
+				Id:          s.Suppression.ID,
+				PackageName: s.Suppression.PackageName,
+				Reason:      s.Suppression.Reason,
+				By:          s.Suppression.By,
+				Until:       fromTime(s.Suppression.Until),
+				Source:      s.Suppression.Source,
+			},
+		})
+	}
+	for _, rem := range r.Remediations {
+		out.Remediations = append(out.Remediations, &Remediation{
+			PackageManager:   rem.PackageManager,
+			PackageName:      rem.PackageName,
+			InstalledVersion: rem.InstalledVersion,
+			FixedVersion:     rem.FixedVersion,
+			Command:          rem.Command,
+			VulnerabilityIds: rem.VulnerabilityIDs,
+		})
+	}
+	if img := r.Image; img != nil {
+		out.Image = &ImageMetadata{
+			Tags:       img.Tags,
+			UploadTime: fromTime(img.UploadTime),
+			UpdateTime: fromTime(img.UpdateTime),
+			BuildTime:  fromTime(img.BuildTime),
+			SizeBytes:  img.SizeBytes,
+			MediaType:  img.MediaType,
+		}
+	}
+	if a := r.BaseImage; a != nil {
+		out.BaseImage = &BaseImageAdvice{
+			Current:           a.Current,
+			Recommended:       a.Recommended,
+			RemovedBySeverity: fromSeverityCounts(a.RemovedBySeverity),
+			AddedBySeverity:   fromSeverityCounts(a.AddedBySeverity),
+			Message:           a.Message,
+		}
+	}
+	if r.Scanner != nil {
+		out.Scanner = &ScannerInfo{Name: r.Scanner.Name, Version: r.Scanner.Version}
+	}
+	if m := r.Metadata; m != nil {
+		out.Metadata = &ScanMetadata{
+			DurationMillis:     m.DurationMillis,
+			OccurrencesFetched: int32(m.OccurrencesFetched),
+			Truncated:          m.Truncated,
+			Warnings:           m.Warnings,
+			ContinuousAnalysis: m.ContinuousAnalysis,
+			LastAnalysisTime:   fromTime(m.LastAnalysisTime),
+			Stale:              m.Stale,
+			RefreshRequested:   m.RefreshRequested,
+		}
+	}
+	return out
+}
+
+// ToSchema converts the protobuf representation back into an analysis result.
+// Empty severities and fix states come back as UNSPECIFIED and UNKNOWN respectively.
+func (x *AnalyzeResult) ToSchema() schemas.AnalyzeResult {
+	out := schemas.AnalyzeResult{
+		Artifact:        x.GetArtifact().toSchema(),
+		Vulnerabilities: make([]schemas.Vulnerability, 0, len(x.GetVulnerabilities())),
+		Summary:         x.GetSummary().toSchema(),
+		Partial:         x.GetPartial(),
+		Deployed:        x.GetDeployed(),
+	}
+	out.ScanTime = toTime(x.GetScanTime())
+	if img := x.GetImage(); img != nil {
+		out.Image = &schemas.ImageMetadata{
+			Tags:       img.GetTags(),
+			UploadTime: toTime(img.GetUploadTime()),
+			UpdateTime: toTime(img.GetUpdateTime()),
+			BuildTime:  toTime(img.GetBuildTime()),
+			SizeBytes:  img.GetSizeBytes(),
+			MediaType:  img.GetMediaType(),
+		}
+	}
+	for _, v := range x.GetVulnerabilities() {
+		out.Vulnerabilities = append(out.Vulnerabilities, v.toSchema())
+	}
+	for _, v := range x.GetResolved() {
+		out.Resolved = append(out.Resolved, v.toSchema())
+	}
+	for _, w := range x.GetWorkloads() {
+		out.Workloads = append(out.Workloads, schemas.Workload{
+			Platform:       w.GetPlatform(),
+			Name:           w.GetName(),
+			Location:       w.GetLocation(),
+			InternetFacing: w.GetInternetFacing(),
+		})
+	}
+	for _, s := range x.GetSuppressed() {
+		sup := s.GetSuppression()
+		out.Suppressed = append(out.Suppressed, schemas.SuppressedFinding{
+			Vulnerability: s.GetVulnerability().toSchema(),
+			Suppression: schemas.Suppression{
+				ID:          sup.GetId(),
+				PackageName: sup.GetPackageName(),
+				Reason:      sup.GetReason(),
+				By:          sup.GetBy(),
+				Until:       toTime(sup.GetUntil()),
+				Source:      sup.GetSource(),
+			},
+		})
+	}
+	for _, rem := range x.GetRemediations() {
+		out.Remediations = append(out.Remediations, schemas.Remediation{
+			PackageManager:   rem.GetPackageManager(),
+			PackageName:      rem.GetPackageName(),
+			InstalledVersion: rem.GetInstalledVersion(),
+			FixedVersion:     rem.GetFixedVersion(),
+			Command:          rem.GetCommand(),
+			VulnerabilityIDs: rem.GetVulnerabilityIds(),
+		})
+	}
+	if a := x.GetBaseImage(); a != nil {
+		out.BaseImage = &schemas.BaseImageAdvice{
+			Current:           a.GetCurrent(),
+			Recommended:       a.GetRecommended(),
+			RemovedBySeverity: toSeverityCounts(a.GetRemovedBySeverity()),
+			AddedBySeverity:   toSeverityCounts(a.GetAddedBySeverity()),
+			Message:           a.GetMessage(),
+		}
+	}
+	if s := x.GetScanner(); s != nil {
+		out.Scanner = &schemas.ScannerInfo{Name: s.GetName(), Version: s.GetVersion()}
+	}
+	if m := x.GetMetadata(); m != nil {
+		out.Metadata = &schemas.ScanMetadata{
+			DurationMillis:     m.GetDurationMillis(),
+			OccurrencesFetched: int(m.GetOccurrencesFetched()),
+			Truncated:          m.GetTruncated(),
+			Warnings:           m.GetWarnings(),
+			ContinuousAnalysis: m.GetContinuousAnalysis(),
+			LastAnalysisTime:   toTime(m.GetLastAnalysisTime()),
+			Stale:              m.GetStale(),
+			RefreshRequested:   m.GetRefreshRequested(),
+		}
+	}
+	return out
+}
+
+// fromSeverityCounts converts counts keyed by severity, keeping nil as nil.
+func fromSeverityCounts(counts map[schemas.Severity]int) map[string]int32 {
+	if counts == nil {
+		return nil
+	}
+	out := make(map[string]int32, len(counts))
+	for k, n := range counts {
+		out[string(k)] = int32(n)
+	}
+	return out
+}
+
+// toSeverityCounts converts counts keyed by severity name, mapping an empty map to nil.
+func toSeverityCounts(counts map[string]int32) map[schemas.Severity]int {
+	if len(counts) == 0 {
+		return nil
+	}
+	out := make(map[schemas.Severity]int, len(counts))
+	for k, n := range counts {
+		out[schemas.Severity(k)] = int(n)
+	}
+	return out
+}
+
+// fromTime converts a time, mapping the zero time to an unset timestamp.
+func fromTime(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// toTime converts a timestamp, mapping an unset timestamp to the zero time.
+func toTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func fromArtifact(a schemas.ArtifactReference) *ArtifactReference {
+	return &ArtifactReference{
+		Host:         a.Host,
+		ProjectId:    a.ProjectID,
+		RepositoryId: a.RepositoryID,
+		ImageName:    a.ImageName,
+		Tag:          a.Tag,
+		Digest:       a.Digest,
+		Uri:          a.String(),
+	}
+}
+
+func (x *ArtifactReference) toSchema() schemas.ArtifactReference {
+	if x == nil {
+		return schemas.ArtifactReference{}
+	}
+	return schemas.ArtifactReference{
+		Host:         x.GetHost(),
+		ProjectID:    x.GetProjectId(),
+		RepositoryID: x.GetRepositoryId(),
+		ImageName:    x.GetImageName(),
+		Tag:          x.Tag,
+		Digest:       x.Digest,
+	}
+}
+
+func fromVulnerability(v schemas.Vulnerability) *Vulnerability {
+	out := &Vulnerability{
+		Id:               v.ID,
+		Severity:         fromSeverity(v.Severity),
+		PackageName:      v.PackageName,
+		Purl:             v.PURL,
+		InstalledVersion: v.InstalledVersion,
+		FixedVersion:     v.FixedVersion,
+		FixState:         fromFixState(v.FixState),
+		PackageType:      v.PackageType,
+		Description:      v.Description,
+		CvssScore:        v.CVSSScore,
+		CvssVersion:      v.CVSSVersion,
+		CvssVector:       v.CVSSVector,
+		Source:           v.Source,
+		Cwes:             v.CWEs,
+		Urls:             v.URLs(),
+		FirstSeen:        fromTime(v.FirstSeen),
+		LastSeen:         fromTime(v.LastSeen),
+		ResolvedAt:       fromTime(v.ResolvedAt),
+		SlaDue:           fromTime(v.SLADue),
+		SlaBreached:      v.SLABreached,
+		DistroStatus:     v.DistroStatus,
+		DependencyPath:   v.DependencyPath,
+	}
+	if v.References != nil {
+		out.References = make([]*Reference, 0, len(v.References))
+		for _, r := range v.References {
+			out.References = append(out.References, &Reference{
+				Url:  r.URL,
+				Type: ReferenceType(ReferenceType_value[referenceTypePrefix+string(r.Type)]),
+			})
+		}
+	}
+	if c := v.CVSS; c != nil {
+		out.Cvss = &CVSSDetails{
+			BaseScore:           c.BaseScore,
+			ExploitabilityScore: c.ExploitabilityScore,
+			ImpactScore:         c.ImpactScore,
+		}
+	}
+	return out
+}
+
+func (x *Vulnerability) toSchema() schemas.Vulnerability {
+	out := schemas.Vulnerability{
+		ID:               x.GetId(),
+		Severity:         x.GetSeverity().toSchema(),
+		PackageName:      x.GetPackageName(),
+		PURL:             x.GetPurl(),
+		InstalledVersion: x.GetInstalledVersion(),
+		FixedVersion:     x.GetFixedVersion(),
+		FixState:         x.GetFixState().toSchema(),
+		PackageType:      x.GetPackageType(),
+		Description:      x.GetDescription(),
+		CVSSScore:        x.GetCvssScore(),
+		CVSSVersion:      x.GetCvssVersion(),
+		CVSSVector:       x.GetCvssVector(),
+		Source:           x.GetSource(),
+		CWEs:             x.GetCwes(),
+		FirstSeen:        toTime(x.GetFirstSeen()),
+		LastSeen:         toTime(x.GetLastSeen()),
+		ResolvedAt:       toTime(x.GetResolvedAt()),
+		SLADue:           toTime(x.GetSlaDue()),
+		SLABreached:      x.GetSlaBreached(),
+		DistroStatus:     x.GetDistroStatus(),
+		DependencyPath:   x.GetDependencyPath(),
+	}
+	switch {
+	case x.GetReferences() != nil:
+		out.References = make([]schemas.Reference, 0, len(x.GetReferences()))
+		for _, r := range x.GetReferences() {
+			out.References = append(out.References, schemas.Reference{
+				URL:  r.GetUrl(),
+				Type: schemas.ReferenceType(strings.TrimPrefix(r.GetType().String(), referenceTypePrefix)),
+			})
+		}
+	case x.GetUrls() != nil:
+		out.References = make([]schemas.Reference, 0, len(x.GetUrls()))
+		for _, u := range x.GetUrls() {
+			out.References = append(out.References, schemas.Reference{URL: u, Type: schemas.ReferenceTypeWeb})
+		}
+	}
+	if c := x.GetCvss(); c != nil {
+		out.CVSS = &schemas.CVSSDetails{
+			BaseScore:           c.GetBaseScore(),
+			ExploitabilityScore: c.GetExploitabilityScore(),
+			ImpactScore:         c.GetImpactScore(),
+		}
+	}
+	return out
+}
+
+func fromSummary(s schemas.VulnerabilitySummary) *VulnerabilitySummary {
+	out := &VulnerabilitySummary{
+		TotalCount:         int32(s.TotalCount),
+		CountBySeverity:    make(map[string]int32, len(s.CountBySeverity)),
+		FixableCount:       int32(s.FixableCount),
+		CountByPackageType: make(map[string]int32, len(s.CountByPackageType)),
+		CountByFixState:    make(map[string]int32, len(s.CountByFixState)),
+		SlaBreachCount:     int32(s.SLABreachCount),
+	}
+	for k, n := range s.CountBySeverity {
+		out.CountBySeverity[string(k)] = int32(n)
+	}
+	for k, n := range s.CountByPackageType {
+		out.CountByPackageType[k] = int32(n)
+	}
+	for k, n := range s.CountByFixState {
+		out.CountByFixState[string(k)] = int32(n)
+	}
+	return out
+}
+
+func (x *VulnerabilitySummary) toSchema() schemas.VulnerabilitySummary {
+	out := schemas.VulnerabilitySummary{
+		TotalCount:         int(x.GetTotalCount()),
+		CountBySeverity:    make(map[schemas.Severity]int, len(x.GetCountBySeverity())),
+		FixableCount:       int(x.GetFixableCount()),
+		CountByPackageType: make(map[string]int, len(x.GetCountByPackageType())),
+		CountByFixState:    make(map[schemas.FixState]int, len(x.GetCountByFixState())),
+		SLABreachCount:     int(x.GetSlaBreachCount()),
+	}
+	for k, n := range x.GetCountBySeverity() {
+		out.CountBySeverity[schemas.Severity(k)] = int(n)
+	}
+	for k, n := range x.GetCountByPackageType() {
+		out.CountByPackageType[k] = int(n)
+	}
+	for k, n := range x.GetCountByFixState() {
+		out.CountByFixState[schemas.FixState(k)] = int(n)
+	}
+	return out
+}
+
+// Enum values are named after the schemas constants with a type prefix (e.g., HIGH -> SEVERITY_HIGH).
+const (
+	severityPrefix      = "SEVERITY_"
+	fixStatePrefix      = "FIX_STATE_"
+	referenceTypePrefix = "REFERENCE_TYPE_"
+)
+
+func fromSeverity(s schemas.Severity) Severity {
+	return Severity(Severity_value[severityPrefix+string(s)])
+}
+
+func (x Severity) toSchema() schemas.Severity {
+	return schemas.Severity(strings.TrimPrefix(x.String(), severityPrefix))
+}
+
+func fromFixState(s schemas.FixState) FixState {
+	return FixState(FixState_value[fixStatePrefix+string(s)])
+}
+
+func (x FixState) toSchema() schemas.FixState {
+	return schemas.FixState(strings.TrimPrefix(x.String(), fixStatePrefix))
+}
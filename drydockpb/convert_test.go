@@ -0,0 +1,149 @@
+package drydockpb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/drydockpb"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAnalyzeResult_RoundTrip(t *testing.T) {
+	tests := map[string]struct {
+		input schemas.AnalyzeResult
+	}{
+		"should preserve every field when the result is fully populated": {
+			input: schemas.AnalyzeResult{
+				Artifact: schemas.ArtifactReference{
+					Host:         "us-central1-docker.pkg.dev",
+					ProjectID:    "project",
+					RepositoryID: "repo",
+					ImageName:    "app/worker",
+					Tag:          utils.Ptr("v1.0.0"),
+					Digest:       utils.Ptr("sha256:abc"),
+				},
+				Image: &schemas.ImageMetadata{
+					Tags:       []string{"v1.0.0", "latest"},
+					UploadTime: time.Date(2023, 12, 1, 9, 0, 0, 0, time.UTC),
+					UpdateTime: time.Date(2023, 12, 2, 9, 0, 0, 0, time.UTC),
+					SizeBytes:  1 << 30,
+					MediaType:  "application/vnd.oci.image.manifest.v1+json",
+				},
+				ScanTime: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+				Vulnerabilities: []schemas.Vulnerability{
+					{
+						ID:               "CVE-2023-0001",
+						Severity:         schemas.SeverityHigh,
+						PackageName:      "openssl",
+						PURL:             "pkg:deb/debian/openssl@1.1.1",
+						InstalledVersion: "1.1.1",
+						FixedVersion:     "1.1.1t",
+						FixState:         schemas.FixStateFixAvailable,
+						DistroStatus:     "no-dsa",
+						PackageType:      "OS",
+						Description:      "Sample vulnerability",
+						CVSSScore:        7.5,
+						CVSSVersion:      "3",
+						CVSSVector:       "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H",
+						CVSS:             &schemas.CVSSDetails{BaseScore: 7.5, ExploitabilityScore: 3.9, ImpactScore: 3.6},
+						Source:           schemas.SourceContainerAnalysis,
+						CWEs:             []string{"CWE-787"},
+						DependencyPath:   []string{"github.com/foo/client@v1.2.0", "golang.org/x/net@v0.17.0"},
+						References:       []schemas.Reference{{URL: "https://cve.mitre.org/example", Type: schemas.ReferenceTypeAdvisory}},
+						FirstSeen:        time.Date(2023, 12, 3, 9, 0, 0, 0, time.UTC),
+						LastSeen:         time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+						SLADue:           time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC),
+						SLABreached:      true,
+					},
+				},
+				Summary: schemas.VulnerabilitySummary{
+					TotalCount:         1,
+					CountBySeverity:    map[schemas.Severity]int{schemas.SeverityHigh: 1},
+					FixableCount:       1,
+					CountByPackageType: map[string]int{"OS": 1},
+					CountByFixState:    map[schemas.FixState]int{schemas.FixStateFixAvailable: 1},
+					SLABreachCount:     1,
+				},
+				Resolved: []schemas.Vulnerability{
+					{
+						ID:          "CVE-2022-0002",
+						Severity:    schemas.SeverityMedium,
+						PackageName: "zlib",
+						FixState:    schemas.FixStateFixAvailable,
+						FirstSeen:   time.Date(2023, 11, 1, 9, 0, 0, 0, time.UTC),
+						LastSeen:    time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+						ResolvedAt:  time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+					},
+				},
+				Deployed: true,
+				Workloads: []schemas.Workload{
+					{Platform: schemas.PlatformCloudRun, Name: "api", Location: "us-central1", InternetFacing: true},
+				},
+				Suppressed: []schemas.SuppressedFinding{
+					{
+						Vulnerability: schemas.Vulnerability{ID: "CVE-2023-0003", Severity: schemas.SeverityCritical, PackageName: "glibc", FixState: schemas.FixStateWontFix},
+						Suppression: schemas.Suppression{
+							ID:     "CVE-2023-0003",
+							Reason: "not reachable",
+							By:     "security@example.com",
+							Until:  time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC),
+							Source: "suppressions.json",
+						},
+					},
+				},
+				BaseImage: &schemas.BaseImageAdvice{
+					Current:           "us-docker.pkg.dev/project/base/debian@sha256:old",
+					Recommended:       "us-docker.pkg.dev/project/base/debian:12@sha256:new",
+					RemovedBySeverity: map[schemas.Severity]int{schemas.SeverityCritical: 2},
+					AddedBySeverity:   map[schemas.Severity]int{schemas.SeverityLow: 1},
+					Message:           "rebuilding on us-docker.pkg.dev/project/base/debian:12@sha256:new would remove 2 CRITICAL findings (and add 1 LOW)",
+				},
+				Remediations: []schemas.Remediation{{
+					PackageManager: "apt", PackageName: "openssl", InstalledVersion: "1.1.1", FixedVersion: "1.1.1t",
+					Command: "apt-get install --only-upgrade openssl=1.1.1t", VulnerabilityIDs: []string{"CVE-2023-0001"},
+				}},
+				Scanner: &schemas.ScannerInfo{Name: schemas.SourceContainerAnalysis, Version: "v1"},
+				Metadata: &schemas.ScanMetadata{
+					DurationMillis: 120, OccurrencesFetched: 3, Truncated: true, Warnings: []string{"w"},
+					ContinuousAnalysis: "INACTIVE", LastAnalysisTime: time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), Stale: true, RefreshRequested: true,
+				},
+				Partial: true,
+			},
+		},
+		"should preserve a minimal result": {
+			input: schemas.AnalyzeResult{
+				Artifact:        schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "i"},
+				Vulnerabilities: []schemas.Vulnerability{},
+				Summary: schemas.VulnerabilitySummary{
+					CountBySeverity:    map[schemas.Severity]int{},
+					CountByPackageType: map[string]int{},
+					CountByFixState:    map[schemas.FixState]int{},
+				},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Go through the wire format to make sure nothing relies on in-memory state
+			data, err := proto.Marshal(drydockpb.FromSchema(tt.input))
+			if err != nil {
+				t.Fatalf("proto.Marshal() error = %v", err)
+			}
+			var decoded drydockpb.AnalyzeResult
+			if err := proto.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("proto.Unmarshal() error = %v", err)
+			}
+
+			if decoded.GetSchemaVersion() != schemas.SchemaVersion {
+				t.Errorf("SchemaVersion = %q, want %q", decoded.GetSchemaVersion(), schemas.SchemaVersion)
+			}
+			if diff := cmp.Diff(tt.input, decoded.ToSchema()); diff != "" {
+				t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
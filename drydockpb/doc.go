@@ -0,0 +1,7 @@
+// Package drydockpb provides the Protocol Buffers definitions of drydock scan results,
+// the canonical wire format for server modes and non-Go consumers.
+//
+// result.pb.go is generated from result.proto; regenerate it with `go generate ./drydockpb`.
+package drydockpb
+
+//go:generate protoc --proto_path=.. --go_out=.. --go_opt=paths=source_relative drydockpb/result.proto
@@ -0,0 +1,1793 @@
+// Canonical wire format of drydock scan results.
+//
+// The messages mirror the Go types in the schemas package; field names follow
+// the JSON output (see `drydock schema`) in snake_case.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: drydockpb/result.proto
+
+package drydockpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Severity is the severity level of a vulnerability.
+type Severity int32
+
+const (
+	Severity_SEVERITY_UNSPECIFIED Severity = 0
+	Severity_SEVERITY_MINIMAL     Severity = 1
+	Severity_SEVERITY_LOW         Severity = 2
+	Severity_SEVERITY_MEDIUM      Severity = 3
+	Severity_SEVERITY_HIGH        Severity = 4
+	Severity_SEVERITY_CRITICAL    Severity = 5
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_UNSPECIFIED",
+		1: "SEVERITY_MINIMAL",
+		2: "SEVERITY_LOW",
+		3: "SEVERITY_MEDIUM",
+		4: "SEVERITY_HIGH",
+		5: "SEVERITY_CRITICAL",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_UNSPECIFIED": 0,
+		"SEVERITY_MINIMAL":     1,
+		"SEVERITY_LOW":         2,
+		"SEVERITY_MEDIUM":      3,
+		"SEVERITY_HIGH":        4,
+		"SEVERITY_CRITICAL":    5,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_drydockpb_result_proto_enumTypes[0].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_drydockpb_result_proto_enumTypes[0]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_drydockpb_result_proto_rawDescGZIP(), []int{0}
+}
+
+// FixState tells whether a fix is available for a vulnerability.
+type FixState int32
+
+const (
+	// The data source did not report fix information.
+	FixState_FIX_STATE_UNKNOWN FixState = 0
+	// A fixed version of the package exists.
+	FixState_FIX_STATE_FIX_AVAILABLE FixState = 1
+	// No fix has been released yet.
+	FixState_FIX_STATE_NO_FIX_AVAILABLE FixState = 2
+	// The vendor does not plan to release a fix.
+	FixState_FIX_STATE_WONT_FIX FixState = 3
+)
+
+// Enum value maps for FixState.
+var (
+	FixState_name = map[int32]string{
+		0: "FIX_STATE_UNKNOWN",
+		1: "FIX_STATE_FIX_AVAILABLE",
+		2: "FIX_STATE_NO_FIX_AVAILABLE",
+		3: "FIX_STATE_WONT_FIX",
+	}
+	FixState_value = map[string]int32{
+		"FIX_STATE_UNKNOWN":          0,
+		"FIX_STATE_FIX_AVAILABLE":    1,
+		"FIX_STATE_NO_FIX_AVAILABLE": 2,
+		"FIX_STATE_WONT_FIX":         3,
+	}
+)
+
+func (x FixState) Enum() *FixState {
+	p := new(FixState)
+	*p = x
+	return p
+}
+
+func (x FixState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FixState) Descriptor() protoreflect.EnumDescriptor {
+	return file_drydockpb_result_proto_enumTypes[1].Descriptor()
+}
+
+func (FixState) Type() protoreflect.EnumType {
+	return &file_drydockpb_result_proto_enumTypes[1]
+}
+
+func (x FixState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FixState.Descriptor instead.
+func (FixState) EnumDescriptor() ([]byte, []int) {
+	return file_drydockpb_result_proto_rawDescGZIP(), []int{1}
+}
+
+// ReferenceType classifies a reference link.
+type ReferenceType int32
+
+const (
+	ReferenceType_REFERENCE_TYPE_UNSPECIFIED ReferenceType = 0
+	// A security advisory (e.g., NVD, a distribution tracker).
+	ReferenceType_REFERENCE_TYPE_ADVISORY ReferenceType = 1
+	// The fix itself (e.g., a commit, patch or release).
+	ReferenceType_REFERENCE_TYPE_FIX ReferenceType = 2
+	// Any other web page.
+	ReferenceType_REFERENCE_TYPE_WEB ReferenceType = 3
+)
+
+// Enum value maps for ReferenceType.
+var (
+	ReferenceType_name = map[int32]string{
+		0: "REFERENCE_TYPE_UNSPECIFIED",
+		1: "REFERENCE_TYPE_ADVISORY",
+		2: "REFERENCE_TYPE_FIX",
+		3: "REFERENCE_TYPE_WEB",
+	}
+	ReferenceType_value = map[string]int32{
+		"REFERENCE_TYPE_UNSPECIFIED": 0,
+		"REFERENCE_TYPE_ADVISORY":    1,
+		"REFERENCE_TYPE_FIX":         2,
+		"REFERENCE_TYPE_WEB":         3,
+	}
+)
+
+func (x ReferenceType) Enum() *ReferenceType {
+	p := new(ReferenceType)
+	*p = x
+	return p
+}
+
+func (x ReferenceType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReferenceType) Descriptor() protoreflect.EnumDescriptor {
+	return file_drydockpb_result_proto_enumTypes[2].Descriptor()
+}
+
+func (ReferenceType) Type() protoreflect.EnumType {
+	return &file_drydockpb_result_proto_enumTypes[2]
+}
+
+func (x ReferenceType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReferenceType.Descriptor instead.
+func (ReferenceType) EnumDescriptor() ([]byte, []int) {
+	return file_drydockpb_result_proto_rawDescGZIP(), []int{2}
+}
+
+// ArtifactReference identifies an image in Artifact Registry.
+type ArtifactReference struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// e.g., us-central1-docker.pkg.dev
+	Host         string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	ProjectId    string `protobuf:"bytes,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	RepositoryId string `protobuf:"bytes,3,opt,name=repository_id,json=repositoryId,proto3" json:"repository_id,omitempty"`
+	// e.g., my-service/worker
+	ImageName string  `protobuf:"bytes,4,opt,name=image_name,json=imageName,proto3" json:"image_name,omitempty"`
+	Tag       *string `protobuf:"bytes,5,opt,name=tag,proto3,oneof" json:"tag,omitempty"`
+	// e.g., sha256:e3b0...
+	Digest *string `protobuf:"bytes,6,opt,name=digest,proto3,oneof" json:"digest,omitempty"`
+	// Full image reference; output only.
+	Uri           string `protobuf:"bytes,7,opt,name=uri,proto3" json:"uri,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArtifactReference) Reset() {
+	*x = ArtifactReference{}
+	mi := &file_drydockpb_result_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArtifactReference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArtifactReference) ProtoMessage() {}
+
+func (x *ArtifactReference) ProtoReflect() protoreflect.Message {
+	mi := &file_drydockpb_result_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArtifactReference.ProtoReflect.Descriptor instead.
+func (*ArtifactReference) Descriptor() ([]byte, []int) {
+	return file_drydockpb_result_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ArtifactReference) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *ArtifactReference) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *ArtifactReference) GetRepositoryId() string {
+	if x != nil {
+		return x.RepositoryId
+	}
+	return ""
+}
+
+func (x *ArtifactReference) GetImageName() string {
+	if x != nil {
+		return x.ImageName
+	}
+	return ""
+}
+
+func (x *ArtifactReference) GetTag() string {
+	if x != nil && x.Tag != nil {
+		return *x.Tag
+	}
+	return ""
+}
+
+func (x *ArtifactReference) GetDigest() string {
+	if x != nil && x.Digest != nil {
+		return *x.Digest
+	}
+	return ""
+}
+
+func (x *ArtifactReference) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+// ImageMetadata describes an image as stored in the registry.
+type ImageMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tags          []string               `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	UploadTime    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=upload_time,json=uploadTime,proto3" json:"upload_time,omitempty"`
+	UpdateTime    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	BuildTime     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=build_time,json=buildTime,proto3" json:"build_time,omitempty"`
+	SizeBytes     int64                  `protobuf:"varint,5,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	MediaType     string                 `protobuf:"bytes,6,opt,name=media_type,json=mediaType,proto3" json:"media_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImageMetadata) Reset() {
+	*x = ImageMetadata{}
+	mi := &file_drydockpb_result_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImageMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageMetadata) ProtoMessage() {}
+
+func (x *ImageMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_drydockpb_result_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageMetadata.ProtoReflect.Descriptor instead.
+func (*ImageMetadata) Descriptor() ([]byte, []int) {
+	return file_drydockpb_result_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ImageMetadata) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ImageMetadata) GetUploadTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UploadTime
+	}
+	return nil
+}
+
+func (x *ImageMetadata) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+func (x *ImageMetadata) GetBuildTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BuildTime
+	}
+	return nil
+}
+
+func (x *ImageMetadata) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *ImageMetadata) GetMediaType() string {
+	if x != nil {
+		return x.MediaType
+	}
+	return ""
+}
+
+// CVSSDetails contains the CVSS base score and its sub-scores.
+type CVSSDetails struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	BaseScore           float32                `protobuf:"fixed32,1,opt,name=base_score,json=baseScore,proto3" json:"base_score,omitempty"`
+	ExploitabilityScore float32                `protobuf:"fixed32,2,opt,name=exploitability_score,json=exploitabilityScore,proto3" json:"exploitability_score,omitempty"`
+	ImpactScore         float32                `protobuf:"fixed32,3,opt,name=impact_score,json=impactScore,proto3" json:"impact_score,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *CVSSDetails) Reset() {
+	*x = CVSSDetails{}
+	mi := &file_drydockpb_result_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CVSSDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CVSSDetails) ProtoMessage() {}
+
+func (x *CVSSDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_drydockpb_result_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CVSSDetails.ProtoReflect.Descriptor instead.
+func (*CVSSDetails) Descriptor() ([]byte, []int) {
+	return file_drydockpb_result_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CVSSDetails) GetBaseScore() float32 {
+	if x != nil {
+		return x.BaseScore
+	}
+	return 0
+}
+
+func (x *CVSSDetails) GetExploitabilityScore() float32 {
+	if x != nil {
+		return x.ExploitabilityScore
+	}
+	return 0
+}
+
+func (x *CVSSDetails) GetImpactScore() float32 {
+	if x != nil {
+		return x.ImpactScore
+	}
+	return 0
+}
+
+// Reference is a link related to a vulnerability.
+type Reference struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Type          ReferenceType          `protobuf:"varint,2,opt,name=type,proto3,enum=drydock.v1.ReferenceType" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reference) Reset() {
+	*x = Reference{}
+	mi := &file_drydockpb_result_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reference) ProtoMessage() {}
+
+func (x *Reference) ProtoReflect() protoreflect.Message {
+	mi := &file_drydockpb_result_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reference.ProtoReflect.Descriptor instead.
+func (*Reference) Descriptor() ([]byte, []int) {
+	return file_drydockpb_result_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Reference) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Reference) GetType() ReferenceType {
+	if x != nil {
+		return x.Type
+	}
+	return ReferenceType_REFERENCE_TYPE_UNSPECIFIED
+}
+
+// Vulnerability is a single vulnerability finding.
+type Vulnerability struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// e.g., CVE-2023-0001
+	Id               string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Severity         Severity `protobuf:"varint,2,opt,name=severity,proto3,enum=drydock.v1.Severity" json:"severity,omitempty"`
+	PackageName      string   `protobuf:"bytes,3,opt,name=package_name,json=packageName,proto3" json:"package_name,omitempty"`
+	InstalledVersion string   `protobuf:"bytes,4,opt,name=installed_version,json=installedVersion,proto3" json:"installed_version,omitempty"`
+	FixedVersion     string   `protobuf:"bytes,5,opt,name=fixed_version,json=fixedVersion,proto3" json:"fixed_version,omitempty"`
+	FixState         FixState `protobuf:"varint,6,opt,name=fix_state,json=fixState,proto3,enum=drydock.v1.FixState" json:"fix_state,omitempty"`
+	// e.g., OS, GO, MAVEN
+	PackageType string  `protobuf:"bytes,7,opt,name=package_type,json=packageType,proto3" json:"package_type,omitempty"`
+	Description string  `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"`
+	CvssScore   float32 `protobuf:"fixed32,9,opt,name=cvss_score,json=cvssScore,proto3" json:"cvss_score,omitempty"`
+	// "2" or "3"
+	CvssVersion string       `protobuf:"bytes,10,opt,name=cvss_version,json=cvssVersion,proto3" json:"cvss_version,omitempty"`
+	CvssVector  string       `protobuf:"bytes,11,opt,name=cvss_vector,json=cvssVector,proto3" json:"cvss_vector,omitempty"`
+	Cvss        *CVSSDetails `protobuf:"bytes,12,opt,name=cvss,proto3" json:"cvss,omitempty"`
+	// e.g., container-analysis
+	Source string `protobuf:"bytes,13,opt,name=source,proto3" json:"source,omitempty"`
+	// e.g., CWE-79
+	Cwes []string `protobuf:"bytes,14,rep,name=cwes,proto3" json:"cwes,omitempty"`
+	// URLs of the references, kept for consumers of earlier versions.
+	Urls       []string     `protobuf:"bytes,15,rep,name=urls,proto3" json:"urls,omitempty"`
+	References []*Reference `protobuf:"bytes,16,rep,name=references,proto3" json:"references,omitempty"`
+	// Package URL of the affected package, e.g. pkg:deb/debian/openssl@1.1.1n-0+deb11u3
+	Purl string `protobuf:"bytes,17,opt,name=purl,proto3" json:"purl,omitempty"`
+	// When the finding was first reported for the image, if known.
+	FirstSeen *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"`
+	// When the finding must be fixed by, if an SLA applies.
+	SlaDue      *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=sla_due,json=slaDue,proto3" json:"sla_due,omitempty"`
+	SlaBreached bool                   `protobuf:"varint,20,opt,name=sla_breached,json=slaBreached,proto3" json:"sla_breached,omitempty"`
+	// When the finding was last reported for the image, if tracked.
+	LastSeen *timestamppb.Timestamp `protobuf:"bytes,21,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	// When the finding was first no longer reported, for resolved findings.
+	ResolvedAt *timestamppb.Timestamp `protobuf:"bytes,22,opt,name=resolved_at,json=resolvedAt,proto3" json:"resolved_at,omitempty"`
+	// Status in the distro security tracker, e.g. no-dsa, ignored, postponed
+	DistroStatus string `protobuf:"bytes,23,opt,name=distro_status,json=distroStatus,proto3" json:"distro_status,omitempty"`
+	// Packages from the direct dependency to the affected package, from an SBOM
+	DependencyPath []string `protobuf:"bytes,24,rep,name=dependency_path,json=dependencyPath,proto3" json:"dependency_path,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Vulnerability) Reset() {
+	*x = Vulnerability{}
+	mi := &file_drydockpb_result_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vulnerability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vulnerability) ProtoMessage() {}
+
+func (x *Vulnerability) ProtoReflect() protoreflect.Message {
+	mi := &file_drydockpb_result_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vulnerability.ProtoReflect.Descriptor instead.
+func (*Vulnerability) Descriptor() ([]byte, []int) {
+	return file_drydockpb_result_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Vulnerability) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *Vulnerability) GetPackageName() string {
+	if x != nil {
+		return x.PackageName
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetInstalledVersion() string {
+	if x != nil {
+		return x.InstalledVersion
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetFixedVersion() string {
+	if x != nil {
+		return x.FixedVersion
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetFixState() FixState {
+	if x != nil {
+		return x.FixState
+	}
+	return FixState_FIX_STATE_UNKNOWN
+}
+
+func (x *Vulnerability) GetPackageType() string {
+	if x != nil {
+		return x.PackageType
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetCvssScore() float32 {
+	if x != nil {
+		return x.CvssScore
+	}
+	return 0
+}
+
+func (x *Vulnerability) GetCvssVersion() string {
+	if x != nil {
+		return x.CvssVersion
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetCvssVector() string {
+	if x != nil {
+		return x.CvssVector
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetCvss() *CVSSDetails {
+	if x != nil {
+		return x.Cvss
+	}
+	return nil
+}
+
+func (x *Vulnerability) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetCwes() []string {
+	if x != nil {
+		return x.Cwes
+	}
+	return nil
+}
+
+func (x *Vulnerability) GetUrls() []string {
+	if x != nil {
+		return x.Urls
+	}
+	return nil
+}
+
+func (x *Vulnerability) GetReferences() []*Reference {
+	if x != nil {
+		return x.References
+	}
+	return nil
+}
+
+func (x *Vulnerability) GetPurl() string {
+	if x != nil {
+		return x.Purl
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetFirstSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstSeen
+	}
+	return nil
+}
+
+func (x *Vulnerability) GetSlaDue() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SlaDue
+	}
+	return nil
+}
+
+func (x *Vulnerability) GetSlaBreached() bool {
+	if x != nil {
+		return x.SlaBreached
+	}
+	return false
+}
+
+func (x *Vulnerability) GetLastSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeen
+	}
+	return nil
+}
+
+func (x *Vulnerability) GetResolvedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ResolvedAt
+	}
+	return nil
+}
+
+func (x *Vulnerability) GetDistroStatus() string {
+	if x != nil {
+		return x.DistroStatus
+	}
+	return ""
+}
+
+func (x *Vulnerability) GetDependencyPath() []string {
+	if x != nil {
+		return x.DependencyPath
+	}
+	return nil
+}
+
+// VulnerabilitySummary provides aggregated statistics of an image's vulnerabilities.
+type VulnerabilitySummary struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	TotalCount int32                  `protobuf:"varint,1,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	// Keyed by severity name (e.g., HIGH).
+	CountBySeverity    map[string]int32 `protobuf:"bytes,2,rep,name=count_by_severity,json=countBySeverity,proto3" json:"count_by_severity,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	FixableCount       int32            `protobuf:"varint,3,opt,name=fixable_count,json=fixableCount,proto3" json:"fixable_count,omitempty"`
+	CountByPackageType map[string]int32 `protobuf:"bytes,4,rep,name=count_by_package_type,json=countByPackageType,proto3" json:"count_by_package_type,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// Keyed by fix state name (e.g., FIX_AVAILABLE).
+	CountByFixState map[string]int32 `protobuf:"bytes,5,rep,name=count_by_fix_state,json=countByFixState,proto3" json:"count_by_fix_state,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	SlaBreachCount  int32            `protobuf:"varint,6,opt,name=sla_breach_count,json=slaBreachCount,proto3" json:"sla_breach_count,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *VulnerabilitySummary) Reset() {
+	*x = VulnerabilitySummary{}
+	mi := &file_drydockpb_result_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VulnerabilitySummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VulnerabilitySummary) ProtoMessage() {}
+
+func (x *VulnerabilitySummary) ProtoReflect() protoreflect.Message {
+	mi := &file_drydockpb_result_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VulnerabilitySummary.ProtoReflect.Descriptor instead.
+func (*VulnerabilitySummary) Descriptor() ([]byte, []int) {
+	return file_drydockpb_result_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VulnerabilitySummary) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *VulnerabilitySummary) GetCountBySeverity() map[string]int32 {
+	if x != nil {
+		return x.CountBySeverity
+	}
+	return nil
+}
+
+func (x *VulnerabilitySummary) GetFixableCount() int32 {
+	if x != nil {
+		return x.FixableCount
+	}
+	return 0
+}
+
+func (x *VulnerabilitySummary) GetCountByPackageType() map[string]int32 {
+	if x != nil {
+		return x.CountByPackageType
+	}
+	return nil
+}
+
+func (x *VulnerabilitySummary) GetCountByFixState() map[string]int32 {
+	if x != nil {
+		return x.CountByFixState
+	}
+	return nil
+}
+
+func (x *VulnerabilitySummary) GetSlaBreachCount() int32 {
+	if x != nil {
+		return x.SlaBreachCount
+	}
+	return 0
+}
+
+// ScannerInfo identifies a vulnerability scanning engine.
+type ScannerInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScannerInfo) Reset() {
+	*x = ScannerInfo{}
+	mi := &file_drydockpb_result_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScannerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScannerInfo) ProtoMessage() {}
+
+func (x *ScannerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_drydockpb_result_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScannerInfo.ProtoReflect.Descriptor instead.
+func (*ScannerInfo) Descriptor() ([]byte, []int) {
+	return file_drydockpb_result_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ScannerInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ScannerInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+// ScanMetadata describes the analysis of a single image.
+type ScanMetadata struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	DurationMillis     int64                  `protobuf:"varint,1,opt,name=duration_millis,json=durationMillis,proto3" json:"duration_millis,omitempty"`
+	OccurrencesFetched int32                  `protobuf:"varint,2,opt,name=occurrences_fetched,json=occurrencesFetched,proto3" json:"occurrences_fetched,omitempty"`
+	Truncated          bool                   `protobuf:"varint,3,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	Warnings           []string               `protobuf:"bytes,4,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	ContinuousAnalysis string                 `protobuf:"bytes,5,opt,name=continuous_analysis,json=continuousAnalysis,proto3" json:"continuous_analysis,omitempty"`
+	LastAnalysisTime   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_analysis_time,json=lastAnalysisTime,proto3" json:"last_analysis_time,omitempty"`
+	Stale              bool                   `protobuf:"varint,7,opt,name=stale,proto3" json:"stale,omitempty"`
+	RefreshRequested   bool                   `protobuf:"varint,8,opt,name=refresh_requested,json=refreshRequested,proto3" json:"refresh_requested,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ScanMetadata) Reset() {
+	*x = ScanMetadata{}
+	mi := &file_drydockpb_result_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanMetadata) ProtoMessage() {}
+
+func (x *ScanMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_drydockpb_result_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanMetadata.ProtoReflect.Descriptor instead.
+func (*ScanMetadata) Descriptor() ([]byte, []int) {
+	return file_drydockpb_result_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ScanMetadata) GetDurationMillis() int64 {
+	if x != nil {
+		return x.DurationMillis
+	}
+	return 0
+}
+
+func (x *ScanMetadata) GetOccurrencesFetched() int32 {
+	if x != nil {
+		return x.OccurrencesFetched
+	}
+	return 0
+}
+
+func (x *ScanMetadata) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *ScanMetadata) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *ScanMetadata) GetContinuousAnalysis() string {
+	if x != nil {
+		return x.ContinuousAnalysis
+	}
+	return ""
+}
+
+func (x *ScanMetadata) GetLastAnalysisTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAnalysisTime
+	}
+	return nil
+}
+
+func (x *ScanMetadata) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *ScanMetadata) GetRefreshRequested() bool {
+	if x != nil {
+		return x.RefreshRequested
+	}
+	return false
+}
+
+// AnalyzeResult is the analysis result of a single image.
+type AnalyzeResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Version of the result format; see schemas.SchemaVersion.
+	SchemaVersion   string                 `protobuf:"bytes,1,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	Artifact        *ArtifactReference     `protobuf:"bytes,2,opt,name=artifact,proto3" json:"artifact,omitempty"`
+	ScanTime        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=scan_time,json=scanTime,proto3" json:"scan_time,omitempty"`
+	Vulnerabilities []*Vulnerability       `protobuf:"bytes,4,rep,name=vulnerabilities,proto3" json:"vulnerabilities,omitempty"`
+	Summary         *VulnerabilitySummary  `protobuf:"bytes,5,opt,name=summary,proto3" json:"summary,omitempty"`
+	Scanner         *ScannerInfo           `protobuf:"bytes,6,opt,name=scanner,proto3" json:"scanner,omitempty"`
+	Metadata        *ScanMetadata          `protobuf:"bytes,7,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// True when the scan was interrupted before all images were analyzed.
+	Partial      bool             `protobuf:"varint,8,opt,name=partial,proto3" json:"partial,omitempty"`
+	Image        *ImageMetadata   `protobuf:"bytes,9,opt,name=image,proto3" json:"image,omitempty"`
+	Remediations []*Remediation   `protobuf:"bytes,10,rep,name=remediations,proto3" json:"remediations,omitempty"`
+	BaseImage    *BaseImageAdvice `protobuf:"bytes,11,opt,name=base_image,json=baseImage,proto3" json:"base_image,omitempty"`
+	// Findings of earlier scans of the image that are gone, if tracked.
+	Resolved []*Vulnerability `protobuf:"bytes,12,rep,name=resolved,proto3" json:"resolved,omitempty"`
+	// Findings left out of the vulnerabilities by suppressions.
+	Suppressed []*SuppressedFinding `protobuf:"bytes,13,rep,name=suppressed,proto3" json:"suppressed,omitempty"`
+	// True when the image is known to run in a workload.
+	Deployed      bool        `protobuf:"varint,14,opt,name=deployed,proto3" json:"deployed,omitempty"`
+	Workloads     []*Workload `protobuf:"bytes,15,rep,name=workloads,proto3" json:"workloads,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeResult) Reset() {
+	*x = AnalyzeResult{}
+	mi := &file_drydockpb_result_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeResult) ProtoMessage() {}
+
+func (x *AnalyzeResult) ProtoReflect() protoreflect.Message {
+	mi := &file_drydockpb_result_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeResult.ProtoReflect.Descriptor instead.
+func (*AnalyzeResult) Descriptor() ([]byte, []int) {
+	return file_drydockpb_result_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AnalyzeResult) GetSchemaVersion() string {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return ""
+}
+
+func (x *AnalyzeResult) GetArtifact() *ArtifactReference {
+	if x != nil {
+		return x.Artifact
+	}
+	return nil
+}
+
+func (x *AnalyzeResult) GetScanTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScanTime
+	}
+	return nil
+}
+
+func (x *AnalyzeResult) GetVulnerabilities() []*Vulnerability {
+	if x != nil {
+		return x.Vulnerabilities
+	}
+	return nil
+}
+
+func (x *AnalyzeResult) GetSummary() *VulnerabilitySummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *AnalyzeResult) GetScanner() *ScannerInfo {
+	if x != nil {
+		return x.Scanner
+	}
+	return nil
+}
+
+func (x *AnalyzeResult) GetMetadata() *ScanMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *AnalyzeResult) GetPartial() bool {
+	if x != nil {
+		return x.Partial
+	}
+	return false
+}
+
+func (x *AnalyzeResult) GetImage() *ImageMetadata {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *AnalyzeResult) GetRemediations() []*Remediation {
+	if x != nil {
+		return x.Remediations
+	}
+	return nil
+}
+
+func (x *AnalyzeResult) GetBaseImage() *BaseImageAdvice {
+	if x != nil {
+		return x.BaseImage
+	}
+	return nil
+}
+
+func (x *AnalyzeResult) GetResolved() []*Vulnerability {
+	if x != nil {
+		return x.Resolved
+	}
+	return nil
+}
+
+func (x *AnalyzeResult) GetSuppressed() []*SuppressedFinding {
+	if x != nil {
+		return x.Suppressed
+	}
+	return nil
+}
+
+func (x *AnalyzeResult) GetDeployed() bool {
+	if x != nil {
+		return x.Deployed
+	}
+	return false
+}
+
+func (x *AnalyzeResult) GetWorkloads() []*Workload {
+	if x != nil {
+		return x.Workloads
+	}
+	return nil
+}
+
+// Workload is a running workload that uses an image.
+type Workload struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// e.g., cloud-run, kubernetes
+	Platform       string `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
+	Name           string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Location       string `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	InternetFacing bool   `protobuf:"varint,4,opt,name=internet_facing,json=internetFacing,proto3" json:"internet_facing,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Workload) Reset() {
+	*x = Workload{}
+	mi := &file_drydockpb_result_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Workload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Workload) ProtoMessage() {}
+
+func (x *Workload) ProtoReflect() protoreflect.Message {
+	mi := &file_drydockpb_result_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Workload.ProtoReflect.Descriptor instead.
+func (*Workload) Descriptor() ([]byte, []int) {
+	return file_drydockpb_result_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Workload) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *Workload) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Workload) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *Workload) GetInternetFacing() bool {
+	if x != nil {
+		return x.InternetFacing
+	}
+	return false
+}
+
+// SuppressedFinding is a finding left out of the reported vulnerabilities by a suppression.
+type SuppressedFinding struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vulnerability *Vulnerability         `protobuf:"bytes,1,opt,name=vulnerability,proto3" json:"vulnerability,omitempty"`
+	Suppression   *Suppression           `protobuf:"bytes,2,opt,name=suppression,proto3" json:"suppression,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuppressedFinding) Reset() {
+	*x = SuppressedFinding{}
+	mi := &file_drydockpb_result_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuppressedFinding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuppressedFinding) ProtoMessage() {}
+
+func (x *SuppressedFinding) ProtoReflect() protoreflect.Message {
+	mi := &file_drydockpb_result_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuppressedFinding.ProtoReflect.Descriptor instead.
+func (*SuppressedFinding) Descriptor() ([]byte, []int) {
+	return file_drydockpb_result_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SuppressedFinding) GetVulnerability() *Vulnerability {
+	if x != nil {
+		return x.Vulnerability
+	}
+	return nil
+}
+
+func (x *SuppressedFinding) GetSuppression() *Suppression {
+	if x != nil {
+		return x.Suppression
+	}
+	return nil
+}
+
+// Suppression is an accepted exception for a vulnerability.
+type Suppression struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Empty for any package.
+	PackageName string `protobuf:"bytes,2,opt,name=package_name,json=packageName,proto3" json:"package_name,omitempty"`
+	Reason      string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	By          string `protobuf:"bytes,4,opt,name=by,proto3" json:"by,omitempty"`
+	// Unset when the suppression does not expire.
+	Until *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=until,proto3" json:"until,omitempty"`
+	// e.g., skip-cve
+	Source        string `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Suppression) Reset() {
+	*x = Suppression{}
+	mi := &file_drydockpb_result_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Suppression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Suppression) ProtoMessage() {}
+
+func (x *Suppression) ProtoReflect() protoreflect.Message {
+	mi := &file_drydockpb_result_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Suppression.ProtoReflect.Descriptor instead.
+func (*Suppression) Descriptor() ([]byte, []int) {
+	return file_drydockpb_result_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Suppression) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Suppression) GetPackageName() string {
+	if x != nil {
+		return x.PackageName
+	}
+	return ""
+}
+
+func (x *Suppression) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Suppression) GetBy() string {
+	if x != nil {
+		return x.By
+	}
+	return ""
+}
+
+func (x *Suppression) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *Suppression) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+// BaseImageAdvice compares the base image of an image with its newest version.
+type BaseImageAdvice struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Current           string                 `protobuf:"bytes,1,opt,name=current,proto3" json:"current,omitempty"`
+	Recommended       string                 `protobuf:"bytes,2,opt,name=recommended,proto3" json:"recommended,omitempty"`
+	RemovedBySeverity map[string]int32       `protobuf:"bytes,3,rep,name=removed_by_severity,json=removedBySeverity,proto3" json:"removed_by_severity,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	AddedBySeverity   map[string]int32       `protobuf:"bytes,4,rep,name=added_by_severity,json=addedBySeverity,proto3" json:"added_by_severity,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Message           string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *BaseImageAdvice) Reset() {
+	*x = BaseImageAdvice{}
+	mi := &file_drydockpb_result_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BaseImageAdvice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BaseImageAdvice) ProtoMessage() {}
+
+func (x *BaseImageAdvice) ProtoReflect() protoreflect.Message {
+	mi := &file_drydockpb_result_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BaseImageAdvice.ProtoReflect.Descriptor instead.
+func (*BaseImageAdvice) Descriptor() ([]byte, []int) {
+	return file_drydockpb_result_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BaseImageAdvice) GetCurrent() string {
+	if x != nil {
+		return x.Current
+	}
+	return ""
+}
+
+func (x *BaseImageAdvice) GetRecommended() string {
+	if x != nil {
+		return x.Recommended
+	}
+	return ""
+}
+
+func (x *BaseImageAdvice) GetRemovedBySeverity() map[string]int32 {
+	if x != nil {
+		return x.RemovedBySeverity
+	}
+	return nil
+}
+
+func (x *BaseImageAdvice) GetAddedBySeverity() map[string]int32 {
+	if x != nil {
+		return x.AddedBySeverity
+	}
+	return nil
+}
+
+func (x *BaseImageAdvice) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// Remediation is a package upgrade fixing some vulnerabilities of an image.
+type Remediation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// e.g., apt, apk, dnf
+	PackageManager   string `protobuf:"bytes,1,opt,name=package_manager,json=packageManager,proto3" json:"package_manager,omitempty"`
+	PackageName      string `protobuf:"bytes,2,opt,name=package_name,json=packageName,proto3" json:"package_name,omitempty"`
+	InstalledVersion string `protobuf:"bytes,3,opt,name=installed_version,json=installedVersion,proto3" json:"installed_version,omitempty"`
+	FixedVersion     string `protobuf:"bytes,4,opt,name=fixed_version,json=fixedVersion,proto3" json:"fixed_version,omitempty"`
+	// e.g., apt-get install --only-upgrade openssl=1.1.1t
+	Command          string   `protobuf:"bytes,5,opt,name=command,proto3" json:"command,omitempty"`
+	VulnerabilityIds []string `protobuf:"bytes,6,rep,name=vulnerability_ids,json=vulnerabilityIds,proto3" json:"vulnerability_ids,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Remediation) Reset() {
+	*x = Remediation{}
+	mi := &file_drydockpb_result_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Remediation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Remediation) ProtoMessage() {}
+
+func (x *Remediation) ProtoReflect() protoreflect.Message {
+	mi := &file_drydockpb_result_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Remediation.ProtoReflect.Descriptor instead.
+func (*Remediation) Descriptor() ([]byte, []int) {
+	return file_drydockpb_result_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Remediation) GetPackageManager() string {
+	if x != nil {
+		return x.PackageManager
+	}
+	return ""
+}
+
+func (x *Remediation) GetPackageName() string {
+	if x != nil {
+		return x.PackageName
+	}
+	return ""
+}
+
+func (x *Remediation) GetInstalledVersion() string {
+	if x != nil {
+		return x.InstalledVersion
+	}
+	return ""
+}
+
+func (x *Remediation) GetFixedVersion() string {
+	if x != nil {
+		return x.FixedVersion
+	}
+	return ""
+}
+
+func (x *Remediation) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *Remediation) GetVulnerabilityIds() []string {
+	if x != nil {
+		return x.VulnerabilityIds
+	}
+	return nil
+}
+
+var File_drydockpb_result_proto protoreflect.FileDescriptor
+
+const file_drydockpb_result_proto_rawDesc = "" +
+	"\n" +
+	"\x16drydockpb/result.proto\x12\n" +
+	"drydock.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe3\x01\n" +
+	"\x11ArtifactReference\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x02 \x01(\tR\tprojectId\x12#\n" +
+	"\rrepository_id\x18\x03 \x01(\tR\frepositoryId\x12\x1d\n" +
+	"\n" +
+	"image_name\x18\x04 \x01(\tR\timageName\x12\x15\n" +
+	"\x03tag\x18\x05 \x01(\tH\x00R\x03tag\x88\x01\x01\x12\x1b\n" +
+	"\x06digest\x18\x06 \x01(\tH\x01R\x06digest\x88\x01\x01\x12\x10\n" +
+	"\x03uri\x18\a \x01(\tR\x03uriB\x06\n" +
+	"\x04_tagB\t\n" +
+	"\a_digest\"\x96\x02\n" +
+	"\rImageMetadata\x12\x12\n" +
+	"\x04tags\x18\x01 \x03(\tR\x04tags\x12;\n" +
+	"\vupload_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"uploadTime\x12;\n" +
+	"\vupdate_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"updateTime\x129\n" +
+	"\n" +
+	"build_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tbuildTime\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x05 \x01(\x03R\tsizeBytes\x12\x1d\n" +
+	"\n" +
+	"media_type\x18\x06 \x01(\tR\tmediaType\"\x82\x01\n" +
+	"\vCVSSDetails\x12\x1d\n" +
+	"\n" +
+	"base_score\x18\x01 \x01(\x02R\tbaseScore\x121\n" +
+	"\x14exploitability_score\x18\x02 \x01(\x02R\x13exploitabilityScore\x12!\n" +
+	"\fimpact_score\x18\x03 \x01(\x02R\vimpactScore\"L\n" +
+	"\tReference\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12-\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x19.drydock.v1.ReferenceTypeR\x04type\"\xb0\a\n" +
+	"\rVulnerability\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
+	"\bseverity\x18\x02 \x01(\x0e2\x14.drydock.v1.SeverityR\bseverity\x12!\n" +
+	"\fpackage_name\x18\x03 \x01(\tR\vpackageName\x12+\n" +
+	"\x11installed_version\x18\x04 \x01(\tR\x10installedVersion\x12#\n" +
+	"\rfixed_version\x18\x05 \x01(\tR\ffixedVersion\x121\n" +
+	"\tfix_state\x18\x06 \x01(\x0e2\x14.drydock.v1.FixStateR\bfixState\x12!\n" +
+	"\fpackage_type\x18\a \x01(\tR\vpackageType\x12 \n" +
+	"\vdescription\x18\b \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"cvss_score\x18\t \x01(\x02R\tcvssScore\x12!\n" +
+	"\fcvss_version\x18\n" +
+	" \x01(\tR\vcvssVersion\x12\x1f\n" +
+	"\vcvss_vector\x18\v \x01(\tR\n" +
+	"cvssVector\x12+\n" +
+	"\x04cvss\x18\f \x01(\v2\x17.drydock.v1.CVSSDetailsR\x04cvss\x12\x16\n" +
+	"\x06source\x18\r \x01(\tR\x06source\x12\x12\n" +
+	"\x04cwes\x18\x0e \x03(\tR\x04cwes\x12\x12\n" +
+	"\x04urls\x18\x0f \x03(\tR\x04urls\x125\n" +
+	"\n" +
+	"references\x18\x10 \x03(\v2\x15.drydock.v1.ReferenceR\n" +
+	"references\x12\x12\n" +
+	"\x04purl\x18\x11 \x01(\tR\x04purl\x129\n" +
+	"\n" +
+	"first_seen\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\tfirstSeen\x123\n" +
+	"\asla_due\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampR\x06slaDue\x12!\n" +
+	"\fsla_breached\x18\x14 \x01(\bR\vslaBreached\x127\n" +
+	"\tlast_seen\x18\x15 \x01(\v2\x1a.google.protobuf.TimestampR\blastSeen\x12;\n" +
+	"\vresolved_at\x18\x16 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"resolvedAt\x12#\n" +
+	"\rdistro_status\x18\x17 \x01(\tR\fdistroStatus\x12'\n" +
+	"\x0fdependency_path\x18\x18 \x03(\tR\x0edependencyPath\"\x89\x05\n" +
+	"\x14VulnerabilitySummary\x12\x1f\n" +
+	"\vtotal_count\x18\x01 \x01(\x05R\n" +
+	"totalCount\x12a\n" +
+	"\x11count_by_severity\x18\x02 \x03(\v25.drydock.v1.VulnerabilitySummary.CountBySeverityEntryR\x0fcountBySeverity\x12#\n" +
+	"\rfixable_count\x18\x03 \x01(\x05R\ffixableCount\x12k\n" +
+	"\x15count_by_package_type\x18\x04 \x03(\v28.drydock.v1.VulnerabilitySummary.CountByPackageTypeEntryR\x12countByPackageType\x12b\n" +
+	"\x12count_by_fix_state\x18\x05 \x03(\v25.drydock.v1.VulnerabilitySummary.CountByFixStateEntryR\x0fcountByFixState\x12(\n" +
+	"\x10sla_breach_count\x18\x06 \x01(\x05R\x0eslaBreachCount\x1aB\n" +
+	"\x14CountBySeverityEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1aE\n" +
+	"\x17CountByPackageTypeEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1aB\n" +
+	"\x14CountByFixStateEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\";\n" +
+	"\vScannerInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\"\xe0\x02\n" +
+	"\fScanMetadata\x12'\n" +
+	"\x0fduration_millis\x18\x01 \x01(\x03R\x0edurationMillis\x12/\n" +
+	"\x13occurrences_fetched\x18\x02 \x01(\x05R\x12occurrencesFetched\x12\x1c\n" +
+	"\ttruncated\x18\x03 \x01(\bR\ttruncated\x12\x1a\n" +
+	"\bwarnings\x18\x04 \x03(\tR\bwarnings\x12/\n" +
+	"\x13continuous_analysis\x18\x05 \x01(\tR\x12continuousAnalysis\x12H\n" +
+	"\x12last_analysis_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x10lastAnalysisTime\x12\x14\n" +
+	"\x05stale\x18\a \x01(\bR\x05stale\x12+\n" +
+	"\x11refresh_requested\x18\b \x01(\bR\x10refreshRequested\"\x9e\x06\n" +
+	"\rAnalyzeResult\x12%\n" +
+	"\x0eschema_version\x18\x01 \x01(\tR\rschemaVersion\x129\n" +
+	"\bartifact\x18\x02 \x01(\v2\x1d.drydock.v1.ArtifactReferenceR\bartifact\x127\n" +
+	"\tscan_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bscanTime\x12C\n" +
+	"\x0fvulnerabilities\x18\x04 \x03(\v2\x19.drydock.v1.VulnerabilityR\x0fvulnerabilities\x12:\n" +
+	"\asummary\x18\x05 \x01(\v2 .drydock.v1.VulnerabilitySummaryR\asummary\x121\n" +
+	"\ascanner\x18\x06 \x01(\v2\x17.drydock.v1.ScannerInfoR\ascanner\x124\n" +
+	"\bmetadata\x18\a \x01(\v2\x18.drydock.v1.ScanMetadataR\bmetadata\x12\x18\n" +
+	"\apartial\x18\b \x01(\bR\apartial\x12/\n" +
+	"\x05image\x18\t \x01(\v2\x19.drydock.v1.ImageMetadataR\x05image\x12;\n" +
+	"\fremediations\x18\n" +
+	" \x03(\v2\x17.drydock.v1.RemediationR\fremediations\x12:\n" +
+	"\n" +
+	"base_image\x18\v \x01(\v2\x1b.drydock.v1.BaseImageAdviceR\tbaseImage\x125\n" +
+	"\bresolved\x18\f \x03(\v2\x19.drydock.v1.VulnerabilityR\bresolved\x12=\n" +
+	"\n" +
+	"suppressed\x18\r \x03(\v2\x1d.drydock.v1.SuppressedFindingR\n" +
+	"suppressed\x12\x1a\n" +
+	"\bdeployed\x18\x0e \x01(\bR\bdeployed\x122\n" +
+	"\tworkloads\x18\x0f \x03(\v2\x14.drydock.v1.WorkloadR\tworkloads\"\x7f\n" +
+	"\bWorkload\x12\x1a\n" +
+	"\bplatform\x18\x01 \x01(\tR\bplatform\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\blocation\x18\x03 \x01(\tR\blocation\x12'\n" +
+	"\x0finternet_facing\x18\x04 \x01(\bR\x0einternetFacing\"\x8f\x01\n" +
+	"\x11SuppressedFinding\x12?\n" +
+	"\rvulnerability\x18\x01 \x01(\v2\x19.drydock.v1.VulnerabilityR\rvulnerability\x129\n" +
+	"\vsuppression\x18\x02 \x01(\v2\x17.drydock.v1.SuppressionR\vsuppression\"\xb2\x01\n" +
+	"\vSuppression\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fpackage_name\x18\x02 \x01(\tR\vpackageName\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x0e\n" +
+	"\x02by\x18\x04 \x01(\tR\x02by\x120\n" +
+	"\x05until\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\x12\x16\n" +
+	"\x06source\x18\x06 \x01(\tR\x06source\"\xb3\x03\n" +
+	"\x0fBaseImageAdvice\x12\x18\n" +
+	"\acurrent\x18\x01 \x01(\tR\acurrent\x12 \n" +
+	"\vrecommended\x18\x02 \x01(\tR\vrecommended\x12b\n" +
+	"\x13removed_by_severity\x18\x03 \x03(\v22.drydock.v1.BaseImageAdvice.RemovedBySeverityEntryR\x11removedBySeverity\x12\\\n" +
+	"\x11added_by_severity\x18\x04 \x03(\v20.drydock.v1.BaseImageAdvice.AddedBySeverityEntryR\x0faddedBySeverity\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\x1aD\n" +
+	"\x16RemovedBySeverityEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1aB\n" +
+	"\x14AddedBySeverityEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\xf2\x01\n" +
+	"\vRemediation\x12'\n" +
+	"\x0fpackage_manager\x18\x01 \x01(\tR\x0epackageManager\x12!\n" +
+	"\fpackage_name\x18\x02 \x01(\tR\vpackageName\x12+\n" +
+	"\x11installed_version\x18\x03 \x01(\tR\x10installedVersion\x12#\n" +
+	"\rfixed_version\x18\x04 \x01(\tR\ffixedVersion\x12\x18\n" +
+	"\acommand\x18\x05 \x01(\tR\acommand\x12+\n" +
+	"\x11vulnerability_ids\x18\x06 \x03(\tR\x10vulnerabilityIds*\x8b\x01\n" +
+	"\bSeverity\x12\x18\n" +
+	"\x14SEVERITY_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10SEVERITY_MINIMAL\x10\x01\x12\x10\n" +
+	"\fSEVERITY_LOW\x10\x02\x12\x13\n" +
+	"\x0fSEVERITY_MEDIUM\x10\x03\x12\x11\n" +
+	"\rSEVERITY_HIGH\x10\x04\x12\x15\n" +
+	"\x11SEVERITY_CRITICAL\x10\x05*v\n" +
+	"\bFixState\x12\x15\n" +
+	"\x11FIX_STATE_UNKNOWN\x10\x00\x12\x1b\n" +
+	"\x17FIX_STATE_FIX_AVAILABLE\x10\x01\x12\x1e\n" +
+	"\x1aFIX_STATE_NO_FIX_AVAILABLE\x10\x02\x12\x16\n" +
+	"\x12FIX_STATE_WONT_FIX\x10\x03*|\n" +
+	"\rReferenceType\x12\x1e\n" +
+	"\x1aREFERENCE_TYPE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17REFERENCE_TYPE_ADVISORY\x10\x01\x12\x16\n" +
+	"\x12REFERENCE_TYPE_FIX\x10\x02\x12\x16\n" +
+	"\x12REFERENCE_TYPE_WEB\x10\x03B(Z&github.com/hiro-o918/drydock/drydockpbb\x06proto3"
+
+var (
+	file_drydockpb_result_proto_rawDescOnce sync.Once
+	file_drydockpb_result_proto_rawDescData []byte
+)
+
+func file_drydockpb_result_proto_rawDescGZIP() []byte {
+	file_drydockpb_result_proto_rawDescOnce.Do(func() {
+		file_drydockpb_result_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_drydockpb_result_proto_rawDesc), len(file_drydockpb_result_proto_rawDesc)))
+	})
+	return file_drydockpb_result_proto_rawDescData
+}
+
+var file_drydockpb_result_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_drydockpb_result_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_drydockpb_result_proto_goTypes = []any{
+	(Severity)(0),                 // 0: drydock.v1.Severity
+	(FixState)(0),                 // 1: drydock.v1.FixState
+	(ReferenceType)(0),            // 2: drydock.v1.ReferenceType
+	(*ArtifactReference)(nil),     // 3: drydock.v1.ArtifactReference
+	(*ImageMetadata)(nil),         // 4: drydock.v1.ImageMetadata
+	(*CVSSDetails)(nil),           // 5: drydock.v1.CVSSDetails
+	(*Reference)(nil),             // 6: drydock.v1.Reference
+	(*Vulnerability)(nil),         // 7: drydock.v1.Vulnerability
+	(*VulnerabilitySummary)(nil),  // 8: drydock.v1.VulnerabilitySummary
+	(*ScannerInfo)(nil),           // 9: drydock.v1.ScannerInfo
+	(*ScanMetadata)(nil),          // 10: drydock.v1.ScanMetadata
+	(*AnalyzeResult)(nil),         // 11: drydock.v1.AnalyzeResult
+	(*Workload)(nil),              // 12: drydock.v1.Workload
+	(*SuppressedFinding)(nil),     // 13: drydock.v1.SuppressedFinding
+	(*Suppression)(nil),           // 14: drydock.v1.Suppression
+	(*BaseImageAdvice)(nil),       // 15: drydock.v1.BaseImageAdvice
+	(*Remediation)(nil),           // 16: drydock.v1.Remediation
+	nil,                           // 17: drydock.v1.VulnerabilitySummary.CountBySeverityEntry
+	nil,                           // 18: drydock.v1.VulnerabilitySummary.CountByPackageTypeEntry
+	nil,                           // 19: drydock.v1.VulnerabilitySummary.CountByFixStateEntry
+	nil,                           // 20: drydock.v1.BaseImageAdvice.RemovedBySeverityEntry
+	nil,                           // 21: drydock.v1.BaseImageAdvice.AddedBySeverityEntry
+	(*timestamppb.Timestamp)(nil), // 22: google.protobuf.Timestamp
+}
+var file_drydockpb_result_proto_depIdxs = []int32{
+	22, // 0: drydock.v1.ImageMetadata.upload_time:type_name -> google.protobuf.Timestamp
+	22, // 1: drydock.v1.ImageMetadata.update_time:type_name -> google.protobuf.Timestamp
+	22, // 2: drydock.v1.ImageMetadata.build_time:type_name -> google.protobuf.Timestamp
+	2,  // 3: drydock.v1.Reference.type:type_name -> drydock.v1.ReferenceType
+	0,  // 4: drydock.v1.Vulnerability.severity:type_name -> drydock.v1.Severity
+	1,  // 5: drydock.v1.Vulnerability.fix_state:type_name -> drydock.v1.FixState
+	5,  // 6: drydock.v1.Vulnerability.cvss:type_name -> drydock.v1.CVSSDetails
+	6,  // 7: drydock.v1.Vulnerability.references:type_name -> drydock.v1.Reference
+	22, // 8: drydock.v1.Vulnerability.first_seen:type_name -> google.protobuf.Timestamp
+	22, // 9: drydock.v1.Vulnerability.sla_due:type_name -> google.protobuf.Timestamp
+	22, // 10: drydock.v1.Vulnerability.last_seen:type_name -> google.protobuf.Timestamp
+	22, // 11: drydock.v1.Vulnerability.resolved_at:type_name -> google.protobuf.Timestamp
+	17, // 12: drydock.v1.VulnerabilitySummary.count_by_severity:type_name -> drydock.v1.VulnerabilitySummary.CountBySeverityEntry
+	18, // 13: drydock.v1.VulnerabilitySummary.count_by_package_type:type_name -> drydock.v1.VulnerabilitySummary.CountByPackageTypeEntry
+	19, // 14: drydock.v1.VulnerabilitySummary.count_by_fix_state:type_name -> drydock.v1.VulnerabilitySummary.CountByFixStateEntry
+	22, // 15: drydock.v1.ScanMetadata.last_analysis_time:type_name -> google.protobuf.Timestamp
+	3,  // 16: drydock.v1.AnalyzeResult.artifact:type_name -> drydock.v1.ArtifactReference
+	22, // 17: drydock.v1.AnalyzeResult.scan_time:type_name -> google.protobuf.Timestamp
+	7,  // 18: drydock.v1.AnalyzeResult.vulnerabilities:type_name -> drydock.v1.Vulnerability
+	8,  // 19: drydock.v1.AnalyzeResult.summary:type_name -> drydock.v1.VulnerabilitySummary
+	9,  // 20: drydock.v1.AnalyzeResult.scanner:type_name -> drydock.v1.ScannerInfo
+	10, // 21: drydock.v1.AnalyzeResult.metadata:type_name -> drydock.v1.ScanMetadata
+	4,  // 22: drydock.v1.AnalyzeResult.image:type_name -> drydock.v1.ImageMetadata
+	16, // 23: drydock.v1.AnalyzeResult.remediations:type_name -> drydock.v1.Remediation
+	15, // 24: drydock.v1.AnalyzeResult.base_image:type_name -> drydock.v1.BaseImageAdvice
+	7,  // 25: drydock.v1.AnalyzeResult.resolved:type_name -> drydock.v1.Vulnerability
+	13, // 26: drydock.v1.AnalyzeResult.suppressed:type_name -> drydock.v1.SuppressedFinding
+	12, // 27: drydock.v1.AnalyzeResult.workloads:type_name -> drydock.v1.Workload
+	7,  // 28: drydock.v1.SuppressedFinding.vulnerability:type_name -> drydock.v1.Vulnerability
+	14, // 29: drydock.v1.SuppressedFinding.suppression:type_name -> drydock.v1.Suppression
+	22, // 30: drydock.v1.Suppression.until:type_name -> google.protobuf.Timestamp
+	20, // 31: drydock.v1.BaseImageAdvice.removed_by_severity:type_name -> drydock.v1.BaseImageAdvice.RemovedBySeverityEntry
+	21, // 32: drydock.v1.BaseImageAdvice.added_by_severity:type_name -> drydock.v1.BaseImageAdvice.AddedBySeverityEntry
+	33, // [33:33] is the sub-list for method output_type
+	33, // [33:33] is the sub-list for method input_type
+	33, // [33:33] is the sub-list for extension type_name
+	33, // [33:33] is the sub-list for extension extendee
+	0,  // [0:33] is the sub-list for field type_name
+}
+
+func init() { file_drydockpb_result_proto_init() }
+func file_drydockpb_result_proto_init() {
+	if File_drydockpb_result_proto != nil {
+		return
+	}
+	file_drydockpb_result_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_drydockpb_result_proto_rawDesc), len(file_drydockpb_result_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_drydockpb_result_proto_goTypes,
+		DependencyIndexes: file_drydockpb_result_proto_depIdxs,
+		EnumInfos:         file_drydockpb_result_proto_enumTypes,
+		MessageInfos:      file_drydockpb_result_proto_msgTypes,
+	}.Build()
+	File_drydockpb_result_proto = out.File
+	file_drydockpb_result_proto_goTypes = nil
+	file_drydockpb_result_proto_depIdxs = nil
+}
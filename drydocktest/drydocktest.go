@@ -0,0 +1,252 @@
+// Package drydocktest provides fakes of the drydock components, so that programs embedding drydock
+// can test their pipelines without Google Cloud credentials or network access:
+//
+//	resolver := drydocktest.NewResolver(drydocktest.Target("us-central1-docker.pkg.dev/p/repo/app:v1"))
+//	analyzer := drydocktest.NewAnalyzer(schemas.AnalyzeResult{Artifact: ..., Vulnerabilities: ...})
+//	exporter := &drydocktest.Exporter{}
+//	scanner, err := drydock.NewScanner(ctx, "us-central1",
+//		drydock.WithProjectID("p"),
+//		drydock.WithResolver(resolver),
+//		drydock.WithAnalyzer(analyzer),
+//		drydock.WithExporter(exporter),
+//	)
+//	...
+//	err = scanner.Scan(ctx, drydock.ScanOptions{MinSeverity: schemas.SeverityHigh})
+//	results := exporter.Results()
+package drydocktest
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// Target returns the scan target of an Artifact Registry image URI such as
+// "us-central1-docker.pkg.dev/project/repository/image:tag" or ".../image@sha256:...".
+// It panics if uri is not a valid Artifact Registry URI.
+func Target(uri string) drydock.ImageTarget {
+	artifact, err := schemas.ParseArtifactURI(uri)
+	if err != nil {
+		panic(err)
+	}
+	if !artifact.IsArtifactRegistry() {
+		panic("drydocktest: not an Artifact Registry image: " + uri)
+	}
+	var tags []string
+	if artifact.Tag != nil && *artifact.Tag != "" {
+		tags = []string{*artifact.Tag}
+	} else {
+		artifact.Tag = nil
+	}
+	if artifact.Digest != nil && *artifact.Digest == "" {
+		artifact.Digest = nil
+	}
+	return drydock.ImageTarget{
+		Artifact: artifact,
+		URI:      uri,
+		Location: strings.TrimSuffix(artifact.Host, "-docker.pkg.dev"),
+		Image:    &schemas.ImageMetadata{Tags: tags},
+	}
+}
+
+// Resolver is a drydock.Resolver yielding static targets, whatever the project and location.
+// Resolve options (e.g., repository priorities) are ignored.
+type Resolver struct {
+	targets []drydock.ImageTarget
+
+	// Err, if set, is yielded after the targets, as a failure to list the registry would be
+	Err error
+
+	mu     sync.Mutex
+	closed bool
+}
+
+var _ drydock.Resolver = (*Resolver)(nil)
+
+// NewResolver returns a resolver yielding the given targets.
+func NewResolver(targets ...drydock.ImageTarget) *Resolver {
+	return &Resolver{targets: targets}
+}
+
+// AllLatestImages yields the targets in order.
+func (r *Resolver) AllLatestImages(_ context.Context, _, _ string, _ ...drydock.ResolveOption) iter.Seq2[drydock.ImageTarget, error] {
+	return r.all()
+}
+
+// AllImages yields the targets in order.
+func (r *Resolver) AllImages(_ context.Context, _, _ string, _ ...drydock.ResolveOption) iter.Seq2[drydock.ImageTarget, error] {
+	return r.all()
+}
+
+func (r *Resolver) all() iter.Seq2[drydock.ImageTarget, error] {
+	return func(yield func(drydock.ImageTarget, error) bool) {
+		for _, t := range r.targets {
+			if !yield(t, nil) {
+				return
+			}
+		}
+		if r.Err != nil {
+			yield(drydock.ImageTarget{}, r.Err)
+		}
+	}
+}
+
+// ResolveImage returns the target of the same image with the reference's digest, or with its tag
+// ("latest" if it has neither).
+func (r *Resolver) ResolveImage(_ context.Context, ref schemas.ArtifactReference) (drydock.ImageTarget, error) {
+	for _, t := range r.targets {
+		if sameImage(t.Artifact, ref) && matchesVersion(t, ref) {
+			return t, nil
+		}
+	}
+	return drydock.ImageTarget{}, fmt.Errorf("image not found: %s", ref)
+}
+
+// Close marks the resolver as closed.
+func (r *Resolver) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	return nil
+}
+
+// Closed reports whether Close was called, e.g. by drydock.Scanner.Close.
+func (r *Resolver) Closed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closed
+}
+
+// Analyzer is a drydock.Analyzer returning canned results. Results are returned as given:
+// the filters of the request (severity, fixability, ...) are not applied. Images without a
+// canned result have no vulnerabilities.
+type Analyzer struct {
+	mu       sync.Mutex
+	results  []schemas.AnalyzeResult
+	errs     map[string]error
+	requests []drydock.AnalyzeRequest
+}
+
+var _ drydock.Analyzer = (*Analyzer)(nil)
+
+// NewAnalyzer returns an analyzer returning the given results for their artifacts. A result whose
+// artifact has no digest is returned for every digest of the image.
+func NewAnalyzer(results ...schemas.AnalyzeResult) *Analyzer {
+	return &Analyzer{results: results, errs: make(map[string]error)}
+}
+
+// SetError makes the analysis of the image fail with err. uri is an image URI as accepted by Target;
+// without a digest, the analysis of every digest of the image fails.
+func (a *Analyzer) SetError(uri string, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.errs[imageKey(Target(uri).Artifact)] = err
+}
+
+// Analyze returns the canned result of the requested artifact.
+func (a *Analyzer) Analyze(ctx context.Context, req drydock.AnalyzeRequest) (*schemas.AnalyzeResult, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.requests = append(a.requests, req)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for _, key := range []string{imageKey(req.Artifact), imageKey(schemas.ArtifactReference{
+		Host: req.Artifact.Host, ProjectID: req.Artifact.ProjectID, RepositoryID: req.Artifact.RepositoryID, ImageName: req.Artifact.ImageName,
+	})} {
+		if err, ok := a.errs[key]; ok {
+			return nil, err
+		}
+	}
+	for _, r := range a.results {
+		if sameImage(r.Artifact, req.Artifact) && (r.Artifact.Digest == nil || sameDigest(r.Artifact, req.Artifact)) {
+			result := r
+			result.Artifact = req.Artifact
+			result.Vulnerabilities = slices.Clone(r.Vulnerabilities)
+			return &result, nil
+		}
+	}
+	return &schemas.AnalyzeResult{Artifact: req.Artifact, Vulnerabilities: []schemas.Vulnerability{}}, nil
+}
+
+// Requests returns the analysis requests received so far, in order.
+func (a *Analyzer) Requests() []drydock.AnalyzeRequest {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return slices.Clone(a.requests)
+}
+
+// Exporter is a drydock.Exporter keeping the exported results in memory.
+type Exporter struct {
+	// Err, if set, is returned by Export after capturing the results
+	Err error
+
+	mu      sync.Mutex
+	results []schemas.AnalyzeResult
+	calls   int
+}
+
+var _ drydock.Exporter = (*Exporter)(nil)
+
+// Export captures the results.
+func (e *Exporter) Export(_ context.Context, results []schemas.AnalyzeResult) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.results = append(e.results, results...)
+	e.calls++
+	return e.Err
+}
+
+// Results returns every result exported so far.
+func (e *Exporter) Results() []schemas.AnalyzeResult {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return slices.Clone(e.results)
+}
+
+// Calls returns the number of Export calls, e.g. to check batching.
+func (e *Exporter) Calls() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.calls
+}
+
+// sameImage reports whether a and b are the same image, regardless of version.
+func sameImage(a, b schemas.ArtifactReference) bool {
+	return a.Host == b.Host && a.ProjectID == b.ProjectID && a.RepositoryID == b.RepositoryID && a.ImageName == b.ImageName
+}
+
+func sameDigest(a, b schemas.ArtifactReference) bool {
+	return a.Digest != nil && b.Digest != nil && *a.Digest == *b.Digest
+}
+
+// matchesVersion reports whether the target is the version ref asks for.
+func matchesVersion(t drydock.ImageTarget, ref schemas.ArtifactReference) bool {
+	if ref.Digest != nil {
+		return sameDigest(t.Artifact, ref)
+	}
+	tag := "latest"
+	if ref.Tag != nil {
+		tag = *ref.Tag
+	}
+	if t.Artifact.Tag != nil && *t.Artifact.Tag == tag {
+		return true
+	}
+	return t.Image != nil && slices.Contains(t.Image.Tags, tag)
+}
+
+// imageKey identifies an image version for SetError.
+func imageKey(a schemas.ArtifactReference) string {
+	key := a.Host + "/" + a.ProjectID + "/" + a.RepositoryID + "/" + a.ImageName
+	if a.Digest != nil && *a.Digest != "" {
+		return key + "@" + *a.Digest
+	}
+	return key
+}
@@ -0,0 +1,498 @@
+package drydocktest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/drydocktest"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+const (
+	apiURI    = "us-central1-docker.pkg.dev/p/repo/api@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	workerURI = "us-central1-docker.pkg.dev/p/repo/worker:v1"
+	brokenURI = "us-central1-docker.pkg.dev/p/repo/broken:v1"
+)
+
+// byImage ignores the order of results, which are analyzed concurrently.
+var byImage = cmpopts.SortSlices(func(a, b schemas.AnalyzeResult) bool {
+	return a.Artifact.ImageName < b.Artifact.ImageName
+})
+
+func TestScanner_WithFakes(t *testing.T) {
+	api := drydocktest.Target(apiURI)
+	critical := schemas.Vulnerability{ID: "CVE-1", Severity: schemas.SeverityCritical, PackageName: "openssl"}
+
+	tests := map[string]struct {
+		targets     []drydock.ImageTarget
+		results     []schemas.AnalyzeResult
+		failing     string
+		want        []schemas.AnalyzeResult
+		wantErr     bool
+		wantAnalyze int
+	}{
+		"should export the canned results of every target": {
+			targets: []drydock.ImageTarget{api, drydocktest.Target(workerURI)},
+			results: []schemas.AnalyzeResult{{
+				Artifact:        api.Artifact,
+				Vulnerabilities: []schemas.Vulnerability{critical},
+				Summary:         schemas.VulnerabilitySummary{TotalCount: 1},
+			}},
+			want: []schemas.AnalyzeResult{
+				{Artifact: api.Artifact, Image: api.Image, Vulnerabilities: []schemas.Vulnerability{critical}, Summary: schemas.VulnerabilitySummary{TotalCount: 1}},
+				{Artifact: drydocktest.Target(workerURI).Artifact, Image: drydocktest.Target(workerURI).Image},
+			},
+			wantAnalyze: 2,
+		},
+		"should report failing analyses as partial errors": {
+			targets:     []drydock.ImageTarget{drydocktest.Target(brokenURI), drydocktest.Target(workerURI)},
+			failing:     brokenURI,
+			want:        []schemas.AnalyzeResult{{Artifact: drydocktest.Target(workerURI).Artifact, Image: drydocktest.Target(workerURI).Image}},
+			wantErr:     true,
+			wantAnalyze: 2,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			resolver := drydocktest.NewResolver(tt.targets...)
+			analyzer := drydocktest.NewAnalyzer(tt.results...)
+			if tt.failing != "" {
+				analyzer.SetError(tt.failing, errors.New("boom"))
+			}
+			exporter := &drydocktest.Exporter{}
+
+			scanner, err := drydock.NewScanner(ctx, "us-central1",
+				drydock.WithProjectID("p"),
+				drydock.WithResolver(resolver),
+				drydock.WithAnalyzer(analyzer),
+				drydock.WithExporter(exporter),
+			)
+			if err != nil {
+				t.Fatalf("NewScanner() error = %v", err)
+			}
+
+			err = scanner.Scan(ctx, drydock.ScanOptions{MinSeverity: schemas.SeverityHigh})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, exporter.Results(), cmpopts.EquateEmpty(), byImage, cmpopts.IgnoreFields(schemas.AnalyzeResult{}, "ScanTime")); diff != "" {
+				t.Errorf("exported results mismatch (-want +got):\n%s", diff)
+			}
+			requests := analyzer.Requests()
+			if len(requests) != tt.wantAnalyze {
+				t.Errorf("len(Requests()) = %d, want %d", len(requests), tt.wantAnalyze)
+			}
+			for _, req := range requests {
+				if req.MinSeverity != schemas.SeverityHigh {
+					t.Errorf("MinSeverity = %s, want %s", req.MinSeverity, schemas.SeverityHigh)
+				}
+			}
+
+			if err := scanner.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if !resolver.Closed() {
+				t.Error("resolver was not closed")
+			}
+		})
+	}
+}
+
+func TestResolver_ResolveImage(t *testing.T) {
+	resolver := drydocktest.NewResolver(drydocktest.Target(apiURI), drydocktest.Target(workerURI))
+	worker := drydocktest.Target(workerURI).Artifact
+	latest := worker
+	latest.Tag = nil
+
+	tests := map[string]struct {
+		ref     schemas.ArtifactReference
+		want    string
+		wantErr bool
+	}{
+		"should resolve by digest": {
+			ref:  drydocktest.Target(apiURI).Artifact,
+			want: apiURI,
+		},
+		"should resolve by tag": {
+			ref:  worker,
+			want: workerURI,
+		},
+		"should not resolve a missing tag": {
+			ref:     latest,
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := resolver.ResolveImage(context.Background(), tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.URI != tt.want {
+				t.Errorf("ResolveImage() = %q, want %q", got.URI, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanner_ResultSpill(t *testing.T) {
+	targets := []drydock.ImageTarget{
+		drydocktest.Target(apiURI),
+		drydocktest.Target(workerURI),
+		drydocktest.Target("us-central1-docker.pkg.dev/p/repo/web:v1"),
+	}
+	var want []schemas.AnalyzeResult
+	for _, target := range targets {
+		want = append(want, schemas.AnalyzeResult{Artifact: target.Artifact, Image: target.Image})
+	}
+
+	tests := map[string]struct {
+		exporter drydock.Exporter
+		results  func(t *testing.T, e drydock.Exporter, out *bytes.Buffer) []schemas.AnalyzeResult
+	}{
+		"should read spilled results back for an exporter of all results": {
+			exporter: &drydocktest.Exporter{},
+			results: func(t *testing.T, e drydock.Exporter, _ *bytes.Buffer) []schemas.AnalyzeResult {
+				return e.(*drydocktest.Exporter).Results()
+			},
+		},
+		"should stream spilled results into a stream exporter": {
+			results: func(t *testing.T, _ drydock.Exporter, out *bytes.Buffer) []schemas.AnalyzeResult {
+				var results []schemas.AnalyzeResult
+				if err := json.Unmarshal(out.Bytes(), &results); err != nil {
+					t.Fatalf("invalid JSON output: %v\n%s", err, out)
+				}
+				return results
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+			out := &bytes.Buffer{}
+			exp := tt.exporter
+			if exp == nil {
+				exp = exporter.NewJSONExporter(out)
+			}
+
+			scanner, err := drydock.NewScanner(ctx, "us-central1",
+				drydock.WithProjectID("p"),
+				drydock.WithResolver(drydocktest.NewResolver(targets...)),
+				drydock.WithAnalyzer(drydocktest.NewAnalyzer()),
+				drydock.WithExporter(exp),
+				drydock.WithResultSpill(2, dir),
+			)
+			if err != nil {
+				t.Fatalf("NewScanner() error = %v", err)
+			}
+			if err := scanner.Scan(ctx, drydock.ScanOptions{MinSeverity: schemas.SeverityHigh}); err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+
+			got := tt.results(t, exp, out)
+			if diff := cmp.Diff(want, got, cmpopts.EquateEmpty(), byImage, cmpopts.IgnoreFields(schemas.AnalyzeResult{}, "ScanTime")); diff != "" {
+				t.Errorf("exported results mismatch (-want +got):\n%s", diff)
+			}
+			files, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("ReadDir() error = %v", err)
+			}
+			if len(files) != 0 {
+				t.Errorf("spill files left behind: %v", files)
+			}
+		})
+	}
+}
+
+// streamRecorder is a StreamExporter recording the order of its calls.
+type streamRecorder struct {
+	drydocktest.Exporter
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *streamRecorder) record(call string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+	return nil
+}
+
+func (r *streamRecorder) Begin(context.Context) error { return r.record("Begin") }
+
+func (r *streamRecorder) ExportOne(context.Context, schemas.AnalyzeResult) error {
+	return r.record("ExportOne")
+}
+
+func (r *streamRecorder) End(context.Context) error { return r.record("End") }
+
+func TestScanner_WithStreamingExport(t *testing.T) {
+	ctx := context.Background()
+	targets := []drydock.ImageTarget{drydocktest.Target(apiURI), drydocktest.Target(workerURI)}
+	exp := &streamRecorder{}
+
+	scanner, err := drydock.NewScanner(ctx, "us-central1",
+		drydock.WithProjectID("p"),
+		drydock.WithResolver(drydocktest.NewResolver(targets...)),
+		drydock.WithAnalyzer(drydocktest.NewAnalyzer()),
+		drydock.WithExporter(exp),
+		drydock.WithStreamingExport(),
+	)
+	if err != nil {
+		t.Fatalf("NewScanner() error = %v", err)
+	}
+	if err := scanner.Scan(ctx, drydock.ScanOptions{}); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"Begin", "ExportOne", "ExportOne", "End"}, exp.calls); diff != "" {
+		t.Errorf("exporter calls mismatch (-want +got):\n%s", diff)
+	}
+	if exp.Calls() != 0 {
+		t.Errorf("Export() called %d times, want 0", exp.Calls())
+	}
+}
+
+func TestScanner_ScanResults(t *testing.T) {
+	ctx := context.Background()
+	api := drydocktest.Target(apiURI)
+	worker := drydocktest.Target(workerURI)
+	critical := schemas.Vulnerability{ID: "CVE-1", Severity: schemas.SeverityCritical, PackageName: "openssl"}
+	exp := &drydocktest.Exporter{}
+
+	scanner, err := drydock.NewScanner(ctx, "us-central1",
+		drydock.WithProjectID("p"),
+		drydock.WithResolver(drydocktest.NewResolver(api, worker)),
+		drydock.WithAnalyzer(drydocktest.NewAnalyzer(schemas.AnalyzeResult{Artifact: api.Artifact, Vulnerabilities: []schemas.Vulnerability{critical}})),
+		drydock.WithExporter(exp),
+	)
+	if err != nil {
+		t.Fatalf("NewScanner() error = %v", err)
+	}
+	got, err := scanner.ScanResults(ctx, drydock.ScanOptions{})
+	if err != nil {
+		t.Fatalf("ScanResults() error = %v", err)
+	}
+
+	want := []schemas.AnalyzeResult{
+		{Artifact: api.Artifact, Image: api.Image, Vulnerabilities: []schemas.Vulnerability{critical}},
+		{Artifact: worker.Artifact, Image: worker.Image},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateEmpty(), byImage, cmpopts.IgnoreFields(schemas.AnalyzeResult{}, "ScanTime")); diff != "" {
+		t.Errorf("ScanResults() mismatch (-want +got):\n%s", diff)
+	}
+	if exp.Calls() != 0 {
+		t.Errorf("Export() called %d times, want 0", exp.Calls())
+	}
+}
+
+func TestScanner_ScanImage(t *testing.T) {
+	ctx := context.Background()
+	api := drydocktest.Target(apiURI)
+	worker := drydocktest.Target(workerURI)
+	analyzer := drydocktest.NewAnalyzer()
+	exp := &drydocktest.Exporter{}
+
+	scanner, err := drydock.NewScanner(ctx, "us-central1",
+		drydock.WithProjectID("p"),
+		drydock.WithResolver(drydocktest.NewResolver(api, worker)),
+		drydock.WithAnalyzer(analyzer),
+		drydock.WithExporter(exp),
+	)
+	if err != nil {
+		t.Fatalf("NewScanner() error = %v", err)
+	}
+	ref, err := schemas.ParseArtifactURI(workerURI)
+	if err != nil {
+		t.Fatalf("ParseArtifactURI() error = %v", err)
+	}
+	if err := scanner.ScanImage(ctx, ref, drydock.ScanOptions{}); err != nil {
+		t.Fatalf("ScanImage() error = %v", err)
+	}
+
+	var analyzed []string
+	for _, req := range analyzer.Requests() {
+		analyzed = append(analyzed, req.Artifact.String())
+	}
+	if diff := cmp.Diff([]string{worker.Artifact.String()}, analyzed); diff != "" {
+		t.Errorf("analyzed images mismatch (-want +got):\n%s", diff)
+	}
+	if len(exp.Results()) != 1 {
+		t.Errorf("exported %d results, want 1", len(exp.Results()))
+	}
+}
+
+func TestScanner_WithScanPolicy(t *testing.T) {
+	api := drydocktest.Target(apiURI)
+	worker := drydocktest.Target(workerURI)
+	results := []schemas.AnalyzeResult{
+		{
+			Artifact:        api.Artifact,
+			Vulnerabilities: []schemas.Vulnerability{{ID: "CVE-1", Severity: schemas.SeverityCritical}, {ID: "CVE-2", Severity: schemas.SeverityHigh}},
+			Summary:         schemas.VulnerabilitySummary{TotalCount: 2, CountBySeverity: map[schemas.Severity]int{schemas.SeverityCritical: 1, schemas.SeverityHigh: 1}},
+		},
+		{
+			Artifact:        worker.Artifact,
+			Vulnerabilities: []schemas.Vulnerability{{ID: "CVE-3", Severity: schemas.SeverityHigh}},
+			Summary:         schemas.VulnerabilitySummary{TotalCount: 1, CountBySeverity: map[schemas.Severity]int{schemas.SeverityHigh: 1}},
+		},
+	}
+
+	tests := map[string]struct {
+		policy  drydock.ScanPolicy
+		wantErr bool
+	}{
+		"should fail on a finding at the severity": {
+			policy:  drydock.ScanPolicy{FailOnSeverity: schemas.SeverityCritical},
+			wantErr: true,
+		},
+		"should count the findings above the severity": {
+			policy:  drydock.ScanPolicy{FailOnSeverity: schemas.SeverityHigh, MaxCount: 2},
+			wantErr: true,
+		},
+		"should tolerate up to the max count": {
+			policy: drydock.ScanPolicy{FailOnSeverity: schemas.SeverityHigh, MaxCount: 3},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			exp := &drydocktest.Exporter{}
+			scanner, err := drydock.NewScanner(ctx, "us-central1",
+				drydock.WithProjectID("p"),
+				drydock.WithResolver(drydocktest.NewResolver(api, worker)),
+				drydock.WithAnalyzer(drydocktest.NewAnalyzer(results...)),
+				drydock.WithExporter(exp),
+				drydock.WithScanPolicy(tt.policy),
+			)
+			if err != nil {
+				t.Fatalf("NewScanner() error = %v", err)
+			}
+			err = scanner.Scan(ctx, drydock.ScanOptions{})
+			if tt.wantErr != errors.Is(err, drydock.ErrPolicyViolated) {
+				t.Errorf("Scan() error = %v, want ErrPolicyViolated: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Scan() error = %v", err)
+			}
+			// Results are exported whether or not the policy is violated
+			if len(exp.Results()) != 2 {
+				t.Errorf("exported %d results, want 2", len(exp.Results()))
+			}
+		})
+	}
+}
+
+func TestScanner_WithScanPolicy_PartialErrors(t *testing.T) {
+	api := drydocktest.Target(apiURI)
+	broken := drydocktest.Target(brokenURI)
+	analyzer := drydocktest.NewAnalyzer(schemas.AnalyzeResult{
+		Artifact:        api.Artifact,
+		Vulnerabilities: []schemas.Vulnerability{{ID: "CVE-1", Severity: schemas.SeverityCritical}},
+		Summary:         schemas.VulnerabilitySummary{TotalCount: 1, CountBySeverity: map[schemas.Severity]int{schemas.SeverityCritical: 1}},
+	})
+	analyzer.SetError(brokenURI, errors.New("permission denied"))
+
+	ctx := context.Background()
+	scanner, err := drydock.NewScanner(ctx, "us-central1",
+		drydock.WithProjectID("p"),
+		drydock.WithResolver(drydocktest.NewResolver(api, broken)),
+		drydock.WithAnalyzer(analyzer),
+		drydock.WithExporter(&drydocktest.Exporter{}),
+		drydock.WithScanPolicy(drydock.ScanPolicy{FailOnSeverity: schemas.SeverityCritical}),
+	)
+	if err != nil {
+		t.Fatalf("NewScanner() error = %v", err)
+	}
+	err = scanner.Scan(ctx, drydock.ScanOptions{})
+	// The failed image does not hide the violation of the analyzed ones
+	if !errors.Is(err, drydock.ErrPolicyViolated) {
+		t.Errorf("Scan() error = %v, want ErrPolicyViolated", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("Scan() error = %v, want the failed image", err)
+	}
+}
+
+func TestScanner_ScanOptions_Policy(t *testing.T) {
+	api := drydocktest.Target(apiURI)
+	result := schemas.AnalyzeResult{
+		Artifact:        api.Artifact,
+		Vulnerabilities: []schemas.Vulnerability{{ID: "CVE-1", Severity: schemas.SeverityHigh}},
+		Summary:         schemas.VulnerabilitySummary{TotalCount: 1, CountBySeverity: map[schemas.Severity]int{schemas.SeverityHigh: 1}},
+	}
+
+	tests := map[string]struct {
+		scannerOpts []drydock.ScannerOption
+		policy      *drydock.ScanPolicy
+		wantErr     bool
+	}{
+		"should fall back to the policy of the scanner": {
+			scannerOpts: []drydock.ScannerOption{drydock.WithScanPolicy(drydock.ScanPolicy{FailOnSeverity: schemas.SeverityHigh})},
+			wantErr:     true,
+		},
+		"should gate the scan without a scanner policy": {
+			policy:  &drydock.ScanPolicy{FailOnSeverity: schemas.SeverityHigh},
+			wantErr: true,
+		},
+		"should replace the policy of the scanner": {
+			scannerOpts: []drydock.ScannerOption{drydock.WithScanPolicy(drydock.ScanPolicy{FailOnSeverity: schemas.SeverityHigh})},
+			policy:      &drydock.ScanPolicy{FailOnSeverity: schemas.SeverityCritical},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			scanner, err := drydock.NewScanner(ctx, "us-central1", append([]drydock.ScannerOption{
+				drydock.WithProjectID("p"),
+				drydock.WithResolver(drydocktest.NewResolver(api)),
+				drydock.WithAnalyzer(drydocktest.NewAnalyzer(result)),
+				drydock.WithExporter(&drydocktest.Exporter{}),
+			}, tt.scannerOpts...)...)
+			if err != nil {
+				t.Fatalf("NewScanner() error = %v", err)
+			}
+			err = scanner.Scan(ctx, drydock.ScanOptions{Policy: tt.policy})
+			if tt.wantErr != errors.Is(err, drydock.ErrPolicyViolated) {
+				t.Errorf("Scan() error = %v, want ErrPolicyViolated: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Scan() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestScanner_ScanOptions_InvalidPolicy(t *testing.T) {
+	ctx := context.Background()
+	scanner, err := drydock.NewScanner(ctx, "us-central1",
+		drydock.WithProjectID("p"),
+		drydock.WithResolver(drydocktest.NewResolver()),
+		drydock.WithAnalyzer(drydocktest.NewAnalyzer()),
+		drydock.WithExporter(&drydocktest.Exporter{}),
+	)
+	if err != nil {
+		t.Fatalf("NewScanner() error = %v", err)
+	}
+	if err := scanner.Scan(ctx, drydock.ScanOptions{Policy: &drydock.ScanPolicy{}}); err == nil {
+		t.Error("Scan() error = nil, want an invalid policy error")
+	}
+}
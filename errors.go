@@ -0,0 +1,154 @@
+package drydock
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrInvalidOption is matched by every OptionError, e.g. errors.Is(err, ErrInvalidOption).
+var ErrInvalidOption = errors.New("invalid option")
+
+// OptionError reports a scanner option that could not be applied.
+// NewScanner validates every option and joins all of their errors, so use OptionErrors
+// to list them rather than errors.As, which only finds the first one.
+type OptionError struct {
+	// Option is the name of the offending option (e.g., "WithConcurrency")
+	Option string
+
+	// Err describes the problem
+	Err error
+}
+
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Option, e.Err)
+}
+
+func (e *OptionError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes every OptionError match ErrInvalidOption.
+func (e *OptionError) Is(target error) bool {
+	return target == ErrInvalidOption
+}
+
+// newOptionError returns an OptionError for option with a formatted message.
+func newOptionError(option, format string, args ...any) *OptionError {
+	return &OptionError{Option: option, Err: fmt.Errorf(format, args...)}
+}
+
+// OptionErrors returns every OptionError in err's tree, in order.
+func OptionErrors(err error) []*OptionError {
+	var found []*OptionError
+	var walk func(error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		if oe, ok := err.(*OptionError); ok {
+			found = append(found, oe)
+			return
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			walk(u.Unwrap())
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				walk(e)
+			}
+		}
+	}
+	walk(err)
+	return found
+}
+
+// Errors returned, wrapped around the underlying API error, when a Google Cloud call fails
+// for a well-known reason. Match them with errors.Is; ErrorHint describes how to fix them.
+var (
+	// ErrPermissionDenied means the caller lacks the IAM roles to list images or read vulnerabilities
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrProjectNotFound means the project does not exist or is not visible to the caller
+	ErrProjectNotFound = errors.New("project not found")
+	// ErrScanningNotEnabled means a required API (e.g., Container Scanning) is disabled in the project
+	ErrScanningNotEnabled = errors.New("scanning API not enabled")
+	// ErrQuotaExceeded means an API quota or rate limit was exhausted
+	ErrQuotaExceeded = errors.New("quota exceeded")
+)
+
+// ErrSLABreached is returned by Scan, with WithFailOnSLABreach, when findings are past their SLA.
+var ErrSLABreached = errors.New("SLA breached")
+
+// ErrPolicyViolated is returned by Scan, with WithScanPolicy, when findings exceed the policy.
+var ErrPolicyViolated = errors.New("scan policy violated")
+
+// ErrSBOMNotFound is returned by FetchSBOM when Artifact Analysis has no SBOM for the image.
+var ErrSBOMNotFound = errors.New("SBOM not found")
+
+// classifyAPIError wraps err with the sentinel error matching its cause, if any.
+// The original error stays reachable through errors.As and status.FromError.
+func classifyAPIError(err error) error {
+	if kind := apiErrorKind(err); kind != nil {
+		return fmt.Errorf("%w: %w", kind, err)
+	}
+	return err
+}
+
+func apiErrorKind(err error) error {
+	if err == nil {
+		return nil
+	}
+	if isQuotaExceeded(err) {
+		return ErrQuotaExceeded
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+	if isServiceDisabled(s) {
+		return ErrScanningNotEnabled
+	}
+	switch s.Code() {
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return ErrPermissionDenied
+	case codes.NotFound:
+		if strings.Contains(strings.ToLower(s.Message()), "project") {
+			return ErrProjectNotFound
+		}
+	}
+	return nil
+}
+
+// isServiceDisabled reports whether s says that an API is disabled (or was never enabled) in the project.
+func isServiceDisabled(s *status.Status) bool {
+	for _, d := range s.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.GetReason() == "SERVICE_DISABLED" {
+			return true
+		}
+	}
+	msg := s.Message()
+	return (s.Code() == codes.PermissionDenied || s.Code() == codes.FailedPrecondition) &&
+		(strings.Contains(msg, "has not been used in project") || strings.Contains(msg, "it is disabled"))
+}
+
+// ErrorHint returns an actionable suggestion for the well-known errors in err's tree,
+// or an empty string if there is none.
+func ErrorHint(err error) string {
+	switch {
+	case errors.Is(err, ErrScanningNotEnabled):
+		return "enable the Container Scanning API (containerscanning.googleapis.com) and the Container Analysis API (containeranalysis.googleapis.com) in the project"
+	case errors.Is(err, ErrProjectNotFound):
+		return "check the project ID; it must be the ID (not the name or number) of an existing project"
+	case errors.Is(err, ErrPermissionDenied):
+		return "grant roles/artifactregistry.reader and roles/containeranalysis.occurrences.viewer to the caller, and check the project ID"
+	case errors.Is(err, ErrQuotaExceeded):
+		return "lower the concurrency, enable adaptive concurrency, or request a higher quota"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,173 @@
+package drydock_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/drydocktest"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewScanner_OptionErrors(t *testing.T) {
+	tests := map[string]struct {
+		location    string
+		opts        []drydock.ScannerOption
+		wantOptions []string
+	}{
+		"should report an empty location": {
+			location:    "",
+			opts:        []drydock.ScannerOption{drydock.WithProjectID("p")},
+			wantOptions: []string{"location"},
+		},
+		"should report every invalid option at once": {
+			location: "us-central1",
+			opts: []drydock.ScannerOption{
+				drydock.WithConcurrency(0),
+				drydock.WithExportBatchSize(-1),
+				drydock.WithFilter("vuln.unknownField >"),
+				drydock.WithLogger(nil),
+			},
+			wantOptions: []string{"WithConcurrency", "WithExportBatchSize", "WithFilter", "WithLogger"},
+		},
+		"should report conflicting exporter settings": {
+			location: "us-central1",
+			opts: []drydock.ScannerOption{
+				drydock.WithOutputFormat(drydock.OutputFormatCSV, &bytes.Buffer{}),
+				drydock.WithExporter(exporter.NewJSONExporter(&bytes.Buffer{})),
+			},
+			wantOptions: []string{"WithExporter"},
+		},
+		"should report invalid connection policies": {
+			location: "us-central1",
+			opts: []drydock.ScannerOption{
+				drydock.WithConnectionPolicy(drydock.ConnectionPolicy{KeepaliveTime: time.Second}),
+				drydock.WithConnectionPolicy(drydock.ConnectionPolicy{KeepaliveTimeout: time.Second}),
+				drydock.WithConnectionPolicy(drydock.ConnectionPolicy{PoolSize: -1}),
+			},
+			wantOptions: []string{"WithConnectionPolicy", "WithConnectionPolicy", "WithConnectionPolicy"},
+		},
+		"should report concurrencies out of range": {
+			location: "us-central1",
+			opts: []drydock.ScannerOption{
+				drydock.WithConcurrency(drydock.MaxConcurrency + 1),
+				drydock.WithDiscoveryConcurrency(0),
+			},
+			wantOptions: []string{"WithConcurrency", "WithDiscoveryConcurrency"},
+		},
+		"should report a streaming export that cannot stream": {
+			location: "us-central1",
+			opts: []drydock.ScannerOption{
+				drydock.WithExporter(&drydocktest.Exporter{}),
+				drydock.WithExportBatchSize(10),
+				drydock.WithStreamingExport(),
+			},
+			wantOptions: []string{"WithStreamingExport", "WithStreamingExport"},
+		},
+		"should report a non-positive number of digests per image": {
+			location:    "us-central1",
+			opts:        []drydock.ScannerOption{drydock.WithDigestsPerImage(0)},
+			wantOptions: []string{"WithDigestsPerImage"},
+		},
+		"should report non-positive discovery limits": {
+			location:    "us-central1",
+			opts:        []drydock.ScannerOption{drydock.WithMaxCandidates(0), drydock.WithListPageSize(-1)},
+			wantOptions: []string{"WithMaxCandidates", "WithListPageSize"},
+		},
+		"should report a negative spill threshold": {
+			location:    "us-central1",
+			opts:        []drydock.ScannerOption{drydock.WithResultSpill(-1, "")},
+			wantOptions: []string{"WithResultSpill"},
+		},
+		"should report invalid scan policies": {
+			location: "us-central1",
+			opts: []drydock.ScannerOption{
+				drydock.WithScanPolicy(drydock.ScanPolicy{}),
+				drydock.WithScanPolicy(drydock.ScanPolicy{FailOnSeverity: schemas.SeverityHigh, MaxCount: -1}),
+			},
+			wantOptions: []string{"WithScanPolicy", "WithScanPolicy"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := drydock.NewScanner(context.Background(), tt.location, tt.opts...)
+			if !errors.Is(err, drydock.ErrInvalidOption) {
+				t.Fatalf("NewScanner() error = %v, want ErrInvalidOption", err)
+			}
+
+			var got []string
+			for _, oe := range drydock.OptionErrors(err) {
+				got = append(got, oe.Option)
+			}
+			if diff := cmp.Diff(tt.wantOptions, got); diff != "" {
+				t.Errorf("OptionErrors() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClassifyAPIError(t *testing.T) {
+	disabled, err := status.New(codes.PermissionDenied, "denied").WithDetails(&errdetails.ErrorInfo{Reason: "SERVICE_DISABLED"})
+	if err != nil {
+		t.Fatalf("failed to build status: %v", err)
+	}
+
+	tests := map[string]struct {
+		err  error
+		want error
+	}{
+		"should classify a disabled API from its error info": {
+			err:  disabled.Err(),
+			want: drydock.ErrScanningNotEnabled,
+		},
+		"should classify a disabled API from its message": {
+			err:  status.Error(codes.PermissionDenied, "Container Analysis API has not been used in project 123 before or it is disabled."),
+			want: drydock.ErrScanningNotEnabled,
+		},
+		"should classify permission denied": {
+			err:  status.Error(codes.PermissionDenied, "Permission 'artifactregistry.repositories.list' denied"),
+			want: drydock.ErrPermissionDenied,
+		},
+		"should classify a missing project": {
+			err:  status.Error(codes.NotFound, "Project 'projects/nope' not found"),
+			want: drydock.ErrProjectNotFound,
+		},
+		"should classify resource exhaustion": {
+			err:  status.Error(codes.ResourceExhausted, "Quota exceeded"),
+			want: drydock.ErrQuotaExceeded,
+		},
+		"should leave other errors unclassified": {
+			err:  status.Error(codes.Internal, "boom"),
+			want: nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := drydock.ExportClassifyAPIError(tt.err)
+			if !errors.Is(got, tt.err) {
+				t.Errorf("classifyAPIError() = %v, want it to wrap %v", got, tt.err)
+			}
+			if s, ok := status.FromError(got); !ok || s.Code() != status.Code(tt.err) {
+				t.Errorf("classifyAPIError() lost the gRPC status: %v", got)
+			}
+			for _, sentinel := range []error{drydock.ErrPermissionDenied, drydock.ErrProjectNotFound, drydock.ErrScanningNotEnabled, drydock.ErrQuotaExceeded} {
+				if want := sentinel == tt.want; errors.Is(got, sentinel) != want {
+					t.Errorf("errors.Is(%v, %v) = %v, want %v", got, sentinel, !want, want)
+				}
+			}
+			if (drydock.ErrorHint(got) != "") != (tt.want != nil) {
+				t.Errorf("ErrorHint(%v) = %q", got, drydock.ErrorHint(got))
+			}
+		})
+	}
+}
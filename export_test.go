@@ -1,13 +1,116 @@
 package drydock
 
+import (
+	"context"
+	"net/http"
+
+	"github.com/googleapis/gax-go/v2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+)
+
 // Export internal functions for black-box testing in analyzer_test package.
 var (
 	ExportConvertToVulnerability       = convertToVulnerability
 	ExportFilterBySeverity             = filterBySeverity
 	ExportFilterFixable                = filterFixable
+	ExportFilterByID                   = filterByID
 	ExportBuildSummary                 = buildSummary
 	ExportSelectBestDigest             = selectBestDigest
 	ExportExtractLocationAndRepository = extractLocationAndRepository
+	ExportBuildScanPlan                = buildScanPlan
+	ExportPrioritizeRepositories       = prioritizeRepositories
+	ExportNewConcurrencyLimiter        = newConcurrencyLimiter
+	ExportIsQuotaExceeded              = isQuotaExceeded
+	ExportLimiterAcquire               = (*concurrencyLimiter).acquire
+	ExportLimiterRelease               = (*concurrencyLimiter).release
+	ExportLimiterThrottled             = (*concurrencyLimiter).throttled
+	ExportLimiterSucceeded             = (*concurrencyLimiter).succeeded
+	ExportConvertCVSS                  = convertCVSS
+	ExportClassifyAPIError             = classifyAPIError
+	ExportExtractCWEs                  = extractCWEs
+	ExportConvertFixState              = convertFixState
+	ExportClassifyReference            = classifyReference
+	ExportPackagePURL                  = packagePURL
+	ExportParseCredentialsJSON         = parseCredentialsJSON
+	ExportProxyDialer                  = proxyDialer
+	ExportParseProxyURL                = parseProxyURL
+	ExportProxyContext                 = proxyContext
+	ExportClosestBaseImage             = closestBaseImage
+	ExportBuildBaseImageAdvice         = buildBaseImageAdvice
+	ExportDiscoverDeployments          = discoverDeployments
+	ExportApplyDiscovery               = applyDiscovery
+	ExportAnnotateDistroStatus         = annotateDistroStatus
+	ExportSuppressUnlikelyFixed        = suppressUnlikelyFixed
+	ExportAnnotateDependencyPaths      = annotateDependencyPaths
+	ExportRefreshStale                 = (*manifestPuller).refreshStale
+	ExportConnectionClientOptions      = ConnectionPolicy.clientOptions
+	ExportSummaryAdd                   = (*ScanSummary).add
 )
 
 type ExportCandidateImage = candidateImage
+
+// ExportRetryerFactory returns the retryer factory the policy installs on API calls, or nil if it disables retries.
+func ExportRetryerFactory(p RetryPolicy) func() gax.Retryer {
+	var settings gax.CallSettings
+	for _, opt := range p.callOptions() {
+		opt.Resolve(&settings)
+	}
+	return settings.Retry
+}
+
+// ExportNewManifestPuller returns a puller sending its requests to baseURL with client.
+func ExportNewManifestPuller(client *http.Client, baseURL string) *manifestPuller {
+	return &manifestPuller{client: client, baseURL: baseURL}
+}
+
+// Fakes of the Google Cloud clients implement these iterators.
+type (
+	ExportLocationIterator    = locationIterator
+	ExportRepositoryIterator  = repositoryIterator
+	ExportDockerImageIterator = dockerImageIterator
+	ExportOccurrenceIterator  = occurrenceIterator
+)
+
+// Fields requested by the list calls.
+var (
+	ExportDockerImageFields             = dockerImageFields
+	ExportVulnerabilityOccurrenceFields = vulnerabilityOccurrenceFields
+	ExportImageOccurrenceFields         = imageOccurrenceFields
+	ExportSBOMReferenceOccurrenceFields = sbomReferenceOccurrenceFields
+)
+
+var (
+	ExportNewImageResolver            = newImageResolver
+	ExportNewArtifactRegistryAnalyzer = newArtifactRegistryAnalyzer
+)
+
+// ExportSetObjectReader makes the analyzer download SBOMs with objects.
+func ExportSetObjectReader(a *ArtifactRegistryAnalyzer, objects objectReader) {
+	a.objects = objects
+}
+
+// ExportRecording returns a resolver and an analyzer recording the responses of the clients to dir.
+func ExportRecording(registry artifactRegistryClient, occurrences occurrenceClient, dir string) (*ImageResolver, *ArtifactRegistryAnalyzer) {
+	store := fixtureStore{dir: dir}
+	return newImageResolver(recordingArtifactRegistry{client: registry, store: store}),
+		newArtifactRegistryAnalyzer(recordingOccurrences{client: occurrences, store: store})
+}
+
+// ExportReplay returns a resolver and an analyzer answering from the responses recorded in dir.
+func ExportReplay(dir string) (*ImageResolver, *ArtifactRegistryAnalyzer) {
+	store := fixtureStore{dir: dir}
+	return newImageResolver(replayArtifactRegistry{store: store}), newArtifactRegistryAnalyzer(replayOccurrences{store: store})
+}
+
+// ExportClientOptions returns the options of the clients of a scanner sharing ADC, if shareADC,
+// looked up with find, for each of n clients.
+func ExportClientOptions(ctx context.Context, base []option.ClientOption, shareADC bool, find func(context.Context, ...string) (*google.Credentials, error), n int) [][]option.ClientOption {
+	m := newClientManager(base, ConnectionPolicy{}, shareADC)
+	m.findCredentials = find
+	opts := make([][]option.ClientOption, n)
+	for i := range opts {
+		opts[i] = m.options(ctx)
+	}
+	return opts
+}
@@ -0,0 +1,207 @@
+package exporter
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha1"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// DefaultAtomTitle is the title of feeds without an explicit one.
+const DefaultAtomTitle = "drydock vulnerabilities"
+
+// atomNamespace is the XML namespace of Atom (RFC 4287).
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+// AtomFeed is an Atom feed (RFC 4287) whose entries are findings on images.
+type AtomFeed struct {
+	XMLName   xml.Name    `xml:"feed"`
+	Namespace string      `xml:"xmlns,attr"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Updated   string      `xml:"updated"`
+	Generator string      `xml:"generator"`
+	Entries   []AtomEntry `xml:"entry"`
+}
+
+// AtomEntry is the entry of a finding on an image.
+type AtomEntry struct {
+	// ID is stable across runs for the same finding on the same image, so that feed readers
+	// show each finding once
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Author     AtomPerson     `xml:"author"`
+	Link       *AtomLink      `xml:"link,omitempty"`
+	Categories []AtomCategory `xml:"category"`
+	Summary    string         `xml:"summary"`
+}
+
+// AtomPerson is the author of an entry.
+type AtomPerson struct {
+	Name string `xml:"name"`
+}
+
+// AtomLink links an entry to its advisory.
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+// AtomCategory tags an entry with the severity of the finding.
+type AtomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// AtomExporter writes an Atom feed with one entry per finding on an image, newest first.
+// Entries are dated when the finding was first seen (see WithHistory), or else when the image was scanned.
+// Combine it with a DeltaExporter to publish only new findings.
+type AtomExporter struct {
+	writer io.Writer
+
+	// Title is the title of the feed, DefaultAtomTitle if empty; the feed ID derives from it
+	Title string
+
+	// entries are the entries since Begin, with their time
+	entries []atomEntry
+}
+
+// atomEntry is an entry with its time, for sorting.
+type atomEntry struct {
+	at    time.Time
+	entry AtomEntry
+}
+
+// NewAtomExporter creates an AtomExporter writing the feed to writer.
+func NewAtomExporter(writer io.Writer) *AtomExporter {
+	return &AtomExporter{writer: writer}
+}
+
+// Export writes the feed of all results
+func (e *AtomExporter) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
+	if err := e.Begin(ctx); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if err := e.ExportOne(ctx, result); err != nil {
+			return err
+		}
+	}
+	return e.End(ctx)
+}
+
+// Begin resets the entries
+func (e *AtomExporter) Begin(ctx context.Context) error {
+	e.entries = nil
+	return nil
+}
+
+// ExportOne adds the findings of a single result as entries
+func (e *AtomExporter) ExportOne(ctx context.Context, result schemas.AnalyzeResult) error {
+	image := result.Artifact
+	image.Tag, image.Digest = nil, nil
+	for _, v := range result.Vulnerabilities {
+		at := cmp.Or(v.FirstSeen, result.ScanTime)
+		entry := AtomEntry{
+			ID:         atomID(image.String() + "\x00" + v.ID + "\x00" + v.PackageName),
+			Title:      fmt.Sprintf("%s %s in %s (%s)", v.Severity, v.ID, v.PackageName, image),
+			Updated:    atomTime(at),
+			Published:  atomTime(at),
+			Author:     AtomPerson{Name: "drydock"},
+			Categories: []AtomCategory{{Term: string(v.Severity)}},
+			Summary:    atomSummary(result.Artifact, v),
+		}
+		if link := advisoryURL(v); link != "" {
+			entry.Link = &AtomLink{Href: link, Rel: "alternate"}
+		}
+		e.entries = append(e.entries, atomEntry{at: at, entry: entry})
+	}
+	return nil
+}
+
+// End writes the feed
+func (e *AtomExporter) End(ctx context.Context) error {
+	slices.SortStableFunc(e.entries, func(a, b atomEntry) int {
+		return cmp.Or(b.at.Compare(a.at), cmp.Compare(a.entry.Title, b.entry.Title))
+	})
+	title := cmp.Or(e.Title, DefaultAtomTitle)
+	feed := AtomFeed{
+		Namespace: atomNamespace,
+		ID:        atomID(title),
+		Title:     title,
+		Updated:   atomTime(time.Now()),
+		Generator: "drydock",
+	}
+	// The feed is as recent as its newest entry, so that unchanged feeds do not look updated
+	if len(e.entries) > 0 && !e.entries[0].at.IsZero() {
+		feed.Updated = atomTime(e.entries[0].at)
+	}
+	for _, entry := range e.entries {
+		feed.Entries = append(feed.Entries, entry.entry)
+	}
+	e.entries = nil
+
+	if _, err := io.WriteString(e.writer, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(e.writer)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		return fmt.Errorf("failed to write atom feed: %w", err)
+	}
+	_, err := io.WriteString(e.writer, "\n")
+	return err
+}
+
+// atomID returns a stable name-based UUID URN (version 5 layout) for name.
+func atomID(name string) string {
+	sum := sha1.Sum([]byte("drydock\x00" + name))
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// atomTime formats t as an RFC 3339 date in UTC.
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// advisoryURL returns the advisory of the finding, or its first reference.
+func advisoryURL(v schemas.Vulnerability) string {
+	for _, r := range v.References {
+		if r.Type == schemas.ReferenceTypeAdvisory {
+			return r.URL
+		}
+	}
+	if len(v.References) > 0 {
+		return v.References[0].URL
+	}
+	return ""
+}
+
+// atomSummary describes the finding in plain text.
+func atomSummary(artifact schemas.ArtifactReference, v schemas.Vulnerability) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s is affected by %s (%s", v.PackageName, v.InstalledVersion, v.ID, v.Severity)
+	if v.CVSSScore > 0 {
+		fmt.Fprintf(&b, ", CVSS %.1f", v.CVSSScore)
+	}
+	fmt.Fprintf(&b, ") in %s.", artifact)
+	if v.FixedVersion != "" {
+		fmt.Fprintf(&b, " Fixed in %s.", v.FixedVersion)
+	} else {
+		b.WriteString(" No fix available yet.")
+	}
+	if v.Description != "" {
+		b.WriteString("\n\n" + v.Description)
+	}
+	return b.String()
+}
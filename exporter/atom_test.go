@@ -0,0 +1,98 @@
+package exporter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestAtomExporter_Export(t *testing.T) {
+	scanned := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+	firstSeen := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	api := schemas.ArtifactReference{
+		Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "api",
+		Tag: utils.Ptr("v1"), Digest: utils.Ptr("sha256:aaa"),
+	}
+	results := []schemas.AnalyzeResult{{
+		Artifact: api,
+		ScanTime: scanned,
+		Vulnerabilities: []schemas.Vulnerability{
+			{
+				ID: "CVE-1", Severity: schemas.SeverityHigh, PackageName: "openssl", InstalledVersion: "3.0.1",
+				FixedVersion: "3.0.2", FirstSeen: firstSeen,
+				References: []schemas.Reference{
+					{URL: "https://example.com/fix", Type: schemas.ReferenceTypeFix},
+					{URL: "https://nvd.nist.gov/vuln/detail/CVE-1", Type: schemas.ReferenceTypeAdvisory},
+				},
+			},
+			{ID: "CVE-2", Severity: schemas.SeverityCritical, PackageName: "glibc", InstalledVersion: "2.36", CVSSScore: 9.8},
+		},
+	}}
+
+	var buf bytes.Buffer
+	exp := exporter.NewAtomExporter(&buf)
+	exp.Title = "prod images"
+	if err := exp.Export(context.Background(), results); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), xml.Header) {
+		t.Errorf("feed does not start with the XML header:\n%s", buf.String())
+	}
+
+	var got exporter.AtomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid feed: %v\n%s", err, buf.String())
+	}
+	if len(got.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(got.Entries))
+	}
+	if got.Title != "prod images" || got.Updated != "2024-03-02T12:00:00Z" {
+		t.Errorf("feed title = %q, updated = %q", got.Title, got.Updated)
+	}
+
+	// Newest first: the finding without history is dated by the scan
+	want := exporter.AtomEntry{
+		ID:         got.Entries[1].ID,
+		Title:      "HIGH CVE-1 in openssl (h/p/r/api)",
+		Updated:    "2024-03-01T08:00:00Z",
+		Published:  "2024-03-01T08:00:00Z",
+		Author:     exporter.AtomPerson{Name: "drydock"},
+		Link:       &exporter.AtomLink{Href: "https://nvd.nist.gov/vuln/detail/CVE-1", Rel: "alternate"},
+		Categories: []exporter.AtomCategory{{Term: "HIGH"}},
+		Summary:    "openssl 3.0.1 is affected by CVE-1 (HIGH) in h/p/r/api:v1@sha256:aaa. Fixed in 3.0.2.",
+	}
+	if diff := cmp.Diff(want, got.Entries[1]); diff != "" {
+		t.Errorf("entry mismatch (-want +got):\n%s", diff)
+	}
+	if got.Entries[0].Title != "CRITICAL CVE-2 in glibc (h/p/r/api)" || got.Entries[0].Link != nil {
+		t.Errorf("unexpected first entry: %+v", got.Entries[0])
+	}
+	if !strings.Contains(got.Entries[0].Summary, "CVSS 9.8") || !strings.Contains(got.Entries[0].Summary, "No fix available yet.") {
+		t.Errorf("unexpected summary: %q", got.Entries[0].Summary)
+	}
+
+	// Entry IDs are stable across digests and tags of the same image, so readers show findings once
+	var again bytes.Buffer
+	results[0].Artifact.Digest = utils.Ptr("sha256:bbb")
+	if err := exporter.NewAtomExporter(&again).Export(context.Background(), results); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	var next exporter.AtomFeed
+	if err := xml.Unmarshal(again.Bytes(), &next); err != nil {
+		t.Fatal(err)
+	}
+	if next.Entries[1].ID != got.Entries[1].ID || !strings.HasPrefix(next.Entries[1].ID, "urn:uuid:") {
+		t.Errorf("entry ID = %q, want %q", next.Entries[1].ID, got.Entries[1].ID)
+	}
+	if next.ID == got.ID {
+		t.Errorf("feeds with different titles share the ID %q", got.ID)
+	}
+}
@@ -0,0 +1,276 @@
+package exporter
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// Defaults of Backstage entity references without a kind or namespace.
+const (
+	BackstageDefaultKind      = "component"
+	BackstageDefaultNamespace = "default"
+)
+
+// BackstageMapping maps images, as "host/project/repository/image" without tag or digest, to the
+// Backstage entities they belong to. Keys may be path.Match patterns (e.g. "us-docker.pkg.dev/p/r/team-a/*");
+// exact keys take precedence over patterns, and longer patterns over shorter ones.
+type BackstageMapping map[string]string
+
+// ReadBackstageMapping reads a JSON mapping, e.g. {"us-docker.pkg.dev/p/r/api": "component:default/api"}.
+// Entity references are normalized to "kind:namespace/name".
+func ReadBackstageMapping(r io.Reader) (BackstageMapping, error) {
+	var m BackstageMapping
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("invalid backstage mapping: %w", err)
+	}
+	for image, ref := range m {
+		if _, err := path.Match(image, ""); err != nil {
+			return nil, fmt.Errorf("invalid backstage mapping: bad pattern %q: %w", image, err)
+		}
+		normalized, err := ParseEntityRef(ref)
+		if err != nil {
+			return nil, fmt.Errorf("invalid backstage mapping for %s: %w", image, err)
+		}
+		m[image] = normalized
+	}
+	return m, nil
+}
+
+// ParseEntityRef normalizes a Backstage entity reference, "[kind:][namespace/]name", to "kind:namespace/name".
+// Kind and namespace are case-insensitive and default to "component" and "default".
+func ParseEntityRef(ref string) (string, error) {
+	kind, rest, ok := strings.Cut(ref, ":")
+	if !ok {
+		kind, rest = BackstageDefaultKind, ref
+	}
+	namespace, name, ok := strings.Cut(rest, "/")
+	if !ok {
+		namespace, name = BackstageDefaultNamespace, rest
+	}
+	if kind == "" || namespace == "" || name == "" || strings.ContainsAny(name, ":/") {
+		return "", fmt.Errorf("invalid entity reference %q (want [kind:][namespace/]name)", ref)
+	}
+	return strings.ToLower(kind) + ":" + strings.ToLower(namespace) + "/" + name, nil
+}
+
+// lookup returns the entity of the image.
+func (m BackstageMapping) lookup(image string) (string, bool) {
+	if ref, ok := m[image]; ok {
+		return ref, true
+	}
+	var best string
+	for pattern := range m {
+		if ok, _ := path.Match(pattern, image); ok && (len(pattern) > len(best) || len(pattern) == len(best) && pattern < best) {
+			best = pattern
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+	return m[best], true
+}
+
+// BackstageReport is the security report of the Backstage entities, for catalog plugins to show
+// the findings of each entity on its page.
+type BackstageReport struct {
+	// GeneratedAt is when the report was written
+	GeneratedAt time.Time `json:"generatedAt"`
+
+	// Entities are the entities with scanned images, sorted by reference
+	Entities []BackstageEntity `json:"entities"`
+
+	// Unmapped are the scanned images without an entity, sorted
+	Unmapped []string `json:"unmapped,omitempty"`
+}
+
+// BackstageEntity is the security status of an entity.
+type BackstageEntity struct {
+	// EntityRef is the entity reference, "kind:namespace/name"
+	EntityRef string `json:"entityRef"`
+
+	// Summary counts the findings of all images of the entity
+	Summary BackstageSummary `json:"summary"`
+
+	// Images are the scanned images of the entity
+	Images []BackstageImage `json:"images"`
+
+	// Findings are the findings of all images, most severe first
+	Findings []BackstageFinding `json:"findings"`
+}
+
+// BackstageSummary counts findings by severity.
+type BackstageSummary struct {
+	Critical int `json:"critical"`
+	High     int `json:"high"`
+	Medium   int `json:"medium"`
+	Low      int `json:"low"`
+	Other    int `json:"other"`
+	Fixable  int `json:"fixable"`
+	Total    int `json:"total"`
+}
+
+// BackstageImage is a scanned image of an entity.
+type BackstageImage struct {
+	Image    string           `json:"image"`
+	Digest   string           `json:"digest,omitempty"`
+	ScanTime time.Time        `json:"scanTime,omitzero"`
+	Summary  BackstageSummary `json:"summary"`
+}
+
+// BackstageFinding is a finding on an image of an entity.
+type BackstageFinding struct {
+	ID               string           `json:"id"`
+	Severity         schemas.Severity `json:"severity"`
+	PackageName      string           `json:"packageName"`
+	InstalledVersion string           `json:"installedVersion"`
+	FixedVersion     string           `json:"fixedVersion,omitempty"`
+	CVSSScore        float32          `json:"cvssScore,omitempty"`
+	Description      string           `json:"description,omitempty"`
+	URL              string           `json:"url,omitempty"`
+	Image            string           `json:"image"`
+}
+
+// BackstageExporter writes a BackstageReport, grouping the results by the entity of their image.
+type BackstageExporter struct {
+	writer  io.Writer
+	mapping BackstageMapping
+
+	// entities are the entities since Begin, by reference
+	entities map[string]*BackstageEntity
+	unmapped map[string]bool
+}
+
+// NewBackstageExporter creates a BackstageExporter writing the report to writer.
+// Images are mapped to entities with mapping; without a mapping, each image is the component named
+// after the last segment of its name in the default namespace (e.g. "component:default/api").
+func NewBackstageExporter(writer io.Writer, mapping BackstageMapping) *BackstageExporter {
+	return &BackstageExporter{
+		writer:  writer,
+		mapping: mapping,
+	}
+}
+
+// Export writes the report of all results
+func (e *BackstageExporter) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
+	if err := e.Begin(ctx); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if err := e.ExportOne(ctx, result); err != nil {
+			return err
+		}
+	}
+	return e.End(ctx)
+}
+
+// Begin resets the entities
+func (e *BackstageExporter) Begin(ctx context.Context) error {
+	e.entities = make(map[string]*BackstageEntity)
+	e.unmapped = make(map[string]bool)
+	return nil
+}
+
+// ExportOne adds a single result to the entity of its image
+func (e *BackstageExporter) ExportOne(ctx context.Context, result schemas.AnalyzeResult) error {
+	image := result.Artifact
+	image.Tag, image.Digest = nil, nil
+	name := image.String()
+
+	ref, ok := e.entityOf(image)
+	if !ok {
+		e.unmapped[name] = true
+		return nil
+	}
+	entity, ok := e.entities[ref]
+	if !ok {
+		entity = &BackstageEntity{EntityRef: ref, Images: []BackstageImage{}, Findings: []BackstageFinding{}}
+		e.entities[ref] = entity
+	}
+
+	img := BackstageImage{Image: name, ScanTime: result.ScanTime}
+	if result.Artifact.Digest != nil {
+		img.Digest = *result.Artifact.Digest
+	}
+	for _, v := range result.Vulnerabilities {
+		img.Summary.add(v)
+		entity.Summary.add(v)
+		entity.Findings = append(entity.Findings, BackstageFinding{
+			ID:               v.ID,
+			Severity:         v.Severity,
+			PackageName:      v.PackageName,
+			InstalledVersion: v.InstalledVersion,
+			FixedVersion:     v.FixedVersion,
+			CVSSScore:        v.CVSSScore,
+			Description:      v.Description,
+			URL:              advisoryURL(v),
+			Image:            name,
+		})
+	}
+	entity.Images = append(entity.Images, img)
+	return nil
+}
+
+// End writes the report
+func (e *BackstageExporter) End(ctx context.Context) error {
+	report := BackstageReport{GeneratedAt: time.Now().UTC(), Entities: []BackstageEntity{}}
+	for _, entity := range e.entities {
+		slices.SortFunc(entity.Images, func(a, b BackstageImage) int { return cmp.Compare(a.Image, b.Image) })
+		slices.SortStableFunc(entity.Findings, func(a, b BackstageFinding) int {
+			return cmp.Or(
+				schemas.CompareSeverity(b.Severity, a.Severity),
+				cmp.Compare(a.ID, b.ID),
+				cmp.Compare(a.Image, b.Image),
+			)
+		})
+		report.Entities = append(report.Entities, *entity)
+	}
+	slices.SortFunc(report.Entities, func(a, b BackstageEntity) int { return cmp.Compare(a.EntityRef, b.EntityRef) })
+	for image := range e.unmapped {
+		report.Unmapped = append(report.Unmapped, image)
+	}
+	slices.Sort(report.Unmapped)
+
+	enc := json.NewEncoder(e.writer)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("failed to write backstage report: %w", err)
+	}
+	return nil
+}
+
+// entityOf returns the entity of the image, by the mapping or by its name.
+func (e *BackstageExporter) entityOf(image schemas.ArtifactReference) (string, bool) {
+	if e.mapping != nil {
+		return e.mapping.lookup(image.String())
+	}
+	return BackstageDefaultKind + ":" + BackstageDefaultNamespace + "/" + path.Base(image.ImageName), true
+}
+
+// add counts the finding.
+func (s *BackstageSummary) add(v schemas.Vulnerability) {
+	s.Total++
+	switch v.Severity {
+	case schemas.SeverityCritical:
+		s.Critical++
+	case schemas.SeverityHigh:
+		s.High++
+	case schemas.SeverityMedium:
+		s.Medium++
+	case schemas.SeverityLow:
+		s.Low++
+	default:
+		s.Other++
+	}
+	if v.FixState == schemas.FixStateFixAvailable {
+		s.Fixable++
+	}
+}
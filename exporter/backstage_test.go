@@ -0,0 +1,168 @@
+package exporter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestParseEntityRef(t *testing.T) {
+	tests := map[string]struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		"should keep full references": {
+			ref:  "component:payments/api",
+			want: "component:payments/api",
+		},
+		"should default the kind and namespace": {
+			ref:  "api",
+			want: "component:default/api",
+		},
+		"should default the namespace": {
+			ref:  "Resource:db",
+			want: "resource:default/db",
+		},
+		"should lowercase the kind and namespace only": {
+			ref:  "Component:Payments/Api",
+			want: "component:payments/Api",
+		},
+		"should reject empty names": {
+			ref:     "component:default/",
+			wantErr: true,
+		},
+		"should reject extra separators": {
+			ref:     "component:default/a/b",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := exporter.ParseEntityRef(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseEntityRef() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseEntityRef() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadBackstageMapping_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"should reject invalid JSON":              `[]`,
+		"should reject invalid entity references": `{"h/p/r/api": "component:"}`,
+		"should reject invalid patterns":          `{"h/p/r/[": "api"}`,
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := exporter.ReadBackstageMapping(strings.NewReader(input)); err == nil {
+				t.Error("ReadBackstageMapping() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestBackstageExporter_Export(t *testing.T) {
+	scanned := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	artifact := func(image string) schemas.ArtifactReference {
+		return schemas.ArtifactReference{
+			Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: image,
+			Tag: utils.Ptr("v1"), Digest: utils.Ptr("sha256:" + strings.ReplaceAll(image, "/", "-")),
+		}
+	}
+	results := []schemas.AnalyzeResult{
+		{
+			Artifact: artifact("api"), ScanTime: scanned,
+			Vulnerabilities: []schemas.Vulnerability{
+				{ID: "CVE-2", Severity: schemas.SeverityHigh, PackageName: "zlib", FixState: schemas.FixStateFixAvailable, FixedVersion: "1.3"},
+				{ID: "CVE-1", Severity: schemas.SeverityCritical, PackageName: "openssl",
+					References: []schemas.Reference{{URL: "https://nvd.nist.gov/vuln/detail/CVE-1", Type: schemas.ReferenceTypeAdvisory}}},
+			},
+		},
+		{Artifact: artifact("team-a/worker"), ScanTime: scanned, Vulnerabilities: []schemas.Vulnerability{{ID: "CVE-3", Severity: schemas.SeverityLow, PackageName: "curl"}}},
+		{Artifact: artifact("team-a/cron"), ScanTime: scanned},
+		{Artifact: artifact("legacy")},
+	}
+
+	tests := map[string]struct {
+		mapping exporter.BackstageMapping
+		want    exporter.BackstageReport
+	}{
+		"should group images by mapped entity": {
+			mapping: exporter.BackstageMapping{
+				"h/p/r/api":      "component:default/api",
+				"h/p/r/team-a/*": "component:team-a/batch",
+			},
+			want: exporter.BackstageReport{
+				Entities: []exporter.BackstageEntity{
+					{
+						EntityRef: "component:default/api",
+						Summary:   exporter.BackstageSummary{Critical: 1, High: 1, Fixable: 1, Total: 2},
+						Images: []exporter.BackstageImage{
+							{Image: "h/p/r/api", Digest: "sha256:api", ScanTime: scanned, Summary: exporter.BackstageSummary{Critical: 1, High: 1, Fixable: 1, Total: 2}},
+						},
+						Findings: []exporter.BackstageFinding{
+							{ID: "CVE-1", Severity: schemas.SeverityCritical, PackageName: "openssl", URL: "https://nvd.nist.gov/vuln/detail/CVE-1", Image: "h/p/r/api"},
+							{ID: "CVE-2", Severity: schemas.SeverityHigh, PackageName: "zlib", FixedVersion: "1.3", Image: "h/p/r/api"},
+						},
+					},
+					{
+						EntityRef: "component:team-a/batch",
+						Summary:   exporter.BackstageSummary{Low: 1, Total: 1},
+						Images: []exporter.BackstageImage{
+							{Image: "h/p/r/team-a/cron", Digest: "sha256:team-a-cron", ScanTime: scanned},
+							{Image: "h/p/r/team-a/worker", Digest: "sha256:team-a-worker", ScanTime: scanned, Summary: exporter.BackstageSummary{Low: 1, Total: 1}},
+						},
+						Findings: []exporter.BackstageFinding{
+							{ID: "CVE-3", Severity: schemas.SeverityLow, PackageName: "curl", Image: "h/p/r/team-a/worker"},
+						},
+					},
+				},
+				Unmapped: []string{"h/p/r/legacy"},
+			},
+		},
+		"should name components after images without a mapping": {
+			want: exporter.BackstageReport{
+				Entities: []exporter.BackstageEntity{
+					{EntityRef: "component:default/api"},
+					{EntityRef: "component:default/cron"},
+					{EntityRef: "component:default/legacy"},
+					{EntityRef: "component:default/worker"},
+				},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := exporter.NewBackstageExporter(&buf, tt.mapping).Export(context.Background(), results); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			var got exporter.BackstageReport
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("invalid report: %v\n%s", err, buf.String())
+			}
+			opts := []cmp.Option{cmpopts.IgnoreFields(exporter.BackstageReport{}, "GeneratedAt")}
+			if tt.mapping == nil {
+				opts = append(opts, cmpopts.IgnoreFields(exporter.BackstageEntity{}, "Summary", "Images", "Findings"))
+			}
+			if diff := cmp.Diff(tt.want, got, opts...); diff != "" {
+				t.Errorf("Export() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
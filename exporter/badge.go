@@ -0,0 +1,148 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// Badge is a shields.io endpoint badge (https://shields.io/badges/endpoint-badge),
+// e.g. rendered as "vulns | 3 critical" in red.
+type Badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// badgeLevels lists the severities shown on badges, most severe first, with their colors.
+var badgeLevels = []struct {
+	severity schemas.Severity
+	color    string
+}{
+	{schemas.SeverityCritical, "red"},
+	{schemas.SeverityHigh, "orange"},
+	{schemas.SeverityMedium, "yellow"},
+	{schemas.SeverityLow, "yellowgreen"},
+}
+
+// NewBadge returns the badge of the given vulnerability counts by severity.
+// The message shows the counts of the two most severe levels found (e.g., "3 critical, 5 high"),
+// and the color the most severe one; "none" in bright green when there are no vulnerabilities.
+func NewBadge(counts map[schemas.Severity]int) Badge {
+	badge := Badge{SchemaVersion: 1, Label: "vulns", Message: "none", Color: "brightgreen"}
+	var parts []string
+	for _, level := range badgeLevels {
+		n := counts[level.severity]
+		if n == 0 {
+			continue
+		}
+		if len(parts) == 0 {
+			badge.Color = level.color
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", n, strings.ToLower(string(level.severity))))
+		if len(parts) == 2 {
+			break
+		}
+	}
+	if len(parts) > 0 {
+		badge.Message = strings.Join(parts, ", ")
+	}
+	return badge
+}
+
+// BadgeExporter writes a single badge summarizing the vulnerabilities of all results,
+// and optionally one badge per image into a directory.
+type BadgeExporter struct {
+	writer   io.Writer
+	imageDir string
+
+	// counts are the vulnerability counts since Begin, overall and by image
+	counts      map[schemas.Severity]int
+	imageCounts map[string]map[schemas.Severity]int
+}
+
+// NewBadgeExporter creates a BadgeExporter writing the overall badge to writer.
+// When imageDir is not empty, it also writes the badge of each image to
+// imageDir/<host>/<project>/<repository>/<image>.json; digests of the same image are counted together.
+func NewBadgeExporter(writer io.Writer, imageDir string) *BadgeExporter {
+	return &BadgeExporter{
+		writer:   writer,
+		imageDir: imageDir,
+	}
+}
+
+// Export writes the badges of all results
+func (e *BadgeExporter) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
+	if err := e.Begin(ctx); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if err := e.ExportOne(ctx, result); err != nil {
+			return err
+		}
+	}
+	return e.End(ctx)
+}
+
+// Begin resets the counts
+func (e *BadgeExporter) Begin(ctx context.Context) error {
+	e.counts = make(map[schemas.Severity]int)
+	e.imageCounts = make(map[string]map[schemas.Severity]int)
+	return nil
+}
+
+// ExportOne adds the vulnerabilities of a single result to the counts
+func (e *BadgeExporter) ExportOne(ctx context.Context, result schemas.AnalyzeResult) error {
+	image := result.Artifact
+	image.Tag, image.Digest = nil, nil
+	counts, ok := e.imageCounts[image.String()]
+	if !ok {
+		counts = make(map[schemas.Severity]int)
+		e.imageCounts[image.String()] = counts
+	}
+	for _, v := range result.Vulnerabilities {
+		e.counts[v.Severity]++
+		counts[v.Severity]++
+	}
+	return nil
+}
+
+// End writes the badges
+func (e *BadgeExporter) End(ctx context.Context) error {
+	if e.imageDir != "" {
+		for image, counts := range e.imageCounts {
+			if err := writeBadgeFile(filepath.Join(e.imageDir, filepath.FromSlash(image)+".json"), NewBadge(counts)); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := json.Marshal(NewBadge(e.counts))
+	if err != nil {
+		return err
+	}
+	_, err = e.writer.Write(append(data, '\n'))
+	return err
+}
+
+// writeBadgeFile writes a badge to path, creating its directory.
+func writeBadgeFile(path string, badge Badge) error {
+	data, err := json.Marshal(badge)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to write badge: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write badge: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package exporter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestNewBadge(t *testing.T) {
+	tests := map[string]struct {
+		counts map[schemas.Severity]int
+		want   exporter.Badge
+	}{
+		"should show the two most severe levels in the color of the worst": {
+			counts: map[schemas.Severity]int{schemas.SeverityCritical: 3, schemas.SeverityHigh: 5, schemas.SeverityLow: 1},
+			want:   exporter.Badge{SchemaVersion: 1, Label: "vulns", Message: "3 critical, 5 high", Color: "red"},
+		},
+		"should skip levels without findings": {
+			counts: map[schemas.Severity]int{schemas.SeverityMedium: 2, schemas.SeverityLow: 4},
+			want:   exporter.Badge{SchemaVersion: 1, Label: "vulns", Message: "2 medium, 4 low", Color: "yellow"},
+		},
+		"should be green without findings": {
+			counts: map[schemas.Severity]int{schemas.SeverityMinimal: 1},
+			want:   exporter.Badge{SchemaVersion: 1, Label: "vulns", Message: "none", Color: "brightgreen"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, exporter.NewBadge(tt.counts)); diff != "" {
+				t.Errorf("NewBadge() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBadgeExporter_Export(t *testing.T) {
+	api := schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "team/api"}
+	apiV1, apiV2 := api, api
+	apiV1.Digest = utils.Ptr("sha256:aaa")
+	apiV2.Tag = utils.Ptr("v2")
+	results := []schemas.AnalyzeResult{
+		{Artifact: apiV1, Vulnerabilities: []schemas.Vulnerability{{ID: "CVE-1", Severity: schemas.SeverityCritical}}},
+		{Artifact: apiV2, Vulnerabilities: []schemas.Vulnerability{{ID: "CVE-2", Severity: schemas.SeverityHigh}}},
+		{Artifact: schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "web"}},
+	}
+	dir := t.TempDir()
+
+	var buf bytes.Buffer
+	if err := exporter.NewBadgeExporter(&buf, dir).Export(context.Background(), results); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if diff := cmp.Diff(`{"schemaVersion":1,"label":"vulns","message":"1 critical, 1 high","color":"red"}`+"\n", buf.String()); diff != "" {
+		t.Errorf("overall badge mismatch (-want +got):\n%s", diff)
+	}
+
+	images := map[string]string{
+		"h/p/r/team/api.json": "1 critical, 1 high",
+		"h/p/r/web.json":      "none",
+	}
+	for path, want := range images {
+		data, err := os.ReadFile(filepath.Join(dir, path))
+		if err != nil {
+			t.Fatalf("image badge: %v", err)
+		}
+		var badge exporter.Badge
+		if err := json.Unmarshal(data, &badge); err != nil {
+			t.Fatalf("image badge %s: %v", path, err)
+		}
+		if badge.Message != want {
+			t.Errorf("%s message = %q, want %q", path, badge.Message, want)
+		}
+	}
+}
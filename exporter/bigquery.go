@@ -0,0 +1,313 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// BigQueryScope is the OAuth scope the HTTP client of BigQueryExporter needs.
+const BigQueryScope = "https://www.googleapis.com/auth/bigquery"
+
+// defaultBigQueryBatchSize is the number of rows per insertAll request, as recommended by BigQuery.
+const defaultBigQueryBatchSize = 500
+
+// BigQueryExporter streams one row per finding into a BigQuery table, so that scan history can be
+// queried and charted (e.g., in Looker Studio). Suppressed findings are included with their reason, and
+// images without findings get a single row without vulnerability, so that clean images are counted too.
+// A missing table is created with the schema of the rows, partitioned by scan date; the dataset must exist.
+type BigQueryExporter struct {
+	// Project, Dataset and Table identify the table, e.g. "my-project", "security", "drydock_findings"
+	Project string
+	Dataset string
+	Table   string
+
+	// HTTPClient sends the requests, authenticated with BigQueryScope (default: http.DefaultClient)
+	HTTPClient *http.Client
+
+	// Endpoint is the BigQuery API endpoint (default: "https://bigquery.googleapis.com")
+	Endpoint string
+
+	// BatchSize is the number of rows per insertAll request (default: 500)
+	BatchSize int
+
+	rows []bigQueryRow
+}
+
+// BigQueryAPIError is returned when the BigQuery API responds with an error status.
+type BigQueryAPIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+// Error implements the error interface.
+func (e *BigQueryAPIError) Error() string {
+	return fmt.Sprintf("BigQuery API %s %s: %d %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// bigQueryRow is a row of the table: a finding on an image, or an image without findings.
+// Keep it in sync with bigQuerySchema.
+type bigQueryRow struct {
+	ScanDate          string   `json:"scan_date"`
+	ScanTime          string   `json:"scan_time"`
+	Image             string   `json:"image"`
+	Project           string   `json:"project"`
+	Location          string   `json:"location,omitempty"`
+	Repository        string   `json:"repository"`
+	ImageName         string   `json:"image_name"`
+	Tag               string   `json:"tag,omitempty"`
+	Digest            string   `json:"digest,omitempty"`
+	ScanStatus        string   `json:"scan_status,omitempty"`
+	VulnerabilityID   string   `json:"vulnerability_id,omitempty"`
+	Severity          string   `json:"severity,omitempty"`
+	CVSSScore         *float32 `json:"cvss_score,omitempty"`
+	PackageName       string   `json:"package_name,omitempty"`
+	PackageType       string   `json:"package_type,omitempty"`
+	InstalledVersion  string   `json:"installed_version,omitempty"`
+	FixedVersion      string   `json:"fixed_version,omitempty"`
+	FixState          string   `json:"fix_state,omitempty"`
+	Suppressed        bool     `json:"suppressed"`
+	SuppressionReason string   `json:"suppression_reason,omitempty"`
+}
+
+// bigQueryField is a column of the table schema.
+type bigQueryField struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Mode        string `json:"mode"`
+	Description string `json:"description,omitempty"`
+}
+
+// bigQuerySchema is the schema of the tables created by BigQueryExporter.
+var bigQuerySchema = []bigQueryField{
+	{Name: "scan_date", Type: "DATE", Mode: "REQUIRED", Description: "UTC date of the scan, the partitioning column"},
+	{Name: "scan_time", Type: "TIMESTAMP", Mode: "REQUIRED"},
+	{Name: "image", Type: "STRING", Mode: "REQUIRED", Description: "Image reference, e.g. us-central1-docker.pkg.dev/p/r/api@sha256:..."},
+	{Name: "project", Type: "STRING", Mode: "REQUIRED"},
+	{Name: "location", Type: "STRING", Mode: "NULLABLE"},
+	{Name: "repository", Type: "STRING", Mode: "REQUIRED"},
+	{Name: "image_name", Type: "STRING", Mode: "REQUIRED"},
+	{Name: "tag", Type: "STRING", Mode: "NULLABLE"},
+	{Name: "digest", Type: "STRING", Mode: "NULLABLE"},
+	{Name: "scan_status", Type: "STRING", Mode: "NULLABLE", Description: "FINISHED, PENDING, UNSUPPORTED or NOT_SCANNED"},
+	{Name: "vulnerability_id", Type: "STRING", Mode: "NULLABLE", Description: "Empty for images without findings"},
+	{Name: "severity", Type: "STRING", Mode: "NULLABLE"},
+	{Name: "cvss_score", Type: "FLOAT", Mode: "NULLABLE"},
+	{Name: "package_name", Type: "STRING", Mode: "NULLABLE"},
+	{Name: "package_type", Type: "STRING", Mode: "NULLABLE"},
+	{Name: "installed_version", Type: "STRING", Mode: "NULLABLE"},
+	{Name: "fixed_version", Type: "STRING", Mode: "NULLABLE"},
+	{Name: "fix_state", Type: "STRING", Mode: "NULLABLE"},
+	{Name: "suppressed", Type: "BOOLEAN", Mode: "REQUIRED"},
+	{Name: "suppression_reason", Type: "STRING", Mode: "NULLABLE"},
+}
+
+// Export inserts the rows of all results
+func (e *BigQueryExporter) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
+	if err := e.Begin(ctx); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if err := e.ExportOne(ctx, result); err != nil {
+			return err
+		}
+	}
+	return e.End(ctx)
+}
+
+// Begin creates the table if it does not exist
+func (e *BigQueryExporter) Begin(ctx context.Context) error {
+	if e.Project == "" || e.Dataset == "" || e.Table == "" {
+		return errors.New("bigquery: Project, Dataset and Table are required")
+	}
+	e.rows = nil
+	return e.ensureTable(ctx)
+}
+
+// ExportOne queues the rows of a single result, inserting them once a batch is full
+func (e *BigQueryExporter) ExportOne(ctx context.Context, result schemas.AnalyzeResult) error {
+	e.rows = append(e.rows, bigQueryRows(result)...)
+	if len(e.rows) < e.batchSize() {
+		return nil
+	}
+	return e.flush(ctx)
+}
+
+// End inserts the remaining rows
+func (e *BigQueryExporter) End(ctx context.Context) error {
+	return e.flush(ctx)
+}
+
+func (e *BigQueryExporter) batchSize() int {
+	if e.BatchSize > 0 {
+		return e.BatchSize
+	}
+	return defaultBigQueryBatchSize
+}
+
+func (e *BigQueryExporter) tablePath() string {
+	return fmt.Sprintf("/bigquery/v2/projects/%s/datasets/%s/tables", e.Project, e.Dataset)
+}
+
+// ensureTable creates the table, partitioned by scan date, unless it exists.
+func (e *BigQueryExporter) ensureTable(ctx context.Context) error {
+	err := e.do(ctx, http.MethodGet, e.tablePath()+"/"+e.Table, nil, nil)
+	var apiErr *BigQueryAPIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		return err
+	}
+
+	table := map[string]any{
+		"tableReference":   map[string]string{"projectId": e.Project, "datasetId": e.Dataset, "tableId": e.Table},
+		"description":      "Vulnerability findings exported by drydock",
+		"schema":           map[string]any{"fields": bigQuerySchema},
+		"timePartitioning": map[string]string{"type": "DAY", "field": "scan_date"},
+		"clustering":       map[string]any{"fields": []string{"project", "repository", "image_name"}},
+	}
+	err = e.do(ctx, http.MethodPost, e.tablePath(), table, nil)
+	// Another exporter may have created it meanwhile
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		return nil
+	}
+	return err
+}
+
+// flush inserts the queued rows in batches.
+func (e *BigQueryExporter) flush(ctx context.Context) error {
+	for len(e.rows) > 0 {
+		n := min(len(e.rows), e.batchSize())
+		if err := e.insert(ctx, e.rows[:n]); err != nil {
+			return err
+		}
+		e.rows = e.rows[n:]
+	}
+	e.rows = nil
+	return nil
+}
+
+// insert streams rows with insertAll. Insert IDs let BigQuery drop the duplicates of retried requests.
+func (e *BigQueryExporter) insert(ctx context.Context, rows []bigQueryRow) error {
+	type insertRow struct {
+		InsertID string      `json:"insertId"`
+		JSON     bigQueryRow `json:"json"`
+	}
+	body := struct {
+		Rows []insertRow `json:"rows"`
+	}{Rows: make([]insertRow, len(rows))}
+	for i, row := range rows {
+		id := strings.Join([]string{row.Image, row.ScanTime, row.VulnerabilityID, row.PackageName, row.InstalledVersion}, "|")
+		body.Rows[i] = insertRow{InsertID: id, JSON: row}
+	}
+
+	var resp struct {
+		InsertErrors []struct {
+			Index  int `json:"index"`
+			Errors []struct {
+				Reason  string `json:"reason"`
+				Message string `json:"message"`
+			} `json:"errors"`
+		} `json:"insertErrors"`
+	}
+	if err := e.do(ctx, http.MethodPost, e.tablePath()+"/"+e.Table+"/insertAll", body, &resp); err != nil {
+		return err
+	}
+	if len(resp.InsertErrors) == 0 {
+		return nil
+	}
+	errs := make([]error, 0, len(resp.InsertErrors))
+	for _, ie := range resp.InsertErrors {
+		var msgs []string
+		for _, err := range ie.Errors {
+			msgs = append(msgs, err.Reason+": "+err.Message)
+		}
+		errs = append(errs, fmt.Errorf("row %s: %s", body.Rows[ie.Index].InsertID, strings.Join(msgs, "; ")))
+	}
+	return fmt.Errorf("bigquery: %d of %d rows not inserted: %w", len(resp.InsertErrors), len(rows), errors.Join(errs...))
+}
+
+// Check checks that the dataset can be read with the credentials; the table is created on export if missing.
+func (e *BigQueryExporter) Check(ctx context.Context) error {
+	return e.do(ctx, http.MethodGet, fmt.Sprintf("/bigquery/v2/projects/%s/datasets/%s", e.Project, e.Dataset), nil, nil)
+}
+
+// do sends a request to the BigQuery API (see doJSON).
+func (e *BigQueryExporter) do(ctx context.Context, method, path string, body, out any) error {
+	endpoint := e.Endpoint
+	if endpoint == "" {
+		endpoint = "https://bigquery.googleapis.com"
+	}
+	return doJSON(ctx, jsonAPI{
+		name:    "BigQuery API",
+		baseURL: endpoint,
+		client:  e.HTTPClient,
+		apiError: func(method, path string, statusCode int, body []byte) error {
+			var msg struct {
+				Error struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			_ = json.Unmarshal(body, &msg)
+			return &BigQueryAPIError{Method: method, Path: path, StatusCode: statusCode, Message: msg.Error.Message}
+		},
+	}, method, path, body, out)
+}
+
+// bigQueryRows flattens a result into one row per finding, suppressed ones included,
+// or a single row without vulnerability for an image without findings.
+func bigQueryRows(result schemas.AnalyzeResult) []bigQueryRow {
+	scanTime := result.ScanTime.UTC()
+	image := bigQueryRow{
+		ScanDate:   scanTime.Format(time.DateOnly),
+		ScanTime:   scanTime.Format(time.RFC3339Nano),
+		Image:      result.Artifact.String(),
+		Project:    result.Artifact.ProjectID,
+		Location:   strings.TrimSuffix(result.Artifact.Host, "-docker.pkg.dev"),
+		Repository: result.Artifact.RepositoryID,
+		ImageName:  result.Artifact.ImageName,
+		ScanStatus: string(result.ScanStatus),
+	}
+	if result.Artifact.Tag != nil {
+		image.Tag = *result.Artifact.Tag
+	}
+	if result.Artifact.Digest != nil {
+		image.Digest = *result.Artifact.Digest
+	}
+
+	finding := func(v schemas.Vulnerability) bigQueryRow {
+		row := image
+		row.VulnerabilityID = v.ID
+		row.Severity = string(v.Severity)
+		if v.CVSSScore > 0 {
+			row.CVSSScore = &v.CVSSScore
+		}
+		row.PackageName = v.PackageName
+		row.PackageType = v.PackageType
+		row.InstalledVersion = v.InstalledVersion
+		row.FixedVersion = v.FixedVersion
+		row.FixState = string(v.FixState)
+		return row
+	}
+
+	rows := make([]bigQueryRow, 0, len(result.Vulnerabilities)+len(result.Suppressed))
+	for _, v := range result.Vulnerabilities {
+		rows = append(rows, finding(v))
+	}
+	for _, s := range result.Suppressed {
+		row := finding(s.Vulnerability)
+		row.Suppressed = true
+		row.SuppressionReason = s.Suppression.Reason
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		rows = append(rows, image)
+	}
+	return rows
+}
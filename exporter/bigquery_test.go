@@ -0,0 +1,151 @@
+package exporter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+// fakeBigQuery serves the table and insertAll calls made by BigQueryExporter.
+type fakeBigQuery struct {
+	exists   bool
+	requests []string
+	batches  [][]map[string]any
+}
+
+func (f *fakeBigQuery) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const tables = "/bigquery/v2/projects/p/datasets/security/tables"
+	f.requests = append(f.requests, r.Method+" "+strings.TrimPrefix(r.URL.Path, tables))
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == tables+"/findings":
+		if !f.exists {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]string{"message": "Not found: Table"}})
+			return
+		}
+	case r.Method == http.MethodPost && r.URL.Path == tables:
+		f.exists = true
+	case r.Method == http.MethodPost && r.URL.Path == tables+"/findings/insertAll":
+		var body struct {
+			Rows []struct {
+				JSON map[string]any `json:"json"`
+			} `json:"rows"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		var rows []map[string]any
+		for _, row := range body.Rows {
+			rows = append(rows, row.JSON)
+		}
+		f.batches = append(f.batches, rows)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	_, _ = w.Write([]byte("{}"))
+}
+
+func TestBigQueryExporter_Export(t *testing.T) {
+	scanTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	api := schemas.ArtifactReference{Host: "us-central1-docker.pkg.dev", ProjectID: "p", RepositoryID: "r", ImageName: "api", Digest: utils.Ptr("sha256:abc")}
+	results := []schemas.AnalyzeResult{
+		{
+			Artifact:   api,
+			ScanTime:   scanTime,
+			ScanStatus: schemas.ScanStatusFinished,
+			Vulnerabilities: []schemas.Vulnerability{
+				{ID: "CVE-1", Severity: schemas.SeverityHigh, PackageName: "openssl", InstalledVersion: "1.1", FixedVersion: "1.2", CVSSScore: 7.5},
+			},
+			Suppressed: []schemas.SuppressedFinding{{
+				Vulnerability: schemas.Vulnerability{ID: "CVE-2", Severity: schemas.SeverityLow, PackageName: "bash"},
+				Suppression:   schemas.Suppression{ID: "CVE-2", Reason: "no shell"},
+			}},
+		},
+		{Artifact: schemas.ArtifactReference{Host: "us-central1-docker.pkg.dev", ProjectID: "p", RepositoryID: "r", ImageName: "web"}, ScanTime: scanTime},
+	}
+	image := func(name string) map[string]any {
+		return map[string]any{
+			"scan_date": "2024-05-01", "scan_time": "2024-05-01T12:00:00Z",
+			"project": "p", "location": "us-central1", "repository": "r", "image_name": name, "suppressed": false,
+		}
+	}
+	with := func(row map[string]any, fields map[string]any) map[string]any {
+		for k, v := range fields {
+			row[k] = v
+		}
+		return row
+	}
+	openssl := with(image("api"), map[string]any{
+		"image": "us-central1-docker.pkg.dev/p/r/api@sha256:abc", "digest": "sha256:abc", "scan_status": "FINISHED",
+		"vulnerability_id": "CVE-1", "severity": "HIGH", "cvss_score": 7.5, "package_name": "openssl",
+		"installed_version": "1.1", "fixed_version": "1.2",
+	})
+	bash := with(image("api"), map[string]any{
+		"image": "us-central1-docker.pkg.dev/p/r/api@sha256:abc", "digest": "sha256:abc", "scan_status": "FINISHED",
+		"vulnerability_id": "CVE-2", "severity": "LOW", "package_name": "bash",
+		"suppressed": true, "suppression_reason": "no shell",
+	})
+	web := with(image("web"), map[string]any{"image": "us-central1-docker.pkg.dev/p/r/web"})
+
+	tests := map[string]struct {
+		exists       bool
+		batchSize    int
+		wantRequests []string
+		wantBatches  [][]map[string]any
+	}{
+		"should create a missing table and insert one row per finding": {
+			wantRequests: []string{"GET /findings", "POST ", "POST /findings/insertAll"},
+			wantBatches:  [][]map[string]any{{openssl, bash, web}},
+		},
+		"should insert into an existing table in batches": {
+			exists:       true,
+			batchSize:    2,
+			wantRequests: []string{"GET /findings", "POST /findings/insertAll", "POST /findings/insertAll"},
+			wantBatches:  [][]map[string]any{{openssl, bash}, {web}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fake := &fakeBigQuery{exists: tt.exists}
+			srv := httptest.NewServer(fake)
+			defer srv.Close()
+
+			e := &exporter.BigQueryExporter{
+				Project: "p", Dataset: "security", Table: "findings",
+				Endpoint: srv.URL, BatchSize: tt.batchSize,
+			}
+			if err := e.Export(context.Background(), results); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.wantRequests, fake.requests); diff != "" {
+				t.Errorf("requests mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBatches, fake.batches); diff != "" {
+				t.Errorf("rows mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBigQueryExporter_Export_InsertErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"insertErrors": [{"index": 0, "errors": [{"reason": "invalid", "message": "no such field"}]}]}`))
+	}))
+	defer srv.Close()
+
+	e := &exporter.BigQueryExporter{Project: "p", Dataset: "security", Table: "findings", Endpoint: srv.URL}
+	results := []schemas.AnalyzeResult{{Artifact: schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "api"}}}
+	err := e.Export(context.Background(), results)
+	if err == nil || !strings.Contains(err.Error(), "1 of 1 rows not inserted") || !strings.Contains(err.Error(), "no such field") {
+		t.Errorf("Export() error = %v, want the rejected rows", err)
+	}
+}
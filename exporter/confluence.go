@@ -0,0 +1,221 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// ConfluenceExporter publishes the report as a Confluence page, creating it on the first export
+// and updating it (as a new page version) afterwards. Pages are identified by their title in the space.
+type ConfluenceExporter struct {
+	// BaseURL is the Confluence endpoint, e.g. "https://example.atlassian.net/wiki" for Confluence Cloud
+	BaseURL string
+
+	// Space is the key of the space the page lives in
+	Space string
+
+	// ParentID is the ID of the page to create the page under (default: the space root)
+	ParentID string
+
+	// Title is the page title (default: "Vulnerability report")
+	Title string
+
+	// User is the account email for Confluence Cloud API tokens; when empty, Token is sent
+	// as a bearer token (personal access tokens of Confluence Data Center)
+	User string
+
+	// Token is the API token or personal access token
+	Token string
+
+	// HTTPClient sends the Confluence API requests (default: http.DefaultClient)
+	HTTPClient *http.Client
+
+	// PageURL is the web URL of the page, once exported
+	PageURL string
+}
+
+// ConfluenceAPIError is returned when the Confluence API responds with an error status.
+type ConfluenceAPIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+// Error implements the error interface.
+func (e *ConfluenceAPIError) Error() string {
+	return fmt.Sprintf("Confluence API %s %s: %d %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// confluencePage is the subset of the content API's page representation used by the exporter.
+type confluencePage struct {
+	ID        string              `json:"id,omitempty"`
+	Type      string              `json:"type"`
+	Title     string              `json:"title"`
+	Space     map[string]string   `json:"space"`
+	Ancestors []map[string]string `json:"ancestors,omitempty"`
+	Version   *confluenceVersion  `json:"version,omitempty"`
+	Body      *confluenceBody     `json:"body,omitempty"`
+	Links     struct {
+		Base  string `json:"base"`
+		WebUI string `json:"webui"`
+	} `json:"_links"`
+}
+
+type confluenceVersion struct {
+	Number int `json:"number"`
+}
+
+type confluenceBody struct {
+	Storage confluenceStorage `json:"storage"`
+}
+
+type confluenceStorage struct {
+	Value          string `json:"value"`
+	Representation string `json:"representation"`
+}
+
+// Export renders the results and creates or updates the page
+func (e *ConfluenceExporter) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
+	if e.BaseURL == "" || e.Space == "" {
+		return errors.New("confluence: BaseURL and Space are required")
+	}
+	title := e.Title
+	if title == "" {
+		title = "Vulnerability report"
+	}
+
+	var found struct {
+		Results []confluencePage `json:"results"`
+	}
+	query := url.Values{"spaceKey": {e.Space}, "title": {title}, "expand": {"version"}}
+	if err := e.do(ctx, http.MethodGet, "/rest/api/content?"+query.Encode(), nil, &found); err != nil {
+		return err
+	}
+
+	page := confluencePage{
+		Type:  "page",
+		Title: title,
+		Space: map[string]string{"key": e.Space},
+		Body:  &confluenceBody{Storage: confluenceStorage{Value: RenderConfluenceStorage(results), Representation: "storage"}},
+	}
+
+	var saved confluencePage
+	if len(found.Results) == 0 {
+		if e.ParentID != "" {
+			page.Ancestors = []map[string]string{{"id": e.ParentID}}
+		}
+		if err := e.do(ctx, http.MethodPost, "/rest/api/content", page, &saved); err != nil {
+			return err
+		}
+	} else {
+		existing := found.Results[0]
+		page.Version = &confluenceVersion{Number: 1}
+		if existing.Version != nil {
+			page.Version.Number = existing.Version.Number + 1
+		}
+		if err := e.do(ctx, http.MethodPut, "/rest/api/content/"+url.PathEscape(existing.ID), page, &saved); err != nil {
+			return err
+		}
+	}
+	if saved.Links.WebUI != "" {
+		base := saved.Links.Base
+		if base == "" {
+			base = strings.TrimSuffix(e.BaseURL, "/")
+		}
+		e.PageURL = base + saved.Links.WebUI
+	}
+	return nil
+}
+
+// Check checks that the space can be read with the credentials, without publishing anything.
+func (e *ConfluenceExporter) Check(ctx context.Context) error {
+	return e.do(ctx, http.MethodGet, "/rest/api/space/"+url.PathEscape(e.Space), nil, nil)
+}
+
+// do sends a request to the Confluence API (see doJSON).
+func (e *ConfluenceExporter) do(ctx context.Context, method, path string, body, out any) error {
+	return doJSON(ctx, jsonAPI{
+		name:    "Confluence API",
+		baseURL: e.BaseURL,
+		client:  e.HTTPClient,
+		header: func(req *http.Request) {
+			req.Header.Set("Accept", "application/json")
+			switch {
+			case e.User != "":
+				req.SetBasicAuth(e.User, e.Token)
+			case e.Token != "":
+				req.Header.Set("Authorization", "Bearer "+e.Token)
+			}
+		},
+		apiError: func(method, path string, statusCode int, body []byte) error {
+			var msg struct {
+				Message string `json:"message"`
+			}
+			_ = json.Unmarshal(body, &msg)
+			return &ConfluenceAPIError{Method: method, Path: path, StatusCode: statusCode, Message: msg.Message}
+		},
+	}, method, path, body, out)
+}
+
+// confluenceSeverities are the severity columns of the summary table.
+var confluenceSeverities = []schemas.Severity{schemas.SeverityCritical, schemas.SeverityHigh, schemas.SeverityMedium, schemas.SeverityLow}
+
+// RenderConfluenceStorage renders the results in the Confluence storage format (XHTML):
+// a summary table of the images, then the findings of each image, most severe first.
+func RenderConfluenceStorage(results []schemas.AnalyzeResult) string {
+	results = slices.Clone(results)
+	slices.SortFunc(results, func(a, b schemas.AnalyzeResult) int {
+		return strings.Compare(a.Artifact.String(), b.Artifact.String())
+	})
+
+	var b strings.Builder
+	scanTime := time.Now()
+	if len(results) > 0 {
+		scanTime = results[0].ScanTime
+	}
+	fmt.Fprintf(&b, "<p>%d images scanned by drydock at %s.</p>", len(results), html.EscapeString(scanTime.UTC().Format(time.RFC3339)))
+
+	b.WriteString("<table><tbody><tr><th>Image</th>")
+	for _, s := range confluenceSeverities {
+		fmt.Fprintf(&b, "<th>%s</th>", s)
+	}
+	b.WriteString("<th>Fixable</th></tr>")
+	for _, r := range results {
+		fmt.Fprintf(&b, "<tr><td><code>%s</code></td>", html.EscapeString(r.Artifact.String()))
+		for _, s := range confluenceSeverities {
+			fmt.Fprintf(&b, "<td>%d</td>", r.Summary.CountBySeverity[s])
+		}
+		fmt.Fprintf(&b, "<td>%d</td></tr>", r.Summary.FixableCount)
+	}
+	b.WriteString("</tbody></table>")
+
+	for _, r := range results {
+		if len(r.Vulnerabilities) == 0 {
+			continue
+		}
+		vulns := slices.Clone(r.Vulnerabilities)
+		slices.SortStableFunc(vulns, func(a, b schemas.Vulnerability) int {
+			return schemas.CompareSeverity(b.Severity, a.Severity)
+		})
+		fmt.Fprintf(&b, "<h2>%s</h2>", html.EscapeString(r.Artifact.String()))
+		b.WriteString("<table><tbody><tr><th>ID</th><th>Severity</th><th>Package</th><th>Installed</th><th>Fixed</th><th>CVSS</th></tr>")
+		for _, v := range vulns {
+			fmt.Fprintf(&b, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%.1f</td></tr>",
+				html.EscapeString(v.ID), v.Severity, html.EscapeString(v.PackageName),
+				html.EscapeString(v.InstalledVersion), html.EscapeString(v.FixedVersion), v.CVSSScore)
+		}
+		b.WriteString("</tbody></table>")
+	}
+	return b.String()
+}
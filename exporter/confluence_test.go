@@ -0,0 +1,118 @@
+package exporter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// fakeConfluence serves the content API calls made by ConfluenceExporter, recording the requests.
+type fakeConfluence struct {
+	existing map[string]any // page found by the title search, if any
+	requests []string
+	saved    map[string]any
+}
+
+func (f *fakeConfluence) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	if user, token, ok := r.BasicAuth(); !ok || user != "me@example.com" || token != "token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]string{"message": "unauthorized"})
+		return
+	}
+
+	switch r.Method + " " + r.URL.Path {
+	case "GET /wiki/rest/api/content":
+		if r.URL.Query().Get("spaceKey") != "SEC" || r.URL.Query().Get("title") != "Scan" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var results []map[string]any
+		if f.existing != nil {
+			results = append(results, f.existing)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"results": results})
+	case "POST /wiki/rest/api/content", "PUT /wiki/rest/api/content/42":
+		_ = json.NewDecoder(r.Body).Decode(&f.saved)
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "42", "_links": map[string]string{"base": "https://example.atlassian.net/wiki", "webui": "/spaces/SEC/pages/42"}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestConfluenceExporter_Export(t *testing.T) {
+	results := []schemas.AnalyzeResult{{
+		Artifact:        schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "api"},
+		Vulnerabilities: []schemas.Vulnerability{{ID: "CVE-1", Severity: schemas.SeverityCritical, PackageName: "<openssl>"}},
+		Summary:         schemas.VulnerabilitySummary{CountBySeverity: map[schemas.Severity]int{schemas.SeverityCritical: 1}},
+	}}
+
+	tests := map[string]struct {
+		existing     map[string]any
+		wantRequests []string
+		wantVersion  any
+		wantParent   bool
+	}{
+		"should create the page under the parent": {
+			wantRequests: []string{"GET /wiki/rest/api/content", "POST /wiki/rest/api/content"},
+			wantParent:   true,
+		},
+		"should update the existing page as a new version": {
+			existing:     map[string]any{"id": "42", "version": map[string]int{"number": 6}},
+			wantRequests: []string{"GET /wiki/rest/api/content", "PUT /wiki/rest/api/content/42"},
+			wantVersion:  map[string]any{"number": float64(7)},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fake := &fakeConfluence{existing: tt.existing}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+
+			e := &exporter.ConfluenceExporter{
+				BaseURL: server.URL + "/wiki", Space: "SEC", ParentID: "7", Title: "Scan",
+				User: "me@example.com", Token: "token",
+			}
+			if err := e.Export(context.Background(), results); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.wantRequests, fake.requests); diff != "" {
+				t.Errorf("requests mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantVersion, fake.saved["version"]); diff != "" {
+				t.Errorf("version mismatch (-want +got):\n%s", diff)
+			}
+			if _, ok := fake.saved["ancestors"]; ok != tt.wantParent {
+				t.Errorf("ancestors = %v, want parent %v", fake.saved["ancestors"], tt.wantParent)
+			}
+			body := fake.saved["body"].(map[string]any)["storage"].(map[string]any)["value"].(string)
+			if !strings.Contains(body, "<td>&lt;openssl&gt;</td>") {
+				t.Errorf("page body does not list the escaped finding:\n%s", body)
+			}
+			if e.PageURL != "https://example.atlassian.net/wiki/spaces/SEC/pages/42" {
+				t.Errorf("PageURL = %s", e.PageURL)
+			}
+		})
+	}
+}
+
+func TestConfluenceExporter_Export_APIError(t *testing.T) {
+	server := httptest.NewServer(&fakeConfluence{})
+	defer server.Close()
+
+	e := &exporter.ConfluenceExporter{BaseURL: server.URL + "/wiki", Space: "SEC", Title: "Scan", User: "me@example.com", Token: "wrong"}
+	err := e.Export(context.Background(), nil)
+	var apiErr *exporter.ConfluenceAPIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Export() error = %v, want a 401 ConfluenceAPIError", err)
+	}
+}
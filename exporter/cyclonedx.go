@@ -0,0 +1,367 @@
+package exporter
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// CycloneDXSpecVersion is the version of the CycloneDX documents written by CycloneDXExporter
+const CycloneDXSpecVersion = "1.5"
+
+// CycloneDXBOM is a CycloneDX 1.5 document: the scanned images with their affected packages, and the
+// vulnerabilities affecting them.
+type CycloneDXBOM struct {
+	BOMFormat       string                   `json:"bomFormat"`
+	SpecVersion     string                   `json:"specVersion"`
+	Version         int                      `json:"version"`
+	Metadata        CycloneDXMetadata        `json:"metadata"`
+	Components      []CycloneDXComponent     `json:"components"`
+	Vulnerabilities []CycloneDXVulnerability `json:"vulnerabilities"`
+}
+
+// CycloneDXMetadata tells when and by which tool the document was produced.
+type CycloneDXMetadata struct {
+	Timestamp string         `json:"timestamp"`
+	Tools     CycloneDXTools `json:"tools"`
+}
+
+// CycloneDXTools lists drydock as the tool that produced the document.
+type CycloneDXTools struct {
+	Components []CycloneDXComponent `json:"components"`
+}
+
+// CycloneDXComponent is a scanned image ("container"), one of its packages ("library"), or drydock itself.
+type CycloneDXComponent struct {
+	BOMRef       string                 `json:"bom-ref,omitempty"`
+	Type         string                 `json:"type"`
+	Name         string                 `json:"name"`
+	Version      string                 `json:"version,omitempty"`
+	PURL         string                 `json:"purl,omitempty"`
+	Components   []CycloneDXComponent   `json:"components,omitempty"`
+	ExternalRefs []CycloneDXExternalRef `json:"externalReferences,omitempty"`
+}
+
+// CycloneDXExternalRef links a component to an external resource, e.g. the website of drydock.
+type CycloneDXExternalRef struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// CycloneDXVulnerability is a vulnerability and the packages it affects. Suppressed findings are
+// listed apart, with an analysis stating they are not affected (VEX).
+type CycloneDXVulnerability struct {
+	ID          string              `json:"id"`
+	Source      *CycloneDXSource    `json:"source,omitempty"`
+	Ratings     []CycloneDXRating   `json:"ratings,omitempty"`
+	CWEs        []int               `json:"cwes,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Advisories  []CycloneDXAdvisory `json:"advisories,omitempty"`
+	Analysis    *CycloneDXAnalysis  `json:"analysis,omitempty"`
+	Affects     []CycloneDXAffect   `json:"affects"`
+}
+
+// CycloneDXSource is the database the vulnerability ID comes from.
+type CycloneDXSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// CycloneDXRating is the severity and score of a vulnerability.
+type CycloneDXRating struct {
+	Score    float32 `json:"score,omitempty"`
+	Severity string  `json:"severity"`
+	Method   string  `json:"method,omitempty"`
+	Vector   string  `json:"vector,omitempty"`
+}
+
+// CycloneDXAdvisory is a link to an advisory of the vulnerability.
+type CycloneDXAdvisory struct {
+	URL string `json:"url"`
+}
+
+// CycloneDXAnalysis is the impact analysis of a vulnerability, from the suppression of the findings.
+type CycloneDXAnalysis struct {
+	State  string `json:"state"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// CycloneDXAffect is a package affected by the vulnerability, referenced by its bom-ref.
+type CycloneDXAffect struct {
+	Ref      string                   `json:"ref"`
+	Versions []CycloneDXAffectVersion `json:"versions,omitempty"`
+}
+
+// CycloneDXAffectVersion is the installed version of an affected package.
+type CycloneDXAffectVersion struct {
+	Version string `json:"version"`
+	Status  string `json:"status"`
+}
+
+// CycloneDXExporter writes the findings as a CycloneDX 1.5 document, e.g. to upload to Dependency-Track.
+// Each image is a container component with its affected packages as nested components, and each
+// vulnerability lists the packages it affects. Suppressed findings are reported as not affected, with
+// the reason of their suppression. The document is written by End, once every result is in.
+type CycloneDXExporter struct {
+	writer io.Writer
+
+	images          []CycloneDXComponent
+	vulnerabilities map[string]*CycloneDXVulnerability // by ID, and analysis state for suppressed ones
+	scanTime        time.Time
+}
+
+// NewCycloneDXExporter creates a CycloneDXExporter writing the document to writer.
+func NewCycloneDXExporter(writer io.Writer) *CycloneDXExporter {
+	return &CycloneDXExporter{writer: writer}
+}
+
+// Export writes the document of all results
+func (e *CycloneDXExporter) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
+	if err := e.Begin(ctx); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if err := e.ExportOne(ctx, result); err != nil {
+			return err
+		}
+	}
+	return e.End(ctx)
+}
+
+// Begin resets the components and vulnerabilities
+func (e *CycloneDXExporter) Begin(ctx context.Context) error {
+	e.images = nil
+	e.vulnerabilities = make(map[string]*CycloneDXVulnerability)
+	e.scanTime = time.Time{}
+	return nil
+}
+
+// ExportOne adds the image of a single result, with its findings
+func (e *CycloneDXExporter) ExportOne(ctx context.Context, result schemas.AnalyzeResult) error {
+	if e.vulnerabilities == nil {
+		e.vulnerabilities = make(map[string]*CycloneDXVulnerability)
+	}
+	if e.scanTime.IsZero() || result.ScanTime.Before(e.scanTime) {
+		e.scanTime = result.ScanTime
+	}
+
+	image := cycloneDXImage(result.Artifact)
+	packages := make(map[string]bool)
+	addPackage := func(v schemas.Vulnerability) string {
+		ref := image.BOMRef + "#" + cmp.Or(v.PURL, v.PackageName+"@"+v.InstalledVersion)
+		if !packages[ref] {
+			packages[ref] = true
+			image.Components = append(image.Components, CycloneDXComponent{
+				BOMRef:  ref,
+				Type:    "library",
+				Name:    v.PackageName,
+				Version: v.InstalledVersion,
+				PURL:    v.PURL,
+			})
+		}
+		return ref
+	}
+
+	for _, v := range result.Vulnerabilities {
+		e.addFinding(v, addPackage(v), nil)
+	}
+	for _, s := range result.Suppressed {
+		e.addFinding(s.Vulnerability, addPackage(s.Vulnerability), &CycloneDXAnalysis{
+			State:  "not_affected",
+			Detail: suppressionDetail(s.Suppression),
+		})
+	}
+	e.images = append(e.images, image)
+	return nil
+}
+
+// End writes the document
+func (e *CycloneDXExporter) End(ctx context.Context) error {
+	vulnerabilities := make([]CycloneDXVulnerability, 0, len(e.vulnerabilities))
+	for _, v := range e.vulnerabilities {
+		vulnerabilities = append(vulnerabilities, *v)
+	}
+	slices.SortFunc(vulnerabilities, func(a, b CycloneDXVulnerability) int {
+		return cmp.Or(cmp.Compare(a.ID, b.ID), cmp.Compare(cycloneDXAnalysisState(a), cycloneDXAnalysisState(b)))
+	})
+	components := e.images
+	if components == nil {
+		components = []CycloneDXComponent{}
+	}
+	scanTime := e.scanTime
+	if scanTime.IsZero() {
+		scanTime = time.Now()
+	}
+	e.images, e.vulnerabilities = nil, nil
+
+	bom := CycloneDXBOM{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: CycloneDXSpecVersion,
+		Version:     1,
+		Metadata: CycloneDXMetadata{
+			Timestamp: scanTime.UTC().Format(time.RFC3339),
+			Tools: CycloneDXTools{Components: []CycloneDXComponent{{
+				Type:         "application",
+				Name:         "drydock",
+				ExternalRefs: []CycloneDXExternalRef{{Type: "website", URL: scannerURI}},
+			}}},
+		},
+		Components:      components,
+		Vulnerabilities: vulnerabilities,
+	}
+	enc := json.NewEncoder(e.writer)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(bom); err != nil {
+		return fmt.Errorf("failed to write CycloneDX document: %w", err)
+	}
+	return nil
+}
+
+// addFinding adds the package to the vulnerability of v, creating it on first sight. Findings of the
+// same ID are merged, keeping the most severe rating: data sources may rate packages differently.
+func (e *CycloneDXExporter) addFinding(v schemas.Vulnerability, ref string, analysis *CycloneDXAnalysis) {
+	key := v.ID
+	if analysis != nil {
+		key += "\x00" + analysis.State
+	}
+	affect := CycloneDXAffect{Ref: ref}
+	if v.InstalledVersion != "" {
+		affect.Versions = []CycloneDXAffectVersion{{Version: v.InstalledVersion, Status: "affected"}}
+	}
+
+	if existing, ok := e.vulnerabilities[key]; ok {
+		if !slices.ContainsFunc(existing.Affects, func(a CycloneDXAffect) bool { return a.Ref == ref }) {
+			existing.Affects = append(existing.Affects, affect)
+		}
+		if rating := cycloneDXRating(v); cycloneDXSeverityRank(rating.Severity) > cycloneDXSeverityRank(existing.Ratings[0].Severity) {
+			existing.Ratings = []CycloneDXRating{rating}
+		}
+		if analysis != nil && !strings.Contains(existing.Analysis.Detail, analysis.Detail) {
+			existing.Analysis.Detail += "; " + analysis.Detail
+		}
+		return
+	}
+
+	vuln := &CycloneDXVulnerability{
+		ID:          v.ID,
+		Source:      cycloneDXSource(v.ID),
+		Ratings:     []CycloneDXRating{cycloneDXRating(v)},
+		Description: v.Description,
+		Analysis:    analysis,
+		Affects:     []CycloneDXAffect{affect},
+	}
+	for _, cwe := range v.CWEs {
+		if n, err := strconv.Atoi(strings.TrimPrefix(cwe, "CWE-")); err == nil {
+			vuln.CWEs = append(vuln.CWEs, n)
+		}
+	}
+	for _, r := range v.References {
+		if r.Type == schemas.ReferenceTypeAdvisory {
+			vuln.Advisories = append(vuln.Advisories, CycloneDXAdvisory{URL: r.URL})
+		}
+	}
+	e.vulnerabilities[key] = vuln
+}
+
+// cycloneDXAnalysisState returns the analysis state of v, or "" for findings without analysis.
+func cycloneDXAnalysisState(v CycloneDXVulnerability) string {
+	if v.Analysis == nil {
+		return ""
+	}
+	return v.Analysis.State
+}
+
+// cycloneDXImage returns the container component of an image, identified by its OCI Package URL.
+func cycloneDXImage(artifact schemas.ArtifactReference) CycloneDXComponent {
+	repository := artifact
+	repository.Tag, repository.Digest = nil, nil
+	component := CycloneDXComponent{
+		BOMRef: artifact.String(),
+		Type:   "container",
+		Name:   repository.String(),
+	}
+
+	qualifiers := url.Values{"repository_url": {repository.String()}}
+	purl := "pkg:oci/" + path.Base(artifact.ImageName)
+	if artifact.Digest != nil && *artifact.Digest != "" {
+		component.Version = *artifact.Digest
+		purl += "@" + strings.ReplaceAll(*artifact.Digest, ":", "%3A")
+	}
+	if artifact.Tag != nil && *artifact.Tag != "" {
+		component.Version = cmp.Or(component.Version, *artifact.Tag)
+		qualifiers.Set("tag", *artifact.Tag)
+	}
+	component.PURL = purl + "?" + qualifiers.Encode()
+	return component
+}
+
+// cycloneDXRating returns the rating of v, with its CVSS score and vector if known.
+func cycloneDXRating(v schemas.Vulnerability) CycloneDXRating {
+	rating := CycloneDXRating{Severity: cycloneDXSeverity(v.Severity)}
+	if v.CVSSScore > 0 {
+		rating.Score = v.CVSSScore
+		rating.Vector = v.CVSSVector
+		switch {
+		case strings.HasPrefix(v.CVSSVector, "CVSS:3.1/"):
+			rating.Method = "CVSSv31"
+		case v.CVSSVersion == "3":
+			rating.Method = "CVSSv3"
+		case v.CVSSVersion == "2":
+			rating.Method = "CVSSv2"
+		default:
+			rating.Method = "other"
+		}
+	}
+	return rating
+}
+
+// cycloneDXSeverity maps a severity to a CycloneDX severity.
+func cycloneDXSeverity(severity schemas.Severity) string {
+	switch severity {
+	case schemas.SeverityCritical, schemas.SeverityHigh, schemas.SeverityMedium, schemas.SeverityLow:
+		return strings.ToLower(string(severity))
+	case schemas.SeverityMinimal:
+		return "info"
+	default:
+		return "unknown"
+	}
+}
+
+// cycloneDXSeverityRank orders CycloneDX severities, from unknown to critical.
+func cycloneDXSeverityRank(severity string) int {
+	return slices.Index([]string{"unknown", "info", "low", "medium", "high", "critical"}, severity)
+}
+
+// cycloneDXSource returns the database of well-known vulnerability IDs, or nil.
+func cycloneDXSource(id string) *CycloneDXSource {
+	switch {
+	case strings.HasPrefix(id, "CVE-"):
+		return &CycloneDXSource{Name: "NVD", URL: "https://nvd.nist.gov/vuln/detail/" + id}
+	case strings.HasPrefix(id, "GHSA-"):
+		return &CycloneDXSource{Name: "GitHub", URL: "https://github.com/advisories/" + id}
+	default:
+		return nil
+	}
+}
+
+// suppressionDetail describes who suppressed a finding, why, and until when.
+func suppressionDetail(s schemas.Suppression) string {
+	detail := cmp.Or(s.Reason, "suppressed")
+	if s.By != "" {
+		detail += " (by " + s.By + ")"
+	}
+	if !s.Until.IsZero() {
+		detail += " until " + s.Until.Format(time.DateOnly)
+	}
+	return detail
+}
@@ -0,0 +1,126 @@
+package exporter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestCycloneDXExporter_Export(t *testing.T) {
+	scanTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	image := func(name, digest string) schemas.ArtifactReference {
+		return schemas.ArtifactReference{
+			Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: name, Digest: utils.Ptr(digest),
+		}
+	}
+	high := schemas.Vulnerability{
+		ID: "CVE-1", Severity: schemas.SeverityHigh, PackageName: "openssl", InstalledVersion: "3.0.1",
+		PURL: "pkg:deb/debian/openssl@3.0.1", CWEs: []string{"CWE-787"}, CVSSScore: 7.5, CVSSVersion: "3",
+		CVSSVector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H",
+		References: []schemas.Reference{{URL: "https://example.com/CVE-1", Type: schemas.ReferenceTypeAdvisory}},
+	}
+	critical := high
+	critical.Severity, critical.CVSSScore, critical.CVSSVector = schemas.SeverityCritical, 0, ""
+	bash := schemas.Vulnerability{ID: "CVE-2", Severity: schemas.SeverityLow, PackageName: "bash", InstalledVersion: "5.1"}
+
+	tests := map[string]struct {
+		results             []schemas.AnalyzeResult
+		wantComponents      []exporter.CycloneDXComponent
+		wantVulnerabilities []exporter.CycloneDXVulnerability
+	}{
+		"should merge findings of the same ID across images": {
+			results: []schemas.AnalyzeResult{
+				{Artifact: image("api", "sha256:aaa"), ScanTime: scanTime, Vulnerabilities: []schemas.Vulnerability{high}},
+				{Artifact: image("worker", "sha256:bbb"), ScanTime: scanTime, Vulnerabilities: []schemas.Vulnerability{critical}},
+			},
+			wantComponents: []exporter.CycloneDXComponent{
+				{
+					BOMRef: "h/p/r/api@sha256:aaa", Type: "container", Name: "h/p/r/api", Version: "sha256:aaa",
+					PURL: "pkg:oci/api@sha256%3Aaaa?repository_url=h%2Fp%2Fr%2Fapi",
+					Components: []exporter.CycloneDXComponent{{
+						BOMRef: "h/p/r/api@sha256:aaa#pkg:deb/debian/openssl@3.0.1", Type: "library", Name: "openssl",
+						Version: "3.0.1", PURL: "pkg:deb/debian/openssl@3.0.1",
+					}},
+				},
+				{
+					BOMRef: "h/p/r/worker@sha256:bbb", Type: "container", Name: "h/p/r/worker", Version: "sha256:bbb",
+					PURL: "pkg:oci/worker@sha256%3Abbb?repository_url=h%2Fp%2Fr%2Fworker",
+					Components: []exporter.CycloneDXComponent{{
+						BOMRef: "h/p/r/worker@sha256:bbb#pkg:deb/debian/openssl@3.0.1", Type: "library", Name: "openssl",
+						Version: "3.0.1", PURL: "pkg:deb/debian/openssl@3.0.1",
+					}},
+				},
+			},
+			wantVulnerabilities: []exporter.CycloneDXVulnerability{{
+				ID:         "CVE-1",
+				Source:     &exporter.CycloneDXSource{Name: "NVD", URL: "https://nvd.nist.gov/vuln/detail/CVE-1"},
+				Ratings:    []exporter.CycloneDXRating{{Severity: "critical"}},
+				CWEs:       []int{787},
+				Advisories: []exporter.CycloneDXAdvisory{{URL: "https://example.com/CVE-1"}},
+				Affects: []exporter.CycloneDXAffect{
+					{Ref: "h/p/r/api@sha256:aaa#pkg:deb/debian/openssl@3.0.1", Versions: []exporter.CycloneDXAffectVersion{{Version: "3.0.1", Status: "affected"}}},
+					{Ref: "h/p/r/worker@sha256:bbb#pkg:deb/debian/openssl@3.0.1", Versions: []exporter.CycloneDXAffectVersion{{Version: "3.0.1", Status: "affected"}}},
+				},
+			}},
+		},
+		"should report suppressed findings as not affected": {
+			results: []schemas.AnalyzeResult{{
+				Artifact: image("api", "sha256:aaa"), ScanTime: scanTime,
+				Suppressed: []schemas.SuppressedFinding{{
+					Vulnerability: bash,
+					Suppression:   schemas.Suppression{ID: "CVE-2", Reason: "no shell", By: "alice"},
+				}},
+			}},
+			wantComponents: []exporter.CycloneDXComponent{{
+				BOMRef: "h/p/r/api@sha256:aaa", Type: "container", Name: "h/p/r/api", Version: "sha256:aaa",
+				PURL: "pkg:oci/api@sha256%3Aaaa?repository_url=h%2Fp%2Fr%2Fapi",
+				Components: []exporter.CycloneDXComponent{{
+					BOMRef: "h/p/r/api@sha256:aaa#bash@5.1", Type: "library", Name: "bash", Version: "5.1",
+				}},
+			}},
+			wantVulnerabilities: []exporter.CycloneDXVulnerability{{
+				ID:       "CVE-2",
+				Source:   &exporter.CycloneDXSource{Name: "NVD", URL: "https://nvd.nist.gov/vuln/detail/CVE-2"},
+				Ratings:  []exporter.CycloneDXRating{{Severity: "low"}},
+				Analysis: &exporter.CycloneDXAnalysis{State: "not_affected", Detail: "no shell (by alice)"},
+				Affects: []exporter.CycloneDXAffect{
+					{Ref: "h/p/r/api@sha256:aaa#bash@5.1", Versions: []exporter.CycloneDXAffectVersion{{Version: "5.1", Status: "affected"}}},
+				},
+			}},
+		},
+		"should write an empty document without results": {
+			results:             nil,
+			wantComponents:      []exporter.CycloneDXComponent{},
+			wantVulnerabilities: []exporter.CycloneDXVulnerability{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := exporter.NewCycloneDXExporter(&buf).Export(context.Background(), tt.results); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			var bom exporter.CycloneDXBOM
+			if err := json.Unmarshal(buf.Bytes(), &bom); err != nil {
+				t.Fatalf("failed to decode document: %v\n%s", err, buf.String())
+			}
+			if bom.BOMFormat != "CycloneDX" || bom.SpecVersion != exporter.CycloneDXSpecVersion {
+				t.Errorf("bomFormat = %q, specVersion = %q", bom.BOMFormat, bom.SpecVersion)
+			}
+			if diff := cmp.Diff(tt.wantComponents, bom.Components); diff != "" {
+				t.Errorf("components mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantVulnerabilities, bom.Vulnerabilities); diff != "" {
+				t.Errorf("vulnerabilities mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package exporter
+
+import "time"
+
+var ExportSignV4 = signV4
+
+// ExportSetNow makes the exporter sign its requests at the given time.
+func (e *S3Exporter) ExportSetNow(now func() time.Time) {
+	e.now = now
+}
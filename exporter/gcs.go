@@ -0,0 +1,304 @@
+package exporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// GCSScope is the OAuth scope the HTTP client of GCSExporter needs.
+const GCSScope = "https://www.googleapis.com/auth/devstorage.read_write"
+
+// DefaultGCSObjectName is the default object name template of GCSExporter.
+// Image names repeat across repositories, hence the repository in the path.
+const DefaultGCSObjectName = "scans/{{.Project}}/{{.Date}}/{{.Repository}}/{{.Image}}.json"
+
+// GCSExporter uploads the JSON result of each image as an object of a Cloud Storage bucket, e.g.
+// "scans/my-project/2024-05-01/repo/api.json", as soon as the image is analyzed. Objects carry the
+// image, digest and finding count as custom metadata, along with the scan parameters in Metadata,
+// so that they can be told apart without being downloaded.
+type GCSExporter struct {
+	// Bucket is the bucket to upload to
+	Bucket string
+
+	// Name is a text/template of the object names (default: DefaultGCSObjectName), executed with GCSObjectData
+	Name string
+
+	// Gzip compresses the objects, stored with Content-Encoding: gzip (Cloud Storage decompresses them
+	// for clients that do not accept gzip)
+	Gzip bool
+
+	// Metadata is added to the custom metadata of every object, e.g. {"min-severity": "HIGH"}
+	Metadata map[string]string
+
+	// HTTPClient sends the requests, authenticated with GCSScope (default: http.DefaultClient)
+	HTTPClient *http.Client
+
+	// Endpoint is the Cloud Storage API endpoint (default: "https://storage.googleapis.com")
+	Endpoint string
+
+	name *template.Template
+}
+
+// GCSObjectData is the data available to the object name template of GCSExporter.
+type GCSObjectData struct {
+	// Project, Location, Repository and Image locate the image (e.g., "my-project", "us-central1", "repo", "team/api")
+	Project    string
+	Location   string
+	Repository string
+	Image      string
+
+	// Digest is the image digest with ":" replaced by "-" (e.g., "sha256-abc..."), empty if unknown
+	Digest string
+
+	// Date and Timestamp are the UTC scan time, as "2006-01-02" and "20060102T150405Z"
+	Date      string
+	Timestamp string
+}
+
+// GCSAPIError is returned when Cloud Storage responds with an error status.
+type GCSAPIError struct {
+	Object     string
+	StatusCode int
+	Message    string
+}
+
+// Error implements the error interface.
+func (e *GCSAPIError) Error() string {
+	return fmt.Sprintf("Cloud Storage upload %s: %d %s", e.Object, e.StatusCode, e.Message)
+}
+
+// Export uploads the result of every image
+func (e *GCSExporter) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
+	if err := e.Begin(ctx); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if err := e.ExportOne(ctx, result); err != nil {
+			return err
+		}
+	}
+	return e.End(ctx)
+}
+
+// Begin checks the settings and parses the object name template
+func (e *GCSExporter) Begin(ctx context.Context) error {
+	if e.Bucket == "" {
+		return errors.New("gcs: Bucket is required")
+	}
+	text := e.Name
+	if text == "" {
+		text = DefaultGCSObjectName
+	}
+	name, err := template.New("name").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return fmt.Errorf("gcs: invalid object name template: %w", err)
+	}
+	e.name = name
+	return nil
+}
+
+// ExportOne uploads the result of a single image
+func (e *GCSExporter) ExportOne(ctx context.Context, result schemas.AnalyzeResult) error {
+	if e.name == nil {
+		if err := e.Begin(ctx); err != nil {
+			return err
+		}
+	}
+	name, err := e.objectName(result)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	if e.Gzip {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	}
+	return e.upload(ctx, name, data, e.objectMetadata(result))
+}
+
+// End does nothing: every object is uploaded by ExportOne
+func (e *GCSExporter) End(ctx context.Context) error {
+	return nil
+}
+
+// objectName executes the name template for the result.
+func (e *GCSExporter) objectName(result schemas.AnalyzeResult) (string, error) {
+	t := result.ScanTime.UTC()
+	if result.ScanTime.IsZero() {
+		t = time.Now().UTC()
+	}
+	data := GCSObjectData{
+		Project:    result.Artifact.ProjectID,
+		Location:   strings.TrimSuffix(result.Artifact.Host, "-docker.pkg.dev"),
+		Repository: result.Artifact.RepositoryID,
+		Image:      result.Artifact.ImageName,
+		Date:       t.Format(time.DateOnly),
+		Timestamp:  t.Format("20060102T150405Z"),
+	}
+	if result.Artifact.Digest != nil {
+		data.Digest = strings.ReplaceAll(*result.Artifact.Digest, ":", "-")
+	}
+	var b strings.Builder
+	if err := e.name.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("gcs: invalid object name template: %w", err)
+	}
+	name := strings.TrimPrefix(b.String(), "/")
+	if name == "" {
+		return "", errors.New("gcs: empty object name")
+	}
+	return name, nil
+}
+
+// objectMetadata returns the custom metadata of the object of result.
+func (e *GCSExporter) objectMetadata(result schemas.AnalyzeResult) map[string]string {
+	metadata := maps.Clone(e.Metadata)
+	if metadata == nil {
+		metadata = make(map[string]string)
+	}
+	metadata["image"] = result.Artifact.String()
+	if result.Artifact.Digest != nil {
+		metadata["digest"] = *result.Artifact.Digest
+	}
+	if !result.ScanTime.IsZero() {
+		metadata["scan-time"] = result.ScanTime.UTC().Format(time.RFC3339)
+	}
+	metadata["vulnerabilities"] = strconv.Itoa(result.Summary.TotalCount)
+	if result.ScanStatus != "" {
+		metadata["scan-status"] = string(result.ScanStatus)
+	}
+	return metadata
+}
+
+// upload creates the object with a multipart upload: its resource, then its content.
+func (e *GCSExporter) upload(ctx context.Context, name string, data []byte, metadata map[string]string) error {
+	resource := map[string]any{
+		"name":        name,
+		"contentType": "application/json",
+		"metadata":    metadata,
+	}
+	if e.Gzip {
+		resource["contentEncoding"] = "gzip"
+	}
+	resourceJSON, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		data        []byte
+	}{
+		{"application/json; charset=UTF-8", resourceJSON},
+		{"application/json", data},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(part.data); err != nil {
+			return err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	u := e.endpoint() + "/upload/storage/v1/b/" + url.PathEscape(e.Bucket) + "/o?uploadType=multipart"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "multipart/related; boundary="+mw.Boundary())
+
+	resp, err := e.client().Do(req)
+	if err != nil {
+		return fmt.Errorf("Cloud Storage upload gs://%s/%s: %w", e.Bucket, name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		var msg struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&msg)
+		return &GCSAPIError{Object: "gs://" + e.Bucket + "/" + name, StatusCode: resp.StatusCode, Message: msg.Error.Message}
+	}
+	return nil
+}
+
+// Check checks that the credentials may create objects in the bucket, without uploading any.
+func (e *GCSExporter) Check(ctx context.Context) error {
+	const permission = "storage.objects.create"
+	u := e.endpoint() + "/storage/v1/b/" + url.PathEscape(e.Bucket) + "/iam/testPermissions?permissions=" + permission
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := e.client().Do(req)
+	if err != nil {
+		return fmt.Errorf("Cloud Storage gs://%s: %w", e.Bucket, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var body struct {
+		Permissions []string `json:"permissions"`
+		Error       struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+	if resp.StatusCode >= 300 {
+		return &GCSAPIError{Object: "gs://" + e.Bucket, StatusCode: resp.StatusCode, Message: body.Error.Message}
+	}
+	if !slices.Contains(body.Permissions, permission) {
+		return &GCSAPIError{Object: "gs://" + e.Bucket, StatusCode: http.StatusForbidden, Message: "missing permission " + permission}
+	}
+	return nil
+}
+
+// endpoint returns the Cloud Storage API endpoint, without trailing slash.
+func (e *GCSExporter) endpoint() string {
+	if e.Endpoint == "" {
+		return "https://storage.googleapis.com"
+	}
+	return strings.TrimSuffix(e.Endpoint, "/")
+}
+
+// client returns the HTTP client of the requests.
+func (e *GCSExporter) client() *http.Client {
+	if e.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return e.HTTPClient
+}
@@ -0,0 +1,196 @@
+package exporter_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+// gcsObject is an object uploaded to fakeGCS.
+type gcsObject struct {
+	Name            string            `json:"name"`
+	ContentEncoding string            `json:"contentEncoding"`
+	Metadata        map[string]string `json:"metadata"`
+	Image           string            // from the uploaded result
+}
+
+// fakeGCS records the multipart uploads made by GCSExporter.
+type fakeGCS struct {
+	objects []gcsObject
+}
+
+func (f *fakeGCS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/upload/storage/v1/b/reports/o" || r.URL.Query().Get("uploadType") != "multipart" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	mr := multipart.NewReader(r.Body, params["boundary"])
+
+	var object gcsObject
+	part, err := mr.NextPart()
+	if err != nil || json.NewDecoder(part).Decode(&object) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	part, err = mr.NextPart()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var content io.Reader = part
+	if object.ContentEncoding == "gzip" {
+		if content, err = gzip.NewReader(part); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	var result struct {
+		Artifact struct {
+			URI string `json:"uri"`
+		} `json:"artifact"`
+	}
+	data, _ := io.ReadAll(content)
+	_ = json.NewDecoder(bytes.NewReader(data)).Decode(&result)
+	object.Image = result.Artifact.URI
+	f.objects = append(f.objects, object)
+	_, _ = w.Write([]byte("{}"))
+}
+
+func TestGCSExporter_Export(t *testing.T) {
+	scanTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	results := []schemas.AnalyzeResult{
+		{
+			Artifact: schemas.ArtifactReference{Host: "us-central1-docker.pkg.dev", ProjectID: "p", RepositoryID: "r", ImageName: "team/api", Digest: utils.Ptr("sha256:abc")},
+			ScanTime: scanTime,
+			Summary:  schemas.VulnerabilitySummary{TotalCount: 2},
+		},
+		{
+			Artifact: schemas.ArtifactReference{Host: "us-central1-docker.pkg.dev", ProjectID: "p", RepositoryID: "r", ImageName: "web"},
+			ScanTime: scanTime,
+		},
+	}
+
+	tests := map[string]struct {
+		exporter *exporter.GCSExporter
+		want     []gcsObject
+	}{
+		"should upload one object per image with its metadata": {
+			exporter: &exporter.GCSExporter{Bucket: "reports", Metadata: map[string]string{"min-severity": "HIGH"}},
+			want: []gcsObject{
+				{
+					Name: "scans/p/2024-05-01/r/team/api.json",
+					Metadata: map[string]string{
+						"min-severity": "HIGH", "image": "us-central1-docker.pkg.dev/p/r/team/api@sha256:abc", "digest": "sha256:abc",
+						"scan-time": "2024-05-01T12:00:00Z", "vulnerabilities": "2",
+					},
+					Image: "us-central1-docker.pkg.dev/p/r/team/api@sha256:abc",
+				},
+				{
+					Name: "scans/p/2024-05-01/r/web.json",
+					Metadata: map[string]string{
+						"min-severity": "HIGH", "image": "us-central1-docker.pkg.dev/p/r/web",
+						"scan-time": "2024-05-01T12:00:00Z", "vulnerabilities": "0",
+					},
+					Image: "us-central1-docker.pkg.dev/p/r/web",
+				},
+			},
+		},
+		"should compress objects and name them from the template": {
+			exporter: &exporter.GCSExporter{Bucket: "reports", Name: "{{.Location}}/{{.Image}}@{{.Digest}}.json.gz", Gzip: true},
+			want: []gcsObject{
+				{
+					Name: "us-central1/team/api@sha256-abc.json.gz", ContentEncoding: "gzip",
+					Metadata: map[string]string{
+						"image": "us-central1-docker.pkg.dev/p/r/team/api@sha256:abc", "digest": "sha256:abc",
+						"scan-time": "2024-05-01T12:00:00Z", "vulnerabilities": "2",
+					},
+					Image: "us-central1-docker.pkg.dev/p/r/team/api@sha256:abc",
+				},
+				{
+					Name: "us-central1/web@.json.gz", ContentEncoding: "gzip",
+					Metadata: map[string]string{
+						"image": "us-central1-docker.pkg.dev/p/r/web", "scan-time": "2024-05-01T12:00:00Z", "vulnerabilities": "0",
+					},
+					Image: "us-central1-docker.pkg.dev/p/r/web",
+				},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fake := &fakeGCS{}
+			srv := httptest.NewServer(fake)
+			defer srv.Close()
+
+			tt.exporter.Endpoint = srv.URL
+			if err := tt.exporter.Export(context.Background(), results); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, fake.objects); diff != "" {
+				t.Errorf("objects mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGCSExporter_Export_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error": {"message": "denied"}}`))
+	}))
+	defer srv.Close()
+
+	e := &exporter.GCSExporter{Bucket: "reports", Endpoint: srv.URL}
+	err := e.Export(context.Background(), []schemas.AnalyzeResult{{Artifact: schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "api"}}})
+	want := &exporter.GCSAPIError{Object: "gs://reports/scans/p/" + time.Now().UTC().Format(time.DateOnly) + "/r/api.json", StatusCode: http.StatusForbidden, Message: "denied"}
+	if diff := cmp.Diff(want, err); diff != "" {
+		t.Errorf("Export() error mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGCSExporter_Check(t *testing.T) {
+	tests := map[string]struct {
+		permissions []string
+		wantErr     bool
+	}{
+		"should accept a bucket the credentials may write to":    {permissions: []string{"storage.objects.create"}},
+		"should reject a bucket the credentials cannot write to": {wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/storage/v1/b/reports/iam/testPermissions" || r.URL.Query().Get("permissions") != "storage.objects.create" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(map[string]any{"permissions": tt.permissions})
+			}))
+			defer srv.Close()
+
+			e := &exporter.GCSExporter{Bucket: "reports", Endpoint: srv.URL}
+			if err := e.Check(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
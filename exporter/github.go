@@ -0,0 +1,191 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/hiro-o918/drydock/internal/githubapi"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// gitHubIssueLabel labels the tracking issues of GitHubActionsExporter, which are looked up by it.
+const gitHubIssueLabel = "drydock"
+
+// gitHubIssueBodyLimit is the maximum length of an issue body accepted by GitHub.
+const gitHubIssueBodyLimit = 65536
+
+// GitHubActionsExporter reports the scan to a GitHub Actions workflow: the Markdown report (see
+// MarkdownExporter) is appended to the job's step summary, and an error annotation is written for each
+// finding at or above AnnotationSeverity. With a Repository, it also files a tracking issue with the
+// report, updated by later runs and closed once no findings remain.
+type GitHubActionsExporter struct {
+	// Annotations receives the workflow commands, usually stdout
+	Annotations io.Writer
+
+	// SummaryPath is the step summary file ($GITHUB_STEP_SUMMARY); empty to not write a summary
+	SummaryPath string
+
+	// AnnotationSeverity is the lowest severity of annotated findings (default: CRITICAL)
+	AnnotationSeverity schemas.Severity
+
+	// Repository is the "owner/name" repository of the tracking issue; empty to not file an issue
+	Repository string
+
+	// IssueTitle is the title of the tracking issue (default: "Vulnerability Scan Report")
+	IssueTitle string
+
+	// Token is a token allowed to create and edit issues, e.g. $GITHUB_TOKEN
+	Token string
+
+	// BaseURL is the API endpoint (default: https://api.github.com; set it for GitHub Enterprise)
+	BaseURL string
+
+	// HTTPClient sends the issue requests (default: http.DefaultClient)
+	HTTPClient *http.Client
+}
+
+// NewGitHubActionsExporter creates a GitHubActionsExporter writing annotations to writer
+// and the summary to $GITHUB_STEP_SUMMARY, if set.
+func NewGitHubActionsExporter(writer io.Writer) *GitHubActionsExporter {
+	return &GitHubActionsExporter{
+		Annotations: writer,
+		SummaryPath: os.Getenv("GITHUB_STEP_SUMMARY"),
+	}
+}
+
+// GitHubAPIError is returned when the GitHub API responds with an error status.
+type GitHubAPIError = githubapi.Error
+
+// Export writes the summary and annotations, then files the tracking issue
+func (e *GitHubActionsExporter) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
+	var report bytes.Buffer
+	if err := NewMarkdownExporter(&report).Export(ctx, results); err != nil {
+		return err
+	}
+
+	if e.SummaryPath != "" {
+		if err := appendFile(e.SummaryPath, report.Bytes()); err != nil {
+			return fmt.Errorf("failed to write step summary: %w", err)
+		}
+	}
+	if e.Annotations != nil {
+		if err := e.annotate(results); err != nil {
+			return err
+		}
+	}
+	if e.Repository != "" {
+		return e.updateIssue(ctx, results, report.String())
+	}
+	return nil
+}
+
+// annotate writes an error annotation for each finding at or above the annotation severity.
+func (e *GitHubActionsExporter) annotate(results []schemas.AnalyzeResult) error {
+	threshold := e.AnnotationSeverity
+	if threshold == "" {
+		threshold = schemas.SeverityCritical
+	}
+	var b strings.Builder
+	for _, result := range results {
+		for _, v := range result.Vulnerabilities {
+			if !v.Severity.AtLeast(threshold) {
+				continue
+			}
+			msg := fmt.Sprintf("%s %s in %s", v.PackageName, v.InstalledVersion, result.Artifact)
+			if v.FixedVersion != "" {
+				msg += ", fixed in " + v.FixedVersion
+			}
+			if link := advisoryURL(v); link != "" {
+				msg += "\n" + link
+			}
+			fmt.Fprintf(&b, "::error title=%s::%s\n", escapeWorkflowProperty(v.ID+" ("+string(v.Severity)+")"), escapeWorkflowData(msg))
+		}
+	}
+	_, err := io.WriteString(e.Annotations, b.String())
+	return err
+}
+
+// updateIssue creates or updates the open tracking issue with the report, or closes it when
+// no findings remain.
+func (e *GitHubActionsExporter) updateIssue(ctx context.Context, results []schemas.AnalyzeResult, report string) error {
+	title := e.IssueTitle
+	if title == "" {
+		title = "Vulnerability Scan Report"
+	}
+	findings := 0
+	for _, result := range results {
+		findings += len(result.Vulnerabilities)
+	}
+	if len(report) > gitHubIssueBodyLimit {
+		const note = "\n\n_Truncated: see the step summary of the workflow run for the full report._\n"
+		report = strings.ToValidUTF8(report[:gitHubIssueBodyLimit-len(note)], "") + note
+	}
+
+	var issues []struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+	}
+	query := url.Values{"state": {"open"}, "labels": {gitHubIssueLabel}, "per_page": {"100"}}
+	if err := e.do(ctx, http.MethodGet, "/repos/"+e.Repository+"/issues?"+query.Encode(), nil, &issues); err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		if issue.Title != title {
+			continue
+		}
+		update := map[string]string{"body": report}
+		if findings == 0 {
+			update["state"] = "closed"
+		}
+		return e.do(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/issues/%d", e.Repository, issue.Number), update, nil)
+	}
+	if findings == 0 {
+		return nil
+	}
+	issue := map[string]any{"title": title, "body": report, "labels": []string{gitHubIssueLabel}}
+	return e.do(ctx, http.MethodPost, "/repos/"+e.Repository+"/issues", issue, nil)
+}
+
+// Check checks that the repository of the tracking issue can be read with the token.
+// The summary and annotations need no access.
+func (e *GitHubActionsExporter) Check(ctx context.Context) error {
+	if e.Repository == "" {
+		return nil
+	}
+	return e.do(ctx, http.MethodGet, "/repos/"+e.Repository, nil, nil)
+}
+
+// do sends a request to the GitHub API (see githubapi.Client.Do).
+func (e *GitHubActionsExporter) do(ctx context.Context, method, path string, body, out any) error {
+	client := &githubapi.Client{BaseURL: e.BaseURL, Token: e.Token, HTTPClient: e.HTTPClient}
+	return client.Do(ctx, method, path, body, out)
+}
+
+// appendFile appends data to the file at path, creating it if needed.
+func appendFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// escapeWorkflowData escapes the message of a workflow command.
+func escapeWorkflowData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeWorkflowProperty escapes a property value of a workflow command.
+func escapeWorkflowProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
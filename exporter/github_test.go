@@ -0,0 +1,166 @@
+package exporter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// fakeGitHubIssues serves the issue calls made by GitHubActionsExporter.
+type fakeGitHubIssues struct {
+	open     []map[string]any
+	requests []string
+	bodies   []map[string]any
+}
+
+func (f *fakeGitHubIssues) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	switch r.Method {
+	case http.MethodGet:
+		if r.URL.Query().Get("labels") != "drydock" || r.URL.Query().Get("state") != "open" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(f.open)
+	default:
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		delete(body, "body")
+		f.bodies = append(f.bodies, body)
+		_, _ = w.Write([]byte("{}"))
+	}
+}
+
+func TestGitHubActionsExporter_Export(t *testing.T) {
+	results := []schemas.AnalyzeResult{{
+		Artifact: schemas.ArtifactReference{Host: "us-central1-docker.pkg.dev", ProjectID: "p", RepositoryID: "r", ImageName: "api"},
+		Vulnerabilities: []schemas.Vulnerability{
+			{ID: "CVE-1", Severity: schemas.SeverityCritical, PackageName: "openssl", InstalledVersion: "1.1", FixedVersion: "1.2"},
+			{ID: "CVE-2", Severity: schemas.SeverityHigh, PackageName: "bash", InstalledVersion: "5.0"},
+		},
+	}}
+
+	tests := map[string]struct {
+		severity        schemas.Severity
+		wantAnnotations string
+	}{
+		"should annotate critical findings by default": {
+			wantAnnotations: "::error title=CVE-1 (CRITICAL)::openssl 1.1 in us-central1-docker.pkg.dev/p/r/api, fixed in 1.2\n",
+		},
+		"should annotate findings at or above the severity": {
+			severity: schemas.SeverityHigh,
+			wantAnnotations: "::error title=CVE-1 (CRITICAL)::openssl 1.1 in us-central1-docker.pkg.dev/p/r/api, fixed in 1.2\n" +
+				"::error title=CVE-2 (HIGH)::bash 5.0 in us-central1-docker.pkg.dev/p/r/api\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			summary := filepath.Join(t.TempDir(), "summary.md")
+			if err := os.WriteFile(summary, []byte("previous step\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			var annotations bytes.Buffer
+			e := &exporter.GitHubActionsExporter{Annotations: &annotations, SummaryPath: summary, AnnotationSeverity: tt.severity}
+			if err := e.Export(context.Background(), results); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.wantAnnotations, annotations.String()); diff != "" {
+				t.Errorf("annotations mismatch (-want +got):\n%s", diff)
+			}
+			got, err := os.ReadFile(summary)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(got), "previous step\n") || !strings.Contains(string(got), "CVE-1") {
+				t.Errorf("summary = %q, want the report appended", got)
+			}
+		})
+	}
+}
+
+func TestGitHubActionsExporter_Export_Issue(t *testing.T) {
+	vulnerable := []schemas.AnalyzeResult{{
+		Artifact:        schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "api"},
+		Vulnerabilities: []schemas.Vulnerability{{ID: "CVE-1", Severity: schemas.SeverityHigh, PackageName: "openssl"}},
+	}}
+	clean := []schemas.AnalyzeResult{{Artifact: schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "api"}}}
+	tracking := map[string]any{"number": 7, "title": "Vulnerabilities"}
+
+	tests := map[string]struct {
+		open         []map[string]any
+		results      []schemas.AnalyzeResult
+		wantRequests []string
+		wantBodies   []map[string]any
+	}{
+		"should create the issue": {
+			open:         []map[string]any{{"number": 3, "title": "Other"}},
+			results:      vulnerable,
+			wantRequests: []string{"GET /repos/o/app/issues", "POST /repos/o/app/issues"},
+			wantBodies:   []map[string]any{{"title": "Vulnerabilities", "labels": []any{"drydock"}}},
+		},
+		"should update the open issue": {
+			open:         []map[string]any{tracking},
+			results:      vulnerable,
+			wantRequests: []string{"GET /repos/o/app/issues", "PATCH /repos/o/app/issues/7"},
+			wantBodies:   []map[string]any{{}},
+		},
+		"should close the open issue once no findings remain": {
+			open:         []map[string]any{tracking},
+			results:      clean,
+			wantRequests: []string{"GET /repos/o/app/issues", "PATCH /repos/o/app/issues/7"},
+			wantBodies:   []map[string]any{{"state": "closed"}},
+		},
+		"should not create an issue without findings": {
+			results:      clean,
+			wantRequests: []string{"GET /repos/o/app/issues"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fake := &fakeGitHubIssues{open: tt.open}
+			srv := httptest.NewServer(fake)
+			defer srv.Close()
+
+			e := &exporter.GitHubActionsExporter{Repository: "o/app", IssueTitle: "Vulnerabilities", Token: "t", BaseURL: srv.URL}
+			if err := e.Export(context.Background(), tt.results); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.wantRequests, fake.requests); diff != "" {
+				t.Errorf("requests mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBodies, fake.bodies); diff != "" {
+				t.Errorf("bodies mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGitHubActionsExporter_Export_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message": "Resource not accessible by integration"}`))
+	}))
+	defer srv.Close()
+
+	e := &exporter.GitHubActionsExporter{Repository: "o/app", BaseURL: srv.URL}
+	err := e.Export(context.Background(), nil)
+	want := &exporter.GitHubAPIError{
+		Method: http.MethodGet, Path: "/repos/o/app/issues?labels=drydock&per_page=100&state=open",
+		StatusCode: http.StatusForbidden, Message: "Resource not accessible by integration",
+	}
+	if diff := cmp.Diff(want, err); diff != "" {
+		t.Errorf("Export() error mismatch (-want +got):\n%s", diff)
+	}
+}
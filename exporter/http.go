@@ -0,0 +1,74 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// jsonAPI describes an HTTP API taking and returning JSON, called by the integration exporters.
+type jsonAPI struct {
+	// name names the API in errors, e.g. "Confluence API"
+	name string
+
+	// baseURL is the endpoint the request paths are relative to
+	baseURL string
+
+	// client is the client used for requests (default: http.DefaultClient)
+	client *http.Client
+
+	// header sets the headers of each request besides Content-Type, e.g. its credentials
+	header func(req *http.Request)
+
+	// apiError returns the error of an error response, given its status and (truncated) body
+	apiError func(method, path string, statusCode int, body []byte) error
+}
+
+// doJSON sends a request with a JSON body to the API and decodes the JSON response into out, if not nil.
+func doJSON(ctx context.Context, api jsonAPI, method, path string, body, out any) error {
+	client := api.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(api.baseURL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	if api.header != nil {
+		api.header(req)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s %s: %w", api.name, method, path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		return api.apiError(method, path, resp.StatusCode, data)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s %s %s: invalid response: %w", api.name, method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+const (
+	// InTotoStatementType is the in-toto Statement type of the attestations
+	InTotoStatementType = "https://in-toto.io/Statement/v0.1"
+
+	// CosignVulnPredicateType is the cosign vulnerability-scan predicate type
+	// (https://github.com/sigstore/cosign/blob/main/specs/COSIGN_VULN_ATTESTATION_SPEC.md)
+	CosignVulnPredicateType = "https://cosign.sigstore.dev/attestation/vuln/v1"
+
+	// scannerURI identifies drydock in the predicate
+	scannerURI = "https://github.com/hiro-o918/drydock"
+)
+
+// InTotoExporter exports one unsigned in-toto Statement per image, carrying a cosign vulnerability-scan predicate.
+// Statements are written one per line; sign and attach them with e.g.
+// `jq .predicate | cosign attest --type vuln --predicate - IMAGE`.
+// Results without a digest cannot be a Statement subject and are skipped.
+type InTotoExporter struct {
+	encoder *json.Encoder
+}
+
+// NewInTotoExporter creates a new InTotoExporter with the specified writer
+func NewInTotoExporter(writer io.Writer) *InTotoExporter {
+	return &InTotoExporter{
+		encoder: json.NewEncoder(writer),
+	}
+}
+
+// InTotoStatement is an in-toto attestation Statement
+type InTotoStatement struct {
+	Type          string              `json:"_type"`
+	PredicateType string              `json:"predicateType"`
+	Subject       []InTotoSubject     `json:"subject"`
+	Predicate     CosignVulnPredicate `json:"predicate"`
+}
+
+// InTotoSubject is the artifact an attestation is about
+type InTotoSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// CosignVulnPredicate is the cosign vulnerability-scan predicate
+type CosignVulnPredicate struct {
+	Invocation CosignVulnInvocation `json:"invocation"`
+	Scanner    CosignVulnScanner    `json:"scanner"`
+	Metadata   CosignVulnMetadata   `json:"metadata"`
+}
+
+// CosignVulnInvocation describes how the scan was run
+type CosignVulnInvocation struct {
+	Parameters any    `json:"parameters"`
+	URI        string `json:"uri"`
+	EventID    string `json:"event_id"`
+	BuilderID  string `json:"builder.id"`
+}
+
+// CosignVulnScanner identifies the scanner and holds its result
+type CosignVulnScanner struct {
+	URI     string           `json:"uri"`
+	Version string           `json:"version,omitempty"`
+	DB      CosignVulnDB     `json:"db"`
+	Result  CosignVulnResult `json:"result"`
+}
+
+// CosignVulnDB identifies the vulnerability database
+type CosignVulnDB struct {
+	URI     string `json:"uri,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// CosignVulnResult is the drydock-specific scan result: the summary, the findings and the suppressed findings
+type CosignVulnResult struct {
+	Summary         schemas.VulnerabilitySummary `json:"summary"`
+	Vulnerabilities []CosignVulnFinding          `json:"vulnerabilities"`
+	Suppressed      []CosignVulnSuppressed       `json:"suppressed,omitempty"`
+}
+
+// CosignVulnFinding is one finding of the result
+type CosignVulnFinding struct {
+	ID               string           `json:"id"`
+	Severity         schemas.Severity `json:"severity"`
+	PackageName      string           `json:"packageName"`
+	InstalledVersion string           `json:"installedVersion"`
+	FixedVersion     string           `json:"fixedVersion,omitempty"`
+}
+
+// CosignVulnSuppressed is a finding left out of the result by a suppression, with who accepted it and why
+type CosignVulnSuppressed struct {
+	CosignVulnFinding
+	Suppression schemas.Suppression `json:"suppression"`
+}
+
+// CosignVulnMetadata holds the scan times
+type CosignVulnMetadata struct {
+	ScanStartedOn  time.Time `json:"scanStartedOn"`
+	ScanFinishedOn time.Time `json:"scanFinishedOn"`
+}
+
+// Export writes one Statement per result
+func (e *InTotoExporter) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
+	for _, result := range results {
+		if err := e.ExportOne(ctx, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Begin does nothing: Statements are independent lines
+func (e *InTotoExporter) Begin(ctx context.Context) error {
+	return nil
+}
+
+// ExportOne writes the Statement of a single result
+func (e *InTotoExporter) ExportOne(ctx context.Context, result schemas.AnalyzeResult) error {
+	statement, ok := NewInTotoStatement(result)
+	if !ok {
+		return nil
+	}
+	return e.encoder.Encode(statement)
+}
+
+// End does nothing: every Statement is complete when written
+func (e *InTotoExporter) End(ctx context.Context) error {
+	return nil
+}
+
+// NewInTotoStatement builds the Statement of a result.
+// It returns false if the result has no sha256 digest to use as the subject.
+func NewInTotoStatement(result schemas.AnalyzeResult) (InTotoStatement, bool) {
+	if result.Artifact.Digest == nil {
+		return InTotoStatement{}, false
+	}
+	algorithm, hex, ok := strings.Cut(*result.Artifact.Digest, ":")
+	if !ok || hex == "" {
+		return InTotoStatement{}, false
+	}
+
+	a := result.Artifact
+	name := a.Host + "/" + a.ProjectID + "/" + a.RepositoryID + "/" + a.ImageName
+
+	findings := make([]CosignVulnFinding, 0, len(result.Vulnerabilities))
+	for _, v := range result.Vulnerabilities {
+		findings = append(findings, newCosignVulnFinding(v))
+	}
+	var suppressed []CosignVulnSuppressed
+	for _, s := range result.Suppressed {
+		suppressed = append(suppressed, CosignVulnSuppressed{
+			CosignVulnFinding: newCosignVulnFinding(s.Vulnerability),
+			Suppression:       s.Suppression,
+		})
+	}
+
+	scanner := CosignVulnScanner{
+		URI:    scannerURI,
+		Result: CosignVulnResult{Summary: result.Summary, Vulnerabilities: findings, Suppressed: suppressed},
+	}
+	if result.Scanner != nil {
+		scanner.DB = CosignVulnDB{URI: result.Scanner.Name, Version: result.Scanner.Version}
+	}
+
+	started := result.ScanTime
+	if result.Metadata != nil {
+		started = result.ScanTime.Add(-time.Duration(result.Metadata.DurationMillis) * time.Millisecond)
+	}
+
+	return InTotoStatement{
+		Type:          InTotoStatementType,
+		PredicateType: CosignVulnPredicateType,
+		Subject:       []InTotoSubject{{Name: name, Digest: map[string]string{algorithm: hex}}},
+		Predicate: CosignVulnPredicate{
+			Scanner:  scanner,
+			Metadata: CosignVulnMetadata{ScanStartedOn: started, ScanFinishedOn: result.ScanTime},
+		},
+	}, true
+}
+
+// newCosignVulnFinding converts a vulnerability to a finding of the predicate.
+func newCosignVulnFinding(v schemas.Vulnerability) CosignVulnFinding {
+	return CosignVulnFinding{
+		ID:               v.ID,
+		Severity:         v.Severity,
+		PackageName:      v.PackageName,
+		InstalledVersion: v.InstalledVersion,
+		FixedVersion:     v.FixedVersion,
+	}
+}
@@ -0,0 +1,167 @@
+package exporter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestNewInTotoStatement(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	artifact := schemas.ArtifactReference{
+		Host:         "us-central1-docker.pkg.dev",
+		ProjectID:    "project",
+		RepositoryID: "repo",
+		ImageName:    "image",
+		Tag:          utils.Ptr("v1"),
+		Digest:       utils.Ptr("sha256:abc123"),
+	}
+	summary := schemas.VulnerabilitySummary{TotalCount: 1, CountBySeverity: map[schemas.Severity]int{schemas.SeverityHigh: 1}}
+
+	tests := map[string]struct {
+		result schemas.AnalyzeResult
+		want   exporter.InTotoStatement
+		wantOK bool
+	}{
+		"should use the digest as the subject and carry the findings": {
+			result: schemas.AnalyzeResult{
+				Artifact: artifact,
+				ScanTime: now,
+				Vulnerabilities: []schemas.Vulnerability{{
+					ID: "CVE-2023-0001", Severity: schemas.SeverityHigh, PackageName: "openssl",
+					InstalledVersion: "1.1.1", FixedVersion: "1.1.1t", Description: "not in the predicate",
+				}},
+				Summary:  summary,
+				Scanner:  &schemas.ScannerInfo{Name: "container-analysis", Version: "v1"},
+				Metadata: &schemas.ScanMetadata{DurationMillis: 1500},
+			},
+			want: exporter.InTotoStatement{
+				Type:          exporter.InTotoStatementType,
+				PredicateType: exporter.CosignVulnPredicateType,
+				Subject: []exporter.InTotoSubject{{
+					Name:   "us-central1-docker.pkg.dev/project/repo/image",
+					Digest: map[string]string{"sha256": "abc123"},
+				}},
+				Predicate: exporter.CosignVulnPredicate{
+					Scanner: exporter.CosignVulnScanner{
+						URI: "https://github.com/hiro-o918/drydock",
+						DB:  exporter.CosignVulnDB{URI: "container-analysis", Version: "v1"},
+						Result: exporter.CosignVulnResult{
+							Summary: summary,
+							Vulnerabilities: []exporter.CosignVulnFinding{{
+								ID: "CVE-2023-0001", Severity: schemas.SeverityHigh, PackageName: "openssl",
+								InstalledVersion: "1.1.1", FixedVersion: "1.1.1t",
+							}},
+						},
+					},
+					Metadata: exporter.CosignVulnMetadata{
+						ScanStartedOn:  now.Add(-1500 * time.Millisecond),
+						ScanFinishedOn: now,
+					},
+				},
+			},
+			wantOK: true,
+		},
+		"should list suppressed findings with their suppression": {
+			result: schemas.AnalyzeResult{
+				Artifact: artifact,
+				ScanTime: now,
+				Suppressed: []schemas.SuppressedFinding{{
+					Vulnerability: schemas.Vulnerability{ID: "CVE-2023-0002", Severity: schemas.SeverityCritical, PackageName: "glibc"},
+					Suppression:   schemas.Suppression{ID: "CVE-2023-0002", Reason: "not reachable", By: "alice"},
+				}},
+			},
+			want: exporter.InTotoStatement{
+				Type:          exporter.InTotoStatementType,
+				PredicateType: exporter.CosignVulnPredicateType,
+				Subject: []exporter.InTotoSubject{{
+					Name:   "us-central1-docker.pkg.dev/project/repo/image",
+					Digest: map[string]string{"sha256": "abc123"},
+				}},
+				Predicate: exporter.CosignVulnPredicate{
+					Scanner: exporter.CosignVulnScanner{
+						URI: "https://github.com/hiro-o918/drydock",
+						Result: exporter.CosignVulnResult{
+							Vulnerabilities: []exporter.CosignVulnFinding{},
+							Suppressed: []exporter.CosignVulnSuppressed{{
+								CosignVulnFinding: exporter.CosignVulnFinding{ID: "CVE-2023-0002", Severity: schemas.SeverityCritical, PackageName: "glibc"},
+								Suppression:       schemas.Suppression{ID: "CVE-2023-0002", Reason: "not reachable", By: "alice"},
+							}},
+						},
+					},
+					Metadata: exporter.CosignVulnMetadata{ScanStartedOn: now, ScanFinishedOn: now},
+				},
+			},
+			wantOK: true,
+		},
+		"should skip results without a digest": {
+			result: schemas.AnalyzeResult{
+				Artifact: schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "i"},
+			},
+			wantOK: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := exporter.NewInTotoStatement(tt.result)
+			if ok != tt.wantOK {
+				t.Fatalf("NewInTotoStatement() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("NewInTotoStatement() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInTotoExporter_Export(t *testing.T) {
+	results := []schemas.AnalyzeResult{
+		{Artifact: schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "a", Digest: utils.Ptr("sha256:aaa")}},
+		{Artifact: schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "untagged"}},
+		{Artifact: schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "b", Digest: utils.Ptr("sha256:bbb")}},
+	}
+
+	var buf bytes.Buffer
+	if err := exporter.NewInTotoExporter(&buf).Export(context.Background(), results); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var got []string
+	for _, line := range lines {
+		var statement struct {
+			Type          string `json:"_type"`
+			PredicateType string `json:"predicateType"`
+			Subject       []struct {
+				Name string `json:"name"`
+			} `json:"subject"`
+			Predicate struct {
+				Invocation map[string]any `json:"invocation"`
+			} `json:"predicate"`
+		}
+		if err := json.Unmarshal([]byte(line), &statement); err != nil {
+			t.Fatalf("line is not JSON: %v\n%s", err, line)
+		}
+		if statement.Type != exporter.InTotoStatementType || statement.PredicateType != exporter.CosignVulnPredicateType {
+			t.Errorf("unexpected types: %s, %s", statement.Type, statement.PredicateType)
+		}
+		if _, ok := statement.Predicate.Invocation["builder.id"]; !ok {
+			t.Errorf("invocation lacks builder.id: %s", line)
+		}
+		got = append(got, statement.Subject[0].Name)
+	}
+
+	want := []string{"h/p/r/a", "h/p/r/b"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("subjects mismatch (-want +got):\n%s", diff)
+	}
+}
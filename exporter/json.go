@@ -12,6 +12,9 @@ import (
 // JSONExporter exports analysis results in JSON format
 type JSONExporter struct {
 	writer io.Writer
+
+	// streamed is the number of results written since Begin
+	streamed int
 }
 
 // NewJSONExporter creates a new JSONExporter with the specified writer
@@ -42,3 +45,42 @@ func (e *JSONExporter) Export(ctx context.Context, results []schemas.AnalyzeResu
 	_, err = e.writer.Write([]byte("\n"))
 	return err
 }
+
+// Begin opens the JSON array for streaming output
+func (e *JSONExporter) Begin(ctx context.Context) error {
+	e.streamed = 0
+	_, err := e.writer.Write([]byte("["))
+	return err
+}
+
+// ExportOne writes a single result as the next element of the JSON array
+func (e *JSONExporter) ExportOne(ctx context.Context, result schemas.AnalyzeResult) error {
+	data, err := json.MarshalIndent(result, "  ", "  ")
+	if err != nil {
+		return err
+	}
+
+	separator := ",\n  "
+	if e.streamed == 0 {
+		separator = "\n  "
+	}
+	if _, err := e.writer.Write([]byte(separator)); err != nil {
+		return err
+	}
+	if _, err := e.writer.Write(data); err != nil {
+		return err
+	}
+
+	e.streamed++
+	return nil
+}
+
+// End closes the JSON array, producing the same layout as Export
+func (e *JSONExporter) End(ctx context.Context) error {
+	closing := "\n]\n"
+	if e.streamed == 0 {
+		closing = "]\n"
+	}
+	_, err := e.writer.Write([]byte(closing))
+	return err
+}
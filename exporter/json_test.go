@@ -23,7 +23,7 @@ func TestJSONExporter_Export(t *testing.T) {
 			ProjectID:    "project",
 			RepositoryID: "repo",
 			ImageName:    "image",
-			Digest:       utils.ToPtr("sha256:abc123"),
+			Digest:       utils.Ptr("sha256:abc123"),
 		},
 		ScanTime: now,
 		Vulnerabilities: []schemas.Vulnerability{
@@ -35,7 +35,7 @@ func TestJSONExporter_Export(t *testing.T) {
 				FixedVersion:     "1.1.1t",
 				Description:      "Sample vulnerability",
 				CVSSScore:        7.5,
-				URLs:             []string{"https://cve.mitre.org/example"},
+				References:       []schemas.Reference{{URL: "https://cve.mitre.org/example", Type: schemas.ReferenceTypeAdvisory}},
 			},
 		},
 		Summary: schemas.VulnerabilitySummary{
@@ -54,7 +54,7 @@ func TestJSONExporter_Export(t *testing.T) {
 			ProjectID:    "project",
 			RepositoryID: "repo",
 			ImageName:    "image",
-			Digest:       utils.ToPtr("sha256:abc123"),
+			Digest:       utils.Ptr("sha256:abc123"),
 		},
 		ScanTime:        now,
 		Vulnerabilities: []schemas.Vulnerability{},
@@ -148,3 +148,64 @@ func TestJSONExporter_Export(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONExporter_Stream(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	result := func(image string) schemas.AnalyzeResult {
+		return schemas.AnalyzeResult{
+			Artifact: schemas.ArtifactReference{
+				Host:         "us-central1-docker.pkg.dev",
+				ProjectID:    "project",
+				RepositoryID: "repo",
+				ImageName:    image,
+			},
+			ScanTime: now,
+			Vulnerabilities: []schemas.Vulnerability{
+				{ID: "CVE-2023-0001", Severity: schemas.SeverityHigh},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		results []schemas.AnalyzeResult
+	}{
+		"should match Export output when multiple results are streamed": {
+			results: []schemas.AnalyzeResult{result("a"), result("b"), result("c")},
+		},
+		"should match Export output when a single result is streamed": {
+			results: []schemas.AnalyzeResult{result("a")},
+		},
+		"should match Export output when nothing is streamed": {
+			results: []schemas.AnalyzeResult{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			var want bytes.Buffer
+			if err := exporter.NewJSONExporter(&want).Export(ctx, tt.results); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+
+			var got bytes.Buffer
+			exp := exporter.NewJSONExporter(&got)
+			if err := exp.Begin(ctx); err != nil {
+				t.Fatalf("Begin() error = %v", err)
+			}
+			for _, r := range tt.results {
+				if err := exp.ExportOne(ctx, r); err != nil {
+					t.Fatalf("ExportOne() error = %v", err)
+				}
+			}
+			if err := exp.End(ctx); err != nil {
+				t.Fatalf("End() error = %v", err)
+			}
+
+			if diff := cmp.Diff(want.String(), got.String()); diff != "" {
+				t.Errorf("streamed output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
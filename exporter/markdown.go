@@ -0,0 +1,127 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// markdownSeverities are the rows of the severity summary table of each image.
+var markdownSeverities = []schemas.Severity{schemas.SeverityCritical, schemas.SeverityHigh, schemas.SeverityMedium, schemas.SeverityLow}
+
+// MarkdownExporter writes a Markdown report with one section per image: a severity summary table,
+// then the vulnerabilities, most severe first, in a collapsible <details> block so that long reports
+// stay readable in pull request comments, issues and GitHub step summaries.
+type MarkdownExporter struct {
+	writer io.Writer
+
+	// written is the number of images written since Begin
+	written int
+}
+
+// NewMarkdownExporter creates a new MarkdownExporter with the specified writer
+func NewMarkdownExporter(writer io.Writer) *MarkdownExporter {
+	return &MarkdownExporter{
+		writer: writer,
+	}
+}
+
+// Export writes the report of all results
+func (e *MarkdownExporter) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
+	if err := e.Begin(ctx); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if err := e.ExportOne(ctx, result); err != nil {
+			return err
+		}
+	}
+	return e.End(ctx)
+}
+
+// Begin writes the title of the report
+func (e *MarkdownExporter) Begin(ctx context.Context) error {
+	e.written = 0
+	_, err := io.WriteString(e.writer, "# Vulnerability Scan Report\n")
+	return err
+}
+
+// ExportOne writes the section of a single image
+func (e *MarkdownExporter) ExportOne(ctx context.Context, result schemas.AnalyzeResult) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n## `%s`\n\n", result.Artifact)
+	fmt.Fprintf(&b, "Scanned %s: %d vulnerabilities, %d fixable.\n", result.ScanTime.UTC().Format(time.DateTime+" MST"),
+		result.Summary.TotalCount, result.Summary.FixableCount)
+	if result.ScanStatus != "" && result.ScanStatus != schemas.ScanStatusFinished {
+		fmt.Fprintf(&b, "\n> **Not analyzed** (%s): findings may be missing.\n", result.ScanStatus)
+	}
+	if result.Partial {
+		b.WriteString("\n> **Partial**: the scan was interrupted before all images were analyzed.\n")
+	}
+
+	b.WriteString("\n| Severity | Count |\n| :------- | ----: |\n")
+	for _, s := range markdownSeverities {
+		fmt.Fprintf(&b, "| %s | %d |\n", s, result.Summary.CountBySeverity[s])
+	}
+
+	if len(result.Vulnerabilities) > 0 {
+		vulns := slices.Clone(result.Vulnerabilities)
+		slices.SortStableFunc(vulns, func(a, b schemas.Vulnerability) int {
+			return schemas.CompareSeverity(b.Severity, a.Severity)
+		})
+		fmt.Fprintf(&b, "\n<details>\n<summary>%d vulnerabilities</summary>\n\n", len(vulns))
+		b.WriteString("| ID | Severity | Package | Installed | Fixed | CVSS |\n| :-- | :-- | :-- | :-- | :-- | --: |\n")
+		for _, v := range vulns {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %.1f |\n", markdownID(v), v.Severity,
+				markdownCell(v.PackageName), markdownCell(v.InstalledVersion), markdownCell(v.FixedVersion), v.CVSSScore)
+		}
+		b.WriteString("\n</details>\n")
+	}
+
+	if len(result.Suppressed) > 0 {
+		fmt.Fprintf(&b, "\n<details>\n<summary>%d suppressed findings</summary>\n\n", len(result.Suppressed))
+		b.WriteString("| ID | Severity | Package | Reason | By | Until |\n| :-- | :-- | :-- | :-- | :-- | :-- |\n")
+		for _, s := range result.Suppressed {
+			until := ""
+			if !s.Suppression.Until.IsZero() {
+				until = s.Suppression.Until.Format(time.DateOnly)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n", markdownID(s.Vulnerability), s.Vulnerability.Severity,
+				markdownCell(s.Vulnerability.PackageName), markdownCell(s.Suppression.Reason), markdownCell(s.Suppression.By), until)
+		}
+		b.WriteString("\n</details>\n")
+	}
+
+	if _, err := io.WriteString(e.writer, b.String()); err != nil {
+		return err
+	}
+	e.written++
+	return nil
+}
+
+// End writes a note when no image was scanned
+func (e *MarkdownExporter) End(ctx context.Context) error {
+	if e.written > 0 {
+		return nil
+	}
+	_, err := io.WriteString(e.writer, "\nNo images scanned.\n")
+	return err
+}
+
+// markdownID returns the vulnerability ID, linked to its advisory if known.
+func markdownID(v schemas.Vulnerability) string {
+	if link := advisoryURL(v); link != "" {
+		return fmt.Sprintf("[%s](%s)", markdownCell(v.ID), link)
+	}
+	return markdownCell(v.ID)
+}
+
+// markdownCell escapes s for a table cell: pipes would end the cell, and line breaks the row.
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\r", " ", "\n", " ", "<", "&lt;").Replace(s)
+}
@@ -0,0 +1,86 @@
+package exporter_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestMarkdownExporter_Export(t *testing.T) {
+	scanTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	api := schemas.AnalyzeResult{
+		Artifact: schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "api", Digest: utils.Ptr("sha256:abc")},
+		ScanTime: scanTime,
+		Vulnerabilities: []schemas.Vulnerability{
+			{ID: "CVE-2", Severity: schemas.SeverityHigh, PackageName: "zlib", InstalledVersion: "1.2.11", CVSSScore: 7.5},
+			{
+				ID: "CVE-1", Severity: schemas.SeverityCritical, PackageName: "openssl", InstalledVersion: "1.1.1", FixedVersion: "1.1.1t", CVSSScore: 9.8,
+				References: []schemas.Reference{{URL: "https://example.com/CVE-1", Type: schemas.ReferenceTypeAdvisory}},
+			},
+		},
+		Summary: schemas.VulnerabilitySummary{
+			TotalCount:      2,
+			FixableCount:    1,
+			CountBySeverity: map[schemas.Severity]int{schemas.SeverityCritical: 1, schemas.SeverityHigh: 1},
+		},
+		Suppressed: []schemas.SuppressedFinding{{
+			Vulnerability: schemas.Vulnerability{ID: "CVE-3", Severity: schemas.SeverityHigh, PackageName: "bash"},
+			Suppression:   schemas.Suppression{ID: "CVE-3", Reason: "not reachable | no shell", By: "alice"},
+		}},
+	}
+	pending := schemas.AnalyzeResult{
+		Artifact:   schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "web"},
+		ScanTime:   scanTime,
+		ScanStatus: schemas.ScanStatusPending,
+	}
+
+	tests := map[string]struct {
+		results []schemas.AnalyzeResult
+		want    string
+	}{
+		"should write a section per image": {
+			results: []schemas.AnalyzeResult{api, pending},
+			want: "# Vulnerability Scan Report\n" +
+				"\n## `h/p/r/api@sha256:abc`\n\n" +
+				"Scanned 2024-01-02 03:04:05 UTC: 2 vulnerabilities, 1 fixable.\n" +
+				"\n| Severity | Count |\n| :------- | ----: |\n" +
+				"| CRITICAL | 1 |\n| HIGH | 1 |\n| MEDIUM | 0 |\n| LOW | 0 |\n" +
+				"\n<details>\n<summary>2 vulnerabilities</summary>\n\n" +
+				"| ID | Severity | Package | Installed | Fixed | CVSS |\n| :-- | :-- | :-- | :-- | :-- | --: |\n" +
+				"| [CVE-1](https://example.com/CVE-1) | CRITICAL | openssl | 1.1.1 | 1.1.1t | 9.8 |\n" +
+				"| CVE-2 | HIGH | zlib | 1.2.11 |  | 7.5 |\n" +
+				"\n</details>\n" +
+				"\n<details>\n<summary>1 suppressed findings</summary>\n\n" +
+				"| ID | Severity | Package | Reason | By | Until |\n| :-- | :-- | :-- | :-- | :-- | :-- |\n" +
+				"| CVE-3 | HIGH | bash | not reachable \\| no shell | alice |  |\n" +
+				"\n</details>\n" +
+				"\n## `h/p/r/web`\n\n" +
+				"Scanned 2024-01-02 03:04:05 UTC: 0 vulnerabilities, 0 fixable.\n" +
+				"\n> **Not analyzed** (PENDING): findings may be missing.\n" +
+				"\n| Severity | Count |\n| :------- | ----: |\n" +
+				"| CRITICAL | 0 |\n| HIGH | 0 |\n| MEDIUM | 0 |\n| LOW | 0 |\n",
+		},
+		"should note when no image was scanned": {
+			results: nil,
+			want:    "# Vulnerability Scan Report\n\nNo images scanned.\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := exporter.NewMarkdownExporter(&buf).Export(context.Background(), tt.results); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("Export() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
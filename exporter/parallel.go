@@ -0,0 +1,27 @@
+package exporter
+
+import "sync"
+
+// forEach calls fn for each index below n, running up to concurrency calls at once (sequentially
+// if concurrency is below 2), and returns their errors by index.
+func forEach(n, concurrency int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	if concurrency < 2 {
+		for i := range n {
+			errs[i] = fn(i)
+		}
+		return errs
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range n {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			errs[i] = fn(i)
+		})
+	}
+	wg.Wait()
+	return errs
+}
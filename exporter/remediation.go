@@ -0,0 +1,81 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// RemediationExporter prints the remediation commands of each image, with the vulnerabilities they fix.
+// Images without remediations are omitted.
+type RemediationExporter struct {
+	writer io.Writer
+
+	// written is the number of images printed since Begin
+	written int
+}
+
+// NewRemediationExporter creates a new RemediationExporter with the specified writer
+func NewRemediationExporter(writer io.Writer) *RemediationExporter {
+	return &RemediationExporter{
+		writer: writer,
+	}
+}
+
+// Export prints the remediations of all results
+func (e *RemediationExporter) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
+	if err := e.Begin(ctx); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if err := e.ExportOne(ctx, result); err != nil {
+			return err
+		}
+	}
+	return e.End(ctx)
+}
+
+// Begin resets the count of printed images
+func (e *RemediationExporter) Begin(ctx context.Context) error {
+	e.written = 0
+	return nil
+}
+
+// ExportOne prints the remediations of a single result
+func (e *RemediationExporter) ExportOne(ctx context.Context, result schemas.AnalyzeResult) error {
+	if len(result.Remediations) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	if e.written > 0 {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "# %s\n", result.Artifact)
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, r := range result.Remediations {
+		_, _ = fmt.Fprintf(tw, "%s\t# %s\n", r.Command, strings.Join(r.VulnerabilityIDs, ", "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(e.writer, b.String()); err != nil {
+		return err
+	}
+	e.written++
+	return nil
+}
+
+// End prints a note when no image had remediations
+func (e *RemediationExporter) End(ctx context.Context) error {
+	if e.written > 0 {
+		return nil
+	}
+	_, err := io.WriteString(e.writer, "No remediations available.\n")
+	return err
+}
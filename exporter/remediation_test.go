@@ -0,0 +1,60 @@
+package exporter_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestRemediationExporter_Export(t *testing.T) {
+	app := schemas.AnalyzeResult{
+		Artifact: schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "app", Digest: utils.Ptr("sha256:abc")},
+		Remediations: []schemas.Remediation{
+			{Command: "apt-get install --only-upgrade openssl=1.1.1t", VulnerabilityIDs: []string{"CVE-1", "CVE-2"}},
+			{Command: "apt-get install --only-upgrade zlib=1.2.13", VulnerabilityIDs: []string{"CVE-3"}},
+		},
+	}
+	clean := schemas.AnalyzeResult{
+		Artifact: schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "clean"},
+	}
+	web := schemas.AnalyzeResult{
+		Artifact:     schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "web"},
+		Remediations: []schemas.Remediation{{Command: "apk add --upgrade musl=1.2.4-r2", VulnerabilityIDs: []string{"CVE-4"}}},
+	}
+
+	tests := map[string]struct {
+		results []schemas.AnalyzeResult
+		want    string
+	}{
+		"should print the commands of each image": {
+			results: []schemas.AnalyzeResult{app, clean, web},
+			want: "# h/p/r/app@sha256:abc\n" +
+				"apt-get install --only-upgrade openssl=1.1.1t  # CVE-1, CVE-2\n" +
+				"apt-get install --only-upgrade zlib=1.2.13     # CVE-3\n" +
+				"\n" +
+				"# h/p/r/web\n" +
+				"apk add --upgrade musl=1.2.4-r2  # CVE-4\n",
+		},
+		"should say when there is nothing to do": {
+			results: []schemas.AnalyzeResult{clean},
+			want:    "No remediations available.\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := exporter.NewRemediationExporter(&buf).Export(context.Background(), tt.results); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("Export() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// DefaultS3Key is the default key template of S3Exporter.
+const DefaultS3Key = "drydock/{{.Project}}/{{.Date}}/{{.Timestamp}}.json"
+
+// S3Exporter uploads the rendered report to S3-compatible object storage (AWS S3, MinIO, ...)
+// with a single PutObject request signed with AWS Signature Version 4.
+// Objects are addressed path-style (endpoint/bucket/key), which S3 and MinIO both accept.
+type S3Exporter struct {
+	// Endpoint is the storage endpoint (default: "https://s3.<Region>.amazonaws.com"),
+	// e.g. "http://minio.internal:9000" for MinIO
+	Endpoint string
+
+	// Region is the bucket region (default: "us-east-1")
+	Region string
+
+	// Bucket is the bucket to upload to
+	Bucket string
+
+	// Key is a text/template of the object key (default: DefaultS3Key), executed with S3KeyData
+	Key string
+
+	// AccessKeyID, SecretAccessKey and SessionToken (for temporary credentials) sign the request
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	// Render writes the report to upload (default: the indented JSON of JSONExporter)
+	Render func(ctx context.Context, w io.Writer, results []schemas.AnalyzeResult) error
+
+	// ContentType is the content type of the object (default: guessed from the key extension)
+	ContentType string
+
+	// HTTPClient is the client used for requests (default: http.DefaultClient)
+	HTTPClient *http.Client
+
+	// now returns the current time, for signing
+	now func() time.Time
+}
+
+// S3KeyData is the data available to the key template of S3Exporter.
+type S3KeyData struct {
+	// Project and Location are those of the first result (e.g., "my-project", "us-central1")
+	Project  string
+	Location string
+
+	// Date and Timestamp are the UTC upload time, as "2006-01-02" and "20060102T150405Z"
+	Date      string
+	Timestamp string
+}
+
+// S3APIError is returned when the storage responds with an error status.
+type S3APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+// Error implements the error interface.
+func (e *S3APIError) Error() string {
+	return fmt.Sprintf("S3 PutObject: %d %s %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Export renders the results and uploads them
+func (e *S3Exporter) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
+	if e.Bucket == "" {
+		return errors.New("s3: Bucket is required")
+	}
+	now := time.Now
+	if e.now != nil {
+		now = e.now
+	}
+	t := now().UTC()
+
+	key, err := e.objectKey(results, t)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	render := e.Render
+	if render == nil {
+		render = func(ctx context.Context, w io.Writer, results []schemas.AnalyzeResult) error {
+			return NewJSONExporter(w).Export(ctx, results)
+		}
+	}
+	if err := render(ctx, &body, results); err != nil {
+		return err
+	}
+
+	region, u, err := e.bucketURL("/" + key)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return err
+	}
+	contentType := e.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(key))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	req.Header.Set("Content-Type", contentType)
+	if e.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", e.SessionToken)
+	}
+	signV4(req, body.Bytes(), region, "s3", e.AccessKeyID, e.SecretAccessKey, t)
+
+	client := e.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("S3 PutObject %s/%s: %w", e.Bucket, key, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		var msg struct {
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		}
+		_ = xml.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&msg)
+		return &S3APIError{StatusCode: resp.StatusCode, Code: msg.Code, Message: msg.Message}
+	}
+	return nil
+}
+
+// Check checks that the bucket exists and accepts the credentials, with a HeadBucket request.
+func (e *S3Exporter) Check(ctx context.Context) error {
+	if e.Bucket == "" {
+		return errors.New("s3: Bucket is required")
+	}
+	now := time.Now
+	if e.now != nil {
+		now = e.now
+	}
+	region, u, err := e.bucketURL("")
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	if e.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", e.SessionToken)
+	}
+	signV4(req, nil, region, "s3", e.AccessKeyID, e.SecretAccessKey, now().UTC())
+
+	client := e.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("S3 HeadBucket %s: %w", e.Bucket, err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("S3 HeadBucket %s: %s", e.Bucket, resp.Status)
+	}
+	return nil
+}
+
+// bucketURL returns the bucket region and the path-style URL of p in the bucket, e.g. "/key".
+func (e *S3Exporter) bucketURL(p string) (string, *url.URL, error) {
+	region := e.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := e.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", nil, fmt.Errorf("s3: invalid endpoint: %w", err)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + e.Bucket + p
+	u.RawPath = canonicalPath(u.Path) // send the path exactly as signed
+	return region, u, nil
+}
+
+// objectKey executes the key template.
+func (e *S3Exporter) objectKey(results []schemas.AnalyzeResult, t time.Time) (string, error) {
+	text := e.Key
+	if text == "" {
+		text = DefaultS3Key
+	}
+	tmpl, err := template.New("key").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("s3: invalid key template: %w", err)
+	}
+	data := S3KeyData{Date: t.Format(time.DateOnly), Timestamp: t.Format("20060102T150405Z")}
+	if len(results) > 0 {
+		data.Project = results[0].Artifact.ProjectID
+		data.Location = strings.TrimSuffix(results[0].Artifact.Host, "-docker.pkg.dev")
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("s3: invalid key template: %w", err)
+	}
+	key := strings.TrimPrefix(b.String(), "/")
+	if key == "" {
+		return "", errors.New("s3: empty object key")
+	}
+	return key, nil
+}
+
+// signV4 signs req with AWS Signature Version 4, covering the host and all headers already set.
+func signV4(req *http.Request, payload []byte, region, service, accessKeyID, secretAccessKey string, t time.Time) {
+	sum := sha256.Sum256(payload)
+	payloadHash := hex.EncodeToString(sum[:])
+	amzDate := t.UTC().Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalPath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	date := amzDate[:8]
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + secretAccessKey)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// canonicalPath URI-encodes each segment of a path, as S3 expects in signatures.
+func canonicalPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = awsURIEncode(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery encodes query parameters sorted by name.
+func canonicalQuery(query url.Values) string {
+	var parts []string
+	for name, values := range query {
+		for _, v := range values {
+			parts = append(parts, awsURIEncode(name)+"="+awsURIEncode(v))
+		}
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, "&")
+}
+
+// awsURIEncode percent-encodes everything but the unreserved characters of RFC 3986.
+func awsURIEncode(s string) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-_.~", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
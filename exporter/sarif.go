@@ -0,0 +1,303 @@
+package exporter
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+const (
+	// SARIFVersion is the version of the SARIF logs written by SARIFExporter
+	SARIFVersion = "2.1.0"
+
+	// SARIFSchema is the JSON schema of SARIF 2.1.0 logs
+	SARIFSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// SARIFLog is a SARIF 2.1.0 log with a single run of drydock.
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is the run of drydock, with its rules and results.
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes drydock and the rules it reports.
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is drydock, with one rule per vulnerability ID.
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule is a vulnerability, e.g. CVE-2024-1234.
+type SARIFRule struct {
+	ID                   string                 `json:"id"`
+	Name                 string                 `json:"name"`
+	ShortDescription     SARIFMessage           `json:"shortDescription"`
+	FullDescription      SARIFMessage           `json:"fullDescription"`
+	HelpURI              string                 `json:"helpUri,omitempty"`
+	Help                 SARIFMessage           `json:"help"`
+	DefaultConfiguration SARIFRuleConfiguration `json:"defaultConfiguration"`
+	Properties           SARIFRuleProperties    `json:"properties"`
+}
+
+// SARIFRuleConfiguration is the level of the results of a rule.
+type SARIFRuleConfiguration struct {
+	Level string `json:"level"`
+}
+
+// SARIFRuleProperties are the properties GitHub code scanning reads from a rule.
+type SARIFRuleProperties struct {
+	// SecuritySeverity is a CVSS-like score from "0.0" to "10.0", shown by GitHub as critical, high, medium or low
+	SecuritySeverity string   `json:"security-severity"`
+	Tags             []string `json:"tags"`
+}
+
+// SARIFMessage is a plain text message.
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFResult is a finding on an image.
+type SARIFResult struct {
+	RuleID              string                `json:"ruleId"`
+	RuleIndex           int                   `json:"ruleIndex"`
+	Level               string                `json:"level"`
+	Message             SARIFMessage          `json:"message"`
+	Locations           []SARIFLocation       `json:"locations"`
+	PartialFingerprints map[string]string     `json:"partialFingerprints"`
+	Properties          SARIFResultProperties `json:"properties"`
+}
+
+// SARIFLocation locates a finding at its image, since images have no source file.
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation is the image of a finding, as a path.
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           SARIFRegion           `json:"region"`
+}
+
+// SARIFArtifactLocation is the path of the image, without tag or digest.
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is the first line of the location; GitHub requires one.
+type SARIFRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// SARIFResultProperties details a finding on an image.
+type SARIFResultProperties struct {
+	Image            string           `json:"image"`
+	Severity         schemas.Severity `json:"severity"`
+	PackageName      string           `json:"packageName"`
+	InstalledVersion string           `json:"installedVersion,omitempty"`
+	FixedVersion     string           `json:"fixedVersion,omitempty"`
+}
+
+// SARIFExporter writes the findings as a SARIF 2.1.0 log, e.g. to upload to GitHub code scanning with
+// github/codeql-action/upload-sarif. Each vulnerability ID is a rule, and each finding on an image a
+// result located at the image. The log is written by End, since rules are known once every result is in.
+type SARIFExporter struct {
+	writer io.Writer
+
+	rules   map[string]*SARIFRule
+	results []SARIFResult // rule indexes are set by End
+}
+
+// NewSARIFExporter creates a SARIFExporter writing the log to writer.
+func NewSARIFExporter(writer io.Writer) *SARIFExporter {
+	return &SARIFExporter{writer: writer}
+}
+
+// Export writes the log of all results
+func (e *SARIFExporter) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
+	if err := e.Begin(ctx); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if err := e.ExportOne(ctx, result); err != nil {
+			return err
+		}
+	}
+	return e.End(ctx)
+}
+
+// Begin resets the rules and results
+func (e *SARIFExporter) Begin(ctx context.Context) error {
+	e.rules = make(map[string]*SARIFRule)
+	e.results = nil
+	return nil
+}
+
+// ExportOne adds the findings of a single result
+func (e *SARIFExporter) ExportOne(ctx context.Context, result schemas.AnalyzeResult) error {
+	if e.rules == nil {
+		e.rules = make(map[string]*SARIFRule)
+	}
+	image := result.Artifact
+	image.Tag, image.Digest = nil, nil
+	for _, v := range result.Vulnerabilities {
+		e.addRule(v)
+		fingerprint := sha256.Sum256([]byte(image.String() + "\x00" + v.ID + "\x00" + v.PackageName))
+		e.results = append(e.results, SARIFResult{
+			RuleID:  v.ID,
+			Level:   sarifLevel(v.Severity),
+			Message: SARIFMessage{Text: sarifMessage(result.Artifact, v)},
+			Locations: []SARIFLocation{{PhysicalLocation: SARIFPhysicalLocation{
+				ArtifactLocation: SARIFArtifactLocation{URI: image.String()},
+				Region:           SARIFRegion{StartLine: 1},
+			}}},
+			// Stable across digests, so that GitHub keeps tracking a finding as the image is rebuilt
+			PartialFingerprints: map[string]string{"drydock/v1": hex.EncodeToString(fingerprint[:])},
+			Properties: SARIFResultProperties{
+				Image:            result.Artifact.String(),
+				Severity:         v.Severity,
+				PackageName:      v.PackageName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+			},
+		})
+	}
+	return nil
+}
+
+// End writes the log
+func (e *SARIFExporter) End(ctx context.Context) error {
+	rules := make([]SARIFRule, 0, len(e.rules))
+	for _, rule := range e.rules {
+		rules = append(rules, *rule)
+	}
+	slices.SortFunc(rules, func(a, b SARIFRule) int { return cmp.Compare(a.ID, b.ID) })
+	index := make(map[string]int, len(rules))
+	for i, rule := range rules {
+		index[rule.ID] = i
+	}
+	results := e.results
+	for i := range results {
+		results[i].RuleIndex = index[results[i].RuleID]
+	}
+	if results == nil {
+		results = []SARIFResult{}
+	}
+	e.rules, e.results = nil, nil
+
+	log := SARIFLog{
+		Schema:  SARIFSchema,
+		Version: SARIFVersion,
+		Runs: []SARIFRun{{
+			Tool: SARIFTool{Driver: SARIFDriver{
+				Name:           "drydock",
+				InformationURI: scannerURI,
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+	enc := json.NewEncoder(e.writer)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(log); err != nil {
+		return fmt.Errorf("failed to write SARIF log: %w", err)
+	}
+	return nil
+}
+
+// addRule adds the rule of v, or raises the severity of the existing one: the same ID may be
+// rated differently by the data sources of different packages.
+func (e *SARIFExporter) addRule(v schemas.Vulnerability) {
+	score := sarifSecuritySeverity(v)
+	if rule, ok := e.rules[v.ID]; ok {
+		if current, _ := strconv.ParseFloat(rule.Properties.SecuritySeverity, 64); score > current {
+			rule.Properties.SecuritySeverity = strconv.FormatFloat(score, 'f', 1, 64)
+			rule.DefaultConfiguration.Level = sarifLevel(v.Severity)
+			rule.Properties.Tags[2] = strings.ToLower(string(v.Severity))
+		}
+		return
+	}
+	description := cmp.Or(v.Description, v.ID)
+	help := description
+	link := advisoryURL(v)
+	if link != "" {
+		help += "\n\n" + link
+	}
+	e.rules[v.ID] = &SARIFRule{
+		ID:                   v.ID,
+		Name:                 v.ID,
+		ShortDescription:     SARIFMessage{Text: fmt.Sprintf("%s (%s)", v.ID, v.Severity)},
+		FullDescription:      SARIFMessage{Text: description},
+		HelpURI:              link,
+		Help:                 SARIFMessage{Text: help},
+		DefaultConfiguration: SARIFRuleConfiguration{Level: sarifLevel(v.Severity)},
+		Properties: SARIFRuleProperties{
+			SecuritySeverity: strconv.FormatFloat(score, 'f', 1, 64),
+			Tags:             []string{"security", "vulnerability", strings.ToLower(string(v.Severity))},
+		},
+	}
+}
+
+// sarifLevel maps a severity to a SARIF level.
+func sarifLevel(severity schemas.Severity) string {
+	switch severity {
+	case schemas.SeverityCritical, schemas.SeverityHigh:
+		return "error"
+	case schemas.SeverityMedium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifSecuritySeverity returns the CVSS score of v, or else a score within the CVSS range of its severity,
+// which GitHub maps back to the same severity.
+func sarifSecuritySeverity(v schemas.Vulnerability) float64 {
+	if v.CVSSScore > 0 {
+		return float64(v.CVSSScore)
+	}
+	switch v.Severity {
+	case schemas.SeverityCritical:
+		return 9.5
+	case schemas.SeverityHigh:
+		return 8.0
+	case schemas.SeverityMedium:
+		return 5.5
+	case schemas.SeverityLow:
+		return 2.0
+	default:
+		return 0.0
+	}
+}
+
+// sarifMessage describes the finding on the image in plain text.
+func sarifMessage(artifact schemas.ArtifactReference, v schemas.Vulnerability) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s in %s is affected by %s (%s).", v.PackageName, v.InstalledVersion, artifact, v.ID, v.Severity)
+	if v.FixedVersion != "" {
+		fmt.Fprintf(&b, " Fixed in %s.", v.FixedVersion)
+	} else {
+		b.WriteString(" No fix available yet.")
+	}
+	return b.String()
+}
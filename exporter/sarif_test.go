@@ -0,0 +1,135 @@
+package exporter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestSARIFExporter_Export(t *testing.T) {
+	image := func(name, digest string) schemas.ArtifactReference {
+		return schemas.ArtifactReference{
+			Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: name, Digest: utils.Ptr(digest),
+		}
+	}
+	high := schemas.Vulnerability{
+		ID: "CVE-1", Severity: schemas.SeverityHigh, PackageName: "openssl", InstalledVersion: "3.0.1", FixedVersion: "3.0.2",
+		Description: "Buffer overflow",
+		References:  []schemas.Reference{{URL: "https://nvd.nist.gov/vuln/detail/CVE-1", Type: schemas.ReferenceTypeAdvisory}},
+	}
+	critical := schemas.Vulnerability{ID: "CVE-2", Severity: schemas.SeverityCritical, PackageName: "glibc", CVSSScore: 9.8}
+	medium := schemas.Vulnerability{ID: "CVE-1", Severity: schemas.SeverityMedium, PackageName: "libssl"}
+
+	tests := map[string]struct {
+		results     []schemas.AnalyzeResult
+		wantRules   []string
+		wantScores  []string
+		wantResults []string
+		wantIndexes []int
+	}{
+		"should write one rule per ID and one result per finding": {
+			results: []schemas.AnalyzeResult{
+				{Artifact: image("api", "sha256:aaa"), Vulnerabilities: []schemas.Vulnerability{critical, high}},
+				{Artifact: image("worker", "sha256:bbb"), Vulnerabilities: []schemas.Vulnerability{medium}},
+			},
+			wantRules:   []string{"CVE-1", "CVE-2"},
+			wantScores:  []string{"8.0", "9.8"},
+			wantResults: []string{"CVE-2", "CVE-1", "CVE-1"},
+			wantIndexes: []int{1, 0, 0},
+		},
+		"should write an empty run without findings": {
+			results:     []schemas.AnalyzeResult{{Artifact: image("api", "sha256:aaa")}},
+			wantRules:   []string{},
+			wantScores:  []string{},
+			wantResults: []string{},
+			wantIndexes: []int{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := exporter.NewSARIFExporter(&buf).Export(context.Background(), tt.results); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			var got exporter.SARIFLog
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("invalid SARIF log: %v\n%s", err, buf.String())
+			}
+			if got.Version != exporter.SARIFVersion || len(got.Runs) != 1 {
+				t.Fatalf("version = %q, runs = %d, want %q and 1 run", got.Version, len(got.Runs), exporter.SARIFVersion)
+			}
+			run := got.Runs[0]
+
+			rules, scores := []string{}, []string{}
+			for _, r := range run.Tool.Driver.Rules {
+				rules = append(rules, r.ID)
+				scores = append(scores, r.Properties.SecuritySeverity)
+			}
+			results, indexes := []string{}, []int{}
+			for _, r := range run.Results {
+				results = append(results, r.RuleID)
+				indexes = append(indexes, r.RuleIndex)
+			}
+			if diff := cmp.Diff(tt.wantRules, rules); diff != "" {
+				t.Errorf("rules mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantScores, scores); diff != "" {
+				t.Errorf("security severities mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantResults, results); diff != "" {
+				t.Errorf("results mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantIndexes, indexes); diff != "" {
+				t.Errorf("rule indexes mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSARIFExporter_Result(t *testing.T) {
+	v := schemas.Vulnerability{
+		ID: "CVE-1", Severity: schemas.SeverityHigh, PackageName: "openssl", InstalledVersion: "3.0.1", FixedVersion: "3.0.2",
+	}
+	export := func(digest string) exporter.SARIFResult {
+		var buf bytes.Buffer
+		result := schemas.AnalyzeResult{
+			Artifact: schemas.ArtifactReference{
+				Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "api", Tag: utils.Ptr("v1"), Digest: utils.Ptr(digest),
+			},
+			Vulnerabilities: []schemas.Vulnerability{v},
+		}
+		if err := exporter.NewSARIFExporter(&buf).Export(context.Background(), []schemas.AnalyzeResult{result}); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		var log exporter.SARIFLog
+		if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+			t.Fatalf("invalid SARIF log: %v", err)
+		}
+		return log.Runs[0].Results[0]
+	}
+
+	got := export("sha256:aaa")
+	want := exporter.SARIFResultProperties{
+		Image: "h/p/r/api:v1@sha256:aaa", Severity: schemas.SeverityHigh, PackageName: "openssl",
+		InstalledVersion: "3.0.1", FixedVersion: "3.0.2",
+	}
+	if diff := cmp.Diff(want, got.Properties); diff != "" {
+		t.Errorf("properties mismatch (-want +got):\n%s", diff)
+	}
+	if got.Level != "error" {
+		t.Errorf("level = %q, want error", got.Level)
+	}
+	if uri := got.Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != "h/p/r/api" {
+		t.Errorf("location = %q, want the image without tag or digest", uri)
+	}
+	if diff := cmp.Diff(got.PartialFingerprints, export("sha256:bbb").PartialFingerprints); diff != "" {
+		t.Errorf("fingerprints differ across digests (-first +second):\n%s", diff)
+	}
+}
@@ -0,0 +1,193 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// ServiceNowExporter creates or updates Vulnerable Item records (sn_vul_vulnerable_item) of ServiceNow
+// Vulnerability Response through the Table API. Each image maps to the configuration item named after it
+// ("host/project/repository/image"), and each finding to the vulnerability entry (sn_vul_entry) with its ID.
+// Images without a configuration item and findings without a vulnerability entry are reported as errors,
+// after the other records were exported, as are the records that could not be written.
+type ServiceNowExporter struct {
+	// BaseURL is the instance URL, e.g. "https://example.service-now.com"
+	BaseURL string
+
+	// User and Password are the basic authentication credentials of an integration user
+	User     string
+	Password string
+
+	// CITable is the table of the configuration items of images (default: "cmdb_ci")
+	CITable string
+
+	// Source is the source recorded on the vulnerable items, which also scopes updates (default: "drydock")
+	Source string
+
+	// HTTPClient sends the ServiceNow API requests (default: http.DefaultClient)
+	HTTPClient *http.Client
+
+	// Concurrency is the number of vulnerable items written at once (default: one at a time)
+	Concurrency int
+}
+
+// serviceNowItem is a vulnerable item to create or update.
+type serviceNowItem struct {
+	ci, entry string
+	result    *schemas.AnalyzeResult
+	v         schemas.Vulnerability
+}
+
+// ServiceNowAPIError is returned when the ServiceNow API responds with an error status.
+type ServiceNowAPIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+// Error implements the error interface.
+func (e *ServiceNowAPIError) Error() string {
+	return fmt.Sprintf("ServiceNow API %s %s: %d %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// serviceNowTime is the date-time format of the Table API.
+const serviceNowTime = "2006-01-02 15:04:05"
+
+// Export creates or updates the vulnerable items of all findings
+func (e *ServiceNowExporter) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
+	if e.BaseURL == "" {
+		return errors.New("servicenow: BaseURL is required")
+	}
+	ciTable := e.CITable
+	if ciTable == "" {
+		ciTable = "cmdb_ci"
+	}
+	source := e.Source
+	if source == "" {
+		source = "drydock"
+	}
+
+	var errs []error
+	var items []serviceNowItem
+	cis := make(map[string]string)     // sys_id by image
+	entries := make(map[string]string) // sys_id by vulnerability ID
+	for i, result := range results {
+		image := result.Artifact
+		image.Tag, image.Digest = nil, nil
+		ci, ok := cis[image.String()]
+		if !ok {
+			var err error
+			if ci, err = e.lookup(ctx, ciTable, "name="+image.String()); err != nil {
+				return err
+			}
+			cis[image.String()] = ci
+			if ci == "" {
+				errs = append(errs, fmt.Errorf("servicenow: no configuration item named %s in %s", image, ciTable))
+			}
+		}
+		if ci == "" {
+			continue
+		}
+
+		for _, v := range result.Vulnerabilities {
+			entry, ok := entries[v.ID]
+			if !ok {
+				var err error
+				if entry, err = e.lookup(ctx, "sn_vul_entry", "id="+v.ID); err != nil {
+					return err
+				}
+				entries[v.ID] = entry
+				if entry == "" {
+					errs = append(errs, fmt.Errorf("servicenow: no vulnerability entry %s", v.ID))
+				}
+			}
+			if entry == "" {
+				continue
+			}
+			items = append(items, serviceNowItem{ci: ci, entry: entry, result: &results[i], v: v})
+		}
+	}
+
+	upserts := forEach(len(items), e.Concurrency, func(i int) error {
+		item := items[i]
+		return e.upsertItem(ctx, item.ci, item.entry, source, *item.result, item.v)
+	})
+	return errors.Join(append(upserts, errs...)...)
+}
+
+// upsertItem creates the vulnerable item of a finding, or updates the one previously exported.
+func (e *ServiceNowExporter) upsertItem(ctx context.Context, ci, entry, source string, result schemas.AnalyzeResult, v schemas.Vulnerability) error {
+	item, err := e.lookup(ctx, "sn_vul_vulnerable_item", "cmdb_ci="+ci+"^vulnerability="+entry+"^source="+source)
+	if err != nil {
+		return err
+	}
+
+	proof := fmt.Sprintf("%s %s is installed in %s", v.PackageName, v.InstalledVersion, result.Artifact)
+	if v.FixedVersion != "" {
+		proof += fmt.Sprintf("; fixed in %s", v.FixedVersion)
+	}
+	fields := map[string]string{
+		"last_found": result.ScanTime.UTC().Format(serviceNowTime),
+		"proof":      proof,
+	}
+	if item != "" {
+		return e.do(ctx, http.MethodPatch, "/api/now/table/sn_vul_vulnerable_item/"+url.PathEscape(item), fields, nil)
+	}
+	fields["cmdb_ci"] = ci
+	fields["vulnerability"] = entry
+	fields["source"] = source
+	fields["first_found"] = fields["last_found"]
+	return e.do(ctx, http.MethodPost, "/api/now/table/sn_vul_vulnerable_item", fields, nil)
+}
+
+// lookup returns the sys_id of the first record of table matching the encoded query, or "" if none does.
+func (e *ServiceNowExporter) lookup(ctx context.Context, table, query string) (string, error) {
+	var found struct {
+		Result []struct {
+			SysID string `json:"sys_id"`
+		} `json:"result"`
+	}
+	params := url.Values{"sysparm_query": {query}, "sysparm_fields": {"sys_id"}, "sysparm_limit": {"1"}}
+	if err := e.do(ctx, http.MethodGet, "/api/now/table/"+table+"?"+params.Encode(), nil, &found); err != nil {
+		return "", err
+	}
+	if len(found.Result) == 0 {
+		return "", nil
+	}
+	return found.Result[0].SysID, nil
+}
+
+// Check checks that vulnerable items can be read with the credentials, without writing any.
+func (e *ServiceNowExporter) Check(ctx context.Context) error {
+	params := url.Values{"sysparm_fields": {"sys_id"}, "sysparm_limit": {"1"}}
+	return e.do(ctx, http.MethodGet, "/api/now/table/sn_vul_vulnerable_item?"+params.Encode(), nil, nil)
+}
+
+// do sends a request to the ServiceNow Table API (see doJSON).
+func (e *ServiceNowExporter) do(ctx context.Context, method, path string, body, out any) error {
+	return doJSON(ctx, jsonAPI{
+		name:    "ServiceNow API",
+		baseURL: e.BaseURL,
+		client:  e.HTTPClient,
+		header: func(req *http.Request) {
+			req.Header.Set("Accept", "application/json")
+			req.SetBasicAuth(e.User, e.Password)
+		},
+		apiError: func(method, path string, statusCode int, body []byte) error {
+			var msg struct {
+				Error struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			_ = json.Unmarshal(body, &msg)
+			return &ServiceNowAPIError{Method: method, Path: path, StatusCode: statusCode, Message: msg.Error.Message}
+		},
+	}, method, path, body, out)
+}
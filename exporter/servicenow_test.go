@@ -0,0 +1,102 @@
+package exporter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+// fakeServiceNow serves the Table API calls made by ServiceNowExporter from in-memory records.
+type fakeServiceNow struct {
+	records  map[string]string // sys_id by "table?query"
+	requests []string
+	bodies   []map[string]string
+}
+
+func (f *fakeServiceNow) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if user, password, _ := r.BasicAuth(); user != "bot" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	table := strings.TrimPrefix(r.URL.Path, "/api/now/table/")
+	if r.Method == http.MethodGet {
+		var result []map[string]string
+		if id, ok := f.records[table+"?"+r.URL.Query().Get("sysparm_query")]; ok {
+			result = append(result, map[string]string{"sys_id": id})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": result})
+		return
+	}
+	f.requests = append(f.requests, r.Method+" "+table)
+	var body map[string]string
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	f.bodies = append(f.bodies, body)
+	_ = json.NewEncoder(w).Encode(map[string]any{"result": body})
+}
+
+func TestServiceNowExporter_Export(t *testing.T) {
+	scanTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	api := schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "api", Tag: utils.Ptr("v1")}
+	results := []schemas.AnalyzeResult{
+		{
+			Artifact: api,
+			ScanTime: scanTime,
+			Vulnerabilities: []schemas.Vulnerability{
+				{ID: "CVE-1", PackageName: "openssl", InstalledVersion: "1.1", FixedVersion: "1.2"},
+				{ID: "CVE-2", PackageName: "zlib", InstalledVersion: "1.0"},
+				{ID: "GHSA-x", PackageName: "lodash", InstalledVersion: "4.0"},
+			},
+		},
+		{
+			Artifact:        schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "unknown"},
+			Vulnerabilities: []schemas.Vulnerability{{ID: "CVE-1"}},
+		},
+	}
+	fake := &fakeServiceNow{records: map[string]string{
+		"cmdb_ci?name=h/p/r/api": "ci-api",
+		"sn_vul_entry?id=CVE-1":  "entry-1",
+		"sn_vul_entry?id=CVE-2":  "entry-2",
+		"sn_vul_vulnerable_item?cmdb_ci=ci-api^vulnerability=entry-2^source=drydock": "item-2",
+	}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	e := &exporter.ServiceNowExporter{BaseURL: server.URL, User: "bot", Password: "secret"}
+	err := e.Export(context.Background(), results)
+
+	wantErrs := []string{"no vulnerability entry GHSA-x", "no configuration item named h/p/r/unknown"}
+	for _, want := range wantErrs {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Export() error = %v, want it to mention %q", err, want)
+		}
+	}
+	if diff := cmp.Diff([]string{"POST sn_vul_vulnerable_item", "PATCH sn_vul_vulnerable_item/item-2"}, fake.requests); diff != "" {
+		t.Errorf("requests mismatch (-want +got):\n%s", diff)
+	}
+	wantBodies := []map[string]string{
+		{
+			"cmdb_ci":       "ci-api",
+			"vulnerability": "entry-1",
+			"source":        "drydock",
+			"first_found":   "2024-05-01 12:00:00",
+			"last_found":    "2024-05-01 12:00:00",
+			"proof":         "openssl 1.1 is installed in h/p/r/api:v1; fixed in 1.2",
+		},
+		{
+			"last_found": "2024-05-01 12:00:00",
+			"proof":      "zlib 1.0 is installed in h/p/r/api:v1",
+		},
+	}
+	if diff := cmp.Diff(wantBodies, fake.bodies); diff != "" {
+		t.Errorf("records mismatch (-want +got):\n%s", diff)
+	}
+}
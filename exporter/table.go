@@ -41,47 +41,63 @@ func NewDefaultCSVExporter() *TableExporter {
 	return NewCSVExporter(os.Stdout)
 }
 
+// tableHeader is the header row shared by CSV and TSV output.
+var tableHeader = []string{
+	"Scan Time",
+	"Host",
+	"Project ID",
+	"Repository ID",
+	"Image Name",
+	"Tag",
+	"Digest",
+	"Vulnerability ID",
+	"Severity",
+	"CVSS Score",
+	"Package Type",
+	"Package Name",
+	"Installed Version",
+	"Fixed Version",
+	"Description",
+	"Reference URL",
+	"Suppressed",
+	"Suppression Reason",
+	"Suppressed By",
+	"Suppressed Until",
+	"Fix State",
+}
+
 // Export outputs the analysis results.
 func (e *TableExporter) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
 	// 1. Write Header
-	header := []string{
-		"Scan Time",
-		"Host",
-		"Project ID",
-		"Repository ID",
-		"Image Name",
-		"Tag",
-		"Digest",
-		"Vulnerability ID",
-		"Severity",
-		"CVSS Score",
-		"Package Type",
-		"Package Name",
-		"Installed Version",
-		"Fixed Version",
-		"Description",
-		"Reference URL",
-	}
-
-	if err := e.writer.Write(header); err != nil {
-		return fmt.Errorf("failed to write header: %w", err)
+	if err := e.Begin(ctx); err != nil {
+		return err
 	}
 
 	// 2. Write Data Rows
 	for _, result := range results {
-		// Pre-calculate shared fields for this artifact
-		scanTime := result.ScanTime.Format(time.RFC3339)
+		if err := e.writeRows(result); err != nil {
+			return err
+		}
+	}
 
-		for _, v := range result.Vulnerabilities {
-			// Use the shared logic to build the row
-			record := buildRecord(scanTime, result.Artifact, v)
+	return e.End(ctx)
+}
 
-			if err := e.writer.Write(record); err != nil {
-				return fmt.Errorf("failed to write record for %s: %w", v.ID, err)
-			}
-		}
+// Begin writes the header row for streaming output.
+func (e *TableExporter) Begin(ctx context.Context) error {
+	if err := e.writer.Write(tableHeader); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
 	}
+	return nil
+}
+
+// ExportOne writes the rows of a single result.
+func (e *TableExporter) ExportOne(ctx context.Context, result schemas.AnalyzeResult) error {
+	return e.writeRows(result)
+}
 
+// End flushes buffered rows to the underlying writer.
+func (e *TableExporter) End(ctx context.Context) error {
 	e.writer.Flush()
 	if err := e.writer.Error(); err != nil {
 		return fmt.Errorf("flush error: %w", err)
@@ -90,9 +106,33 @@ func (e *TableExporter) Export(ctx context.Context, results []schemas.AnalyzeRes
 	return nil
 }
 
+// writeRows writes one row per vulnerability of the given result, then one per suppressed finding.
+func (e *TableExporter) writeRows(result schemas.AnalyzeResult) error {
+	// Pre-calculate shared fields for this artifact
+	scanTime := result.ScanTime.Format(time.RFC3339)
+
+	for _, v := range result.Vulnerabilities {
+		// Use the shared logic to build the row
+		record := buildRecord(scanTime, result.Artifact, v, nil)
+
+		if err := e.writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write record for %s: %w", v.ID, err)
+		}
+	}
+	for _, s := range result.Suppressed {
+		record := buildRecord(scanTime, result.Artifact, s.Vulnerability, &s.Suppression)
+
+		if err := e.writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write record for %s: %w", s.Vulnerability.ID, err)
+		}
+	}
+	return nil
+}
+
 // buildRecord centralizes the logic of converting a single vulnerability into a row of strings.
 // This ensures CSV and TSV always output the same data structure.
-func buildRecord(scanTime string, artifact schemas.ArtifactReference, v schemas.Vulnerability) []string {
+// The suppression columns are only filled in for suppressed findings (sup != nil).
+func buildRecord(scanTime string, artifact schemas.ArtifactReference, v schemas.Vulnerability, sup *schemas.Suppression) []string {
 	// Extract Tag and Digest with nil-safe handling
 	tag := ""
 	if artifact.Tag != nil {
@@ -106,14 +146,22 @@ func buildRecord(scanTime string, artifact schemas.ArtifactReference, v schemas.
 
 	// Handle URL logic (pick first or empty)
 	urlStr := ""
-	if len(v.URLs) > 0 {
-		urlStr = v.URLs[0]
+	if len(v.References) > 0 {
+		urlStr = v.References[0].URL
 	}
 
 	// Clean description (optional: limit length or remove excessive newlines if needed)
 	// For standard CSV/TSV, the writer handles newlines automatically via quoting.
 	desc := strings.TrimSpace(v.Description)
 
+	var suppressed, reason, by, until string
+	if sup != nil {
+		suppressed, reason, by = "true", sup.Reason, sup.By
+		if !sup.Until.IsZero() {
+			until = sup.Until.Format(time.RFC3339)
+		}
+	}
+
 	return []string{
 		scanTime,
 		artifact.Host,
@@ -131,5 +179,10 @@ func buildRecord(scanTime string, artifact schemas.ArtifactReference, v schemas.
 		v.FixedVersion,
 		desc,
 		urlStr,
+		suppressed,
+		reason,
+		by,
+		until,
+		string(v.FixState),
 	}
 }
@@ -36,7 +36,7 @@ func TestTableExporter_Export_CSV(t *testing.T) {
 				results: []schemas.AnalyzeResult{},
 			},
 			want: [][]string{
-				{"Scan Time", "Host", "Project ID", "Repository ID", "Image Name", "Tag", "Digest", "Vulnerability ID", "Severity", "CVSS Score", "Package Type", "Package Name", "Installed Version", "Fixed Version", "Description", "Reference URL"},
+				{"Scan Time", "Host", "Project ID", "Repository ID", "Image Name", "Tag", "Digest", "Vulnerability ID", "Severity", "CVSS Score", "Package Type", "Package Name", "Installed Version", "Fixed Version", "Description", "Reference URL", "Suppressed", "Suppression Reason", "Suppressed By", "Suppressed Until", "Fix State"},
 			},
 		},
 		"should format standard vulnerability data correctly": {
@@ -59,15 +59,16 @@ func TestTableExporter_Export_CSV(t *testing.T) {
 								PackageName:      "openssl",
 								InstalledVersion: "1.1.1",
 								FixedVersion:     "1.1.2",
+								FixState:         schemas.FixStateFixAvailable,
 								Description:      "Buffer overflow",
-								URLs:             []string{"https://cve.mitre.org/..."},
+								References:       []schemas.Reference{{URL: "https://cve.mitre.org/...", Type: schemas.ReferenceTypeAdvisory}},
 							},
 						},
 					},
 				},
 			},
 			want: [][]string{
-				{"Scan Time", "Host", "Project ID", "Repository ID", "Image Name", "Tag", "Digest", "Vulnerability ID", "Severity", "CVSS Score", "Package Type", "Package Name", "Installed Version", "Fixed Version", "Description", "Reference URL"},
+				{"Scan Time", "Host", "Project ID", "Repository ID", "Image Name", "Tag", "Digest", "Vulnerability ID", "Severity", "CVSS Score", "Package Type", "Package Name", "Installed Version", "Fixed Version", "Description", "Reference URL", "Suppressed", "Suppression Reason", "Suppressed By", "Suppressed Until", "Fix State"},
 				{
 					fixedTimeStr,
 					"asia.gcr.io",
@@ -85,6 +86,8 @@ func TestTableExporter_Export_CSV(t *testing.T) {
 					"1.1.2",
 					"Buffer overflow",
 					"https://cve.mitre.org/...",
+					"", "", "", "",
+					"FIX_AVAILABLE",
 				},
 			},
 		},
@@ -107,9 +110,31 @@ func TestTableExporter_Export_CSV(t *testing.T) {
 				},
 			},
 			want: [][]string{
-				{"Scan Time", "Host", "Project ID", "Repository ID", "Image Name", "Tag", "Digest", "Vulnerability ID", "Severity", "CVSS Score", "Package Type", "Package Name", "Installed Version", "Fixed Version", "Description", "Reference URL"},
-				{fixedTimeStr, "gcr.io", "p", "r", "multi", "", "", "CVE-1", "LOW", "0.0", "", "", "", "", "", ""},
-				{fixedTimeStr, "gcr.io", "p", "r", "multi", "", "", "CVE-2", "MEDIUM", "0.0", "", "", "", "", "", ""},
+				{"Scan Time", "Host", "Project ID", "Repository ID", "Image Name", "Tag", "Digest", "Vulnerability ID", "Severity", "CVSS Score", "Package Type", "Package Name", "Installed Version", "Fixed Version", "Description", "Reference URL", "Suppressed", "Suppression Reason", "Suppressed By", "Suppressed Until", "Fix State"},
+				{fixedTimeStr, "gcr.io", "p", "r", "multi", "", "", "CVE-1", "LOW", "0.0", "", "", "", "", "", "", "", "", "", "", ""},
+				{fixedTimeStr, "gcr.io", "p", "r", "multi", "", "", "CVE-2", "MEDIUM", "0.0", "", "", "", "", "", "", "", "", "", "", ""},
+			},
+		},
+		"should list suppressed findings after the reported ones": {
+			args: args{
+				results: []schemas.AnalyzeResult{
+					{
+						Artifact:        schemas.ArtifactReference{Host: "gcr.io", ProjectID: "p", RepositoryID: "r", ImageName: "app"},
+						ScanTime:        fixedTime,
+						Vulnerabilities: []schemas.Vulnerability{{ID: "CVE-1", Severity: schemas.SeverityLow}},
+						Suppressed: []schemas.SuppressedFinding{
+							{
+								Vulnerability: schemas.Vulnerability{ID: "CVE-2", Severity: schemas.SeverityHigh},
+								Suppression:   schemas.Suppression{ID: "CVE-2", Reason: "not reachable", By: "alice", Until: fixedTime},
+							},
+						},
+					},
+				},
+			},
+			want: [][]string{
+				{"Scan Time", "Host", "Project ID", "Repository ID", "Image Name", "Tag", "Digest", "Vulnerability ID", "Severity", "CVSS Score", "Package Type", "Package Name", "Installed Version", "Fixed Version", "Description", "Reference URL", "Suppressed", "Suppression Reason", "Suppressed By", "Suppressed Until", "Fix State"},
+				{fixedTimeStr, "gcr.io", "p", "r", "app", "", "", "CVE-1", "LOW", "0.0", "", "", "", "", "", "", "", "", "", "", ""},
+				{fixedTimeStr, "gcr.io", "p", "r", "app", "", "", "CVE-2", "HIGH", "0.0", "", "", "", "", "", "", "true", "not reachable", "alice", fixedTimeStr, ""},
 			},
 		},
 		"should handle special characters (CSV escaping)": {
@@ -134,7 +159,7 @@ func TestTableExporter_Export_CSV(t *testing.T) {
 				},
 			},
 			want: [][]string{
-				{"Scan Time", "Host", "Project ID", "Repository ID", "Image Name", "Tag", "Digest", "Vulnerability ID", "Severity", "CVSS Score", "Package Type", "Package Name", "Installed Version", "Fixed Version", "Description", "Reference URL"},
+				{"Scan Time", "Host", "Project ID", "Repository ID", "Image Name", "Tag", "Digest", "Vulnerability ID", "Severity", "CVSS Score", "Package Type", "Package Name", "Installed Version", "Fixed Version", "Description", "Reference URL", "Suppressed", "Suppression Reason", "Suppressed By", "Suppressed Until", "Fix State"},
 				{
 					fixedTimeStr,
 					"pkg.dev",
@@ -150,6 +175,8 @@ func TestTableExporter_Export_CSV(t *testing.T) {
 					"", "", "",
 					"Line 1\nLine 2, with \"quotes\"", // CSV reader automatically handles unescaping
 					"",
+					"", "", "", "",
+					"", // Fix State
 				},
 			},
 		},
@@ -193,8 +220,8 @@ func TestTableExporter_Export_TSV(t *testing.T) {
 	}
 
 	want := [][]string{
-		{"Scan Time", "Host", "Project ID", "Repository ID", "Image Name", "Tag", "Digest", "Vulnerability ID", "Severity", "CVSS Score", "Package Type", "Package Name", "Installed Version", "Fixed Version", "Description", "Reference URL"},
-		{fixedTimeStr, "h", "p", "r", "i", "", "", "CVE-TSV", "CRITICAL", "0.0", "", "", "", "", "", ""},
+		{"Scan Time", "Host", "Project ID", "Repository ID", "Image Name", "Tag", "Digest", "Vulnerability ID", "Severity", "CVSS Score", "Package Type", "Package Name", "Installed Version", "Fixed Version", "Description", "Reference URL", "Suppressed", "Suppression Reason", "Suppressed By", "Suppressed Until", "Fix State"},
+		{fixedTimeStr, "h", "p", "r", "i", "", "", "CVE-TSV", "CRITICAL", "0.0", "", "", "", "", "", "", "", "", "", "", ""},
 	}
 
 	out := &bytes.Buffer{}
@@ -214,6 +241,50 @@ func TestTableExporter_Export_TSV(t *testing.T) {
 	}
 }
 
+// TestTableExporter_Stream verifies that streaming rows produces the same table as a single Export call.
+func TestTableExporter_Stream(t *testing.T) {
+	fixedTime := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	results := []schemas.AnalyzeResult{
+		{
+			Artifact: schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "a"},
+			ScanTime: fixedTime,
+			Vulnerabilities: []schemas.Vulnerability{
+				{ID: "CVE-1", Severity: schemas.SeverityLow},
+				{ID: "CVE-2", Severity: schemas.SeverityHigh},
+			},
+		},
+		{
+			Artifact:        schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "b"},
+			ScanTime:        fixedTime,
+			Vulnerabilities: []schemas.Vulnerability{{ID: "CVE-3", Severity: schemas.SeverityCritical}},
+		},
+	}
+	ctx := context.Background()
+
+	want := &bytes.Buffer{}
+	if err := exporter.NewCSVExporter(want).Export(ctx, results); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	got := &bytes.Buffer{}
+	e := exporter.NewCSVExporter(got)
+	if err := e.Begin(ctx); err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	for _, r := range results {
+		if err := e.ExportOne(ctx, r); err != nil {
+			t.Fatalf("ExportOne() error = %v", err)
+		}
+	}
+	if err := e.End(ctx); err != nil {
+		t.Fatalf("End() error = %v", err)
+	}
+
+	if diff := cmp.Diff(want.String(), got.String()); diff != "" {
+		t.Errorf("streamed output mismatch (-want +got):\n%s", diff)
+	}
+}
+
 // parseTable is a helper to read CSV/TSV bytes back into a 2D slice
 func parseTable(t *testing.T, data []byte, comma rune) [][]string {
 	t.Helper()
@@ -0,0 +1,102 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// Exporter outputs analysis results; it is the drydock.Exporter interface, which all exporters implement.
+type Exporter interface {
+	Export(ctx context.Context, results []schemas.AnalyzeResult) error
+}
+
+// streamExporter is the drydock.StreamExporter interface.
+type streamExporter interface {
+	Exporter
+	Begin(ctx context.Context) error
+	ExportOne(ctx context.Context, result schemas.AnalyzeResult) error
+	End(ctx context.Context) error
+}
+
+// Tee exports the same results to several exporters, e.g. to stdout and to a file.
+// A failing exporter does not stop the others: the errors are returned together, and the
+// exporter is skipped until the next Export or Begin.
+// Tee streams results to the exporters that support streaming, and buffers them for the others until End.
+type Tee struct {
+	// Concurrency is the number of exporters written to at once, e.g. to upload to several slow
+	// destinations in parallel (default: one at a time). Each exporter still sees results in order.
+	Concurrency int
+
+	exporters []Exporter
+
+	// failed marks the exporters that failed since Begin
+	failed []bool
+
+	// buffered are the results streamed since Begin, if an exporter does not support streaming
+	buffered []schemas.AnalyzeResult
+}
+
+// NewTee creates a Tee exporting to all the given exporters
+func NewTee(exporters ...Exporter) *Tee {
+	return &Tee{
+		exporters: exporters,
+	}
+}
+
+// Export exports the results to every exporter
+func (t *Tee) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
+	return errors.Join(forEach(len(t.exporters), t.Concurrency, func(i int) error {
+		return t.exporters[i].Export(ctx, results)
+	})...)
+}
+
+// Begin begins streaming on the exporters that support it
+func (t *Tee) Begin(ctx context.Context) error {
+	t.failed = make([]bool, len(t.exporters))
+	t.buffered = nil
+	return t.each(func(e streamExporter) error { return e.Begin(ctx) })
+}
+
+// ExportOne streams the result to the exporters that support streaming, and buffers it for the others
+func (t *Tee) ExportOne(ctx context.Context, result schemas.AnalyzeResult) error {
+	for _, e := range t.exporters {
+		if _, ok := e.(streamExporter); !ok {
+			t.buffered = append(t.buffered, result)
+			break
+		}
+	}
+	return t.each(func(e streamExporter) error { return e.ExportOne(ctx, result) })
+}
+
+// End ends streaming, and exports the buffered results to the exporters that do not support streaming
+func (t *Tee) End(ctx context.Context) error {
+	err := t.each(func(e streamExporter) error { return e.End(ctx) })
+	errs := forEach(len(t.exporters), t.Concurrency, func(i int) error {
+		if _, ok := t.exporters[i].(streamExporter); ok || t.failed[i] {
+			return nil
+		}
+		return t.exporters[i].Export(ctx, t.buffered)
+	})
+	t.buffered = nil
+	return errors.Join(append([]error{err}, errs...)...)
+}
+
+// each calls fn on the streaming exporters that did not fail yet.
+func (t *Tee) each(fn func(e streamExporter) error) error {
+	if len(t.failed) != len(t.exporters) {
+		t.failed = make([]bool, len(t.exporters))
+	}
+	return errors.Join(forEach(len(t.exporters), t.Concurrency, func(i int) error {
+		s, ok := t.exporters[i].(streamExporter)
+		if !ok || t.failed[i] {
+			return nil
+		}
+		err := fn(s)
+		if err != nil {
+			t.failed[i] = true
+		}
+		return err
+	})...)
+}
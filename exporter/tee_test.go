@@ -0,0 +1,96 @@
+package exporter_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// batchExporter records the results of each Export call; it does not support streaming.
+type batchExporter struct {
+	calls [][]string
+	err   error
+}
+
+func (e *batchExporter) Export(_ context.Context, results []schemas.AnalyzeResult) error {
+	var images []string
+	for _, r := range results {
+		images = append(images, r.Artifact.ImageName)
+	}
+	e.calls = append(e.calls, images)
+	return e.err
+}
+
+func TestTee(t *testing.T) {
+	results := []schemas.AnalyzeResult{
+		{Artifact: schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "api"}},
+		{Artifact: schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "web"}},
+	}
+	export := func(ctx context.Context, tee *exporter.Tee) error {
+		return tee.Export(ctx, results)
+	}
+	stream := func(ctx context.Context, tee *exporter.Tee) error {
+		errs := []error{tee.Begin(ctx)}
+		for _, r := range results {
+			errs = append(errs, tee.ExportOne(ctx, r))
+		}
+		return errors.Join(append(errs, tee.End(ctx))...)
+	}
+
+	tests := map[string]struct {
+		run         func(context.Context, *exporter.Tee) error
+		concurrency int
+		batchErr    error
+		wantCalls   [][]string
+	}{
+		"should export to every exporter": {
+			run:       export,
+			wantCalls: [][]string{{"api", "web"}},
+		},
+		"should buffer streamed results for exporters without streaming": {
+			run:       stream,
+			wantCalls: [][]string{{"api", "web"}},
+		},
+		"should keep exporting to the others when one fails": {
+			run:       stream,
+			batchErr:  errors.New("disk full"),
+			wantCalls: [][]string{{"api", "web"}},
+		},
+		"should export to the exporters concurrently": {
+			run:         export,
+			concurrency: 3,
+			wantCalls:   [][]string{{"api", "web"}},
+		},
+		"should stream to the exporters concurrently": {
+			run:         stream,
+			concurrency: 3,
+			batchErr:    errors.New("disk full"),
+			wantCalls:   [][]string{{"api", "web"}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var first, second bytes.Buffer
+			batch := &batchExporter{err: tt.batchErr}
+			tee := exporter.NewTee(exporter.NewCSVExporter(&first), batch, exporter.NewCSVExporter(&second))
+			tee.Concurrency = tt.concurrency
+
+			err := tt.run(context.Background(), tee)
+			if !errors.Is(err, tt.batchErr) || (tt.batchErr == nil && err != nil) {
+				t.Fatalf("error = %v, want %v", err, tt.batchErr)
+			}
+			if first.Len() == 0 || first.String() != second.String() {
+				t.Errorf("outputs differ:\n%s\n---\n%s", first.String(), second.String())
+			}
+			if diff := cmp.Diff(tt.wantCalls, batch.calls); diff != "" {
+				t.Errorf("batch exporter calls mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -3,19 +3,108 @@ package drydock
 import (
 	"fmt"
 	"io"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/hiro-o918/drydock/exporter"
 )
 
+// Built-in exporters support bounded-memory streaming.
+var (
+	_ StreamExporter = (*exporter.JSONExporter)(nil)
+	_ StreamExporter = (*exporter.TableExporter)(nil)
+	_ StreamExporter = (*exporter.InTotoExporter)(nil)
+	_ StreamExporter = (*exporter.RemediationExporter)(nil)
+	_ StreamExporter = (*exporter.MarkdownExporter)(nil)
+	_ StreamExporter = (*exporter.BadgeExporter)(nil)
+	_ StreamExporter = (*exporter.AtomExporter)(nil)
+	_ StreamExporter = (*exporter.BackstageExporter)(nil)
+	_ StreamExporter = (*exporter.SARIFExporter)(nil)
+	_ StreamExporter = (*exporter.CycloneDXExporter)(nil)
+	_ StreamExporter = (*exporter.Tee)(nil)
+)
+
+// FormatFactory creates an Exporter that writes to the given writer.
+type FormatFactory func(w io.Writer) Exporter
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[OutputFormat]FormatFactory{
+		OutputFormatJSON:         func(w io.Writer) Exporter { return exporter.NewJSONExporter(w) },
+		OutputFormatCSV:          func(w io.Writer) Exporter { return exporter.NewCSVExporter(w) },
+		OutputFormatTSV:          func(w io.Writer) Exporter { return exporter.NewTSVExporter(w) },
+		OutputFormatMarkdown:     func(w io.Writer) Exporter { return exporter.NewMarkdownExporter(w) },
+		OutputFormatInToto:       func(w io.Writer) Exporter { return exporter.NewInTotoExporter(w) },
+		OutputFormatRemediations: func(w io.Writer) Exporter { return exporter.NewRemediationExporter(w) },
+		OutputFormatBadge:        func(w io.Writer) Exporter { return exporter.NewBadgeExporter(w, "") },
+		OutputFormatAtom:         func(w io.Writer) Exporter { return exporter.NewAtomExporter(w) },
+		OutputFormatBackstage:    func(w io.Writer) Exporter { return exporter.NewBackstageExporter(w, nil) },
+		OutputFormatSARIF:        func(w io.Writer) Exporter { return exporter.NewSARIFExporter(w) },
+		OutputFormatCycloneDX:    func(w io.Writer) Exporter { return exporter.NewCycloneDXExporter(w) },
+		OutputFormatGitHub:       func(w io.Writer) Exporter { return exporter.NewGitHubActionsExporter(w) },
+	}
+)
+
+// RegisterFormat makes an output format available to NewExporter, WithOutputFormat
+// and OutputFormat.Set (and therefore the CLI's --output-format flag).
+// Names are case-insensitive. Registering an existing name replaces its factory.
+// It panics if name is empty or factory is nil.
+func RegisterFormat(name string, factory func(io.Writer) Exporter) {
+	format := normalizeFormat(name)
+	if format == "" {
+		panic("drydock: RegisterFormat called with empty name")
+	}
+	if factory == nil {
+		panic("drydock: RegisterFormat factory is nil for " + name)
+	}
+
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats[format] = factory
+}
+
+// RegisteredFormats returns the names of all available output formats, sorted.
+func RegisteredFormats() []OutputFormat {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	names := make([]OutputFormat, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// lookupFormat returns the factory registered for format.
+func lookupFormat(format OutputFormat) (FormatFactory, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	factory, ok := formats[format]
+	return factory, ok
+}
+
+// normalizeFormat converts user input into the canonical format name.
+func normalizeFormat(name string) OutputFormat {
+	return OutputFormat(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// formatList renders the registered formats for messages, e.g. "csv, json, tsv".
+func formatList() string {
+	names := RegisteredFormats()
+	parts := make([]string, len(names))
+	for i, n := range names {
+		parts[i] = string(n)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// NewExporter creates an exporter for a registered output format.
 func NewExporter(format OutputFormat, writer io.Writer) (Exporter, error) {
-	switch format {
-	case OutputFormatJSON:
-		return exporter.NewJSONExporter(writer), nil
-	case OutputFormatCSV:
-		return exporter.NewCSVExporter(writer), nil
-	case OutputFormatTSV:
-		return exporter.NewTSVExporter(writer), nil
-	default:
-		return nil, fmt.Errorf("unsupported output format: %s", format)
+	factory, ok := lookupFormat(normalizeFormat(string(format)))
+	if !ok {
+		return nil, fmt.Errorf("unsupported output format: %s (allowed: %s)", format, formatList())
 	}
+	return factory(writer), nil
 }
@@ -0,0 +1,68 @@
+package drydock_test
+
+import (
+	"context"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// stubExporter is a minimal Exporter used to verify format registration.
+type stubExporter struct {
+	w io.Writer
+}
+
+func (e *stubExporter) Export(ctx context.Context, results []schemas.AnalyzeResult) error {
+	_, err := io.WriteString(e.w, "stub")
+	return err
+}
+
+func TestRegisterFormat(t *testing.T) {
+	drydock.RegisterFormat("Stub-Test", func(w io.Writer) drydock.Exporter {
+		return &stubExporter{w: w}
+	})
+
+	t.Run("should be listed among registered formats", func(t *testing.T) {
+		got := drydock.RegisteredFormats()
+		if !slices.Contains(got, "stub-test") {
+			t.Errorf("RegisteredFormats() = %v, want it to contain stub-test", got)
+		}
+		if !slices.IsSorted(got) {
+			t.Errorf("RegisteredFormats() = %v, want sorted", got)
+		}
+	})
+
+	t.Run("should be accepted by OutputFormat.Set case-insensitively", func(t *testing.T) {
+		var f drydock.OutputFormat
+		if err := f.Set("STUB-TEST"); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		if f != "stub-test" {
+			t.Errorf("Set() = %v, want stub-test", f)
+		}
+	})
+
+	t.Run("should be created by NewExporter", func(t *testing.T) {
+		e, err := drydock.NewExporter("stub-test", io.Discard)
+		if err != nil {
+			t.Fatalf("NewExporter() error = %v", err)
+		}
+		if _, ok := e.(*stubExporter); !ok {
+			t.Errorf("NewExporter() returned %T, want *stubExporter", e)
+		}
+	})
+}
+
+func TestNewExporter_Unsupported(t *testing.T) {
+	if _, err := drydock.NewExporter("xml", io.Discard); err == nil {
+		t.Error("NewExporter() expected error for unsupported format")
+	}
+
+	var f drydock.OutputFormat
+	if err := f.Set("xml"); err == nil {
+		t.Error("Set() expected error for unsupported format")
+	}
+}
@@ -0,0 +1,98 @@
+package drydock
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// VulnerabilityFilter keeps only the vulnerabilities for which a CEL expression evaluates to true.
+//
+// The expression can reference two variables, whose fields use the same names as the JSON output:
+//   - vuln:  the vulnerability (e.g., vuln.id, vuln.severity, vuln.cvssScore, vuln.packageName)
+//   - image: the analyzed artifact (e.g., image.repositoryID, image.imageName, image.uri)
+//
+// Optional fields that are absent (such as image.tag) must be guarded with has(), e.g. has(image.tag).
+type VulnerabilityFilter struct {
+	expr    string
+	program cel.Program
+}
+
+// NewVulnerabilityFilter compiles a CEL expression into a VulnerabilityFilter.
+// The expression must evaluate to a bool.
+func NewVulnerabilityFilter(expr string) (*VulnerabilityFilter, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("vuln", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("image", cel.MapType(cel.StringType, cel.DynType)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	ast, issues := env.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid filter expression: %w", issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("filter expression must evaluate to bool, got %s", ast.OutputType())
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build filter program: %w", err)
+	}
+
+	return &VulnerabilityFilter{expr: expr, program: program}, nil
+}
+
+// String returns the source expression.
+func (f *VulnerabilityFilter) String() string {
+	return f.expr
+}
+
+// Filter returns the vulnerabilities of the artifact that match the expression.
+func (f *VulnerabilityFilter) Filter(artifact schemas.ArtifactReference, vulns []schemas.Vulnerability) ([]schemas.Vulnerability, error) {
+	image, err := toCELValue(artifact)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]schemas.Vulnerability, 0)
+	for _, v := range vulns {
+		vuln, err := toCELValue(v)
+		if err != nil {
+			return nil, err
+		}
+
+		out, _, err := f.program.Eval(map[string]any{
+			"vuln":  vuln,
+			"image": image,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate filter on %s: %w", v.ID, err)
+		}
+		match, ok := out.Value().(bool)
+		if !ok {
+			return nil, fmt.Errorf("filter expression returned %T instead of bool on %s", out.Value(), v.ID)
+		}
+		if match {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered, nil
+}
+
+// toCELValue converts a value into a map keyed by its JSON field names.
+func toCELValue(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode filter input: %w", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to decode filter input: %w", err)
+	}
+	return m, nil
+}
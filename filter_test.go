@@ -0,0 +1,109 @@
+package drydock_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestVulnerabilityFilter_Filter(t *testing.T) {
+	artifact := schemas.ArtifactReference{
+		Host:         "us-central1-docker.pkg.dev",
+		ProjectID:    "my-project",
+		RepositoryID: "prod",
+		ImageName:    "api",
+		Digest:       utils.Ptr("sha256:abc123"),
+	}
+	vulns := []schemas.Vulnerability{
+		{ID: "CVE-1", Severity: schemas.SeverityCritical, CVSSScore: 9.8, PackageName: "openssl", FixedVersion: "1.1.1t"},
+		{ID: "CVE-2", Severity: schemas.SeverityHigh, CVSSScore: 7.5, PackageName: "glibc"},
+		{ID: "CVE-3", Severity: schemas.SeverityMedium, CVSSScore: 5.0, PackageName: "openssl", FixedVersion: "1.1.1u"},
+	}
+
+	tests := map[string]struct {
+		expr    string
+		want    []string
+		wantErr bool
+	}{
+		"should filter by CVSS score and fixability": {
+			expr: `vuln.cvssScore >= 7.0 && has(vuln.fixedVersion)`,
+			want: []string{"CVE-1"},
+		},
+		"should filter by package name": {
+			expr: `vuln.packageName == "openssl"`,
+			want: []string{"CVE-1", "CVE-3"},
+		},
+		"should filter by image attributes": {
+			expr: `image.repositoryID == "prod" && vuln.severity in ["CRITICAL", "HIGH"]`,
+			want: []string{"CVE-1", "CVE-2"},
+		},
+		"should return empty list when nothing matches": {
+			expr: `image.imageName == "other"`,
+			want: []string{},
+		},
+		"should fail when an absent field is accessed without has()": {
+			expr:    `image.tag == "latest"`,
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			filter, err := drydock.NewVulnerabilityFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("NewVulnerabilityFilter() error = %v", err)
+			}
+
+			got, err := filter.Filter(artifact, vulns)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Filter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			ids := make([]string, 0, len(got))
+			for _, v := range got {
+				ids = append(ids, v.ID)
+			}
+			if diff := cmp.Diff(tt.want, ids); diff != "" {
+				t.Errorf("Filter() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewVulnerabilityFilter(t *testing.T) {
+	tests := map[string]struct {
+		expr    string
+		wantErr bool
+	}{
+		"should compile a boolean expression": {
+			expr: `vuln.severity == "CRITICAL"`,
+		},
+		"should reject syntax errors": {
+			expr:    `vuln.severity ==`,
+			wantErr: true,
+		},
+		"should reject non-boolean expressions": {
+			expr:    `"CRITICAL"`,
+			wantErr: true,
+		},
+		"should reject unknown variables": {
+			expr:    `package.name == "openssl"`,
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := drydock.NewVulnerabilityFilter(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewVulnerabilityFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
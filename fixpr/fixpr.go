@@ -0,0 +1,181 @@
+// Package fixpr opens pull requests fixing the vulnerabilities found by a scan:
+// it bumps the base image and the package pins of the Dockerfile an image is built from.
+package fixpr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// Source locates the Dockerfile an image is built from.
+type Source struct {
+	// Repository is the GitHub repository, e.g. "my-org/my-service"
+	Repository string `json:"repository"`
+
+	// Dockerfile is the path of the Dockerfile in the repository (default: "Dockerfile")
+	Dockerfile string `json:"dockerfile,omitempty"`
+
+	// Branch is the branch to open pull requests against (default: the repository's default branch)
+	Branch string `json:"branch,omitempty"`
+}
+
+// Mapping maps images, as "host/project/repository/image" without tag or digest, to their sources.
+type Mapping map[string]Source
+
+// ReadMapping reads a JSON mapping, e.g. {"us-docker.pkg.dev/p/r/api": {"repository": "org/api"}}.
+func ReadMapping(r io.Reader) (Mapping, error) {
+	var m Mapping
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("invalid mapping: %w", err)
+	}
+	for image, src := range m {
+		if src.Repository == "" {
+			return nil, fmt.Errorf("invalid mapping: %s has no repository", image)
+		}
+		if src.Dockerfile == "" {
+			src.Dockerfile = "Dockerfile"
+			m[image] = src
+		}
+	}
+	return m, nil
+}
+
+// Fix is a pull request to open for one image.
+type Fix struct {
+	// Result is the scan result of the image
+	Result schemas.AnalyzeResult
+
+	// Source is where the image is built from
+	Source Source
+}
+
+// Image returns the image name the fix is for, as used in Mapping.
+func (f Fix) Image() string {
+	return imageName(f.Result.Artifact)
+}
+
+// Plan selects the results that need a fix: mapped images with a fixable finding at or above minSeverity,
+// that also have something to change (a remediation or a recommended base image).
+func Plan(results []schemas.AnalyzeResult, mapping Mapping, minSeverity schemas.Severity) []Fix {
+	var fixes []Fix
+	for _, r := range results {
+		src, ok := mapping[imageName(r.Artifact)]
+		if !ok || !hasFixable(r.Vulnerabilities, minSeverity) {
+			continue
+		}
+		if len(r.Remediations) == 0 && (r.BaseImage == nil || r.BaseImage.Recommended == "") {
+			continue
+		}
+		fixes = append(fixes, Fix{Result: r, Source: src})
+	}
+	slices.SortFunc(fixes, func(a, b Fix) int { return strings.Compare(a.Image(), b.Image()) })
+	return fixes
+}
+
+// hasFixable reports whether a fixable vulnerability is at least as severe as minSeverity.
+func hasFixable(vulns []schemas.Vulnerability, minSeverity schemas.Severity) bool {
+	return slices.ContainsFunc(vulns, func(v schemas.Vulnerability) bool {
+		return v.FixState == schemas.FixStateFixAvailable && v.Severity.AtLeast(minSeverity)
+	})
+}
+
+// imageName returns "host/project/repository/image" of an artifact.
+func imageName(a schemas.ArtifactReference) string {
+	return a.Host + "/" + a.ProjectID + "/" + a.RepositoryID + "/" + a.ImageName
+}
+
+// fromLine matches a FROM instruction, capturing the image reference.
+var fromLine = regexp.MustCompile(`(?im)^(\s*FROM\s+(?:--platform=\S+\s+)?)(\S+)`)
+
+// Rewrite applies the fix to the content of a Dockerfile: the FROM lines using the current base image
+// are moved to the recommended one, and package pins ("name=version") are bumped to the fixed versions.
+// It returns the new content and a description of each change.
+func (f Fix) Rewrite(dockerfile string) (string, []string) {
+	var changes []string
+
+	if advice := f.Result.BaseImage; advice != nil && advice.Recommended != "" {
+		current := stripVersion(advice.Current)
+		dockerfile = fromLine.ReplaceAllStringFunc(dockerfile, func(line string) string {
+			m := fromLine.FindStringSubmatch(line)
+			if stripVersion(m[2]) != current || m[2] == advice.Recommended {
+				return line
+			}
+			changes = append(changes, fmt.Sprintf("base image %s -> %s", m[2], advice.Recommended))
+			return m[1] + advice.Recommended
+		})
+	}
+
+	for _, r := range f.Result.Remediations {
+		pin := regexp.MustCompile(`(^|[\s\\])` + regexp.QuoteMeta(r.PackageName) + `=([^\s\\]+)`)
+		dockerfile = pin.ReplaceAllStringFunc(dockerfile, func(match string) string {
+			m := pin.FindStringSubmatch(match)
+			if m[2] == r.FixedVersion {
+				return match
+			}
+			changes = append(changes, fmt.Sprintf("%s %s -> %s", r.PackageName, m[2], r.FixedVersion))
+			return m[1] + r.PackageName + "=" + r.FixedVersion
+		})
+	}
+	return dockerfile, changes
+}
+
+// stripVersion removes the tag and digest of an image reference.
+func stripVersion(ref string) string {
+	ref, _, _ = strings.Cut(ref, "@")
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref = ref[:i]
+	}
+	return ref
+}
+
+// Title returns the pull request title.
+func (f Fix) Title() string {
+	return fmt.Sprintf("Fix vulnerabilities in %s", f.Result.Artifact.ImageName)
+}
+
+// Body returns the pull request body: the changes and an excerpt of the scan.
+func (f Fix) Body(changes []string, minSeverity schemas.Severity) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Drydock found fixable vulnerabilities in `%s`.\n\n", f.Result.Artifact)
+	b.WriteString("### Changes\n\n")
+	for _, c := range changes {
+		fmt.Fprintf(&b, "- %s\n", c)
+	}
+	if advice := f.Result.BaseImage; advice != nil && advice.Recommended != "" {
+		fmt.Fprintf(&b, "\n%s.\n", advice.Message)
+	}
+
+	b.WriteString("\n### Findings\n\n")
+	b.WriteString("| ID | Severity | Package | Installed | Fixed |\n")
+	b.WriteString("|----|----------|---------|-----------|-------|\n")
+	for _, v := range f.Result.Vulnerabilities {
+		if v.FixState != schemas.FixStateFixAvailable || !v.Severity.AtLeast(minSeverity) {
+			continue
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", v.ID, v.Severity, v.PackageName, v.InstalledVersion, v.FixedVersion)
+	}
+	if !f.Result.ScanTime.IsZero() {
+		fmt.Fprintf(&b, "\nScanned at %s.\n", f.Result.ScanTime.UTC().Format("2006-01-02 15:04 MST"))
+	}
+	return b.String()
+}
+
+// Branch returns the name of the branch the fix is pushed to.
+// It depends on the image digest, so a new scan of the same image reuses the branch.
+func (f Fix) Branch() string {
+	name := strings.NewReplacer("/", "-", ":", "-").Replace(f.Result.Artifact.ImageName)
+	if d := f.Result.Artifact.Digest; d != nil {
+		_, hex, _ := strings.Cut(*d, ":")
+		if len(hex) > 12 {
+			hex = hex[:12]
+		}
+		name += "-" + hex
+	}
+	return "drydock/fix-" + name
+}
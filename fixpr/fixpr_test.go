@@ -0,0 +1,168 @@
+package fixpr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/fixpr"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+var (
+	apiArtifact = schemas.ArtifactReference{
+		Host: "us-docker.pkg.dev", ProjectID: "p", RepositoryID: "r", ImageName: "api",
+		Digest: utils.Ptr("sha256:0123456789abcdef0123"),
+	}
+	criticalFix = schemas.Vulnerability{
+		ID: "CVE-1", Severity: schemas.SeverityCritical, PackageName: "openssl",
+		InstalledVersion: "1.1.1n", FixedVersion: "1.1.1t", FixState: schemas.FixStateFixAvailable,
+	}
+	opensslRemediation = schemas.Remediation{
+		PackageManager: "apt", PackageName: "openssl", InstalledVersion: "1.1.1n", FixedVersion: "1.1.1t",
+		Command: "apt-get install --only-upgrade openssl=1.1.1t", VulnerabilityIDs: []string{"CVE-1"},
+	}
+)
+
+func TestReadMapping(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    fixpr.Mapping
+		wantErr bool
+	}{
+		"should default the Dockerfile path": {
+			input: `{"us-docker.pkg.dev/p/r/api": {"repository": "org/api"}}`,
+			want:  fixpr.Mapping{"us-docker.pkg.dev/p/r/api": {Repository: "org/api", Dockerfile: "Dockerfile"}},
+		},
+		"should require a repository": {
+			input:   `{"us-docker.pkg.dev/p/r/api": {"dockerfile": "Dockerfile"}}`,
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := fixpr.ReadMapping(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadMapping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ReadMapping() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPlan(t *testing.T) {
+	mapping := fixpr.Mapping{"us-docker.pkg.dev/p/r/api": {Repository: "org/api", Dockerfile: "Dockerfile"}}
+	high := criticalFix
+	high.Severity = schemas.SeverityHigh
+
+	tests := map[string]struct {
+		result schemas.AnalyzeResult
+		want   int
+	}{
+		"should fix mapped images with a fixable critical finding": {
+			result: schemas.AnalyzeResult{Artifact: apiArtifact, Vulnerabilities: []schemas.Vulnerability{criticalFix}, Remediations: []schemas.Remediation{opensslRemediation}},
+			want:   1,
+		},
+		"should skip findings below the threshold": {
+			result: schemas.AnalyzeResult{Artifact: apiArtifact, Vulnerabilities: []schemas.Vulnerability{high}, Remediations: []schemas.Remediation{opensslRemediation}},
+			want:   0,
+		},
+		"should skip results without anything to change": {
+			result: schemas.AnalyzeResult{Artifact: apiArtifact, Vulnerabilities: []schemas.Vulnerability{criticalFix}},
+			want:   0,
+		},
+		"should skip unmapped images": {
+			result: schemas.AnalyzeResult{
+				Artifact:        schemas.ArtifactReference{Host: "us-docker.pkg.dev", ProjectID: "p", RepositoryID: "r", ImageName: "web"},
+				Vulnerabilities: []schemas.Vulnerability{criticalFix}, Remediations: []schemas.Remediation{opensslRemediation},
+			},
+			want: 0,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := fixpr.Plan([]schemas.AnalyzeResult{tt.result}, mapping, schemas.SeverityCritical)
+			if len(got) != tt.want {
+				t.Errorf("Plan() returned %d fixes, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestFix_Rewrite(t *testing.T) {
+	dockerfile := strings.Join([]string{
+		"FROM us-docker.pkg.dev/p/base/debian:11@sha256:old AS build",
+		"RUN apt-get update && apt-get install -y \\",
+		"    openssl=1.1.1n \\",
+		"    curl=7.74.0",
+		"FROM --platform=linux/amd64 us-docker.pkg.dev/p/base/debian:11",
+		"FROM golang:1.22",
+		"",
+	}, "\n")
+
+	tests := map[string]struct {
+		result      schemas.AnalyzeResult
+		want        string
+		wantChanges []string
+	}{
+		"should bump the base image and package pins": {
+			result: schemas.AnalyzeResult{
+				Artifact:     apiArtifact,
+				Remediations: []schemas.Remediation{opensslRemediation},
+				BaseImage: &schemas.BaseImageAdvice{
+					Current:     "us-docker.pkg.dev/p/base/debian@sha256:old",
+					Recommended: "us-docker.pkg.dev/p/base/debian:12@sha256:new",
+				},
+			},
+			want: strings.Join([]string{
+				"FROM us-docker.pkg.dev/p/base/debian:12@sha256:new AS build",
+				"RUN apt-get update && apt-get install -y \\",
+				"    openssl=1.1.1t \\",
+				"    curl=7.74.0",
+				"FROM --platform=linux/amd64 us-docker.pkg.dev/p/base/debian:12@sha256:new",
+				"FROM golang:1.22",
+				"",
+			}, "\n"),
+			wantChanges: []string{
+				"base image us-docker.pkg.dev/p/base/debian:11@sha256:old -> us-docker.pkg.dev/p/base/debian:12@sha256:new",
+				"base image us-docker.pkg.dev/p/base/debian:11 -> us-docker.pkg.dev/p/base/debian:12@sha256:new",
+				"openssl 1.1.1n -> 1.1.1t",
+			},
+		},
+		"should leave unpinned Dockerfiles alone": {
+			result: schemas.AnalyzeResult{
+				Artifact: apiArtifact,
+				Remediations: []schemas.Remediation{{
+					PackageName: "zlib", FixedVersion: "1.2.13", VulnerabilityIDs: []string{"CVE-2"},
+				}},
+			},
+			want: dockerfile,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, changes := fixpr.Fix{Result: tt.result}.Rewrite(dockerfile)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Rewrite() content mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantChanges, changes); diff != "" {
+				t.Errorf("Rewrite() changes mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFix_Branch(t *testing.T) {
+	fix := fixpr.Fix{Result: schemas.AnalyzeResult{Artifact: schemas.ArtifactReference{
+		ImageName: "team/api", Digest: utils.Ptr("sha256:0123456789abcdef0123"),
+	}}}
+	if got, want := fix.Branch(), "drydock/fix-team-api-0123456789ab"; got != want {
+		t.Errorf("Branch() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,153 @@
+package fixpr
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hiro-o918/drydock/internal/githubapi"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// DefaultGitHubURL is the GitHub REST API endpoint.
+const DefaultGitHubURL = githubapi.DefaultURL
+
+// ErrNothingToChange is returned when the Dockerfile does not pin anything the fix would change.
+var ErrNothingToChange = errors.New("nothing to change in the Dockerfile")
+
+// GitHub opens fix pull requests through the GitHub REST API.
+type GitHub struct {
+	// BaseURL is the API endpoint (default: DefaultGitHubURL; set it for GitHub Enterprise)
+	BaseURL string
+
+	// Token is a token allowed to push branches and open pull requests
+	Token string
+
+	// HTTPClient is the client used for requests (default: http.DefaultClient)
+	HTTPClient *http.Client
+}
+
+// PullRequest is an opened (or already open) pull request.
+type PullRequest struct {
+	// URL is the web URL of the pull request
+	URL string
+
+	// Existing is true when the pull request was already open for the same image digest
+	Existing bool
+
+	// Changes describes the changes made to the Dockerfile
+	Changes []string
+}
+
+// Open pushes the fix to a new branch and opens a pull request with the scan excerpt.
+// If the branch already exists with an open pull request, that pull request is returned.
+func (g *GitHub) Open(ctx context.Context, fix Fix, minSeverity schemas.Severity) (PullRequest, error) {
+	repo := fix.Source.Repository
+
+	base := fix.Source.Branch
+	if base == "" {
+		var info struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if err := g.do(ctx, http.MethodGet, "/repos/"+repo, nil, &info); err != nil {
+			return PullRequest{}, err
+		}
+		base = info.DefaultBranch
+	}
+
+	var file struct {
+		SHA     string `json:"sha"`
+		Content string `json:"content"`
+	}
+	contentPath := "/repos/" + repo + "/contents/" + fix.Source.Dockerfile
+	if err := g.do(ctx, http.MethodGet, contentPath+"?ref="+url.QueryEscape(base), nil, &file); err != nil {
+		return PullRequest{}, err
+	}
+	content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(file.Content, "\n", ""))
+	if err != nil {
+		return PullRequest{}, fmt.Errorf("failed to decode %s: %w", fix.Source.Dockerfile, err)
+	}
+
+	updated, changes := fix.Rewrite(string(content))
+	if len(changes) == 0 {
+		return PullRequest{}, ErrNothingToChange
+	}
+
+	var ref struct {
+		Object struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	if err := g.do(ctx, http.MethodGet, "/repos/"+repo+"/git/ref/heads/"+base, nil, &ref); err != nil {
+		return PullRequest{}, err
+	}
+
+	branch := fix.Branch()
+	err = g.do(ctx, http.MethodPost, "/repos/"+repo+"/git/refs", map[string]string{
+		"ref": "refs/heads/" + branch,
+		"sha": ref.Object.SHA,
+	}, nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity {
+		// The branch exists: the fix was already proposed for this digest
+		if pr, ok, err := g.findOpen(ctx, repo, branch); err != nil || ok {
+			pr.Changes = changes
+			return pr, err
+		}
+		return PullRequest{}, fmt.Errorf("branch %s already exists without an open pull request", branch)
+	}
+	if err != nil {
+		return PullRequest{}, err
+	}
+
+	if err := g.do(ctx, http.MethodPut, contentPath, map[string]string{
+		"message": fix.Title(),
+		"content": base64.StdEncoding.EncodeToString([]byte(updated)),
+		"sha":     file.SHA,
+		"branch":  branch,
+	}, nil); err != nil {
+		return PullRequest{}, err
+	}
+
+	var pr struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := g.do(ctx, http.MethodPost, "/repos/"+repo+"/pulls", map[string]string{
+		"title": fix.Title(),
+		"head":  branch,
+		"base":  base,
+		"body":  fix.Body(changes, minSeverity),
+	}, &pr); err != nil {
+		return PullRequest{}, err
+	}
+	return PullRequest{URL: pr.HTMLURL, Changes: changes}, nil
+}
+
+// findOpen returns the open pull request from branch, if any.
+func (g *GitHub) findOpen(ctx context.Context, repo, branch string) (PullRequest, bool, error) {
+	owner, _, _ := strings.Cut(repo, "/")
+	var prs []struct {
+		HTMLURL string `json:"html_url"`
+	}
+	query := url.Values{"head": {owner + ":" + branch}, "state": {"open"}}
+	if err := g.do(ctx, http.MethodGet, "/repos/"+repo+"/pulls?"+query.Encode(), nil, &prs); err != nil {
+		return PullRequest{}, false, err
+	}
+	if len(prs) == 0 {
+		return PullRequest{}, false, nil
+	}
+	return PullRequest{URL: prs[0].HTMLURL, Existing: true}, true, nil
+}
+
+// APIError is an error response of the GitHub API.
+type APIError = githubapi.Error
+
+// do sends a request to the GitHub API (see githubapi.Client.Do).
+func (g *GitHub) do(ctx context.Context, method, path string, body, out any) error {
+	client := &githubapi.Client{BaseURL: g.BaseURL, Token: g.Token, HTTPClient: g.HTTPClient}
+	return client.Do(ctx, method, path, body, out)
+}
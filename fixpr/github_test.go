@@ -0,0 +1,144 @@
+package fixpr_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/fixpr"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// fakeGitHub serves the GitHub API calls made by GitHub.Open, recording the requests.
+type fakeGitHub struct {
+	dockerfile  string
+	branchTaken bool
+	openPRs     []map[string]string
+	requests    []string
+	updated     string
+	prBody      string
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var body map[string]string
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	switch r.Method + " " + r.URL.Path {
+	case "GET /repos/org/api":
+		_ = json.NewEncoder(w).Encode(map[string]string{"default_branch": "main"})
+	case "GET /repos/org/api/contents/Dockerfile":
+		_ = json.NewEncoder(w).Encode(map[string]string{"sha": "file-sha", "content": base64.StdEncoding.EncodeToString([]byte(f.dockerfile))})
+	case "GET /repos/org/api/git/ref/heads/main":
+		_ = json.NewEncoder(w).Encode(map[string]any{"object": map[string]string{"sha": "head-sha"}})
+	case "POST /repos/org/api/git/refs":
+		if f.branchTaken {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": "Reference already exists"})
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	case "PUT /repos/org/api/contents/Dockerfile":
+		content, _ := base64.StdEncoding.DecodeString(body["content"])
+		f.updated = string(content)
+	case "GET /repos/org/api/pulls":
+		_ = json.NewEncoder(w).Encode(f.openPRs)
+	case "POST /repos/org/api/pulls":
+		f.prBody = body["body"]
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{"html_url": "https://github.com/org/api/pull/1"})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestGitHub_Open(t *testing.T) {
+	fix := fixpr.Fix{
+		Result: schemas.AnalyzeResult{
+			Artifact:        apiArtifact,
+			Vulnerabilities: []schemas.Vulnerability{criticalFix},
+			Remediations:    []schemas.Remediation{opensslRemediation},
+		},
+		Source: fixpr.Source{Repository: "org/api", Dockerfile: "Dockerfile"},
+	}
+
+	tests := map[string]struct {
+		fake         *fakeGitHub
+		want         fixpr.PullRequest
+		wantErr      error
+		wantUpdated  string
+		wantRequests []string
+	}{
+		"should push the fix and open a pull request": {
+			fake:        &fakeGitHub{dockerfile: "FROM debian:11\nRUN apt-get install -y openssl=1.1.1n\n"},
+			want:        fixpr.PullRequest{URL: "https://github.com/org/api/pull/1", Changes: []string{"openssl 1.1.1n -> 1.1.1t"}},
+			wantUpdated: "FROM debian:11\nRUN apt-get install -y openssl=1.1.1t\n",
+			wantRequests: []string{
+				"GET /repos/org/api",
+				"GET /repos/org/api/contents/Dockerfile",
+				"GET /repos/org/api/git/ref/heads/main",
+				"POST /repos/org/api/git/refs",
+				"PUT /repos/org/api/contents/Dockerfile",
+				"POST /repos/org/api/pulls",
+			},
+		},
+		"should return the pull request already open for the digest": {
+			fake: &fakeGitHub{
+				dockerfile:  "RUN apt-get install -y openssl=1.1.1n\n",
+				branchTaken: true,
+				openPRs:     []map[string]string{{"html_url": "https://github.com/org/api/pull/7"}},
+			},
+			want: fixpr.PullRequest{URL: "https://github.com/org/api/pull/7", Existing: true, Changes: []string{"openssl 1.1.1n -> 1.1.1t"}},
+			wantRequests: []string{
+				"GET /repos/org/api",
+				"GET /repos/org/api/contents/Dockerfile",
+				"GET /repos/org/api/git/ref/heads/main",
+				"POST /repos/org/api/git/refs",
+				"GET /repos/org/api/pulls",
+			},
+		},
+		"should not open anything when the Dockerfile pins nothing": {
+			fake:    &fakeGitHub{dockerfile: "FROM debian:11\nRUN apt-get install -y openssl\n"},
+			wantErr: fixpr.ErrNothingToChange,
+			wantRequests: []string{
+				"GET /repos/org/api",
+				"GET /repos/org/api/contents/Dockerfile",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.fake)
+			defer srv.Close()
+
+			gh := &fixpr.GitHub{BaseURL: srv.URL, Token: "token"}
+			got, err := gh.Open(context.Background(), fix, schemas.SeverityCritical)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Open() error = %v, want %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Open() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRequests, tt.fake.requests); diff != "" {
+				t.Errorf("requests mismatch (-want +got):\n%s", diff)
+			}
+			if tt.fake.updated != tt.wantUpdated {
+				t.Errorf("updated Dockerfile = %q, want %q", tt.fake.updated, tt.wantUpdated)
+			}
+			if tt.fake.prBody != "" && !strings.Contains(tt.fake.prBody, "| CVE-1 | CRITICAL | openssl | 1.1.1n | 1.1.1t |") {
+				t.Errorf("pull request body lacks the scan excerpt:\n%s", tt.fake.prBody)
+			}
+		})
+	}
+}
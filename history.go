@@ -0,0 +1,77 @@
+package drydock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// HistoryStore loads and saves the history of findings across scans (see WithHistory).
+type HistoryStore interface {
+	// Load returns the stored history, or an empty one if nothing was stored yet
+	Load(ctx context.Context) (*schemas.History, error)
+
+	// Save replaces the stored history
+	Save(ctx context.Context, history *schemas.History) error
+}
+
+// FileHistoryStore keeps the history in a local JSON file.
+type FileHistoryStore struct {
+	path string
+}
+
+var _ HistoryStore = (*FileHistoryStore)(nil)
+
+// NewFileHistoryStore returns a store keeping the history in the JSON file at path.
+// The file and its parent directories are created on the first Save.
+func NewFileHistoryStore(path string) *FileHistoryStore {
+	return &FileHistoryStore{path: path}
+}
+
+// Load implements HistoryStore.
+func (s *FileHistoryStore) Load(_ context.Context) (*schemas.History, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &schemas.History{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var history schemas.History
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("invalid history file %s: %w", s.path, err)
+	}
+	return &history, nil
+}
+
+// Save implements HistoryStore. The file is replaced atomically, so an interrupted save
+// leaves the previous history intact.
+func (s *FileHistoryStore) Save(_ context.Context, history *schemas.History) error {
+	data, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,54 @@
+package drydock_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+func TestFileHistoryStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "state", "history.json")
+	store := drydock.NewFileHistoryStore(path)
+
+	got, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() of a missing file error = %v", err)
+	}
+	if diff := cmp.Diff(&schemas.History{}, got); diff != "" {
+		t.Errorf("Load() of a missing file mismatch (-want +got):\n%s", diff)
+	}
+
+	seen := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	want := &schemas.History{Images: map[string]map[string]schemas.Vulnerability{
+		"us-docker.pkg.dev/p/r/app:latest": {
+			"CVE-1\x00openssl": {ID: "CVE-1", PackageName: "openssl", Severity: schemas.SeverityHigh, FirstSeen: seen, LastSeen: seen},
+		},
+	}}
+	if err := store.Save(ctx, want); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, err = store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFileHistoryStore_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := drydock.NewFileHistoryStore(path).Load(context.Background()); err == nil {
+		t.Error("Load() error = nil, want an error for an invalid file")
+	}
+}
@@ -0,0 +1,95 @@
+// Package githubapi is the GitHub REST API client shared by the exporter and fix-pr.
+package githubapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultURL is the GitHub REST API endpoint.
+const DefaultURL = "https://api.github.com"
+
+// Client sends requests to the GitHub REST API.
+type Client struct {
+	// BaseURL is the API endpoint (default: DefaultURL; set it for GitHub Enterprise)
+	BaseURL string
+
+	// Token authenticates the requests, if set
+	Token string
+
+	// HTTPClient is the client used for requests (default: http.DefaultClient)
+	HTTPClient *http.Client
+}
+
+// Error is an error response of the GitHub API.
+type Error struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("GitHub API %s %s: %d %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// Do sends a request with a JSON body and decodes the JSON response into out, if not nil.
+// Error responses are returned as an *Error.
+func (c *Client) Do(ctx context.Context, method, path string, body, out any) error {
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(baseURL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("GitHub API %s %s: %w", method, path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		var msg struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&msg)
+		return &Error{Method: method, Path: path, StatusCode: resp.StatusCode, Message: msg.Message}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("GitHub API %s %s: invalid response: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package drydock
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// concurrencyLimiter bounds the number of concurrent analyses.
+// Its limit can be adjusted at runtime (AIMD): it is halved when the API signals
+// quota exhaustion and grows by one after a full window of successful calls,
+// never exceeding the configured maximum.
+type concurrencyLimiter struct {
+	mu        sync.Mutex
+	max       int
+	limit     int
+	inFlight  int
+	successes int
+	// wake is closed (and replaced) whenever a slot may have become available
+	wake chan struct{}
+}
+
+func newConcurrencyLimiter(maxLimit int) *concurrencyLimiter {
+	maxLimit = max(1, maxLimit)
+	return &concurrencyLimiter{
+		max:   maxLimit,
+		limit: maxLimit,
+		wake:  make(chan struct{}),
+	}
+}
+
+// acquire blocks until a slot is available or ctx is done.
+func (l *concurrencyLimiter) acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.inFlight < l.limit {
+			l.inFlight++
+			l.mu.Unlock()
+			return nil
+		}
+		wake := l.wake
+		l.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release frees a slot acquired by acquire.
+func (l *concurrencyLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+	l.broadcastLocked()
+}
+
+// throttled halves the limit (minimum 1). It reports the new limit and whether it changed.
+func (l *concurrencyLimiter) throttled() (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.successes = 0
+	next := max(1, l.limit/2)
+	changed := next != l.limit
+	l.limit = next
+	return l.limit, changed
+}
+
+// succeeded records a successful call and increases the limit by one once
+// as many consecutive successes as the current limit have been observed.
+// It reports the new limit and whether it changed.
+func (l *concurrencyLimiter) succeeded() (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.limit >= l.max {
+		return l.limit, false
+	}
+	l.successes++
+	if l.successes < l.limit {
+		return l.limit, false
+	}
+	l.successes = 0
+	l.limit++
+	l.broadcastLocked()
+	return l.limit, true
+}
+
+// broadcastLocked wakes up all waiters. l.mu must be held.
+func (l *concurrencyLimiter) broadcastLocked() {
+	close(l.wake)
+	l.wake = make(chan struct{})
+}
+
+// isQuotaExceeded reports whether err signals quota exhaustion or rate limiting
+// (gRPC RESOURCE_EXHAUSTED or HTTP 429).
+func isQuotaExceeded(err error) bool {
+	if err == nil {
+		return false
+	}
+	if s, ok := status.FromError(err); ok && s.Code() == codes.ResourceExhausted {
+		return true
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusTooManyRequests {
+		return true
+	}
+	return false
+}
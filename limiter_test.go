@@ -0,0 +1,145 @@
+package drydock_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConcurrencyLimiter_Adjustments(t *testing.T) {
+	type step struct {
+		throttled   bool
+		wantLimit   int
+		wantChanged bool
+	}
+
+	tests := map[string]struct {
+		max   int
+		steps []step
+	}{
+		"should halve the limit down to one when throttled repeatedly": {
+			max: 8,
+			steps: []step{
+				{throttled: true, wantLimit: 4, wantChanged: true},
+				{throttled: true, wantLimit: 2, wantChanged: true},
+				{throttled: true, wantLimit: 1, wantChanged: true},
+				{throttled: true, wantLimit: 1, wantChanged: false},
+			},
+		},
+		"should ramp up by one after a full window of successes": {
+			max: 4,
+			steps: []step{
+				{throttled: true, wantLimit: 2, wantChanged: true},
+				{wantLimit: 2, wantChanged: false},
+				{wantLimit: 3, wantChanged: true},
+				{wantLimit: 3, wantChanged: false},
+				{wantLimit: 3, wantChanged: false},
+				{wantLimit: 4, wantChanged: true},
+			},
+		},
+		"should never exceed the configured maximum": {
+			max: 2,
+			steps: []step{
+				{wantLimit: 2, wantChanged: false},
+				{wantLimit: 2, wantChanged: false},
+				{wantLimit: 2, wantChanged: false},
+			},
+		},
+		"should reset the success window when throttled": {
+			max: 4,
+			steps: []step{
+				{throttled: true, wantLimit: 2, wantChanged: true},
+				{wantLimit: 2, wantChanged: false},
+				{throttled: true, wantLimit: 1, wantChanged: true},
+				{wantLimit: 2, wantChanged: true},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			l := drydock.ExportNewConcurrencyLimiter(tt.max)
+			for i, s := range tt.steps {
+				var limit int
+				var changed bool
+				if s.throttled {
+					limit, changed = drydock.ExportLimiterThrottled(l)
+				} else {
+					limit, changed = drydock.ExportLimiterSucceeded(l)
+				}
+				got := step{throttled: s.throttled, wantLimit: limit, wantChanged: changed}
+				if diff := cmp.Diff(s, got, cmp.AllowUnexported(step{})); diff != "" {
+					t.Errorf("step %d mismatch (-want +got):\n%s", i, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestConcurrencyLimiter_Acquire(t *testing.T) {
+	l := drydock.ExportNewConcurrencyLimiter(1)
+	if err := drydock.ExportLimiterAcquire(l, context.Background()); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	// The only slot is taken, so acquiring again must wait until the context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := drydock.ExportLimiterAcquire(l, ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("acquire() error = %v, want %v", err, context.Canceled)
+	}
+
+	// Releasing the slot makes it available again.
+	drydock.ExportLimiterRelease(l)
+	if err := drydock.ExportLimiterAcquire(l, context.Background()); err != nil {
+		t.Errorf("acquire() after release error = %v", err)
+	}
+}
+
+func TestIsQuotaExceeded(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"should detect gRPC RESOURCE_EXHAUSTED": {
+			err:  status.Error(codes.ResourceExhausted, "quota exceeded"),
+			want: true,
+		},
+		"should detect wrapped gRPC RESOURCE_EXHAUSTED": {
+			err:  fmt.Errorf("failed to list occurrences: %w", status.Error(codes.ResourceExhausted, "quota exceeded")),
+			want: true,
+		},
+		"should detect HTTP 429": {
+			err:  &googleapi.Error{Code: http.StatusTooManyRequests},
+			want: true,
+		},
+		"should ignore other gRPC codes": {
+			err:  status.Error(codes.PermissionDenied, "denied"),
+			want: false,
+		},
+		"should ignore plain errors": {
+			err:  errors.New("boom"),
+			want: false,
+		},
+		"should ignore nil": {
+			err:  nil,
+			want: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := drydock.ExportIsQuotaExceeded(tt.err); got != tt.want {
+				t.Errorf("isQuotaExceeded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
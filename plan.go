@@ -0,0 +1,141 @@
+package drydock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ScanPlan describes the work a scan would perform, without analyzing any image.
+type ScanPlan struct {
+	// ProjectID is the GCP project that would be scanned
+	ProjectID string `json:"projectID"`
+
+	// Location is the Artifact Registry location that would be scanned
+	Location string `json:"location"`
+
+	// Repositories lists the repositories containing images to analyze, sorted by name
+	Repositories []RepositoryPlan `json:"repositories"`
+
+	// ImageCount is the total number of images that would be analyzed
+	ImageCount int `json:"imageCount"`
+
+	// EstimatedAPICalls is the estimated number of API calls the scan would make
+	EstimatedAPICalls APICallEstimate `json:"estimatedAPICalls"`
+}
+
+// RepositoryPlan describes the images of a single repository that would be analyzed.
+type RepositoryPlan struct {
+	// Name is the repository ID (e.g., my-app-repo)
+	Name string `json:"name"`
+
+	// ImageCount is the number of images in the repository that would be analyzed
+	ImageCount int `json:"imageCount"`
+}
+
+// APICallEstimate breaks down the expected API calls per method.
+// Each list call is counted once, so paginated responses make the actual number higher.
+type APICallEstimate struct {
+	// ListLocations is the number of Artifact Registry ListLocations calls, made for AllLocations
+	ListLocations int `json:"listLocations,omitempty"`
+
+	// ListRepositories is the number of Artifact Registry ListRepositories calls
+	ListRepositories int `json:"listRepositories"`
+
+	// ListDockerImages is the number of Artifact Registry ListDockerImages calls
+	ListDockerImages int `json:"listDockerImages"`
+
+	// ListOccurrences is the number of Container Analysis ListOccurrences calls
+	ListOccurrences int `json:"listOccurrences"`
+
+	// Total is the sum of all calls
+	Total int `json:"total"`
+}
+
+// Plan resolves the scan targets and estimates the cost of a scan without performing analysis.
+// Resolution errors are collected and returned alongside the (partial) plan.
+func (s *Scanner) Plan(ctx context.Context) (*ScanPlan, error) {
+	ctx = s.logger.WithContext(ctx)
+	log := s.logger
+
+	log.Debug().Msg("Resolving images from Artifact Registry...")
+
+	var targets []ImageTarget
+	var errs error
+	for target, err := range s.resolver.AllLatestImages(ctx, s.projectID, s.location, s.resolveOptions()...) {
+		if err != nil {
+			log.Warn().Err(err).Msg("Error occurred during image resolution stream")
+			errs = errors.Join(errs, fmt.Errorf("resolving image stream: %w", err))
+			continue
+		}
+		targets = append(targets, target)
+	}
+
+	locations := 1
+	if s.location == AllLocations {
+		locations = s.countLocations(ctx, targets)
+	}
+	plan := buildScanPlan(s.projectID, s.location, locations, targets)
+	if errs != nil {
+		return &plan, fmt.Errorf("planning completed with partial errors:\n%w", errs)
+	}
+	return &plan, nil
+}
+
+// countLocations returns the number of locations an AllLocations scan lists repositories in.
+// Resolvers that cannot list locations are assumed to list those of the resolved targets.
+func (s *Scanner) countLocations(ctx context.Context, targets []ImageTarget) int {
+	if lister, ok := s.resolver.(interface {
+		ListLocations(ctx context.Context, projectID string) ([]string, error)
+	}); ok {
+		if locations, err := lister.ListLocations(ctx, s.projectID); err == nil {
+			return len(locations)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, t := range targets {
+		seen[t.Location] = true
+	}
+	return len(seen)
+}
+
+// buildScanPlan aggregates resolved targets into a ScanPlan.
+// locations is the number of locations whose repositories are listed.
+func buildScanPlan(projectID, location string, locations int, targets []ImageTarget) ScanPlan {
+	counts := make(map[string]int)
+	for _, t := range targets {
+		counts[t.Artifact.RepositoryID]++
+	}
+
+	repos := make([]RepositoryPlan, 0, len(counts))
+	for name, n := range counts {
+		repos = append(repos, RepositoryPlan{Name: name, ImageCount: n})
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].Name < repos[j].Name
+	})
+
+	// Repositories of different locations may share an ID, but are listed separately
+	listed := make(map[[2]string]bool)
+	for _, t := range targets {
+		listed[[2]string{t.Location, t.Artifact.RepositoryID}] = true
+	}
+	estimate := APICallEstimate{
+		ListRepositories: locations,
+		ListDockerImages: len(listed),
+		ListOccurrences:  len(targets),
+	}
+	if location == AllLocations {
+		estimate.ListLocations = 1
+	}
+	estimate.Total = estimate.ListLocations + estimate.ListRepositories + estimate.ListDockerImages + estimate.ListOccurrences
+
+	return ScanPlan{
+		ProjectID:         projectID,
+		Location:          location,
+		Repositories:      repos,
+		ImageCount:        len(targets),
+		EstimatedAPICalls: estimate,
+	}
+}
@@ -0,0 +1,94 @@
+package drydock_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+func TestBuildScanPlan(t *testing.T) {
+	target := func(repo, image string) drydock.ImageTarget {
+		return drydock.ImageTarget{
+			Artifact: schemas.ArtifactReference{RepositoryID: repo, ImageName: image},
+			Location: "us-central1",
+		}
+	}
+
+	tests := map[string]struct {
+		location  string
+		locations int
+		targets   []drydock.ImageTarget
+		want      drydock.ScanPlan
+	}{
+		"should group images by repository and estimate calls": {
+			location:  "us-central1",
+			locations: 1,
+			targets: []drydock.ImageTarget{
+				target("web", "frontend"),
+				target("backend", "api"),
+				target("web", "admin"),
+			},
+			want: drydock.ScanPlan{
+				ProjectID: "my-project",
+				Location:  "us-central1",
+				Repositories: []drydock.RepositoryPlan{
+					{Name: "backend", ImageCount: 1},
+					{Name: "web", ImageCount: 2},
+				},
+				ImageCount: 3,
+				EstimatedAPICalls: drydock.APICallEstimate{
+					ListRepositories: 1,
+					ListDockerImages: 2,
+					ListOccurrences:  3,
+					Total:            6,
+				},
+			},
+		},
+		"should only count repository listing when no targets are found": {
+			location:  "us-central1",
+			locations: 1,
+			targets:   nil,
+			want: drydock.ScanPlan{
+				ProjectID:    "my-project",
+				Location:     "us-central1",
+				Repositories: []drydock.RepositoryPlan{},
+				EstimatedAPICalls: drydock.APICallEstimate{
+					ListRepositories: 1,
+					Total:            1,
+				},
+			},
+		},
+		"should list the repositories of every location with all locations": {
+			location:  drydock.AllLocations,
+			locations: 3,
+			targets: []drydock.ImageTarget{
+				{Artifact: schemas.ArtifactReference{RepositoryID: "web", ImageName: "frontend"}, Location: "us-central1"},
+				{Artifact: schemas.ArtifactReference{RepositoryID: "web", ImageName: "frontend"}, Location: "asia-northeast1"},
+			},
+			want: drydock.ScanPlan{
+				ProjectID:    "my-project",
+				Location:     drydock.AllLocations,
+				Repositories: []drydock.RepositoryPlan{{Name: "web", ImageCount: 2}},
+				ImageCount:   2,
+				EstimatedAPICalls: drydock.APICallEstimate{
+					ListLocations:    1,
+					ListRepositories: 3,
+					ListDockerImages: 2,
+					ListOccurrences:  2,
+					Total:            8,
+				},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := drydock.ExportBuildScanPlan("my-project", tt.location, tt.locations, tt.targets)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("BuildScanPlan() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package drydock
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// ScanPolicy gates a scan on its findings, e.g. to fail a CI job: Scan returns an error matching
+// ErrPolicyViolated, after exporting the results, when more than MaxCount findings are at or above
+// FailOnSeverity.
+type ScanPolicy struct {
+	// FailOnSeverity is the least severe severity counted against the policy
+	FailOnSeverity schemas.Severity
+
+	// MaxCount is the number of such findings tolerated; 0 fails on the first one
+	MaxCount int
+}
+
+// validate checks that the policy is usable.
+func (p ScanPolicy) validate() error {
+	if _, err := schemas.ParseSeverity(string(p.FailOnSeverity)); err != nil || p.FailOnSeverity == schemas.SeverityUnspecified {
+		return fmt.Errorf("invalid severity %q", p.FailOnSeverity)
+	}
+	if p.MaxCount < 0 {
+		return errors.New("max count must not be negative")
+	}
+	return nil
+}
+
+// violations returns the number of findings of the summary counted against the policy.
+func (p ScanPolicy) violations(summary ScanSummary) int {
+	n := 0
+	for severity, count := range summary.CountBySeverity {
+		if severity.AtLeast(p.FailOnSeverity) {
+			n += count
+		}
+	}
+	return n
+}
+
+// check returns an error matching ErrPolicyViolated if the summary violates the policy.
+func (p ScanPolicy) check(summary ScanSummary) error {
+	if n := p.violations(summary); n > p.MaxCount {
+		return fmt.Errorf("%w: %d finding(s) at or above %s (at most %d allowed)", ErrPolicyViolated, n, p.FailOnSeverity, p.MaxCount)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package drydock
+
+import "time"
+
+// ProgressEventType is the kind of a ProgressEvent.
+type ProgressEventType string
+
+const (
+	// ProgressResolved is reported when an image to analyze has been found
+	ProgressResolved ProgressEventType = "resolved"
+	// ProgressStarted is reported when the analysis of an image starts
+	ProgressStarted ProgressEventType = "started"
+	// ProgressCompleted is reported when the analysis of an image succeeds
+	ProgressCompleted ProgressEventType = "completed"
+	// ProgressFailed is reported when an image could not be resolved or analyzed
+	ProgressFailed ProgressEventType = "failed"
+)
+
+// ProgressEvent reports the progress of a scan, one image at a time.
+type ProgressEvent struct {
+	// Type is the kind of event
+	Type ProgressEventType `json:"type"`
+
+	// Time is when the event happened
+	Time time.Time `json:"time"`
+
+	// Image is the URI of the image, if known
+	Image string `json:"image,omitempty"`
+
+	// Vulnerabilities is the number of reported vulnerabilities (completed events only)
+	Vulnerabilities int `json:"vulnerabilities,omitempty"`
+
+	// Error is the reason of a failure (failed events only)
+	Error string `json:"error,omitempty"`
+}
+
+// ProgressFunc receives progress events. It is called from concurrent analyses,
+// so it must be safe for concurrent use, and should return quickly.
+type ProgressFunc func(ProgressEvent)
+
+// report sends an event to fn, if it is set.
+func (fn ProgressFunc) report(eventType ProgressEventType, image string, vulnerabilities int, err error) {
+	if fn == nil {
+		return
+	}
+	event := ProgressEvent{
+		Type:            eventType,
+		Time:            time.Now(),
+		Image:           image,
+		Vulnerabilities: vulnerabilities,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	fn(event)
+}
@@ -0,0 +1,151 @@
+package drydock
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	grafeaspb "google.golang.org/genproto/googleapis/grafeas/v1"
+)
+
+// osDistros maps the CPE vendor of an OS package to its purl type and namespace.
+var osDistros = map[string]struct {
+	purlType  string
+	namespace string
+}{
+	"debian":    {"deb", "debian"},
+	"canonical": {"deb", "ubuntu"},
+	"alpine":    {"apk", "alpine"},
+	"redhat":    {"rpm", "redhat"},
+	"centos":    {"rpm", "centos"},
+	"rocky":     {"rpm", "rocky"},
+	"fedora":    {"rpm", "fedora"},
+}
+
+// packagePURL builds the Package URL (https://github.com/package-url/purl-spec) of the package
+// affected by a package issue, e.g. "pkg:deb/debian/openssl@1.1.1n-0+deb11u3?distro=debian-11".
+// It returns an empty string when the package ecosystem is not recognized.
+func packagePURL(issue *grafeaspb.VulnerabilityOccurrence_PackageIssue) string {
+	name := issue.GetAffectedPackage()
+	if name == "" {
+		return ""
+	}
+	version := fullVersion(issue.GetAffectedVersion())
+
+	var purlType, namespace string
+	var qualifiers url.Values
+
+	switch strings.ToUpper(issue.GetPackageType()) {
+	case "OS":
+		vendor, distroVersion := parseOSCPE(issue.GetAffectedCpeUri())
+		distro, ok := osDistros[vendor]
+		if !ok {
+			return ""
+		}
+		purlType, namespace = distro.purlType, distro.namespace
+		qualifiers = url.Values{}
+		if distroVersion != "" {
+			qualifiers.Set("distro", distro.namespace+"-"+distroVersion)
+		}
+		if epoch := issue.GetAffectedVersion().GetEpoch(); epoch > 0 {
+			qualifiers.Set("epoch", strconv.Itoa(int(epoch)))
+		}
+	case "GO":
+		purlType = "golang"
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			namespace, name = name[:i], name[i+1:]
+		}
+	case "MAVEN":
+		purlType = "maven"
+		if group, artifact, ok := strings.Cut(name, ":"); ok {
+			namespace, name = group, artifact
+		}
+	case "NPM":
+		purlType = "npm"
+		if strings.HasPrefix(name, "@") {
+			if scope, pkg, ok := strings.Cut(name, "/"); ok {
+				namespace, name = scope, pkg
+			}
+		}
+	case "PYPI":
+		purlType = "pypi"
+		name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	case "RUBYGEMS":
+		purlType = "gem"
+	case "NUGET":
+		purlType = "nuget"
+	case "COMPOSER":
+		purlType = "composer"
+		if vendor, pkg, ok := strings.Cut(name, "/"); ok {
+			namespace, name = vendor, pkg
+		}
+	case "CARGO", "RUST":
+		purlType = "cargo"
+	default:
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("pkg:")
+	b.WriteString(purlType)
+	if namespace != "" {
+		for _, segment := range strings.Split(namespace, "/") {
+			b.WriteString("/")
+			b.WriteString(escapePURL(segment))
+		}
+	}
+	b.WriteString("/")
+	b.WriteString(escapePURL(name))
+	if version != "" {
+		b.WriteString("@")
+		b.WriteString(escapePURL(version))
+	}
+	if len(qualifiers) > 0 {
+		// Encode sorts qualifiers by key, as the spec requires
+		b.WriteString("?")
+		b.WriteString(qualifiers.Encode())
+	}
+	return b.String()
+}
+
+// fullVersion returns the package version without the epoch (e.g., "1.1.1n-0+deb11u3").
+func fullVersion(v *grafeaspb.Version) string {
+	if v.GetKind() != grafeaspb.Version_NORMAL || v.GetName() == "" {
+		return ""
+	}
+	if v.GetRevision() != "" {
+		return v.GetName() + "-" + v.GetRevision()
+	}
+	return v.GetName()
+}
+
+// parseOSCPE extracts the vendor and version from an OS CPE URI (e.g., "cpe:/o:debian:debian_linux:11").
+func parseOSCPE(cpe string) (vendor, version string) {
+	parts := strings.Split(strings.TrimPrefix(cpe, "cpe:/"), ":")
+	if len(parts) < 2 || parts[0] != "o" {
+		return "", ""
+	}
+	vendor = parts[1]
+	if len(parts) >= 4 {
+		version = parts[3]
+	}
+	return vendor, version
+}
+
+// escapePURL percent-encodes a purl component.
+func escapePURL(s string) string {
+	return strings.NewReplacer("@", "%40", "+", "%2B").Replace(url.PathEscape(s))
+}
+
+// purlDistro returns the distro qualifier of a purl (e.g., "debian-11"), or an empty string.
+func purlDistro(purl string) string {
+	_, query, ok := strings.Cut(purl, "?")
+	if !ok {
+		return ""
+	}
+	q, err := url.ParseQuery(query)
+	if err != nil {
+		return ""
+	}
+	return q.Get("distro")
+}
@@ -0,0 +1,106 @@
+package drydock_test
+
+import (
+	"testing"
+
+	"github.com/hiro-o918/drydock"
+	grafeaspb "google.golang.org/genproto/googleapis/grafeas/v1"
+)
+
+func TestPackagePURL(t *testing.T) {
+	version := func(name, revision string) *grafeaspb.Version {
+		return &grafeaspb.Version{Name: name, Revision: revision, Kind: grafeaspb.Version_NORMAL}
+	}
+
+	tests := map[string]struct {
+		input *grafeaspb.VulnerabilityOccurrence_PackageIssue
+		want  string
+	}{
+		"should build a deb purl with distro for Debian packages": {
+			input: &grafeaspb.VulnerabilityOccurrence_PackageIssue{
+				AffectedPackage: "openssl",
+				AffectedVersion: version("1.1.1n", "0+deb11u3"),
+				AffectedCpeUri:  "cpe:/o:debian:debian_linux:11",
+				PackageType:     "OS",
+			},
+			want: "pkg:deb/debian/openssl@1.1.1n-0%2Bdeb11u3?distro=debian-11",
+		},
+		"should build an rpm purl with epoch for Red Hat packages": {
+			input: &grafeaspb.VulnerabilityOccurrence_PackageIssue{
+				AffectedPackage: "openssl-libs",
+				AffectedVersion: &grafeaspb.Version{Epoch: 1, Name: "1.1.1k", Revision: "7.el8", Kind: grafeaspb.Version_NORMAL},
+				AffectedCpeUri:  "cpe:/o:redhat:enterprise_linux:8",
+				PackageType:     "OS",
+			},
+			want: "pkg:rpm/redhat/openssl-libs@1.1.1k-7.el8?distro=redhat-8&epoch=1",
+		},
+		"should split Go module paths into namespace and name": {
+			input: &grafeaspb.VulnerabilityOccurrence_PackageIssue{
+				AffectedPackage: "golang.org/x/net",
+				AffectedVersion: version("v0.7.0", ""),
+				PackageType:     "GO",
+			},
+			want: "pkg:golang/golang.org/x/net@v0.7.0",
+		},
+		"should split Maven coordinates into group and artifact": {
+			input: &grafeaspb.VulnerabilityOccurrence_PackageIssue{
+				AffectedPackage: "org.apache.logging.log4j:log4j-core",
+				AffectedVersion: version("2.14.1", ""),
+				PackageType:     "MAVEN",
+			},
+			want: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+		},
+		"should encode npm scopes": {
+			input: &grafeaspb.VulnerabilityOccurrence_PackageIssue{
+				AffectedPackage: "@babel/core",
+				AffectedVersion: version("7.0.0", ""),
+				PackageType:     "NPM",
+			},
+			want: "pkg:npm/%40babel/core@7.0.0",
+		},
+		"should normalize PyPI names": {
+			input: &grafeaspb.VulnerabilityOccurrence_PackageIssue{
+				AffectedPackage: "Django_Rest",
+				AffectedVersion: version("3.0", ""),
+				PackageType:     "PYPI",
+			},
+			want: "pkg:pypi/django-rest@3.0",
+		},
+		"should omit the version when it is not a normal version": {
+			input: &grafeaspb.VulnerabilityOccurrence_PackageIssue{
+				AffectedPackage: "rack",
+				AffectedVersion: &grafeaspb.Version{Kind: grafeaspb.Version_MAXIMUM},
+				PackageType:     "RUBYGEMS",
+			},
+			want: "pkg:gem/rack",
+		},
+		"should return empty string for unknown OS distributions": {
+			input: &grafeaspb.VulnerabilityOccurrence_PackageIssue{
+				AffectedPackage: "busybox",
+				AffectedVersion: version("1.0", ""),
+				AffectedCpeUri:  "cpe:/o:unknown:linux:1",
+				PackageType:     "OS",
+			},
+			want: "",
+		},
+		"should return empty string for unknown package types": {
+			input: &grafeaspb.VulnerabilityOccurrence_PackageIssue{
+				AffectedPackage: "foo",
+				PackageType:     "SOMETHING",
+			},
+			want: "",
+		},
+		"should return empty string when there is no package issue": {
+			input: nil,
+			want:  "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := drydock.ExportPackagePURL(tt.input); got != tt.want {
+				t.Errorf("PackagePURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
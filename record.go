@@ -0,0 +1,327 @@
+package drydock
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"cloud.google.com/go/artifactregistry/apiv1/artifactregistrypb"
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/api/iterator"
+	locationpb "google.golang.org/genproto/googleapis/cloud/location"
+	grafeaspb "google.golang.org/genproto/googleapis/grafeas/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// fixtureStore reads and writes recorded API responses, one JSON file per distinct request.
+type fixtureStore struct {
+	dir string
+}
+
+// fixture is a recorded API response: the items of a list call (or the single item of a get call),
+// and the API error that ended it, if any.
+type fixture struct {
+	Method  string            `json:"method"`
+	Request json.RawMessage   `json:"request"`
+	Items   []json.RawMessage `json:"items,omitempty"`
+	Error   *fixtureError     `json:"error,omitempty"`
+}
+
+type fixtureError struct {
+	Code    codes.Code `json:"code"`
+	Message string     `json:"message"`
+}
+
+// path returns the file of the response to req, e.g. "ListDockerImages-0123456789abcdef.json".
+func (s fixtureStore) path(method string, req proto.Message) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append([]byte(method+"\x00"), data...))
+	return filepath.Join(s.dir, method+"-"+hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// save records a response. Only API errors are recorded; others (e.g., cancellations) are not responses.
+func (s fixtureStore) save(method string, req proto.Message, items []proto.Message, apiErr error) error {
+	path, err := s.path(method, req)
+	if err != nil {
+		return err
+	}
+	f := fixture{Method: method}
+	if f.Request, err = protojson.Marshal(req); err != nil {
+		return err
+	}
+	for _, item := range items {
+		data, err := protojson.Marshal(item)
+		if err != nil {
+			return err
+		}
+		f.Items = append(f.Items, data)
+	}
+	if apiErr != nil {
+		st, _ := status.FromError(apiErr)
+		f.Error = &fixtureError{Code: st.Code(), Message: st.Message()}
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Concurrent analyses may record the same request; write atomically
+	tmp, err := os.CreateTemp(s.dir, ".fixture-*")
+	if err != nil {
+		return fmt.Errorf("failed to record %s: %w", method, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to record %s: %w", method, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to record %s: %w", method, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to record %s: %w", method, err)
+	}
+	return nil
+}
+
+// load returns the recorded response to req.
+func (s fixtureStore) load(method string, req proto.Message) (*fixture, error) {
+	path, err := s.path(method, req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no recorded response to %s %s in %s (record it with WithRecording)", method, protojson.Format(req), s.dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// isAPIError reports whether err is an error returned by a Google API, as opposed to e.g. a cancellation.
+func isAPIError(err error) bool {
+	_, ok := status.FromError(err)
+	return ok && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// recordingIterator passes items through and records them once the iteration ends.
+type recordingIterator[T proto.Message] struct {
+	next  func() (T, error)
+	save  func(items []proto.Message, err error) error
+	items []proto.Message
+	ended bool
+}
+
+func (it *recordingIterator[T]) Next() (T, error) {
+	item, err := it.next()
+	switch {
+	case err == nil:
+		it.items = append(it.items, item)
+	case it.ended:
+	case errors.Is(err, iterator.Done):
+		it.ended = true
+		if saveErr := it.save(it.items, nil); saveErr != nil {
+			return item, saveErr
+		}
+	case isAPIError(err):
+		it.ended = true
+		if saveErr := it.save(it.items, err); saveErr != nil {
+			return item, errors.Join(err, saveErr)
+		}
+	}
+	return item, err
+}
+
+// replayIterator yields recorded items, then the recorded error or iterator.Done.
+type replayIterator[T proto.Message] struct {
+	items []T
+	err   error
+}
+
+func (it *replayIterator[T]) Next() (T, error) {
+	var zero T
+	if len(it.items) > 0 {
+		item := it.items[0]
+		it.items = it.items[1:]
+		return item, nil
+	}
+	if it.err != nil {
+		return zero, it.err
+	}
+	return zero, iterator.Done
+}
+
+// replay decodes the recorded response to req into items created by newItem.
+func replay[T proto.Message](s fixtureStore, method string, req proto.Message, newItem func() T) ([]T, error) {
+	f, err := s.load(method, req)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]T, 0, len(f.Items))
+	for _, data := range f.Items {
+		item := newItem()
+		if err := protojson.Unmarshal(data, item); err != nil {
+			return nil, fmt.Errorf("invalid recorded %s response: %w", method, err)
+		}
+		items = append(items, item)
+	}
+	if f.Error != nil {
+		return items, status.Error(f.Error.Code, f.Error.Message)
+	}
+	return items, nil
+}
+
+// replayList returns an iterator over the recorded response to a list call.
+func replayList[T proto.Message](s fixtureStore, method string, req proto.Message, newItem func() T) *replayIterator[T] {
+	items, err := replay(s, method, req, newItem)
+	return &replayIterator[T]{items: items, err: err}
+}
+
+// replayGet returns the recorded response to a get call.
+func replayGet[T proto.Message](s fixtureStore, method string, req proto.Message, newItem func() T) (T, error) {
+	var zero T
+	items, err := replay(s, method, req, newItem)
+	if err != nil {
+		return zero, err
+	}
+	if len(items) != 1 {
+		return zero, fmt.Errorf("invalid recorded %s response: %d items", method, len(items))
+	}
+	return items[0], nil
+}
+
+// recordGet records the response to a get call.
+func recordGet[T proto.Message](s fixtureStore, method string, req proto.Message, item T, err error) (T, error) {
+	var saveErr error
+	switch {
+	case err == nil:
+		saveErr = s.save(method, req, []proto.Message{item}, nil)
+	case isAPIError(err):
+		saveErr = s.save(method, req, nil, err)
+	}
+	if saveErr != nil {
+		return item, errors.Join(err, saveErr)
+	}
+	return item, err
+}
+
+// recordingArtifactRegistry records the responses of an Artifact Registry client.
+type recordingArtifactRegistry struct {
+	client artifactRegistryClient
+	store  fixtureStore
+}
+
+func (r recordingArtifactRegistry) ListLocations(ctx context.Context, req *locationpb.ListLocationsRequest, opts ...gax.CallOption) locationIterator {
+	it := r.client.ListLocations(ctx, req, opts...)
+	return &recordingIterator[*locationpb.Location]{
+		next: it.Next,
+		save: func(items []proto.Message, err error) error { return r.store.save("ListLocations", req, items, err) },
+	}
+}
+
+func (r recordingArtifactRegistry) ListRepositories(ctx context.Context, req *artifactregistrypb.ListRepositoriesRequest, opts ...gax.CallOption) repositoryIterator {
+	it := r.client.ListRepositories(ctx, req, opts...)
+	return &recordingIterator[*artifactregistrypb.Repository]{
+		next: it.Next,
+		save: func(items []proto.Message, err error) error { return r.store.save("ListRepositories", req, items, err) },
+	}
+}
+
+func (r recordingArtifactRegistry) ListDockerImages(ctx context.Context, req *artifactregistrypb.ListDockerImagesRequest, opts ...gax.CallOption) dockerImageIterator {
+	it := r.client.ListDockerImages(ctx, req, opts...)
+	return &recordingIterator[*artifactregistrypb.DockerImage]{
+		next: it.Next,
+		save: func(items []proto.Message, err error) error { return r.store.save("ListDockerImages", req, items, err) },
+	}
+}
+
+func (r recordingArtifactRegistry) GetTag(ctx context.Context, req *artifactregistrypb.GetTagRequest, opts ...gax.CallOption) (*artifactregistrypb.Tag, error) {
+	tag, err := r.client.GetTag(ctx, req, opts...)
+	return recordGet(r.store, "GetTag", req, tag, err)
+}
+
+func (r recordingArtifactRegistry) GetDockerImage(ctx context.Context, req *artifactregistrypb.GetDockerImageRequest, opts ...gax.CallOption) (*artifactregistrypb.DockerImage, error) {
+	img, err := r.client.GetDockerImage(ctx, req, opts...)
+	return recordGet(r.store, "GetDockerImage", req, img, err)
+}
+
+func (r recordingArtifactRegistry) Close() error {
+	return r.client.Close()
+}
+
+// replayArtifactRegistry answers Artifact Registry calls from recorded responses.
+type replayArtifactRegistry struct {
+	store fixtureStore
+}
+
+func (r replayArtifactRegistry) ListLocations(_ context.Context, req *locationpb.ListLocationsRequest, _ ...gax.CallOption) locationIterator {
+	return replayList(r.store, "ListLocations", req, func() *locationpb.Location { return &locationpb.Location{} })
+}
+
+func (r replayArtifactRegistry) ListRepositories(_ context.Context, req *artifactregistrypb.ListRepositoriesRequest, _ ...gax.CallOption) repositoryIterator {
+	return replayList(r.store, "ListRepositories", req, func() *artifactregistrypb.Repository { return &artifactregistrypb.Repository{} })
+}
+
+func (r replayArtifactRegistry) ListDockerImages(_ context.Context, req *artifactregistrypb.ListDockerImagesRequest, _ ...gax.CallOption) dockerImageIterator {
+	return replayList(r.store, "ListDockerImages", req, func() *artifactregistrypb.DockerImage { return &artifactregistrypb.DockerImage{} })
+}
+
+func (r replayArtifactRegistry) GetTag(_ context.Context, req *artifactregistrypb.GetTagRequest, _ ...gax.CallOption) (*artifactregistrypb.Tag, error) {
+	return replayGet(r.store, "GetTag", req, func() *artifactregistrypb.Tag { return &artifactregistrypb.Tag{} })
+}
+
+func (r replayArtifactRegistry) GetDockerImage(_ context.Context, req *artifactregistrypb.GetDockerImageRequest, _ ...gax.CallOption) (*artifactregistrypb.DockerImage, error) {
+	return replayGet(r.store, "GetDockerImage", req, func() *artifactregistrypb.DockerImage { return &artifactregistrypb.DockerImage{} })
+}
+
+func (r replayArtifactRegistry) Close() error {
+	return nil
+}
+
+// recordingOccurrences records the responses of a Container Analysis client.
+type recordingOccurrences struct {
+	client occurrenceClient
+	store  fixtureStore
+}
+
+func (r recordingOccurrences) ListOccurrences(ctx context.Context, req *grafeaspb.ListOccurrencesRequest, opts ...gax.CallOption) occurrenceIterator {
+	it := r.client.ListOccurrences(ctx, req, opts...)
+	return &recordingIterator[*grafeaspb.Occurrence]{
+		next: it.Next,
+		save: func(items []proto.Message, err error) error { return r.store.save("ListOccurrences", req, items, err) },
+	}
+}
+
+func (r recordingOccurrences) Close() error {
+	return r.client.Close()
+}
+
+// replayOccurrences answers Container Analysis calls from recorded responses.
+type replayOccurrences struct {
+	store fixtureStore
+}
+
+func (r replayOccurrences) ListOccurrences(_ context.Context, req *grafeaspb.ListOccurrencesRequest, _ ...gax.CallOption) occurrenceIterator {
+	return replayList(r.store, "ListOccurrences", req, func() *grafeaspb.Occurrence { return &grafeaspb.Occurrence{} })
+}
+
+func (r replayOccurrences) Close() error {
+	return nil
+}
@@ -0,0 +1,86 @@
+package drydock_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+	grafeaspb "google.golang.org/genproto/googleapis/grafeas/v1"
+)
+
+// recordedScan summarizes the responses seen by a resolver and an analyzer.
+type recordedScan struct {
+	Targets    []string
+	Resolved   string
+	MissingErr string
+	IDs        []string
+}
+
+func runRecordedScan(t *testing.T, resolver *drydock.ImageResolver, analyzer *drydock.ArtifactRegistryAnalyzer) recordedScan {
+	t.Helper()
+	ctx := context.Background()
+	api := schemas.ArtifactReference{Host: "us-central1-docker.pkg.dev", ProjectID: "p", RepositoryID: "repo", ImageName: "api"}
+
+	var got recordedScan
+	var errs []error
+	got.Targets, errs = targetLines(t, resolver.AllLatestImages(ctx, "p", "us-central1"))
+	if len(errs) > 0 {
+		t.Fatalf("AllLatestImages() errors = %v", errs)
+	}
+
+	byTag := api
+	byTag.Tag = utils.Ptr("v1")
+	target, err := resolver.ResolveImage(ctx, byTag)
+	if err != nil {
+		t.Fatalf("ResolveImage() error = %v", err)
+	}
+	got.Resolved = target.URI
+
+	missing := api
+	missing.Tag = utils.Ptr("v9")
+	if _, err := resolver.ResolveImage(ctx, missing); err != nil {
+		got.MissingErr = err.Error()
+	}
+
+	result, err := analyzer.Analyze(ctx, drydock.AnalyzeRequest{Artifact: target.Artifact, Location: "us-central1"})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	for _, v := range result.Vulnerabilities {
+		got.IDs = append(got.IDs, v.ID)
+	}
+	return got
+}
+
+func TestRecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	occurrences := &fakeOccurrences{occurrences: []*grafeaspb.Occurrence{
+		vulnerabilityOccurrence("CVE-1", grafeaspb.Severity_CRITICAL),
+		vulnerabilityOccurrence("CVE-2", grafeaspb.Severity_LOW),
+	}}
+
+	resolver, analyzer := drydock.ExportRecording(newFakeRegistry(), occurrences, dir)
+	recorded := runRecordedScan(t, resolver, analyzer)
+	if recorded.MissingErr == "" {
+		t.Fatal("ResolveImage() of a missing tag succeeded while recording")
+	}
+
+	t.Run("should replay the recorded responses", func(t *testing.T) {
+		resolver, analyzer := drydock.ExportReplay(dir)
+		replayed := runRecordedScan(t, resolver, analyzer)
+		if diff := cmp.Diff(recorded, replayed); diff != "" {
+			t.Errorf("replayed responses mismatch (-recorded +replayed):\n%s", diff)
+		}
+	})
+
+	t.Run("should fail on calls that were not recorded", func(t *testing.T) {
+		resolver, _ := drydock.ExportReplay(dir)
+		_, errs := targetLines(t, resolver.AllLatestImages(context.Background(), "other", "us-central1"))
+		if len(errs) != 1 {
+			t.Errorf("AllLatestImages() errors = %v, want 1", errs)
+		}
+	})
+}
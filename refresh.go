@@ -0,0 +1,93 @@
+package drydock
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hiro-o918/drydock/schemas"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+)
+
+// manifestMediaTypes are the manifest formats accepted when pulling an image.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// manifestPuller pulls image manifests from the Docker registry API of Artifact Registry.
+// A pull re-activates the continuous analysis of an image that was stopped after 30 days without pulls.
+type manifestPuller struct {
+	client *http.Client
+
+	// baseURL replaces "https://<host>" when set (for tests)
+	baseURL string
+}
+
+// newManifestPuller creates a puller authenticating with the given client options.
+func newManifestPuller(ctx context.Context, opts ...option.ClientOption) (*manifestPuller, error) {
+	opts = append([]option.ClientOption{option.WithScopes(cloudPlatformScope)}, opts...)
+	client, _, err := htransport.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry client: %w", err)
+	}
+	return &manifestPuller{client: client}, nil
+}
+
+// Close releases the connections of the registry client.
+func (p *manifestPuller) Close() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
+
+// pull fetches the manifest of the artifact, by digest if known and by tag otherwise.
+func (p *manifestPuller) pull(ctx context.Context, artifact schemas.ArtifactReference) error {
+	ref := "latest"
+	switch {
+	case artifact.Digest != nil && *artifact.Digest != "":
+		ref = *artifact.Digest
+	case artifact.Tag != nil && *artifact.Tag != "":
+		ref = *artifact.Tag
+	}
+	base := p.baseURL
+	if base == "" {
+		base = "https://" + artifact.Host
+	}
+	url := fmt.Sprintf("%s/v2/%s/%s/%s/manifests/%s", base, artifact.ProjectID, artifact.RepositoryID, artifact.ImageName, ref)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to pull manifest: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to pull manifest: %s", resp.Status)
+	}
+	return nil
+}
+
+// refreshStale pulls the image of a stale result so that Container Analysis resumes updating it,
+// and records the outcome in the result metadata. The refreshed findings show up in a later scan.
+func (p *manifestPuller) refreshStale(ctx context.Context, result *schemas.AnalyzeResult) error {
+	if result.Metadata == nil || !result.Metadata.Stale {
+		return nil
+	}
+	if err := p.pull(ctx, result.Artifact); err != nil {
+		result.Metadata.Warnings = append(result.Metadata.Warnings, fmt.Sprintf("refreshing stale analysis: %v", err))
+		return err
+	}
+	result.Metadata.RefreshRequested = true
+	result.Metadata.Warnings = append(result.Metadata.Warnings, "pulled the image to re-activate continuous analysis; refreshed findings show up in a later scan")
+	return nil
+}
@@ -0,0 +1,76 @@
+package drydock_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestRefreshStale(t *testing.T) {
+	artifact := schemas.ArtifactReference{
+		Host: "us-central1-docker.pkg.dev", ProjectID: "p", RepositoryID: "repo", ImageName: "app/api",
+		Digest: utils.Ptr("sha256:abc"),
+	}
+
+	tests := map[string]struct {
+		metadata *schemas.ScanMetadata
+		status   int
+		wantPath string
+		want     *schemas.ScanMetadata
+		wantErr  bool
+	}{
+		"should pull the manifest of a stale image": {
+			metadata: &schemas.ScanMetadata{Stale: true},
+			status:   http.StatusOK,
+			wantPath: "/v2/p/repo/app/api/manifests/sha256:abc",
+			want: &schemas.ScanMetadata{
+				Stale:            true,
+				RefreshRequested: true,
+				Warnings:         []string{"pulled the image to re-activate continuous analysis; refreshed findings show up in a later scan"},
+			},
+		},
+		"should record a failed pull": {
+			metadata: &schemas.ScanMetadata{Stale: true},
+			status:   http.StatusForbidden,
+			wantPath: "/v2/p/repo/app/api/manifests/sha256:abc",
+			want: &schemas.ScanMetadata{
+				Stale:    true,
+				Warnings: []string{"refreshing stale analysis: failed to pull manifest: 403 Forbidden"},
+			},
+			wantErr: true,
+		},
+		"should not pull an image whose analysis is up to date": {
+			metadata: &schemas.ScanMetadata{ContinuousAnalysis: "ACTIVE"},
+			want:     &schemas.ScanMetadata{ContinuousAnalysis: "ACTIVE"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gotPath string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			result := &schemas.AnalyzeResult{Artifact: artifact, Metadata: tt.metadata}
+			err := drydock.ExportRefreshStale(drydock.ExportNewManifestPuller(srv.Client(), srv.URL), context.Background(), result)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("refreshStale() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("requested path = %q, want %q", gotPath, tt.wantPath)
+			}
+			if diff := cmp.Diff(tt.want, result.Metadata); diff != "" {
+				t.Errorf("metadata mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
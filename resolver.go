@@ -4,29 +4,41 @@ import (
 	"context"
 	"fmt"
 	"iter"
-	"regexp"
+	"net/url"
+	"path"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	artifactregistry "cloud.google.com/go/artifactregistry/apiv1"
 	"cloud.google.com/go/artifactregistry/apiv1/artifactregistrypb"
+	"github.com/googleapis/gax-go/v2"
 	"github.com/hiro-o918/drydock/schemas"
 	"github.com/hiro-o918/drydock/utils"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
+	locationpb "google.golang.org/genproto/googleapis/cloud/location"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
-	// MaxCandidates is the number of latest digests to consider per image during discovery.
-	// Limiting this prevents scanning thousands of old tags, significantly improving performance.
+	// MaxCandidates is the default number of latest digests to consider per image during discovery
+	// (see ResolveMaxCandidates). Limiting this prevents scanning thousands of old tags, significantly
+	// improving performance.
 	MaxCandidates = 5
+
+	// AllLocations is the location that stands for every Artifact Registry location of the project:
+	// discovery lists the repositories of each location returned by the locations API.
+	AllLocations = "all"
 )
 
 // ImageResolver handles resolving Docker image tags to SHA256 digests.
 type ImageResolver struct {
-	client *artifactregistry.Client
+	client   artifactRegistryClient
+	callOpts []gax.CallOption
+	retrySet bool
 }
 
 // ImageTarget represents a resolved target for scanning.
@@ -34,6 +46,7 @@ type ImageTarget struct {
 	Artifact schemas.ArtifactReference // Structured image reference
 	URI      string                    // Original API response URI (for debugging)
 	Location string                    // GCP location (e.g., "us-central1")
+	Image    *schemas.ImageMetadata    // Registry metadata of the image (nil if unknown)
 }
 
 // candidateImage is an internal struct used for selection logic.
@@ -42,6 +55,38 @@ type candidateImage struct {
 	Tags       []string
 	UpdateTime time.Time
 	URI        string
+
+	// Metadata carried into the result of the selected candidate
+	UploadTime time.Time
+	BuildTime  time.Time
+	SizeBytes  int64
+	MediaType  string
+}
+
+// newCandidateImage builds a candidate from an image returned by the API.
+func newCandidateImage(img *artifactregistrypb.DockerImage, digest string) candidateImage {
+	return candidateImage{
+		Digest:     digest,
+		Tags:       img.Tags,
+		UpdateTime: asTime(img.GetUpdateTime()),
+		URI:        img.Uri,
+		UploadTime: asTime(img.GetUploadTime()),
+		BuildTime:  asTime(img.GetBuildTime()),
+		SizeBytes:  img.GetImageSizeBytes(),
+		MediaType:  img.GetMediaType(),
+	}
+}
+
+// metadata returns the registry metadata of the candidate.
+func (c candidateImage) metadata() *schemas.ImageMetadata {
+	return &schemas.ImageMetadata{
+		Tags:       c.Tags,
+		UploadTime: c.UploadTime,
+		UpdateTime: c.UpdateTime,
+		BuildTime:  c.BuildTime,
+		SizeBytes:  c.SizeBytes,
+		MediaType:  c.MediaType,
+	}
 }
 
 // NewImageResolver creates a new resolver with ADC authentication.
@@ -50,7 +95,24 @@ func NewImageResolver(ctx context.Context, opts ...option.ClientOption) (*ImageR
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Artifact Registry client: %w", err)
 	}
-	return &ImageResolver{client: client}, nil
+	return newImageResolver(gcpArtifactRegistryClient{client}), nil
+}
+
+// newImageResolver creates a resolver on top of the given Artifact Registry client.
+func newImageResolver(client artifactRegistryClient) *ImageResolver {
+	return &ImageResolver{client: client}
+}
+
+// SetRetryPolicy makes the resolver retry failed API calls according to p.
+// Without it, calls are not retried unless the resolver is used by a Scanner,
+// which applies its own policy (see WithRetryPolicy).
+func (r *ImageResolver) SetRetryPolicy(p RetryPolicy) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+	r.callOpts = p.callOptions()
+	r.retrySet = true
+	return nil
 }
 
 // Close closes the underlying API client.
@@ -58,152 +120,493 @@ func (r *ImageResolver) Close() error {
 	return r.client.Close()
 }
 
+var _ Resolver = (*ImageResolver)(nil)
+
+// ResolveOption configures a single discovery run of the resolver.
+type ResolveOption func(*resolveConfig)
+
+// resolveConfig holds the settings applied by ResolveOptions.
+type resolveConfig struct {
+	priorities  []string
+	concurrency int
+	digests     int // digests per image, 0 or 1 for the best candidate only
+	candidates  int // candidates considered per image, 0 for MaxCandidates
+	pageSize    int // items per page of list calls, 0 for the API default
+}
+
+// maxCandidates returns the number of candidates considered per image.
+func (c *resolveConfig) maxCandidates() int {
+	if c.candidates > 0 {
+		return c.candidates
+	}
+	return MaxCandidates
+}
+
+// PrioritizeRepositories makes repositories whose ID matches one of the given glob patterns
+// (path.Match syntax, e.g. "prod-*") be resolved before all others, in pattern order.
+// This guarantees that the most important images are covered first when a run is time-boxed or interrupted.
+func PrioritizeRepositories(patterns ...string) ResolveOption {
+	return func(c *resolveConfig) {
+		c.priorities = append(c.priorities, patterns...)
+	}
+}
+
+// ResolveConcurrency lists up to n repositories at once. Targets of different repositories are
+// then interleaved, and priorities (see PrioritizeRepositories) only order when the listing of
+// each repository starts.
+func ResolveConcurrency(n int) ResolveOption {
+	return func(c *resolveConfig) {
+		c.concurrency = n
+	}
+}
+
+// DigestsPerImage makes AllLatestImages yield the n most recently updated digests of each image,
+// instead of its single best candidate, so that older versions still deployed are covered too.
+// Each target carries the first tag of its digest, if any.
+func DigestsPerImage(n int) ResolveOption {
+	return func(c *resolveConfig) {
+		c.digests = n
+	}
+}
+
+// ResolveMaxCandidates makes AllLatestImages consider up to n of the newest digests of each image
+// when selecting its best candidate, instead of MaxCandidates. Without a "latest" tag, the newest
+// of them is selected anyway; a larger window only finds a "latest" tag further back.
+func ResolveMaxCandidates(n int) ResolveOption {
+	return func(c *resolveConfig) {
+		c.candidates = n
+	}
+}
+
+// ResolvePageSize requests n items per page when listing repositories and images, instead of the
+// API default. Larger pages mean fewer calls on huge repositories; smaller ones, earlier first results.
+func ResolvePageSize(n int) ResolveOption {
+	return func(c *resolveConfig) {
+		c.pageSize = n
+	}
+}
+
 // AllLatestImages returns an iterator that yields resolved image targets one by one.
 // It scans all Docker repositories in the specified project and location.
 // For each image found, it selects the best digest (preferring "latest" tag, otherwise newest).
-func (r *ImageResolver) AllLatestImages(ctx context.Context, projectID, location string) iter.Seq2[ImageTarget, error] {
+// Log output goes to the logger attached to ctx (see zerolog.Ctx); nothing is logged otherwise.
+func (r *ImageResolver) AllLatestImages(ctx context.Context, projectID, location string, opts ...ResolveOption) iter.Seq2[ImageTarget, error] {
+	cfg := &resolveConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	return func(yield func(ImageTarget, error) bool) {
-		parent := fmt.Sprintf("projects/%s/locations/%s", projectID, location)
-		repoReq := &artifactregistrypb.ListRepositoriesRequest{Parent: parent}
-		repoIt := r.client.ListRepositories(ctx, repoReq)
+		// 1. Fetch all Docker repositories up front so they can be ordered by priority
+		repoNames, failed, err := r.listDockerRepositories(ctx, projectID, location, cfg)
+		if err != nil {
+			// Yield error and stop iteration to be safe.
+			yield(ImageTarget{}, err)
+			return
+		}
+		for _, err := range failed {
+			if !yield(ImageTarget{}, err) {
+				return
+			}
+		}
+		repoNames = prioritizeRepositories(repoNames, cfg.priorities)
+
+		// 2. Scan the repositories, yielding each target as soon as its best digest is known,
+		// so that analysis starts while large repositories are still being listed
+		scan := func(ctx context.Context, repoName string) iter.Seq2[ImageTarget, error] {
+			return func(yield func(ImageTarget, error) bool) {
+				for target, err := range r.repositoryLatestImages(ctx, repoName, cfg) {
+					if err != nil {
+						err = fmt.Errorf("failed to scan repo %s: %w", repoName, err)
+					}
+					if !yield(target, err) {
+						return
+					}
+				}
+			}
+		}
+		if cfg.digests > 1 {
+			scan = func(ctx context.Context, repoName string) iter.Seq2[ImageTarget, error] {
+				return r.repositoryRecentImages(ctx, repoName, cfg)
+			}
+		}
+		for target, err := range eachRepository(ctx, repoNames, cfg.concurrency, scan) {
+			if !yield(target, err) {
+				return
+			}
+		}
+	}
+}
+
+// AllImages returns an iterator over every image digest in the Docker repositories of the project
+// and location, with its registry metadata, newest first within each repository.
+// Unlike AllLatestImages, no digest is selected or skipped; this only calls Artifact Registry.
+func (r *ImageResolver) AllImages(ctx context.Context, projectID, location string, opts ...ResolveOption) iter.Seq2[ImageTarget, error] {
+	cfg := &resolveConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(yield func(ImageTarget, error) bool) {
+		repoNames, failed, err := r.listDockerRepositories(ctx, projectID, location, cfg)
+		if err != nil {
+			yield(ImageTarget{}, err)
+			return
+		}
+		for _, err := range failed {
+			if !yield(ImageTarget{}, err) {
+				return
+			}
+		}
+		repoNames = prioritizeRepositories(repoNames, cfg.priorities)
+
+		list := func(ctx context.Context, repoName string) iter.Seq2[ImageTarget, error] {
+			return r.repositoryImages(ctx, repoName, cfg)
+		}
+		for target, err := range eachRepository(ctx, repoNames, cfg.concurrency, list) {
+			if !yield(target, err) {
+				return
+			}
+		}
+	}
+}
 
+// repositoryImages returns an iterator over every image digest of a repo, newest first.
+func (r *ImageResolver) repositoryImages(ctx context.Context, repoName string, cfg *resolveConfig) iter.Seq2[ImageTarget, error] {
+	return func(yield func(ImageTarget, error) bool) {
+		repoLocation, _ := extractLocationAndRepository(repoName)
+		it := r.client.ListDockerImages(withFieldMask(ctx, dockerImageFields), &artifactregistrypb.ListDockerImagesRequest{
+			Parent:   repoName,
+			PageSize: int32(cfg.pageSize),
+			OrderBy:  "update_time desc",
+		}, r.callOpts...)
 		for {
-			// 1. Fetch next repository
-			repo, err := repoIt.Next()
+			img, err := it.Next()
 			if err == iterator.Done {
-				return // All repositories scanned
+				return
 			}
 			if err != nil {
-				// Yield error. If caller stops, we return.
-				if !yield(ImageTarget{}, fmt.Errorf("failed to list repositories: %w", err)) {
-					return
-				}
-				// If error occurs, we stop iteration to be safe.
+				yield(ImageTarget{}, fmt.Errorf("failed to list images of repo %s: %w", repoName, classifyAPIError(err)))
 				return
 			}
 
-			// Filter: Only process Docker repositories
-			if repo.Format != artifactregistrypb.Repository_DOCKER {
+			ref, err := ParseArtifactURI(img.Uri)
+			if err != nil || ref.Digest == nil {
+				if !yield(ImageTarget{}, fmt.Errorf("invalid image URI %s: %v", img.Uri, err)) {
+					return
+				}
 				continue
 			}
+			target := ImageTarget{
+				Artifact: ref,
+				URI:      img.Uri,
+				Location: repoLocation,
+				Image:    newCandidateImage(img, *ref.Digest).metadata(),
+			}
+			if !yield(target, nil) {
+				return
+			}
+		}
+	}
+}
 
-			// 2. Scan the repository for targets
-			// We buffer results per repository to perform the "best digest" selection logic.
-			targets, err := r.scanRepository(ctx, repo.Name)
+// repositoryRecentImages returns an iterator over the newest digests of each image of a repo
+// (see DigestsPerImage).
+func (r *ImageResolver) repositoryRecentImages(ctx context.Context, repoName string, cfg *resolveConfig) iter.Seq2[ImageTarget, error] {
+	return func(yield func(ImageTarget, error) bool) {
+		seen := make(map[string]int)
+		for target, err := range r.repositoryImages(ctx, repoName, cfg) {
 			if err != nil {
-				if !yield(ImageTarget{}, fmt.Errorf("failed to scan repo %s: %w", repo.Name, err)) {
+				if !yield(target, err) {
 					return
 				}
 				continue
 			}
-
-			// 3. Yield resolved targets
-			for _, target := range targets {
-				if !yield(target, nil) {
-					return
-				}
+			// Images are listed newest first
+			name := target.Artifact.ImageName
+			if seen[name] >= cfg.digests {
+				continue
+			}
+			seen[name]++
+			if len(target.Image.Tags) > 0 {
+				target.Artifact.Tag = utils.Ptr(target.Image.Tags[0])
+			}
+			if !yield(target, nil) {
+				return
 			}
 		}
 	}
 }
 
-// scanRepository fetches images from a repo, grouped by image name, and selects the best candidate for each.
-func (r *ImageResolver) scanRepository(ctx context.Context, repoName string) ([]ImageTarget, error) {
-	// Extract location and repository from repoName
-	location, repository := extractLocationAndRepository(repoName)
+// eachRepository chains the iterators of list over the repositories, in order, or runs up to
+// concurrency of them at once, yielding their items as they come.
+func eachRepository(ctx context.Context, repoNames []string, concurrency int, list func(ctx context.Context, repoName string) iter.Seq2[ImageTarget, error]) iter.Seq2[ImageTarget, error] {
+	return func(yield func(ImageTarget, error) bool) {
+		if concurrency <= 1 || len(repoNames) <= 1 {
+			for _, repoName := range repoNames {
+				for target, err := range list(ctx, repoName) {
+					if !yield(target, err) {
+						return
+					}
+				}
+			}
+			return
+		}
+
+		// Listings stop when the consumer does
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
 
-	// Optimization: Fetch only recent images (server-side sort)
-	imageReq := &artifactregistrypb.ListDockerImagesRequest{
-		Parent:  repoName,
-		OrderBy: "update_time desc",
+		type item struct {
+			target ImageTarget
+			err    error
+		}
+		items := make(chan item)
+		names := make(chan string)
+		var wg sync.WaitGroup
+		for range min(concurrency, len(repoNames)) {
+			wg.Go(func() {
+				for repoName := range names {
+					for target, err := range list(ctx, repoName) {
+						select {
+						case items <- item{target: target, err: err}:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+			})
+		}
+		go func() {
+			defer close(items)
+		feed:
+			for _, repoName := range repoNames {
+				select {
+				case names <- repoName:
+				case <-ctx.Done():
+					break feed
+				}
+			}
+			close(names)
+			wg.Wait()
+		}()
+
+		for it := range items {
+			if !yield(it.target, it.err) {
+				cancel()
+				for range items {
+				}
+				return
+			}
+		}
 	}
-	it := r.client.ListDockerImages(ctx, imageReq)
+}
 
-	// Group: ImageName -> []candidateImage
-	grouped := make(map[string][]candidateImage)
-	// Optimization: Track counts to stop collecting after MaxCandidates per image
-	counts := make(map[string]int)
+// ListLocations returns the IDs of the Artifact Registry locations available to the project, e.g. "us-central1".
+func (r *ImageResolver) ListLocations(ctx context.Context, projectID string) ([]string, error) {
+	req := &locationpb.ListLocationsRequest{Name: "projects/" + projectID}
+	it := r.client.ListLocations(ctx, req, r.callOpts...)
 
+	var locations []string
 	for {
-		img, err := it.Next()
+		loc, err := it.Next()
 		if err == iterator.Done {
-			break
+			return locations, nil
 		}
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to list locations: %w", classifyAPIError(err))
 		}
+		locations = append(locations, loc.GetLocationId())
+	}
+}
 
-		artifactReference, err := ParseArtifactURI(img.Uri)
+// listDockerRepositories returns the full resource names of all Docker repositories in the location,
+// or in every location of the project if location is AllLocations.
+// With AllLocations, a location that cannot be listed does not stop the others: its error is
+// returned in failed, along with the repositories of the remaining locations.
+func (r *ImageResolver) listDockerRepositories(ctx context.Context, projectID, location string, cfg *resolveConfig) (names []string, failed []error, err error) {
+	if location == AllLocations {
+		locations, err := r.ListLocations(ctx, projectID)
 		if err != nil {
-			return nil, fmt.Errorf("invalid image URI %s: %v", img.Uri, err)
+			return nil, nil, err
 		}
-		if artifactReference.Digest == nil {
-			log.Warn().
-				Str("uri", img.Uri).
-				Msg("Skipping image without digest")
-			// Skip images without digest (should not happen in GAR)
-			continue
+		for _, loc := range locations {
+			locNames, _, err := r.listDockerRepositories(ctx, projectID, loc, cfg)
+			if err != nil {
+				failed = append(failed, fmt.Errorf("location %s: %w", loc, err))
+				continue
+			}
+			names = append(names, locNames...)
 		}
-		imageName := artifactReference.ImageName
-		digest := *artifactReference.Digest
+		return names, failed, nil
+	}
 
-		// Skip if we already have enough candidates for this image
-		if counts[imageName] >= MaxCandidates {
+	parent := fmt.Sprintf("projects/%s/locations/%s", projectID, location)
+	repoReq := &artifactregistrypb.ListRepositoriesRequest{Parent: parent, PageSize: int32(cfg.pageSize)}
+	repoIt := r.client.ListRepositories(ctx, repoReq, r.callOpts...)
+
+	for {
+		repo, err := repoIt.Next()
+		if err == iterator.Done {
+			return names, nil, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list repositories: %w", classifyAPIError(err))
+		}
+
+		// Filter: Only process Docker repositories
+		if repo.Format != artifactregistrypb.Repository_DOCKER {
 			continue
 		}
+		names = append(names, repo.Name)
+	}
+}
+
+// prioritizeRepositories stably reorders repository names so that those whose repository ID
+// matches a priority pattern come first, ordered by the first pattern they match.
+func prioritizeRepositories(repoNames []string, patterns []string) []string {
+	if len(patterns) == 0 {
+		return repoNames
+	}
 
-		c := candidateImage{
-			Digest:     digest,
-			Tags:       img.Tags,
-			UpdateTime: img.UpdateTime.AsTime(),
-			URI:        img.Uri,
+	rank := func(repoName string) int {
+		_, repository := extractLocationAndRepository(repoName)
+		for i, p := range patterns {
+			if ok, _ := path.Match(p, repository); ok {
+				return i
+			}
 		}
-		grouped[imageName] = append(grouped[imageName], c)
-		counts[imageName]++
+		return len(patterns)
 	}
 
-	// Select the single best digest for each image group
-	var results []ImageTarget
-	for name, candidates := range grouped {
-		best := selectBestDigest(name, location, repository, candidates)
-		if best.Digest == "" {
-			return nil, fmt.Errorf("no valid candidates found for image %s", name)
+	ordered := slices.Clone(repoNames)
+	slices.SortStableFunc(ordered, func(a, b string) int {
+		return rank(a) - rank(b)
+	})
+	return ordered
+}
+
+// repositoryLatestImages returns an iterator over the best candidate of each image of a repo.
+// Images are listed newest first, so the candidates of an image are settled as soon as one of them
+// is tagged "latest" or the maximum number of candidates of them are seen: its target is yielded then, while the
+// listing goes on, and the targets of the other images when the listing ends. On error, the
+// targets of images not settled yet are dropped, as their best candidate is unknown.
+func (r *ImageResolver) repositoryLatestImages(ctx context.Context, repoName string, cfg *resolveConfig) iter.Seq2[ImageTarget, error] {
+	return func(yield func(ImageTarget, error) bool) {
+		log := zerolog.Ctx(ctx)
+
+		// Extract location and repository from repoName
+		location, repository := extractLocationAndRepository(repoName)
+
+		// Optimization: Fetch only recent images (server-side sort)
+		imageReq := &artifactregistrypb.ListDockerImagesRequest{
+			Parent:   repoName,
+			PageSize: int32(cfg.pageSize),
+			OrderBy:  "update_time desc",
 		}
+		it := r.client.ListDockerImages(withFieldMask(ctx, dockerImageFields), imageReq, r.callOpts...)
 
-		// Parse the URI to get ArtifactReference
-		artifactRef, err := ParseArtifactURI(best.URI)
-		if err != nil {
-			log.Warn().Err(err).Str("uri", best.URI).Msg("Failed to parse URI, skipping image")
-			continue
+		// Group: ImageName -> []candidateImage, for images not settled yet, in listing order
+		grouped := make(map[string][]candidateImage)
+		var pending []string
+		// Images whose target was yielded; their older digests are skipped
+		settled := make(map[string]bool)
+
+		for {
+			img, err := it.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				yield(ImageTarget{}, fmt.Errorf("failed to list images: %w", classifyAPIError(err)))
+				return
+			}
+
+			artifactReference, err := ParseArtifactURI(img.Uri)
+			if err != nil {
+				yield(ImageTarget{}, fmt.Errorf("invalid image URI %s: %v", img.Uri, err))
+				return
+			}
+			if artifactReference.Digest == nil {
+				log.Warn().
+					Str("uri", img.Uri).
+					Msg("Skipping image without digest")
+				// Skip images without digest (should not happen in GAR)
+				continue
+			}
+			imageName := artifactReference.ImageName
+			if settled[imageName] {
+				continue
+			}
+
+			candidate := newCandidateImage(img, *artifactReference.Digest)
+			if _, ok := grouped[imageName]; !ok {
+				pending = append(pending, imageName)
+			}
+			grouped[imageName] = append(grouped[imageName], candidate)
+
+			// No later candidate can be selected over a "latest" tag or beyond the window
+			if !slices.Contains(candidate.Tags, "latest") && len(grouped[imageName]) < cfg.maxCandidates() {
+				continue
+			}
+			settled[imageName] = true
+			target, ok := resolveTarget(log, imageName, location, repository, grouped[imageName])
+			delete(grouped, imageName)
+			if ok && !yield(target, nil) {
+				return
+			}
 		}
 
-		log.Debug().
-			Str("location", location).
-			Str("repository", repository).
-			Str("image_name", name).
-			Str("digest", best.Digest).
-			Str("uri", best.URI).
-			Msg("Resolved image target")
-
-		// get one tag if available
-		if len(best.Tags) > 0 {
-			artifactRef.Tag = utils.ToPtr(best.Tags[0])
+		// Select the single best digest for each remaining image group
+		for _, name := range pending {
+			candidates, ok := grouped[name]
+			if !ok {
+				continue
+			}
+			target, ok := resolveTarget(log, name, location, repository, candidates)
+			if ok && !yield(target, nil) {
+				return
+			}
 		}
+	}
+}
+
+// resolveTarget returns the target of the best of the candidates of an image.
+func resolveTarget(log *zerolog.Logger, name, location, repository string, candidates []candidateImage) (ImageTarget, bool) {
+	best := selectBestDigest(log, name, location, repository, candidates)
 
-		results = append(results, ImageTarget{
-			Artifact: artifactRef,
-			URI:      best.URI,
-			Location: location,
-		})
+	// Parse the URI to get ArtifactReference
+	artifactRef, err := ParseArtifactURI(best.URI)
+	if err != nil {
+		log.Warn().Err(err).Str("uri", best.URI).Msg("Failed to parse URI, skipping image")
+		return ImageTarget{}, false
 	}
 
-	return results, nil
+	log.Debug().
+		Str("location", location).
+		Str("repository", repository).
+		Str("image_name", name).
+		Str("digest", best.Digest).
+		Str("uri", best.URI).
+		Msg("Resolved image target")
+
+	// get one tag if available
+	if len(best.Tags) > 0 {
+		artifactRef.Tag = utils.Ptr(best.Tags[0])
+	}
+
+	return ImageTarget{
+		Artifact: artifactRef,
+		URI:      best.URI,
+		Location: location,
+		Image:    best.metadata(),
+	}, true
 }
 
 // selectBestDigest chooses the best candidate based on policy:
 // 1. Prefer candidate with "latest" tag.
 // 2. If no "latest", prefer the one with the most recent UpdateTime.
-func selectBestDigest(imageName, location, repository string, candidates []candidateImage) candidateImage {
+func selectBestDigest(log *zerolog.Logger, imageName, location, repository string, candidates []candidateImage) candidateImage {
 	if len(candidates) == 0 {
 		return candidateImage{}
 	}
@@ -245,28 +648,63 @@ func selectBestDigest(imageName, location, repository string, candidates []candi
 	return newest
 }
 
-// compiledGarRegex pre-compiles the regex for performance.
-// Regex remains the same as the previous version.
-var compiledGarRegex = regexp.MustCompile(`^([a-z0-9-]+-docker\.pkg\.dev)/([^/]+)/([^/]+)/([^:@]+)(?::([^@]+))?(?:@(sha256:[a-fA-F0-9]{64}))?$`)
-
-// ParseArtifactURI parses a raw GAR URI string into a structured ArtifactReference.
-func ParseArtifactURI(uri string) (schemas.ArtifactReference, error) {
-	matches := compiledGarRegex.FindStringSubmatch(uri)
+// ResolveImage turns an explicit image reference into a scan target without listing the registry.
+// A reference without digest is resolved through its tag ("latest" if it has none either).
+func (r *ImageResolver) ResolveImage(ctx context.Context, ref schemas.ArtifactReference) (ImageTarget, error) {
+	location, ok := strings.CutSuffix(ref.Host, "-docker.pkg.dev")
+	if !ok || location == "" {
+		return ImageTarget{}, fmt.Errorf("invalid Artifact Registry host: %s", ref.Host)
+	}
+	repoName := fmt.Sprintf("projects/%s/locations/%s/repositories/%s", ref.ProjectID, location, ref.RepositoryID)
+	// Image names may contain slashes, which must be escaped in resource names
+	imageID := url.PathEscape(ref.ImageName)
+
+	if ref.Digest == nil {
+		tag := "latest"
+		if ref.Tag != nil {
+			tag = *ref.Tag
+		}
+		t, err := r.client.GetTag(ctx, &artifactregistrypb.GetTagRequest{
+			Name: fmt.Sprintf("%s/packages/%s/tags/%s", repoName, imageID, tag),
+		}, r.callOpts...)
+		if err != nil {
+			return ImageTarget{}, fmt.Errorf("failed to resolve tag of %s: %w", ref, classifyAPIError(err))
+		}
+		// The tag points at projects/.../versions/{digest}
+		version := t.GetVersion()
+		digest := version[strings.LastIndex(version, "/")+1:]
+		ref.Tag = &tag
+		ref.Digest = &digest
+	}
 
-	if matches == nil {
-		return schemas.ArtifactReference{}, fmt.Errorf("invalid GAR URI format: %s", uri)
+	img, err := r.client.GetDockerImage(ctx, &artifactregistrypb.GetDockerImageRequest{
+		Name: fmt.Sprintf("%s/dockerImages/%s@%s", repoName, imageID, *ref.Digest),
+	}, r.callOpts...)
+	if err != nil {
+		return ImageTarget{}, fmt.Errorf("failed to get image %s: %w", ref, classifyAPIError(err))
 	}
 
-	return schemas.ArtifactReference{
-		Host:         matches[1],
-		ProjectID:    matches[2],
-		RepositoryID: matches[3],
-		ImageName:    matches[4],
-		Tag:          utils.ToPtr(matches[5]),
-		Digest:       utils.ToPtr(matches[6]),
+	return ImageTarget{
+		Artifact: ref,
+		URI:      img.GetUri(),
+		Location: location,
+		Image:    newCandidateImage(img, *ref.Digest).metadata(),
 	}, nil
 }
 
+// ParseArtifactURI parses an image reference into a structured ArtifactReference (see schemas.ParseArtifactURI).
+func ParseArtifactURI(uri string) (schemas.ArtifactReference, error) {
+	return schemas.ParseArtifactURI(uri)
+}
+
+// asTime converts an optional API timestamp, returning the zero time when it is unset.
+func asTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
 func extractLocationAndRepository(repoName string) (location, repository string) {
 	// Expected format: projects/{project}/locations/{location}/repositories/{repo}
 	parts := strings.Split(repoName, "/")
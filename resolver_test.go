@@ -1,6 +1,8 @@
 package drydock_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -8,6 +10,7 @@ import (
 	"github.com/hiro-o918/drydock"
 	"github.com/hiro-o918/drydock/schemas"
 	"github.com/hiro-o918/drydock/utils"
+	"github.com/rs/zerolog"
 )
 
 func TestParseArtifactURI(t *testing.T) {
@@ -28,7 +31,7 @@ func TestParseArtifactURI(t *testing.T) {
 				RepositoryID: "my-repo",
 				ImageName:    "my-image",
 				Tag:          nil,
-				Digest:       utils.ToPtr(validHash),
+				Digest:       utils.Ptr(validHash),
 			},
 			wantErr: false,
 		},
@@ -41,7 +44,7 @@ func TestParseArtifactURI(t *testing.T) {
 				RepositoryID: "my-repo",
 				ImageName:    "namespace/my-image",
 				Tag:          nil,
-				Digest:       utils.ToPtr(validHash),
+				Digest:       utils.Ptr(validHash),
 			},
 			wantErr: false,
 		},
@@ -53,7 +56,7 @@ func TestParseArtifactURI(t *testing.T) {
 				ProjectID:    "prod",
 				RepositoryID: "docker",
 				ImageName:    "nginx",
-				Tag:          utils.ToPtr("v1.2.3"),
+				Tag:          utils.Ptr("v1.2.3"),
 				Digest:       nil,
 			},
 			wantErr: false,
@@ -66,16 +69,56 @@ func TestParseArtifactURI(t *testing.T) {
 				ProjectID:    "prod",
 				RepositoryID: "docker",
 				ImageName:    "nginx",
-				Tag:          utils.ToPtr("latest"),
-				Digest:       utils.ToPtr(validHash),
+				Tag:          utils.Ptr("latest"),
+				Digest:       utils.Ptr(validHash),
 			},
 			wantErr: false,
 		},
+		{
+			name:  "Valid non-GAR URI with port and sha512 digest",
+			input: "localhost:5000/team/my_app.v2@sha512:" + strings.Repeat("ab", 64),
+			want: schemas.ArtifactReference{
+				Host:      "localhost:5000",
+				ImageName: "team/my_app.v2",
+				Digest:    utils.Ptr("sha512:" + strings.Repeat("ab", 64)),
+			},
+			wantErr: false,
+		},
+		{
+			name:  "Valid non-GAR URI with port and tag",
+			input: "registry.example.com:443/library/app__x--y:1.0_rc",
+			want: schemas.ArtifactReference{
+				Host:      "registry.example.com:443",
+				ImageName: "library/app__x--y",
+				Tag:       utils.Ptr("1.0_rc"),
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Fail: Missing registry host",
+			input:   "library/nginx:latest",
+			wantErr: true,
+		},
+		{
+			name:    "Fail: Uppercase repository name",
+			input:   "localhost:5000/Team/app",
+			wantErr: true,
+		},
+		{
+			name:    "Fail: Truncated sha512 digest",
+			input:   "localhost:5000/app@sha512:" + validHash[len("sha256:"):],
+			wantErr: true,
+		},
 		{
 			name:    "Fail: Insufficient path segments",
 			input:   "us-central1-docker.pkg.dev/project@" + validHash,
 			wantErr: true,
 		},
+		{
+			name:    "should reject an empty tag",
+			input:   "us-central1-docker.pkg.dev/my-project/my-repo/my-image:",
+			wantErr: true,
+		},
 		{
 			name:    "Fail: Invalid digest prefix (md5)",
 			input:   "us-central1-docker.pkg.dev/my-project/my-repo/my-image@md5:12345",
@@ -101,11 +144,65 @@ func TestParseArtifactURI(t *testing.T) {
 	}
 }
 
+func TestParseArtifactURI_ParseError(t *testing.T) {
+	tests := map[string]struct {
+		input         string
+		wantComponent string
+	}{
+		"should blame the host when it is missing": {
+			input:         "nginx:latest",
+			wantComponent: schemas.ReferenceHost,
+		},
+		"should blame the host when it is invalid": {
+			input:         "bad_host:5000/app",
+			wantComponent: schemas.ReferenceHost,
+		},
+		"should blame the path for invalid characters": {
+			input:         "localhost:5000/app!/x",
+			wantComponent: schemas.ReferencePath,
+		},
+		"should blame the path for too few GAR segments": {
+			input:         "us-central1-docker.pkg.dev/p/app",
+			wantComponent: schemas.ReferencePath,
+		},
+		"should blame the tag": {
+			input:         "localhost:5000/app:.bad",
+			wantComponent: schemas.ReferenceTag,
+		},
+		"should blame the tag when a colon has none": {
+			input:         "localhost:5000/app:",
+			wantComponent: schemas.ReferenceTag,
+		},
+		"should blame the digest when an at sign has none": {
+			input:         "localhost:5000/app@",
+			wantComponent: schemas.ReferenceDigest,
+		},
+		"should blame the digest for unsupported algorithms": {
+			input:         "localhost:5000/app@md5:d41d8cd98f00b204e9800998ecf8427e",
+			wantComponent: schemas.ReferenceDigest,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := drydock.ParseArtifactURI(tt.input)
+			var parseErr *schemas.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("ParseArtifactURI() error = %v, want *schemas.ParseError", err)
+			}
+			if parseErr.Component != tt.wantComponent {
+				t.Errorf("Component = %s, want %s (%v)", parseErr.Component, tt.wantComponent, err)
+			}
+		})
+	}
+}
+
 func TestSelectBestDigest(t *testing.T) {
 	// Helper times for comparison
 	now := time.Now()
 	yesterday := now.Add(-24 * time.Hour)
 	lastWeek := now.Add(-7 * 24 * time.Hour)
+	logger := zerolog.Nop()
 
 	tests := map[string]struct {
 		candidates []drydock.ExportCandidateImage
@@ -148,7 +245,7 @@ func TestSelectBestDigest(t *testing.T) {
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			got := drydock.ExportSelectBestDigest("test-image", "us-central1", "test-repo", tt.candidates)
+			got := drydock.ExportSelectBestDigest(&logger, "test-image", "us-central1", "test-repo", tt.candidates)
 
 			// cmp.Diff handles deep comparison including time.Time
 			if diff := cmp.Diff(tt.want, got); diff != "" {
@@ -198,3 +295,39 @@ func TestExtractLocationAndRepository(t *testing.T) {
 		})
 	}
 }
+
+func TestPrioritizeRepositories(t *testing.T) {
+	repo := func(id string) string {
+		return "projects/p/locations/us-central1/repositories/" + id
+	}
+
+	tests := map[string]struct {
+		repos    []string
+		patterns []string
+		want     []string
+	}{
+		"should keep original order when no patterns are given": {
+			repos: []string{repo("b"), repo("a")},
+			want:  []string{repo("b"), repo("a")},
+		},
+		"should move matching repositories first in pattern order": {
+			repos:    []string{repo("dev-api"), repo("prod-web"), repo("staging"), repo("prod-api"), repo("infra")},
+			patterns: []string{"prod-*", "infra"},
+			want:     []string{repo("prod-web"), repo("prod-api"), repo("infra"), repo("dev-api"), repo("staging")},
+		},
+		"should keep original order when nothing matches": {
+			repos:    []string{repo("b"), repo("a")},
+			patterns: []string{"prod-*"},
+			want:     []string{repo("b"), repo("a")},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := drydock.ExportPrioritizeRepositories(tt.repos, tt.patterns)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("PrioritizeRepositories() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package drydock
+
+import (
+	"errors"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy controls how failed Google Cloud API calls are retried.
+// The same policy is used by the resolver and the analyzer, for every page of every list call.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts per call, including the first one. 1 disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the pause before the first retry
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the pause between retries
+	MaxBackoff time.Duration
+
+	// Multiplier grows the pause after each retry
+	Multiplier float64
+
+	// Codes are the gRPC status codes that are retried
+	Codes []codes.Code
+
+	// Budget limits the total number of retries across all calls made with the policy.
+	// 0 means unlimited.
+	Budget int
+}
+
+// DefaultRetryPolicy returns the policy used unless WithRetryPolicy is given:
+// up to 4 attempts with exponential backoff on transient errors and quota exhaustion.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     16 * time.Second,
+		Multiplier:     2,
+		Codes: []codes.Code{
+			codes.Unavailable,
+			codes.DeadlineExceeded,
+			codes.ResourceExhausted,
+			codes.Aborted,
+		},
+	}
+}
+
+// validate checks that the policy can be applied.
+func (p RetryPolicy) validate() error {
+	if p.MaxAttempts < 1 {
+		return errors.New("max attempts must be at least 1")
+	}
+	if p.InitialBackoff < 0 || p.MaxBackoff < 0 {
+		return errors.New("backoff must not be negative")
+	}
+	if p.Multiplier != 0 && p.Multiplier < 1 {
+		return errors.New("multiplier must be at least 1")
+	}
+	if p.Budget < 0 {
+		return errors.New("budget must not be negative")
+	}
+	return nil
+}
+
+// callOptions returns the gax call options applying the policy. The retry budget is shared
+// by every call made with the returned options.
+func (p RetryPolicy) callOptions() []gax.CallOption {
+	if p.MaxAttempts <= 1 {
+		return nil
+	}
+	var budget *atomic.Int64
+	if p.Budget > 0 {
+		budget = &atomic.Int64{}
+		budget.Store(int64(p.Budget))
+	}
+	return []gax.CallOption{
+		gax.WithRetry(func() gax.Retryer {
+			return &policyRetryer{
+				policy: p,
+				budget: budget,
+				backoff: gax.Backoff{
+					Initial:    p.InitialBackoff,
+					Max:        p.MaxBackoff,
+					Multiplier: p.Multiplier,
+				},
+			}
+		}),
+	}
+}
+
+// policyRetryer is the gax.Retryer of a single call.
+type policyRetryer struct {
+	policy   RetryPolicy
+	budget   *atomic.Int64 // nil when unlimited
+	backoff  gax.Backoff
+	attempts int
+}
+
+// Retry implements gax.Retryer.
+func (r *policyRetryer) Retry(err error) (time.Duration, bool) {
+	r.attempts++
+	if r.attempts >= r.policy.MaxAttempts {
+		return 0, false
+	}
+	s, ok := status.FromError(err)
+	if !ok || !slices.Contains(r.policy.Codes, s.Code()) {
+		return 0, false
+	}
+	if r.budget != nil && r.budget.Add(-1) < 0 {
+		return 0, false
+	}
+	return r.backoff.Pause(), true
+}
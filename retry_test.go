@@ -0,0 +1,88 @@
+package drydock_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	base := drydock.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		Multiplier:     2,
+		Codes:          []codes.Code{codes.Unavailable},
+	}
+	withBudget := base
+	withBudget.Budget = 1
+
+	tests := map[string]struct {
+		policy    drydock.RetryPolicy
+		calls     int
+		err       error
+		wantRetry []bool // per call, per failed attempt
+	}{
+		"should retry retryable codes until max attempts": {
+			policy:    base,
+			calls:     1,
+			err:       status.Error(codes.Unavailable, "try again"),
+			wantRetry: []bool{true, true, false},
+		},
+		"should not retry other codes": {
+			policy:    base,
+			calls:     1,
+			err:       status.Error(codes.PermissionDenied, "denied"),
+			wantRetry: []bool{false},
+		},
+		"should not retry non-gRPC errors": {
+			policy:    base,
+			calls:     1,
+			err:       errors.New("boom"),
+			wantRetry: []bool{false},
+		},
+		"should share the budget across calls": {
+			policy:    withBudget,
+			calls:     2,
+			err:       status.Error(codes.Unavailable, "try again"),
+			wantRetry: []bool{true, false, false},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			factory := drydock.ExportRetryerFactory(tt.policy)
+			if factory == nil {
+				t.Fatal("policy installed no retryer")
+			}
+
+			var got []bool
+			for range tt.calls {
+				retryer := factory()
+				for {
+					_, ok := retryer.Retry(tt.err)
+					got = append(got, ok)
+					if !ok {
+						break
+					}
+				}
+			}
+			if diff := cmp.Diff(tt.wantRetry, got); diff != "" {
+				t.Errorf("Retry() decisions mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Disabled(t *testing.T) {
+	p := drydock.DefaultRetryPolicy()
+	p.MaxAttempts = 1
+	if factory := drydock.ExportRetryerFactory(p); factory != nil {
+		t.Errorf("policy with one attempt installed a retryer")
+	}
+}
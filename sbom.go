@@ -0,0 +1,213 @@
+package drydock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// DependencyGraph is the dependency graph of an image's language packages, read from an SBOM.
+// It tells which direct dependency pulls in a vulnerable transitive package (see WithSBOM).
+type DependencyGraph struct {
+	roots []string
+	nodes map[string]sbomNode
+	edges map[string][]string // ref → refs it depends on
+}
+
+// sbomNode is a package of the dependency graph.
+type sbomNode struct {
+	label string // e.g., "golang.org/x/net@v0.17.0"
+	key   string // purl without version and qualifiers, see purlKey
+}
+
+// ReadSBOM reads the dependency graph of a CycloneDX or SPDX JSON document.
+// Packages are only linked by explicit dependency relationships: SBOMs listing what an image
+// contains without them give a graph without paths.
+func ReadSBOM(r io.Reader) (*DependencyGraph, error) {
+	var doc struct {
+		// CycloneDX
+		BOMFormat string `json:"bomFormat"`
+		Metadata  struct {
+			Component *cycloneDXComponent `json:"component"`
+		} `json:"metadata"`
+		Components   []cycloneDXComponent `json:"components"`
+		Dependencies []struct {
+			Ref       string   `json:"ref"`
+			DependsOn []string `json:"dependsOn"`
+		} `json:"dependencies"`
+
+		// SPDX
+		SPDXVersion string   `json:"spdxVersion"`
+		Describes   []string `json:"documentDescribes"`
+		Packages    []struct {
+			ID           string `json:"SPDXID"`
+			Name         string `json:"name"`
+			Version      string `json:"versionInfo"`
+			ExternalRefs []struct {
+				Type    string `json:"referenceType"`
+				Locator string `json:"referenceLocator"`
+			} `json:"externalRefs"`
+		} `json:"packages"`
+		Relationships []struct {
+			From    string `json:"spdxElementId"`
+			Type    string `json:"relationshipType"`
+			Related string `json:"relatedSpdxElement"`
+		} `json:"relationships"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode SBOM: %w", err)
+	}
+
+	g := &DependencyGraph{nodes: make(map[string]sbomNode), edges: make(map[string][]string)}
+	switch {
+	case doc.BOMFormat == "CycloneDX":
+		if root := doc.Metadata.Component; root != nil {
+			g.roots = append(g.roots, root.Ref)
+			g.addCycloneDX(*root)
+		}
+		for _, c := range doc.Components {
+			g.addCycloneDX(c)
+		}
+		for _, d := range doc.Dependencies {
+			g.edges[d.Ref] = append(g.edges[d.Ref], d.DependsOn...)
+		}
+	case doc.SPDXVersion != "":
+		for _, p := range doc.Packages {
+			node := sbomNode{label: packageLabel(p.Name, p.Version)}
+			for _, ref := range p.ExternalRefs {
+				if ref.Type == "purl" {
+					node.key = purlKey(ref.Locator)
+				}
+			}
+			g.nodes[p.ID] = node
+		}
+		g.roots = append(g.roots, doc.Describes...)
+		for _, rel := range doc.Relationships {
+			switch rel.Type {
+			case "DESCRIBES":
+				g.roots = append(g.roots, rel.Related)
+			case "DEPENDS_ON":
+				g.edges[rel.From] = append(g.edges[rel.From], rel.Related)
+			case "DEPENDENCY_OF":
+				g.edges[rel.Related] = append(g.edges[rel.Related], rel.From)
+			}
+		}
+	default:
+		return nil, errors.New("unsupported SBOM format: expected CycloneDX or SPDX JSON")
+	}
+	return g, nil
+}
+
+// cycloneDXComponent is a component of a CycloneDX document, possibly with nested components.
+type cycloneDXComponent struct {
+	Ref        string               `json:"bom-ref"`
+	Group      string               `json:"group"`
+	Name       string               `json:"name"`
+	Version    string               `json:"version"`
+	PURL       string               `json:"purl"`
+	Components []cycloneDXComponent `json:"components"`
+}
+
+func (g *DependencyGraph) addCycloneDX(c cycloneDXComponent) {
+	name := c.Name
+	if c.Group != "" {
+		name = c.Group + ":" + c.Name
+	}
+	g.nodes[c.Ref] = sbomNode{label: packageLabel(name, c.Version), key: purlKey(c.PURL)}
+	for _, nested := range c.Components {
+		g.addCycloneDX(nested)
+	}
+}
+
+// Path returns the shortest dependency chain from a direct dependency of the SBOM's root to the
+// package with the given purl (versions and qualifiers are ignored), e.g.
+// ["github.com/foo/client@v1.2.0", "golang.org/x/net@v0.17.0"].
+// It returns nil if the package is not reachable through dependency relationships.
+func (g *DependencyGraph) Path(purl string) []string {
+	target := purlKey(purl)
+	if target == "" {
+		return nil
+	}
+
+	// Breadth-first from the roots, remembering how each package was reached
+	parent := make(map[string]string)
+	visited := make(map[string]bool)
+	queue := make([]string, 0, len(g.roots))
+	for _, root := range g.roots {
+		if !visited[root] {
+			visited[root] = true
+			queue = append(queue, root)
+		}
+	}
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		for _, dep := range g.edges[ref] {
+			if visited[dep] {
+				continue
+			}
+			visited[dep] = true
+			parent[dep] = ref
+			if g.nodes[dep].key == target {
+				return g.chain(dep, parent)
+			}
+			queue = append(queue, dep)
+		}
+	}
+	return nil
+}
+
+// chain returns the labels from the direct dependency down to ref, leaving out the root.
+func (g *DependencyGraph) chain(ref string, parent map[string]string) []string {
+	var path []string
+	for {
+		up, ok := parent[ref]
+		if !ok {
+			break
+		}
+		path = append([]string{g.nodes[ref].label}, path...)
+		ref = up
+	}
+	return path
+}
+
+// annotateDependencyPaths sets the dependency path of the language package findings found in g.
+func annotateDependencyPaths(vulns []schemas.Vulnerability, g *DependencyGraph) {
+	for i := range vulns {
+		if strings.EqualFold(vulns[i].PackageType, "OS") || vulns[i].PURL == "" {
+			continue
+		}
+		vulns[i].DependencyPath = g.Path(vulns[i].PURL)
+	}
+}
+
+// packageLabel formats a package as "name@version".
+func packageLabel(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + "@" + version
+}
+
+// purlKey normalizes a purl to its type, namespace and name (e.g., "golang/golang.org/x/net"),
+// so that packages can be matched regardless of version and qualifiers.
+func purlKey(purl string) string {
+	rest, ok := strings.CutPrefix(purl, "pkg:")
+	if !ok {
+		return ""
+	}
+	rest, _, _ = strings.Cut(rest, "#")
+	rest, _, _ = strings.Cut(rest, "?")
+	if i := strings.LastIndex(rest, "@"); i > strings.LastIndex(rest, "/") {
+		rest = rest[:i]
+	}
+	if unescaped, err := url.PathUnescape(rest); err == nil {
+		rest = unescaped
+	}
+	return strings.ToLower(rest)
+}
@@ -0,0 +1,112 @@
+package drydock_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+const cycloneDXSBOM = `{
+  "bomFormat": "CycloneDX",
+  "metadata": {"component": {"bom-ref": "app", "name": "example.com/app"}},
+  "components": [
+    {"bom-ref": "client", "name": "github.com/foo/client", "version": "v1.2.0", "purl": "pkg:golang/github.com/foo/client@v1.2.0"},
+    {"bom-ref": "net", "name": "golang.org/x/net", "version": "v0.17.0", "purl": "pkg:golang/golang.org/x/net@v0.17.0"},
+    {"bom-ref": "log4j", "group": "org.apache.logging.log4j", "name": "log4j-core", "version": "2.14.1", "purl": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"},
+    {"bom-ref": "orphan", "name": "github.com/bar/orphan", "version": "v0.1.0", "purl": "pkg:golang/github.com/bar/orphan@v0.1.0"}
+  ],
+  "dependencies": [
+    {"ref": "app", "dependsOn": ["client", "log4j"]},
+    {"ref": "client", "dependsOn": ["net"]}
+  ]
+}`
+
+const spdxSBOM = `{
+  "spdxVersion": "SPDX-2.3",
+  "documentDescribes": ["SPDXRef-app"],
+  "packages": [
+    {"SPDXID": "SPDXRef-app", "name": "app"},
+    {"SPDXID": "SPDXRef-express", "name": "express", "versionInfo": "4.18.2", "externalRefs": [{"referenceType": "purl", "referenceLocator": "pkg:npm/express@4.18.2"}]},
+    {"SPDXID": "SPDXRef-qs", "name": "qs", "versionInfo": "6.11.0", "externalRefs": [{"referenceType": "purl", "referenceLocator": "pkg:npm/qs@6.11.0"}]}
+  ],
+  "relationships": [
+    {"spdxElementId": "SPDXRef-app", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-express"},
+    {"spdxElementId": "SPDXRef-qs", "relationshipType": "DEPENDENCY_OF", "relatedSpdxElement": "SPDXRef-express"}
+  ]
+}`
+
+func TestDependencyGraph_Path(t *testing.T) {
+	tests := map[string]struct {
+		sbom string
+		purl string
+		want []string
+	}{
+		"should report the chain to a transitive Go module": {
+			sbom: cycloneDXSBOM,
+			purl: "pkg:golang/golang.org/x/net@v0.17.0",
+			want: []string{"github.com/foo/client@v1.2.0", "golang.org/x/net@v0.17.0"},
+		},
+		"should report a direct Maven dependency alone": {
+			sbom: cycloneDXSBOM,
+			purl: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+			want: []string{"org.apache.logging.log4j:log4j-core@2.14.1"},
+		},
+		"should ignore versions and qualifiers": {
+			sbom: cycloneDXSBOM,
+			purl: "pkg:golang/golang.org/x/net@v0.10.0?type=module",
+			want: []string{"github.com/foo/client@v1.2.0", "golang.org/x/net@v0.17.0"},
+		},
+		"should not report packages outside of the dependency graph": {
+			sbom: cycloneDXSBOM,
+			purl: "pkg:golang/github.com/bar/orphan@v0.1.0",
+			want: nil,
+		},
+		"should follow SPDX relationships in both directions": {
+			sbom: spdxSBOM,
+			purl: "pkg:npm/qs@6.11.0",
+			want: []string{"express@4.18.2", "qs@6.11.0"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := drydock.ReadSBOM(strings.NewReader(tt.sbom))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, g.Path(tt.purl)); diff != "" {
+				t.Errorf("Path() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadSBOM_UnsupportedFormat(t *testing.T) {
+	if _, err := drydock.ReadSBOM(strings.NewReader(`{"packages": []}`)); err == nil {
+		t.Error("ReadSBOM() error = nil, want an error for an unknown format")
+	}
+}
+
+func TestAnnotateDependencyPaths(t *testing.T) {
+	g, err := drydock.ReadSBOM(strings.NewReader(cycloneDXSBOM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vulns := []schemas.Vulnerability{
+		{ID: "GO-1", PackageType: "GO", PURL: "pkg:golang/golang.org/x/net@v0.17.0"},
+		{ID: "CVE-1", PackageType: "OS", PURL: "pkg:deb/debian/openssl@3.0.11"},
+	}
+
+	drydock.ExportAnnotateDependencyPaths(vulns, g)
+
+	want := []schemas.Vulnerability{
+		{ID: "GO-1", PackageType: "GO", PURL: "pkg:golang/golang.org/x/net@v0.17.0", DependencyPath: []string{"github.com/foo/client@v1.2.0", "golang.org/x/net@v0.17.0"}},
+		{ID: "CVE-1", PackageType: "OS", PURL: "pkg:deb/debian/openssl@3.0.11"},
+	}
+	if diff := cmp.Diff(want, vulns); diff != "" {
+		t.Errorf("annotateDependencyPaths() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,126 @@
+package drydock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hiro-o918/drydock/schemas"
+	"google.golang.org/api/iterator"
+	grafeaspb "google.golang.org/genproto/googleapis/grafeas/v1"
+)
+
+// SBOMDocument is an SBOM generated by Artifact Analysis for an image (e.g., with
+// "gcloud artifacts sbom export"), as downloaded from Cloud Storage.
+type SBOMDocument struct {
+	// Reference tells where the document is stored, and in which format
+	Reference schemas.SBOMReference
+
+	// Data is the document itself, e.g. SPDX JSON
+	Data []byte
+}
+
+// FetchSBOM downloads the SBOM of the image, found through its SBOM reference occurrence.
+// The image must be given by digest. It returns ErrSBOMNotFound if no SBOM was generated for it.
+// Downloads require EnableSBOMDownload, which WithSBOMDownload calls on the default analyzer.
+func (a *ArtifactRegistryAnalyzer) FetchSBOM(ctx context.Context, ref schemas.ArtifactReference) (*SBOMDocument, error) {
+	if ref.Digest == nil || *ref.Digest == "" {
+		return nil, fmt.Errorf("%s: an SBOM can only be fetched by digest", ref)
+	}
+	if a.objects == nil {
+		return nil, errors.New("SBOM download is not enabled on the analyzer (see EnableSBOMDownload)")
+	}
+
+	location := strings.TrimSuffix(ref.Host, "-docker.pkg.dev")
+	listReq := &grafeaspb.ListOccurrencesRequest{
+		Parent: fmt.Sprintf("projects/%s", ref.ProjectID),
+		Filter: fmt.Sprintf(`resourceUrl="%s" AND kind="SBOM_REFERENCE"`, ref.ToResourceURL(location)),
+	}
+	it := a.occurrences.ListOccurrences(withFieldMask(ctx, sbomReferenceOccurrenceFields), listReq, a.callOpts...)
+
+	var reference *schemas.SBOMReference
+	for {
+		occ, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list SBOM references: %w", classifyAPIError(err))
+		}
+		predicate := occ.GetSbomReference().GetPayload().GetPredicate()
+		if predicate.GetLocation() != "" {
+			reference = &schemas.SBOMReference{URI: predicate.GetLocation(), MediaType: predicate.GetMimeType()}
+			break
+		}
+	}
+	if reference == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSBOMNotFound, ref)
+	}
+
+	bucket, object, err := parseGCSURI(reference.URI)
+	if err != nil {
+		return nil, err
+	}
+	body, err := a.objects.ReadObject(ctx, bucket, object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download SBOM %s: %w", reference.URI, classifyAPIError(err))
+	}
+	defer func() { _ = body.Close() }()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download SBOM %s: %w", reference.URI, err)
+	}
+	return &SBOMDocument{Reference: *reference, Data: data}, nil
+}
+
+// parseGCSURI splits a gs://bucket/object URI.
+func parseGCSURI(uri string) (bucket, object string, err error) {
+	rest, ok := strings.CutPrefix(uri, "gs://")
+	if ok {
+		bucket, object, ok = strings.Cut(rest, "/")
+	}
+	if !ok || bucket == "" || object == "" {
+		return "", "", fmt.Errorf("invalid Cloud Storage URI %q: expected gs://BUCKET/OBJECT", uri)
+	}
+	return bucket, object, nil
+}
+
+// sbomFileName names the downloaded SBOM of an image after its repository, image and digest,
+// e.g. "repo_team_api_sha256-abc.spdx.json".
+func sbomFileName(ref schemas.ArtifactReference, mediaType string) string {
+	name := ref.RepositoryID + "/" + ref.ImageName
+	if ref.Digest != nil {
+		name += "/" + *ref.Digest
+	}
+	name = strings.NewReplacer("/", "_", ":", "-").Replace(name)
+
+	switch {
+	case strings.Contains(mediaType, "spdx"):
+		return name + ".spdx.json"
+	case strings.Contains(mediaType, "cyclonedx"):
+		return name + ".cdx.json"
+	default:
+		return name + ".json"
+	}
+}
+
+// downloadSBOM fetches the SBOM of the artifact and writes it to the SBOM directory.
+func (s *Scanner) downloadSBOM(ctx context.Context, artifact schemas.ArtifactReference) (*schemas.SBOMReference, error) {
+	doc, err := s.sbomFetcher.FetchSBOM(ctx, artifact)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.sbomDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create SBOM directory: %w", err)
+	}
+	reference := doc.Reference
+	reference.Path = filepath.Join(s.sbomDir, sbomFileName(artifact, reference.MediaType))
+	if err := os.WriteFile(reference.Path, doc.Data, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write SBOM: %w", err)
+	}
+	return &reference, nil
+}
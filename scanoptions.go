@@ -0,0 +1,73 @@
+package drydock
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// ScanOptions selects what one scan reports (see Scanner.Scan). Fields left unset fall back to
+// the settings of the Scanner, so a Scanner configured once can run differently filtered scans.
+type ScanOptions struct {
+	// MinSeverity is the lowest severity reported; unspecified reports every severity
+	MinSeverity schemas.Severity
+
+	// FixableOnly reports only vulnerabilities with a fix available
+	FixableOnly bool
+
+	// OnlyIDs, if non-empty, reports only these vulnerability IDs, instead of those of WithOnlyVulnerabilities
+	OnlyIDs []string
+
+	// SkipIDs suppresses these vulnerability IDs, in addition to those of WithSkipVulnerabilities
+	SkipIDs []string
+
+	// Filter, if set, is used instead of the filter of WithFilter (see NewVulnerabilityFilter)
+	Filter *VulnerabilityFilter
+
+	// SLA, if set, is used instead of the policy of WithSLA
+	SLA schemas.SLAPolicy
+
+	// Policy, if set, is used instead of the policy of WithScanPolicy
+	Policy *ScanPolicy
+
+	// Progress, if set, receives the progress events of this scan, in addition to the function of WithProgress
+	Progress ProgressFunc
+}
+
+// scanOptions validates opts and completes it with the settings of the scanner.
+func (s *Scanner) scanOptions(opts ScanOptions) (ScanOptions, error) {
+	for severity, window := range opts.SLA {
+		if window <= 0 {
+			return opts, fmt.Errorf("invalid scan options: SLA window for %s must be positive", severity)
+		}
+	}
+	if opts.Policy != nil {
+		if err := opts.Policy.validate(); err != nil {
+			return opts, fmt.Errorf("invalid scan options: policy: %w", err)
+		}
+	} else {
+		opts.Policy = s.policy
+	}
+	if len(opts.OnlyIDs) == 0 {
+		opts.OnlyIDs = s.onlyIDs
+	}
+	opts.SkipIDs = append(slices.Clip(s.skipIDs), opts.SkipIDs...)
+	if opts.Filter == nil {
+		opts.Filter = s.filter
+	}
+	if opts.SLA == nil {
+		opts.SLA = s.sla
+	}
+	switch {
+	case s.progress != nil && opts.Progress != nil:
+		scanner, scan := s.progress, opts.Progress
+		opts.Progress = func(e ProgressEvent) {
+			scanner(e)
+			scan(e)
+		}
+	case opts.Progress == nil:
+		opts.Progress = s.progress
+	}
+	return opts, nil
+}
@@ -0,0 +1,117 @@
+package drydock_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/drydocktest"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+func TestScanner_ScanOptions(t *testing.T) {
+	target := drydocktest.Target("us-central1-docker.pkg.dev/p/repo/api:v1")
+
+	tests := map[string]struct {
+		scannerOpts []drydock.ScannerOption
+		opts        drydock.ScanOptions
+		wantOnly    []string
+		wantSkip    []string
+	}{
+		"should fall back to the filters of the scanner": {
+			scannerOpts: []drydock.ScannerOption{
+				drydock.WithOnlyVulnerabilities("CVE-1", "CVE-2"),
+				drydock.WithSkipVulnerabilities("CVE-3"),
+			},
+			opts:     drydock.ScanOptions{MinSeverity: schemas.SeverityHigh, FixableOnly: true},
+			wantOnly: []string{"CVE-1", "CVE-2"},
+			wantSkip: []string{"CVE-3"},
+		},
+		"should replace only IDs and add skipped IDs": {
+			scannerOpts: []drydock.ScannerOption{
+				drydock.WithOnlyVulnerabilities("CVE-1", "CVE-2"),
+				drydock.WithSkipVulnerabilities("CVE-3"),
+			},
+			opts:     drydock.ScanOptions{MinSeverity: schemas.SeverityHigh, FixableOnly: true, OnlyIDs: []string{"CVE-2"}, SkipIDs: []string{"CVE-4"}},
+			wantOnly: []string{"CVE-2"},
+			wantSkip: []string{"CVE-3", "CVE-4"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			analyzer := drydocktest.NewAnalyzer()
+			var mu sync.Mutex
+			var scannerEvents, scanEvents []drydock.ProgressEventType
+			opts := append([]drydock.ScannerOption{
+				drydock.WithProjectID("p"),
+				drydock.WithResolver(drydocktest.NewResolver(target)),
+				drydock.WithAnalyzer(analyzer),
+				drydock.WithExporter(&drydocktest.Exporter{}),
+				drydock.WithProgress(func(e drydock.ProgressEvent) {
+					mu.Lock()
+					defer mu.Unlock()
+					scannerEvents = append(scannerEvents, e.Type)
+				}),
+			}, tt.scannerOpts...)
+			scanner, err := drydock.NewScanner(ctx, "us-central1", opts...)
+			if err != nil {
+				t.Fatalf("NewScanner() error = %v", err)
+			}
+			defer func() { _ = scanner.Close() }()
+
+			tt.opts.Progress = func(e drydock.ProgressEvent) {
+				mu.Lock()
+				defer mu.Unlock()
+				scanEvents = append(scanEvents, e.Type)
+			}
+			if err := scanner.Scan(ctx, tt.opts); err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+
+			requests := analyzer.Requests()
+			if len(requests) != 1 {
+				t.Fatalf("len(Requests()) = %d, want 1", len(requests))
+			}
+			req := requests[0]
+			if req.MinSeverity != tt.opts.MinSeverity || req.FixableOnly != tt.opts.FixableOnly {
+				t.Errorf("MinSeverity, FixableOnly = %s, %v, want %s, %v", req.MinSeverity, req.FixableOnly, tt.opts.MinSeverity, tt.opts.FixableOnly)
+			}
+			if diff := cmp.Diff(tt.wantOnly, req.OnlyIDs); diff != "" {
+				t.Errorf("OnlyIDs mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantSkip, req.SkipIDs); diff != "" {
+				t.Errorf("SkipIDs mismatch (-want +got):\n%s", diff)
+			}
+			wantEvents := []drydock.ProgressEventType{drydock.ProgressResolved, drydock.ProgressStarted, drydock.ProgressCompleted}
+			if diff := cmp.Diff(wantEvents, scannerEvents); diff != "" {
+				t.Errorf("events of WithProgress mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(wantEvents, scanEvents); diff != "" {
+				t.Errorf("events of ScanOptions.Progress mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestScanner_ScanOptionsInvalidSLA(t *testing.T) {
+	ctx := context.Background()
+	scanner, err := drydock.NewScanner(ctx, "us-central1",
+		drydock.WithProjectID("p"),
+		drydock.WithResolver(drydocktest.NewResolver()),
+		drydock.WithAnalyzer(drydocktest.NewAnalyzer()),
+		drydock.WithExporter(&drydocktest.Exporter{}),
+	)
+	if err != nil {
+		t.Fatalf("NewScanner() error = %v", err)
+	}
+	defer func() { _ = scanner.Close() }()
+
+	err = scanner.Scan(ctx, drydock.ScanOptions{SLA: schemas.SLAPolicy{schemas.SeverityCritical: 0}})
+	if err == nil {
+		t.Error("Scan() error = nil, want an error for a zero SLA window")
+	}
+}
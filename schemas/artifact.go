@@ -3,10 +3,15 @@ package schemas
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
+
+	"github.com/hiro-o918/drydock/utils"
 )
 
 // ArtifactReference represents the parsed components of a Google Artifact Registry URI.
+// References to other registries, which have no projects or repositories, keep their whole path in ImageName.
 type ArtifactReference struct {
 	Host         string  `json:"host"`             // e.g., region-docker.pkg.dev
 	ProjectID    string  `json:"projectID"`        // e.g., my-project-id
@@ -26,10 +31,19 @@ func (a ArtifactReference) ToResourceURL(location string) string {
 		location, a.ProjectID, a.RepositoryID, a.ImageName, digestStr)
 }
 
+// IsArtifactRegistry reports whether the reference points at Artifact Registry (e.g., us-central1-docker.pkg.dev).
+func (a ArtifactReference) IsArtifactRegistry() bool {
+	return strings.HasSuffix(a.Host, garHostSuffix)
+}
+
 // String returns a human-readable string representation
 func (a ArtifactReference) String() string {
-	ref := fmt.Sprintf("%s/%s/%s/%s",
-		a.Host, a.ProjectID, a.RepositoryID, a.ImageName)
+	ref := a.Host
+	for _, component := range []string{a.ProjectID, a.RepositoryID, a.ImageName} {
+		if component != "" {
+			ref += "/" + component
+		}
+	}
 
 	if a.Tag != nil {
 		ref += ":" + *a.Tag
@@ -52,11 +66,150 @@ func (a ArtifactReference) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// UnmarshalJSON accepts both the structured fields and the "uri" field emitted by MarshalJSON.
+// Structured fields take precedence; the uri is parsed only when they are absent,
+// so that inputs such as {"uri": "us-docker.pkg.dev/p/r/img:tag"} are supported.
+func (a *ArtifactReference) UnmarshalJSON(data []byte) error {
+	type Alias ArtifactReference
+	aux := &struct {
+		*Alias
+		URI string `json:"uri"`
+	}{
+		Alias: (*Alias)(a),
+	}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	if aux.URI == "" || a.Host != "" || a.ImageName != "" {
+		return nil
+	}
+
+	parsed, err := ParseArtifactURI(aux.URI)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Components of an image reference, as reported by ParseError.
+const (
+	ReferenceHost   = "host"
+	ReferencePath   = "path"
+	ReferenceTag    = "tag"
+	ReferenceDigest = "digest"
+)
+
+// ParseError reports which component of an image reference failed to parse.
+type ParseError struct {
+	URI       string
+	Component string // one of ReferenceHost, ReferencePath, ReferenceTag and ReferenceDigest
+	Reason    string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid image reference %q: %s: %s", e.URI, e.Component, e.Reason)
+}
+
+const garHostSuffix = "-docker.pkg.dev"
+
+var (
+	// hostRegex matches a registry host: a domain name or IPv6 address, with an optional port (e.g., localhost:5000)
+	hostRegex = regexp.MustCompile(`^(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*|\[[a-fA-F0-9:]+\])(?::[0-9]+)?$`)
+	// pathComponentRegex matches a component of a repository path, as defined by the OCI distribution spec
+	pathComponentRegex = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*$`)
+	tagRegex           = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127}$`)
+	digestRegex        = regexp.MustCompile(`^([a-z0-9]+(?:[.+_-][a-z0-9]+)*):([a-fA-F0-9]+)$`)
+)
+
+// digestLengths is the number of hex digits of the digest algorithms.
+var digestLengths = map[string]int{"sha256": 64, "sha384": 96, "sha512": 128}
+
+// ValidateDigest checks an image digest such as "sha256:e3b0...": its algorithm must be
+// sha256, sha384 or sha512, followed by a hash of the matching length.
+func ValidateDigest(digest string) error {
+	m := digestRegex.FindStringSubmatch(digest)
+	if m == nil {
+		return fmt.Errorf("must look like <algorithm>:<hex>, got %q", digest)
+	}
+	length, ok := digestLengths[m[1]]
+	if !ok {
+		return fmt.Errorf("unsupported algorithm %q", m[1])
+	}
+	if len(m[2]) != length {
+		return fmt.Errorf("%s digest must have %d hex digits, got %d", m[1], length, len(m[2]))
+	}
+	return nil
+}
+
+// ParseArtifactURI parses an image reference such as "us-central1-docker.pkg.dev/project/repo/image:tag"
+// into a structured ArtifactReference. Other registries are accepted too (e.g., "localhost:5000/team/app@sha512:..."),
+// but their host must be explicit: a name with a dot or a port, or localhost.
+// It returns a *ParseError telling which component is invalid.
+func ParseArtifactURI(uri string) (ArtifactReference, error) {
+	fail := func(component, format string, args ...any) (ArtifactReference, error) {
+		return ArtifactReference{}, &ParseError{URI: uri, Component: component, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	rest, digest, hasDigest := strings.Cut(uri, "@")
+	if hasDigest {
+		if err := ValidateDigest(digest); err != nil {
+			return fail(ReferenceDigest, "%v", err)
+		}
+	}
+
+	host, path, ok := strings.Cut(rest, "/")
+	if !ok || !(strings.ContainsAny(host, ".:") || host == "localhost") {
+		return fail(ReferenceHost, "missing registry host (e.g., us-central1-docker.pkg.dev)")
+	}
+	if !hostRegex.MatchString(host) {
+		return fail(ReferenceHost, "invalid host %q", host)
+	}
+
+	// A colon in the last path component separates the tag, which must not be empty when present
+	var tag *string
+	if i := strings.LastIndex(path, ":"); i > strings.LastIndex(path, "/") {
+		if !tagRegex.MatchString(path[i+1:]) {
+			return fail(ReferenceTag, "invalid tag %q", path[i+1:])
+		}
+		path, tag = path[:i], utils.Ptr(path[i+1:])
+	}
+
+	components := strings.Split(path, "/")
+	for _, component := range components {
+		if !pathComponentRegex.MatchString(component) {
+			return fail(ReferencePath, "invalid component %q (lowercase letters, digits and separators only)", component)
+		}
+	}
+
+	ref := ArtifactReference{
+		Host:      host,
+		ImageName: path,
+		Tag:       tag,
+	}
+	if hasDigest {
+		ref.Digest = utils.Ptr(digest)
+	}
+	if ref.IsArtifactRegistry() {
+		if len(components) < 3 {
+			return fail(ReferencePath, "Artifact Registry references need project/repository/image, got %q", path)
+		}
+		ref.ProjectID = components[0]
+		ref.RepositoryID = components[1]
+		ref.ImageName = strings.Join(components[2:], "/")
+	}
+	return ref, nil
+}
+
 // AnalyzeResult contains the analysis results
 type AnalyzeResult struct {
 	// Artifact is the analyzed image reference
 	Artifact ArtifactReference `json:"artifact" yaml:"artifact"`
 
+	// Image is the registry metadata of the analyzed image, if known
+	Image *ImageMetadata `json:"image,omitempty" yaml:"image,omitempty"`
+
 	// ScanTime is when the scan was performed
 	ScanTime time.Time `json:"scanTime" yaml:"scanTime"`
 
@@ -65,4 +218,127 @@ type AnalyzeResult struct {
 
 	// Summary provides aggregated statistics
 	Summary VulnerabilitySummary `json:"summary" yaml:"summary"`
+
+	// Deployed is true when the image is known to run in a workload (see Workloads)
+	Deployed bool `json:"deployed,omitempty" yaml:"deployed,omitempty"`
+
+	// Workloads lists the discovered workloads running the image
+	Workloads []Workload `json:"workloads,omitempty" yaml:"workloads,omitempty"`
+
+	// BaseImage is the advice on the base image, if requested and the base image is known
+	BaseImage *BaseImageAdvice `json:"baseImage,omitempty" yaml:"baseImage,omitempty"`
+
+	// SBOM locates the SBOM of the image, if downloaded
+	SBOM *SBOMReference `json:"sbom,omitempty" yaml:"sbom,omitempty"`
+
+	// Resolved lists the findings reported by earlier scans of the image that are gone, if tracked (see History)
+	Resolved []Vulnerability `json:"resolved,omitempty" yaml:"resolved,omitempty"`
+
+	// Suppressed lists the findings left out of Vulnerabilities by suppressions, with who accepted them and why
+	Suppressed []SuppressedFinding `json:"suppressed,omitempty" yaml:"suppressed,omitempty"`
+
+	// Remediations lists the package upgrades that fix the fixable OS-package vulnerabilities
+	Remediations []Remediation `json:"remediations,omitempty" yaml:"remediations,omitempty"`
+
+	// Scanner identifies the engine that produced the vulnerabilities
+	Scanner *ScannerInfo `json:"scanner,omitempty" yaml:"scanner,omitempty"`
+
+	// Metadata describes how the analysis went, so degraded results can be told apart from clean ones
+	Metadata *ScanMetadata `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// ScanStatus tells whether the image was analyzed at all, so that an image without findings
+	// because it was never analyzed is not mistaken for a clean one. Empty if unknown.
+	ScanStatus ScanStatus `json:"scanStatus,omitempty" yaml:"scanStatus,omitempty"`
+
+	// Partial is true when the result comes from a scan that was interrupted
+	// before all images were analyzed, so the report does not cover every image
+	Partial bool `json:"partial,omitempty" yaml:"partial,omitempty"`
+}
+
+// ScanStatus is the state of the analysis of an image by its vulnerability scanner.
+type ScanStatus string
+
+const (
+	// ScanStatusFinished means the image was analyzed: no findings means no known vulnerabilities
+	ScanStatusFinished ScanStatus = "FINISHED"
+	// ScanStatusPending means the analysis has not finished yet, e.g. right after a push
+	ScanStatusPending ScanStatus = "PENDING"
+	// ScanStatusUnsupported means the scanner does not support the image, e.g. its OS
+	ScanStatusUnsupported ScanStatus = "UNSUPPORTED"
+	// ScanStatusNotScanned means the image has no analysis, e.g. it was pushed before scanning was
+	// enabled, or the analysis failed
+	ScanStatusNotScanned ScanStatus = "NOT_SCANNED"
+)
+
+// SBOMReference locates the SBOM Artifact Analysis generated for an image, and the copy downloaded of it.
+type SBOMReference struct {
+	// URI is where Artifact Analysis stores the document (e.g., gs://bucket/path/to/sbom.json)
+	URI string `json:"uri" yaml:"uri"`
+
+	// MediaType is the format of the document (e.g., application/spdx+json)
+	MediaType string `json:"mediaType,omitempty" yaml:"mediaType,omitempty"`
+
+	// Path is the local file the document was written to
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// ImageMetadata describes an image as stored in the registry
+type ImageMetadata struct {
+	// Tags lists all tags pointing at the image digest
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// UploadTime is when the image was pushed
+	UploadTime time.Time `json:"uploadTime,omitzero" yaml:"uploadTime,omitempty"`
+
+	// UpdateTime is when the image was last updated (e.g., re-tagged)
+	UpdateTime time.Time `json:"updateTime,omitzero" yaml:"updateTime,omitempty"`
+
+	// BuildTime is when the image was built, if reported by the registry
+	BuildTime time.Time `json:"buildTime,omitzero" yaml:"buildTime,omitempty"`
+
+	// SizeBytes is the compressed size of the image
+	SizeBytes int64 `json:"sizeBytes,omitempty" yaml:"sizeBytes,omitempty"`
+
+	// MediaType is the manifest media type (e.g., "application/vnd.docker.distribution.manifest.v2+json")
+	MediaType string `json:"mediaType,omitempty" yaml:"mediaType,omitempty"`
+}
+
+// ScannerInfo identifies a vulnerability scanning engine
+type ScannerInfo struct {
+	// Name is the engine name (e.g., "container-analysis", "trivy", "osv")
+	Name string `json:"name" yaml:"name"`
+
+	// Version is the engine or API version, if known
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+}
+
+// ScanMetadata describes the analysis of a single image
+type ScanMetadata struct {
+	// DurationMillis is how long the analysis took, in milliseconds
+	DurationMillis int64 `json:"durationMillis" yaml:"durationMillis"`
+
+	// OccurrencesFetched is the number of occurrences read from the API, before any filtering
+	OccurrencesFetched int `json:"occurrencesFetched" yaml:"occurrencesFetched"`
+
+	// Truncated is true when listing occurrences stopped before the last page,
+	// so the vulnerabilities may be incomplete
+	Truncated bool `json:"truncated,omitempty" yaml:"truncated,omitempty"`
+
+	// Warnings lists non-fatal problems encountered during the analysis
+	Warnings []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+
+	// ContinuousAnalysis is the continuous analysis state of the image ("ACTIVE" or "INACTIVE"),
+	// as reported by its discovery occurrence
+	ContinuousAnalysis string `json:"continuousAnalysis,omitempty" yaml:"continuousAnalysis,omitempty"`
+
+	// LastAnalysisTime is when Container Analysis last scanned the image
+	LastAnalysisTime time.Time `json:"lastAnalysisTime,omitzero" yaml:"lastAnalysisTime,omitempty"`
+
+	// Stale is true when continuous analysis stopped (the image was not pulled for 30 days),
+	// so newly published vulnerabilities are missing from the findings
+	Stale bool `json:"stale,omitempty" yaml:"stale,omitempty"`
+
+	// RefreshRequested is true when the image was pulled to re-activate continuous analysis;
+	// refreshed findings show up in a later scan
+	RefreshRequested bool `json:"refreshRequested,omitempty" yaml:"refreshRequested,omitempty"`
 }
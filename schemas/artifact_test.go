@@ -22,7 +22,7 @@ func TestArtifactReference_ToResourceURL(t *testing.T) {
 				ProjectID:    "my-project",
 				RepositoryID: "my-repo",
 				ImageName:    "my-image",
-				Digest:       utils.ToPtr("sha256:abc123"),
+				Digest:       utils.Ptr("sha256:abc123"),
 			},
 			location: "us-central1",
 			want:     "https://us-central1-docker.pkg.dev/my-project/my-repo/my-image@sha256:abc123",
@@ -44,7 +44,7 @@ func TestArtifactReference_ToResourceURL(t *testing.T) {
 				ProjectID:    "my-project",
 				RepositoryID: "my-repo",
 				ImageName:    "namespace/my-image",
-				Digest:       utils.ToPtr("sha256:def456"),
+				Digest:       utils.Ptr("sha256:def456"),
 			},
 			location: "us-central1",
 			want:     "https://us-central1-docker.pkg.dev/my-project/my-repo/namespace/my-image@sha256:def456",
@@ -72,7 +72,7 @@ func TestArtifactReference_String(t *testing.T) {
 				ProjectID:    "my-project",
 				RepositoryID: "my-repo",
 				ImageName:    "my-image",
-				Digest:       utils.ToPtr("sha256:abc123"),
+				Digest:       utils.Ptr("sha256:abc123"),
 			},
 			want: "us-central1-docker.pkg.dev/my-project/my-repo/my-image@sha256:abc123",
 		},
@@ -82,7 +82,7 @@ func TestArtifactReference_String(t *testing.T) {
 				ProjectID:    "my-project",
 				RepositoryID: "my-repo",
 				ImageName:    "my-image",
-				Tag:          utils.ToPtr("latest"),
+				Tag:          utils.Ptr("latest"),
 			},
 			want: "us-central1-docker.pkg.dev/my-project/my-repo/my-image:latest",
 		},
@@ -92,8 +92,8 @@ func TestArtifactReference_String(t *testing.T) {
 				ProjectID:    "my-project",
 				RepositoryID: "my-repo",
 				ImageName:    "my-image",
-				Tag:          utils.ToPtr("v1.0.0"),
-				Digest:       utils.ToPtr("sha256:abc123"),
+				Tag:          utils.Ptr("v1.0.0"),
+				Digest:       utils.Ptr("sha256:abc123"),
 			},
 			want: "us-central1-docker.pkg.dev/my-project/my-repo/my-image:v1.0.0@sha256:abc123",
 		},
@@ -112,7 +112,7 @@ func TestArtifactReference_String(t *testing.T) {
 				ProjectID:    "test-project",
 				RepositoryID: "test-repo",
 				ImageName:    "namespace/service/worker",
-				Tag:          utils.ToPtr("prod"),
+				Tag:          utils.Ptr("prod"),
 			},
 			want: "asia-northeast1-docker.pkg.dev/test-project/test-repo/namespace/service/worker:prod",
 		},
@@ -139,7 +139,7 @@ func TestArtifactReference_MarshalJSON(t *testing.T) {
 				ProjectID:    "my-project",
 				RepositoryID: "my-repo",
 				ImageName:    "my-image",
-				Digest:       utils.ToPtr("sha256:abc123"),
+				Digest:       utils.Ptr("sha256:abc123"),
 			},
 			want: `{"host":"us-central1-docker.pkg.dev","projectID":"my-project","repositoryID":"my-repo","imageName":"my-image","digest":"sha256:abc123","uri":"us-central1-docker.pkg.dev/my-project/my-repo/my-image@sha256:abc123"}`,
 		},
@@ -158,8 +158,8 @@ func TestArtifactReference_MarshalJSON(t *testing.T) {
 				ProjectID:    "my-project",
 				RepositoryID: "my-repo",
 				ImageName:    "my-image",
-				Tag:          utils.ToPtr("latest"),
-				Digest:       utils.ToPtr("sha256:abc123"),
+				Tag:          utils.Ptr("latest"),
+				Digest:       utils.Ptr("sha256:abc123"),
 			},
 			want: `{"host":"us-central1-docker.pkg.dev","projectID":"my-project","repositoryID":"my-repo","imageName":"my-image","tag":"latest","digest":"sha256:abc123","uri":"us-central1-docker.pkg.dev/my-project/my-repo/my-image:latest@sha256:abc123"}`,
 		},
@@ -178,6 +178,90 @@ func TestArtifactReference_MarshalJSON(t *testing.T) {
 	}
 }
 
+func TestArtifactReference_UnmarshalJSON(t *testing.T) {
+	const validHash = "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	tests := map[string]struct {
+		input   string
+		want    schemas.ArtifactReference
+		wantErr bool
+	}{
+		"should decode structured fields": {
+			input: `{"host":"us-central1-docker.pkg.dev","projectID":"my-project","repositoryID":"my-repo","imageName":"my-image","tag":"latest"}`,
+			want: schemas.ArtifactReference{
+				Host:         "us-central1-docker.pkg.dev",
+				ProjectID:    "my-project",
+				RepositoryID: "my-repo",
+				ImageName:    "my-image",
+				Tag:          utils.Ptr("latest"),
+			},
+		},
+		"should parse uri when structured fields are absent": {
+			input: `{"uri":"us-central1-docker.pkg.dev/my-project/my-repo/ns/my-image:v1@` + validHash + `"}`,
+			want: schemas.ArtifactReference{
+				Host:         "us-central1-docker.pkg.dev",
+				ProjectID:    "my-project",
+				RepositoryID: "my-repo",
+				ImageName:    "ns/my-image",
+				Tag:          utils.Ptr("v1"),
+				Digest:       utils.Ptr(validHash),
+			},
+		},
+		"should prefer structured fields over uri": {
+			input: `{"host":"us-central1-docker.pkg.dev","projectID":"p","repositoryID":"r","imageName":"i","uri":"asia-docker.pkg.dev/x/y/z"}`,
+			want: schemas.ArtifactReference{
+				Host:         "us-central1-docker.pkg.dev",
+				ProjectID:    "p",
+				RepositoryID: "r",
+				ImageName:    "i",
+			},
+		},
+		"should return error when uri is invalid": {
+			input:   `{"uri":"not-a-registry/image"}`,
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got schemas.ArtifactReference
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("UnmarshalJSON() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestArtifactReference_JSONRoundTrip(t *testing.T) {
+	want := schemas.ArtifactReference{
+		Host:         "us-central1-docker.pkg.dev",
+		ProjectID:    "my-project",
+		RepositoryID: "my-repo",
+		ImageName:    "my-image",
+		Tag:          utils.Ptr("v1.0.0"),
+		Digest:       utils.Ptr("sha256:abc123"),
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+	var got schemas.ArtifactReference
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Round-trip mismatch (-want +got):\n%s", diff)
+	}
+}
+
 // TestArtifactReference_StringRoundTrip tests the round-trip between String() and ParseArtifactURI().
 // Note: ParseArtifactURI currently supports cases with both Tag and Digest,
 // so we test cases with only Tag or only Digest.
@@ -191,7 +275,7 @@ func TestArtifactReference_StringRoundTrip(t *testing.T) {
 				ProjectID:    "my-project",
 				RepositoryID: "my-repo",
 				ImageName:    "my-image",
-				Digest:       utils.ToPtr("sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"),
+				Digest:       utils.Ptr("sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"),
 			},
 		},
 		"should round-trip with tag only": {
@@ -200,7 +284,14 @@ func TestArtifactReference_StringRoundTrip(t *testing.T) {
 				ProjectID:    "test-project",
 				RepositoryID: "test-repo",
 				ImageName:    "nginx",
-				Tag:          utils.ToPtr("latest"),
+				Tag:          utils.Ptr("latest"),
+			},
+		},
+		"should round-trip a registry without projects": {
+			artifact: schemas.ArtifactReference{
+				Host:      "localhost:5000",
+				ImageName: "team/app",
+				Tag:       utils.Ptr("v1"),
 			},
 		},
 	}
@@ -0,0 +1,19 @@
+package schemas
+
+// BaseImageAdvice compares the base image an image was built on with the newest version of that base image.
+type BaseImageAdvice struct {
+	// Current is the base image detected from the image layers
+	Current string `json:"current" yaml:"current"`
+
+	// Recommended is the newer base image to rebuild on; empty when Current is the newest or could not be compared
+	Recommended string `json:"recommended,omitempty" yaml:"recommended,omitempty"`
+
+	// RemovedBySeverity counts the findings of Current that Recommended no longer has
+	RemovedBySeverity map[Severity]int `json:"removedBySeverity,omitempty" yaml:"removedBySeverity,omitempty"`
+
+	// AddedBySeverity counts the findings Recommended has that Current does not
+	AddedBySeverity map[Severity]int `json:"addedBySeverity,omitempty" yaml:"addedBySeverity,omitempty"`
+
+	// Message summarizes the advice, e.g. "rebuilding on X would remove 2 CRITICAL / 5 HIGH findings"
+	Message string `json:"message" yaml:"message"`
+}
@@ -0,0 +1,61 @@
+package schemas
+
+import "strings"
+
+// Baseline is the set of findings known before a scan, so that only new findings are reported.
+// Findings are matched by image (regardless of tag and digest), vulnerability ID and package.
+type Baseline struct {
+	// known maps image keys to the keys of their findings
+	known map[string]map[string]bool
+}
+
+// NewBaseline returns the baseline of the findings of results, e.g. those of a previous report.
+func NewBaseline(results []AnalyzeResult) *Baseline {
+	b := &Baseline{known: make(map[string]map[string]bool)}
+	for _, r := range results {
+		for _, v := range r.Vulnerabilities {
+			b.add(imageKey(r.Artifact), v)
+		}
+	}
+	return b
+}
+
+// Baseline returns the baseline of the findings the history has not seen resolved.
+func (h *History) Baseline() *Baseline {
+	b := &Baseline{known: make(map[string]map[string]bool)}
+	for key, findings := range h.Images {
+		// History keys carry the tag, if any (see historyKey)
+		if i := strings.LastIndex(key, ":"); i > strings.LastIndex(key, "/") {
+			key = key[:i]
+		}
+		for _, v := range findings {
+			if v.ResolvedAt.IsZero() {
+				b.add(key, v)
+			}
+		}
+	}
+	return b
+}
+
+func (b *Baseline) add(image string, v Vulnerability) {
+	if b.known[image] == nil {
+		b.known[image] = make(map[string]bool)
+	}
+	b.known[image][findingKey(v)] = true
+}
+
+// Known reports whether the baseline has the finding for the image.
+func (b *Baseline) Known(image ArtifactReference, v Vulnerability) bool {
+	return b.known[imageKey(image)][findingKey(v)]
+}
+
+// New returns the findings of result that are not in the baseline, in order.
+func (b *Baseline) New(result AnalyzeResult) []Vulnerability {
+	var vulns []Vulnerability
+	for _, v := range result.Vulnerabilities {
+		if !b.Known(result.Artifact, v) {
+			vulns = append(vulns, v)
+		}
+	}
+	return vulns
+}
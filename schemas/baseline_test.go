@@ -0,0 +1,76 @@
+package schemas_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestBaseline_New(t *testing.T) {
+	artifact := func(image, tag string) schemas.ArtifactReference {
+		return schemas.ArtifactReference{
+			Host: "us-docker.pkg.dev", ProjectID: "p", RepositoryID: "r", ImageName: image,
+			Tag: utils.Ptr(tag), Digest: utils.Ptr("sha256:" + tag),
+		}
+	}
+	openssl := schemas.Vulnerability{ID: "CVE-1", PackageName: "openssl", Severity: schemas.SeverityHigh}
+	zlib := schemas.Vulnerability{ID: "CVE-2", PackageName: "zlib", Severity: schemas.SeverityLow}
+	curl := schemas.Vulnerability{ID: "CVE-3", PackageName: "curl", Severity: schemas.SeverityCritical}
+
+	day1 := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	var history schemas.History
+	first := schemas.AnalyzeResult{Artifact: artifact("app", "v1"), Vulnerabilities: []schemas.Vulnerability{openssl, zlib}}
+	history.Observe(&first, day1)
+	// zlib is resolved on day 2
+	second := schemas.AnalyzeResult{Artifact: artifact("app", "v1"), Vulnerabilities: []schemas.Vulnerability{openssl}}
+	history.Observe(&second, day1.Add(24*time.Hour))
+
+	tests := map[string]struct {
+		baseline *schemas.Baseline
+		result   schemas.AnalyzeResult
+		want     []schemas.Vulnerability
+	}{
+		"should return every finding for an empty baseline": {
+			baseline: schemas.NewBaseline(nil),
+			result:   schemas.AnalyzeResult{Artifact: artifact("app", "v1"), Vulnerabilities: []schemas.Vulnerability{openssl, curl}},
+			want:     []schemas.Vulnerability{openssl, curl},
+		},
+		"should match findings of a report regardless of tag and digest": {
+			baseline: schemas.NewBaseline([]schemas.AnalyzeResult{
+				{Artifact: artifact("app", "v1"), Vulnerabilities: []schemas.Vulnerability{openssl}},
+			}),
+			result: schemas.AnalyzeResult{Artifact: artifact("app", "v2"), Vulnerabilities: []schemas.Vulnerability{openssl, curl}},
+			want:   []schemas.Vulnerability{curl},
+		},
+		"should not match findings of another image": {
+			baseline: schemas.NewBaseline([]schemas.AnalyzeResult{
+				{Artifact: artifact("worker", "v1"), Vulnerabilities: []schemas.Vulnerability{openssl}},
+			}),
+			result: schemas.AnalyzeResult{Artifact: artifact("app", "v1"), Vulnerabilities: []schemas.Vulnerability{openssl}},
+			want:   []schemas.Vulnerability{openssl},
+		},
+		"should return nil when every finding is known": {
+			baseline: schemas.NewBaseline([]schemas.AnalyzeResult{
+				{Artifact: artifact("app", "v1"), Vulnerabilities: []schemas.Vulnerability{openssl, curl}},
+			}),
+			result: schemas.AnalyzeResult{Artifact: artifact("app", "v1"), Vulnerabilities: []schemas.Vulnerability{curl}},
+			want:   nil,
+		},
+		"should treat findings resolved in the history as new": {
+			baseline: history.Baseline(),
+			result:   schemas.AnalyzeResult{Artifact: artifact("app", "v2"), Vulnerabilities: []schemas.Vulnerability{openssl, zlib, curl}},
+			want:     []schemas.Vulnerability{zlib, curl},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tt.baseline.New(tt.result)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("New() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
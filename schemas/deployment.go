@@ -0,0 +1,50 @@
+package schemas
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Workload platforms
+const (
+	// PlatformCloudRun is a Cloud Run service
+	PlatformCloudRun = "cloud-run"
+	// PlatformKubernetes is a Kubernetes (e.g., GKE) workload
+	PlatformKubernetes = "kubernetes"
+)
+
+// Workload is a running workload that uses an image.
+type Workload struct {
+	// Platform is where the workload runs (e.g., "cloud-run", "kubernetes")
+	Platform string `json:"platform" yaml:"platform"`
+
+	// Name identifies the workload on its platform (e.g., "my-service" or "default/Deployment/api")
+	Name string `json:"name" yaml:"name"`
+
+	// Location is the region or cluster of the workload, if known
+	Location string `json:"location,omitempty" yaml:"location,omitempty"`
+
+	// InternetFacing is true when the workload accepts traffic from the internet
+	InternetFacing bool `json:"internetFacing,omitempty" yaml:"internetFacing,omitempty"`
+}
+
+// exposure ranks a result for SortByExposure: internet-facing deployed images first, then deployed ones.
+func exposure(r AnalyzeResult) int {
+	if !r.Deployed {
+		return 2
+	}
+	for _, w := range r.Workloads {
+		if w.InternetFacing {
+			return 0
+		}
+	}
+	return 1
+}
+
+// SortByExposure orders results so that images running in internet-facing workloads come first,
+// then other deployed images, then images not known to be deployed. The order is otherwise kept.
+func SortByExposure(results []AnalyzeResult) {
+	slices.SortStableFunc(results, func(a, b AnalyzeResult) int {
+		return cmp.Compare(exposure(a), exposure(b))
+	})
+}
@@ -0,0 +1,35 @@
+package schemas_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+func TestSortByExposure(t *testing.T) {
+	result := func(name string, deployed, public bool) schemas.AnalyzeResult {
+		r := schemas.AnalyzeResult{Artifact: schemas.ArtifactReference{ImageName: name}, Deployed: deployed}
+		if deployed {
+			r.Workloads = []schemas.Workload{{Platform: schemas.PlatformCloudRun, Name: name, InternetFacing: public}}
+		}
+		return r
+	}
+	results := []schemas.AnalyzeResult{
+		result("idle-a", false, false),
+		result("internal", true, false),
+		result("public", true, true),
+		result("idle-b", false, false),
+	}
+
+	schemas.SortByExposure(results)
+
+	got := make([]string, 0, len(results))
+	for _, r := range results {
+		got = append(got, r.Artifact.ImageName)
+	}
+	want := []string{"public", "internal", "idle-a", "idle-b"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SortByExposure() order mismatch (-want +got):\n%s", diff)
+	}
+}
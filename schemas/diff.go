@@ -0,0 +1,144 @@
+package schemas
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// ImageDiff lists how the findings of one image changed between two reports.
+// Images are matched by repository and name, so a new digest of the same image is compared
+// with the previous one.
+type ImageDiff struct {
+	// Image identifies the image, without tag or digest (e.g., "us-docker.pkg.dev/p/r/app")
+	Image string `json:"image"`
+
+	// Before is the image in the first report (nil if it was not there)
+	Before *ArtifactReference `json:"before,omitempty"`
+
+	// After is the image in the second report (nil if it is gone)
+	After *ArtifactReference `json:"after,omitempty"`
+
+	// Added are findings only in the second report
+	Added []Vulnerability `json:"added,omitempty"`
+
+	// Removed are findings only in the first report
+	Removed []Vulnerability `json:"removed,omitempty"`
+
+	// Changed are findings in both reports whose details differ
+	Changed []VulnerabilityChange `json:"changed,omitempty"`
+}
+
+// VulnerabilityChange is a finding whose details differ between two reports.
+type VulnerabilityChange struct {
+	// Before is the finding in the first report
+	Before Vulnerability `json:"before"`
+
+	// After is the finding in the second report
+	After Vulnerability `json:"after"`
+
+	// Fields names the JSON fields that differ (e.g., "severity", "fixedVersion")
+	Fields []string `json:"fields"`
+}
+
+// Empty reports whether nothing changed for the image.
+func (d ImageDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// imageKey identifies an image across reports, ignoring its tag and digest.
+func imageKey(a ArtifactReference) string {
+	return fmt.Sprintf("%s/%s/%s/%s", a.Host, a.ProjectID, a.RepositoryID, a.ImageName)
+}
+
+// findingKey identifies a finding within an image across reports.
+func findingKey(v Vulnerability) string {
+	return v.ID + "\x00" + v.PackageName
+}
+
+// DiffResults compares two reports and returns the images whose findings differ, sorted by image.
+// Images without any change are omitted.
+func DiffResults(before, after []AnalyzeResult) []ImageDiff {
+	diffs := make(map[string]*ImageDiff)
+	beforeVulns := make(map[string][]Vulnerability)
+	afterVulns := make(map[string][]Vulnerability)
+
+	get := func(a ArtifactReference) *ImageDiff {
+		key := imageKey(a)
+		if d, ok := diffs[key]; ok {
+			return d
+		}
+		d := &ImageDiff{Image: key}
+		diffs[key] = d
+		return d
+	}
+	for _, r := range before {
+		d := get(r.Artifact)
+		d.Before = &r.Artifact
+		beforeVulns[d.Image] = append(beforeVulns[d.Image], r.Vulnerabilities...)
+	}
+	for _, r := range after {
+		d := get(r.Artifact)
+		d.After = &r.Artifact
+		afterVulns[d.Image] = append(afterVulns[d.Image], r.Vulnerabilities...)
+	}
+
+	var result []ImageDiff
+	for key, d := range diffs {
+		diffVulnerabilities(d, beforeVulns[key], afterVulns[key])
+		if !d.Empty() {
+			result = append(result, *d)
+		}
+	}
+	slices.SortFunc(result, func(a, b ImageDiff) int {
+		return cmp.Compare(a.Image, b.Image)
+	})
+	return result
+}
+
+// diffVulnerabilities fills the added, removed and changed findings of d, in report order.
+func diffVulnerabilities(d *ImageDiff, before, after []Vulnerability) {
+	old := make(map[string]Vulnerability, len(before))
+	for _, v := range before {
+		old[findingKey(v)] = v
+	}
+	seen := make(map[string]bool, len(after))
+	for _, v := range after {
+		key := findingKey(v)
+		seen[key] = true
+		prev, ok := old[key]
+		if !ok {
+			d.Added = append(d.Added, v)
+			continue
+		}
+		if fields := changedFields(prev, v); len(fields) > 0 {
+			d.Changed = append(d.Changed, VulnerabilityChange{Before: prev, After: v, Fields: fields})
+		}
+	}
+	for _, v := range before {
+		if !seen[findingKey(v)] {
+			d.Removed = append(d.Removed, v)
+		}
+	}
+}
+
+// changedFields returns the JSON names of the fields that matter for triage and differ between a and b.
+func changedFields(a, b Vulnerability) []string {
+	var fields []string
+	if a.Severity != b.Severity {
+		fields = append(fields, "severity")
+	}
+	if a.InstalledVersion != b.InstalledVersion {
+		fields = append(fields, "installedVersion")
+	}
+	if a.FixedVersion != b.FixedVersion {
+		fields = append(fields, "fixedVersion")
+	}
+	if a.FixState != b.FixState {
+		fields = append(fields, "fixState")
+	}
+	if a.CVSSScore != b.CVSSScore {
+		fields = append(fields, "cvssScore")
+	}
+	return fields
+}
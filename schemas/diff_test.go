@@ -0,0 +1,85 @@
+package schemas_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestDiffResults(t *testing.T) {
+	app := func(digest string) schemas.ArtifactReference {
+		return schemas.ArtifactReference{
+			Host: "us-docker.pkg.dev", ProjectID: "p", RepositoryID: "r", ImageName: "app",
+			Digest: utils.Ptr(digest),
+		}
+	}
+	worker := schemas.ArtifactReference{Host: "us-docker.pkg.dev", ProjectID: "p", RepositoryID: "r", ImageName: "worker"}
+	vuln := func(id string, severity schemas.Severity, fixed string) schemas.Vulnerability {
+		return schemas.Vulnerability{ID: id, PackageName: "openssl", Severity: severity, FixedVersion: fixed}
+	}
+
+	before := []schemas.AnalyzeResult{
+		{Artifact: app("sha256:old"), Vulnerabilities: []schemas.Vulnerability{
+			vuln("CVE-1", schemas.SeverityHigh, ""),
+			vuln("CVE-2", schemas.SeverityLow, ""),
+			vuln("CVE-3", schemas.SeverityLow, ""),
+		}},
+		{Artifact: worker, Vulnerabilities: []schemas.Vulnerability{vuln("CVE-9", schemas.SeverityLow, "")}},
+	}
+	after := []schemas.AnalyzeResult{
+		{Artifact: app("sha256:new"), Vulnerabilities: []schemas.Vulnerability{
+			vuln("CVE-1", schemas.SeverityCritical, "1.2"),
+			vuln("CVE-3", schemas.SeverityLow, ""),
+			vuln("CVE-4", schemas.SeverityMedium, ""),
+		}},
+		{Artifact: worker, Vulnerabilities: []schemas.Vulnerability{vuln("CVE-9", schemas.SeverityLow, "")}},
+	}
+
+	tests := map[string]struct {
+		before []schemas.AnalyzeResult
+		after  []schemas.AnalyzeResult
+		want   []schemas.ImageDiff
+	}{
+		"should match images across digests and omit unchanged images": {
+			before: before,
+			after:  after,
+			want: []schemas.ImageDiff{{
+				Image:   "us-docker.pkg.dev/p/r/app",
+				Before:  utils.Ptr(app("sha256:old")),
+				After:   utils.Ptr(app("sha256:new")),
+				Added:   []schemas.Vulnerability{vuln("CVE-4", schemas.SeverityMedium, "")},
+				Removed: []schemas.Vulnerability{vuln("CVE-2", schemas.SeverityLow, "")},
+				Changed: []schemas.VulnerabilityChange{{
+					Before: vuln("CVE-1", schemas.SeverityHigh, ""),
+					After:  vuln("CVE-1", schemas.SeverityCritical, "1.2"),
+					Fields: []string{"severity", "fixedVersion"},
+				}},
+			}},
+		},
+		"should report every finding of a removed image": {
+			before: before[1:],
+			after:  nil,
+			want: []schemas.ImageDiff{{
+				Image:   "us-docker.pkg.dev/p/r/worker",
+				Before:  &worker,
+				Removed: []schemas.Vulnerability{vuln("CVE-9", schemas.SeverityLow, "")},
+			}},
+		},
+		"should return nothing for identical reports": {
+			before: before,
+			after:  before,
+			want:   nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := schemas.DiffResults(tt.before, tt.after)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("DiffResults() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
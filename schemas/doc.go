@@ -0,0 +1,4 @@
+// Package schemas defines the domain types shared by every drydock component:
+// the scanner and analyzers produce them, and exporters, servers and library users consume them.
+// It is the single source of truth for the result format (see SchemaVersion and JSONSchema).
+package schemas
@@ -0,0 +1,101 @@
+package schemas
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// History records the findings of earlier scans, so findings can be tracked across runs.
+// Findings are tracked per image and tag (or per image, for results without a tag),
+// so a new digest pushed to the same tag keeps the history of the previous one.
+type History struct {
+	// Images maps tracked images to their findings, keyed by vulnerability ID and package.
+	// Findings no longer observed are kept with ResolvedAt set.
+	Images map[string]map[string]Vulnerability `json:"images"`
+}
+
+// historyKey identifies the image and tag of a result across runs.
+func historyKey(a ArtifactReference) string {
+	if a.Tag != nil && *a.Tag != "" {
+		return imageKey(a) + ":" + *a.Tag
+	}
+	return imageKey(a)
+}
+
+// Observe records the findings of result, seen at now, and stamps them with when they were first
+// and last seen. Findings the history has for the image but result no longer has are marked as
+// resolved at now and listed in result.Resolved.
+// Results must be observed with the same severity and fixability filters across runs,
+// otherwise filtered-out findings are reported as resolved.
+func (h *History) Observe(result *AnalyzeResult, now time.Time) {
+	if h.Images == nil {
+		h.Images = make(map[string]map[string]Vulnerability)
+	}
+	key := historyKey(result.Artifact)
+	prev := h.Images[key]
+	next := make(map[string]Vulnerability, len(result.Vulnerabilities))
+
+	for i := range result.Vulnerabilities {
+		v := &result.Vulnerabilities[i]
+		k := findingKey(*v)
+		// Keep the earliest sighting of open findings; resolved ones start over when they come back
+		if p, ok := prev[k]; ok && p.ResolvedAt.IsZero() && !p.FirstSeen.IsZero() {
+			if v.FirstSeen.IsZero() || p.FirstSeen.Before(v.FirstSeen) {
+				v.FirstSeen = p.FirstSeen
+			}
+		}
+		if v.FirstSeen.IsZero() {
+			v.FirstSeen = now
+		}
+		v.LastSeen = now
+		v.ResolvedAt = time.Time{}
+		next[k] = *v
+	}
+
+	result.Resolved = nil
+	for k, p := range prev {
+		if _, ok := next[k]; ok {
+			continue
+		}
+		if p.ResolvedAt.IsZero() {
+			p.ResolvedAt = now
+			result.Resolved = append(result.Resolved, p)
+		}
+		next[k] = p
+	}
+	slices.SortFunc(result.Resolved, func(a, b Vulnerability) int {
+		return cmp.Or(cmp.Compare(a.ID, b.ID), cmp.Compare(a.PackageName, b.PackageName))
+	})
+	h.Images[key] = next
+}
+
+// TrendPoint counts the findings open at the end of a day.
+type TrendPoint struct {
+	// Date is the start of the day
+	Date time.Time `json:"date"`
+
+	// CountBySeverity counts the open findings by severity
+	CountBySeverity map[Severity]int `json:"countBySeverity"`
+}
+
+// Trend returns the findings open at the end of each of the last days up to the day of until,
+// oldest first. Findings count once per tracked image and tag. Days are in the location of until.
+func (h *History) Trend(until time.Time, days int) []TrendPoint {
+	today := time.Date(until.Year(), until.Month(), until.Day(), 0, 0, 0, 0, until.Location())
+	points := make([]TrendPoint, 0, days)
+	for i := days - 1; i >= 0; i-- {
+		start := today.AddDate(0, 0, -i)
+		end := start.AddDate(0, 0, 1)
+		point := TrendPoint{Date: start, CountBySeverity: make(map[Severity]int)}
+		for _, findings := range h.Images {
+			for _, v := range findings {
+				if v.FirstSeen.Before(end) && (v.ResolvedAt.IsZero() || !v.ResolvedAt.Before(end)) {
+					point.CountBySeverity[v.Severity]++
+				}
+			}
+		}
+		points = append(points, point)
+	}
+	return points
+}
@@ -0,0 +1,109 @@
+package schemas_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestHistory_Observe(t *testing.T) {
+	day1 := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	day3 := day2.Add(24 * time.Hour)
+	artifact := func(digest string) schemas.ArtifactReference {
+		return schemas.ArtifactReference{
+			Host: "us-docker.pkg.dev", ProjectID: "p", RepositoryID: "r", ImageName: "app",
+			Tag: utils.Ptr("latest"), Digest: utils.Ptr(digest),
+		}
+	}
+	openssl := schemas.Vulnerability{ID: "CVE-1", PackageName: "openssl", Severity: schemas.SeverityHigh}
+	zlib := schemas.Vulnerability{ID: "CVE-2", PackageName: "zlib", Severity: schemas.SeverityLow}
+
+	var history schemas.History
+
+	// Day 1: both findings are new
+	first := schemas.AnalyzeResult{Artifact: artifact("sha256:a"), Vulnerabilities: []schemas.Vulnerability{openssl, zlib}}
+	history.Observe(&first, day1)
+	want := []schemas.Vulnerability{
+		{ID: "CVE-1", PackageName: "openssl", Severity: schemas.SeverityHigh, FirstSeen: day1, LastSeen: day1},
+		{ID: "CVE-2", PackageName: "zlib", Severity: schemas.SeverityLow, FirstSeen: day1, LastSeen: day1},
+	}
+	if diff := cmp.Diff(want, first.Vulnerabilities); diff != "" {
+		t.Errorf("day 1 vulnerabilities mismatch (-want +got):\n%s", diff)
+	}
+
+	// Day 2: a new digest of the same tag fixes zlib
+	second := schemas.AnalyzeResult{Artifact: artifact("sha256:b"), Vulnerabilities: []schemas.Vulnerability{openssl}}
+	history.Observe(&second, day2)
+	want = []schemas.Vulnerability{
+		{ID: "CVE-1", PackageName: "openssl", Severity: schemas.SeverityHigh, FirstSeen: day1, LastSeen: day2},
+	}
+	if diff := cmp.Diff(want, second.Vulnerabilities); diff != "" {
+		t.Errorf("day 2 vulnerabilities mismatch (-want +got):\n%s", diff)
+	}
+	wantResolved := []schemas.Vulnerability{
+		{ID: "CVE-2", PackageName: "zlib", Severity: schemas.SeverityLow, FirstSeen: day1, LastSeen: day1, ResolvedAt: day2},
+	}
+	if diff := cmp.Diff(wantResolved, second.Resolved); diff != "" {
+		t.Errorf("day 2 resolved mismatch (-want +got):\n%s", diff)
+	}
+
+	// Day 3: zlib comes back and starts over; resolved findings are only reported once
+	third := schemas.AnalyzeResult{Artifact: artifact("sha256:c"), Vulnerabilities: []schemas.Vulnerability{openssl, zlib}}
+	history.Observe(&third, day3)
+	want = []schemas.Vulnerability{
+		{ID: "CVE-1", PackageName: "openssl", Severity: schemas.SeverityHigh, FirstSeen: day1, LastSeen: day3},
+		{ID: "CVE-2", PackageName: "zlib", Severity: schemas.SeverityLow, FirstSeen: day3, LastSeen: day3},
+	}
+	if diff := cmp.Diff(want, third.Vulnerabilities); diff != "" {
+		t.Errorf("day 3 vulnerabilities mismatch (-want +got):\n%s", diff)
+	}
+	if len(third.Resolved) != 0 {
+		t.Errorf("day 3 resolved = %v, want none", third.Resolved)
+	}
+}
+
+func TestHistory_Observe_KeepsEarlierFirstSeen(t *testing.T) {
+	day1 := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	reported := day1.Add(-48 * time.Hour)
+	history := schemas.History{}
+
+	// Findings stamped by the registry keep the earlier of both dates
+	result := schemas.AnalyzeResult{
+		Artifact:        schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "app"},
+		Vulnerabilities: []schemas.Vulnerability{{ID: "CVE-1", PackageName: "openssl", FirstSeen: reported}},
+	}
+	history.Observe(&result, day1)
+	if got := result.Vulnerabilities[0].FirstSeen; !got.Equal(reported) {
+		t.Errorf("FirstSeen = %v, want %v", got, reported)
+	}
+}
+
+func TestHistory_Trend(t *testing.T) {
+	day1 := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	day3 := day2.Add(24 * time.Hour)
+	midnight := func(t time.Time) time.Time { return t.Truncate(24 * time.Hour) }
+	history := schemas.History{Images: map[string]map[string]schemas.Vulnerability{
+		"h/p/r/app:latest": {
+			"CVE-1\x00openssl": {ID: "CVE-1", PackageName: "openssl", Severity: schemas.SeverityHigh, FirstSeen: day1, LastSeen: day3},
+			"CVE-2\x00zlib":    {ID: "CVE-2", PackageName: "zlib", Severity: schemas.SeverityLow, FirstSeen: day1, LastSeen: day1, ResolvedAt: day2},
+		},
+		"h/p/r/worker": {
+			"CVE-3\x00curl": {ID: "CVE-3", PackageName: "curl", Severity: schemas.SeverityHigh, FirstSeen: day3, LastSeen: day3},
+		},
+	}}
+
+	want := []schemas.TrendPoint{
+		{Date: midnight(day1).Add(-24 * time.Hour), CountBySeverity: map[schemas.Severity]int{}},
+		{Date: midnight(day1), CountBySeverity: map[schemas.Severity]int{schemas.SeverityHigh: 1, schemas.SeverityLow: 1}},
+		{Date: midnight(day2), CountBySeverity: map[schemas.Severity]int{schemas.SeverityHigh: 1}},
+		{Date: midnight(day3), CountBySeverity: map[schemas.Severity]int{schemas.SeverityHigh: 2}},
+	}
+	if diff := cmp.Diff(want, history.Trend(day3, 4)); diff != "" {
+		t.Errorf("Trend() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,133 @@
+package schemas
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Remediation is a concrete step that fixes some vulnerabilities of an image,
+// e.g. upgrading an OS package with its package manager.
+type Remediation struct {
+	// PackageManager is the tool the command runs (e.g., "apt", "apk", "dnf")
+	PackageManager string `json:"packageManager" yaml:"packageManager"`
+
+	// PackageName is the package to upgrade
+	PackageName string `json:"packageName" yaml:"packageName"`
+
+	// InstalledVersion is the version currently in the image
+	InstalledVersion string `json:"installedVersion" yaml:"installedVersion"`
+
+	// FixedVersion is the lowest version fixing every listed vulnerability
+	FixedVersion string `json:"fixedVersion" yaml:"fixedVersion"`
+
+	// Command upgrades the package, e.g. "apt-get install --only-upgrade openssl=1.1.1t"
+	Command string `json:"command" yaml:"command"`
+
+	// VulnerabilityIDs lists the vulnerabilities fixed by the upgrade
+	VulnerabilityIDs []string `json:"vulnerabilityIDs" yaml:"vulnerabilityIDs"`
+}
+
+// packageManagers maps OS package purl types to the package manager and its upgrade command.
+var packageManagers = map[string]struct {
+	name    string
+	command func(name, version string) string
+}{
+	"deb": {"apt", func(name, version string) string {
+		return fmt.Sprintf("apt-get install --only-upgrade %s=%s", name, version)
+	}},
+	"apk": {"apk", func(name, version string) string {
+		return fmt.Sprintf("apk add --upgrade %s=%s", name, version)
+	}},
+	"rpm": {"dnf", func(name, version string) string {
+		return fmt.Sprintf("dnf upgrade %s-%s", name, version)
+	}},
+}
+
+// BuildRemediations returns the package upgrades fixing the fixable OS-package vulnerabilities, one per package,
+// sorted by package name. Vulnerabilities of other ecosystems, or without a purl, are not covered.
+func BuildRemediations(vulns []Vulnerability) []Remediation {
+	byPackage := make(map[string]*Remediation)
+	for _, v := range vulns {
+		if v.FixState != FixStateFixAvailable || v.FixedVersion == "" {
+			continue
+		}
+		pm, ok := packageManagers[purlType(v.PURL)]
+		if !ok {
+			continue
+		}
+
+		key := pm.name + "/" + v.PackageName
+		r, ok := byPackage[key]
+		if !ok {
+			r = &Remediation{PackageManager: pm.name, PackageName: v.PackageName, InstalledVersion: v.InstalledVersion}
+			byPackage[key] = r
+		}
+		// The highest fixed version is the one that fixes every vulnerability of the package
+		if compareVersions(v.FixedVersion, r.FixedVersion) > 0 {
+			r.FixedVersion = v.FixedVersion
+			r.Command = pm.command(v.PackageName, v.FixedVersion)
+		}
+		if !slices.Contains(r.VulnerabilityIDs, v.ID) {
+			r.VulnerabilityIDs = append(r.VulnerabilityIDs, v.ID)
+		}
+	}
+
+	remediations := make([]Remediation, 0, len(byPackage))
+	for _, r := range byPackage {
+		slices.Sort(r.VulnerabilityIDs)
+		remediations = append(remediations, *r)
+	}
+	slices.SortFunc(remediations, func(a, b Remediation) int {
+		return cmp.Or(strings.Compare(a.PackageName, b.PackageName), strings.Compare(a.PackageManager, b.PackageManager))
+	})
+	if len(remediations) == 0 {
+		return nil
+	}
+	return remediations
+}
+
+// purlType returns the type of a Package URL, e.g. "deb" for "pkg:deb/debian/openssl@1.1.1".
+func purlType(purl string) string {
+	rest, ok := strings.CutPrefix(purl, "pkg:")
+	if !ok {
+		return ""
+	}
+	typ, _, _ := strings.Cut(rest, "/")
+	return typ
+}
+
+// compareVersions orders package versions by comparing their numeric runs as numbers and the rest as text.
+// It is an approximation of distribution version ordering, good enough to pick the highest of several fix versions.
+func compareVersions(a, b string) int {
+	for a != "" && b != "" {
+		var ca, cb string
+		ca, a = nextVersionChunk(a)
+		cb, b = nextVersionChunk(b)
+		na, errA := strconv.ParseUint(ca, 10, 64)
+		nb, errB := strconv.ParseUint(cb, 10, 64)
+		var c int
+		if errA == nil && errB == nil {
+			c = cmp.Compare(na, nb)
+		} else {
+			c = strings.Compare(ca, cb)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// nextVersionChunk splits off the leading run of digits or non-digits of s.
+func nextVersionChunk(s string) (chunk, rest string) {
+	isDigit := func(c byte) bool { return c >= '0' && c <= '9' }
+	digit := isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digit {
+		i++
+	}
+	return s[:i], s[i:]
+}
@@ -0,0 +1,55 @@
+package schemas_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+func TestBuildRemediations(t *testing.T) {
+	tests := map[string]struct {
+		vulns []schemas.Vulnerability
+		want  []schemas.Remediation
+	}{
+		"should upgrade each package to the highest fixed version": {
+			vulns: []schemas.Vulnerability{
+				{ID: "CVE-2", PackageName: "openssl", PURL: "pkg:deb/debian/openssl@1.1.1n", InstalledVersion: "1.1.1n", FixedVersion: "1.1.1t", FixState: schemas.FixStateFixAvailable},
+				{ID: "CVE-1", PackageName: "openssl", PURL: "pkg:deb/debian/openssl@1.1.1n", InstalledVersion: "1.1.1n", FixedVersion: "1.1.1p", FixState: schemas.FixStateFixAvailable},
+				{ID: "CVE-3", PackageName: "busybox", PURL: "pkg:apk/alpine/busybox@1.36.0-r0", InstalledVersion: "1.36.0-r0", FixedVersion: "1.36.1-r10", FixState: schemas.FixStateFixAvailable},
+				{ID: "CVE-4", PackageName: "glibc", PURL: "pkg:rpm/redhat/glibc@2.28-1", InstalledVersion: "2.28-1", FixedVersion: "2.28-9", FixState: schemas.FixStateFixAvailable},
+			},
+			want: []schemas.Remediation{
+				{PackageManager: "apk", PackageName: "busybox", InstalledVersion: "1.36.0-r0", FixedVersion: "1.36.1-r10", Command: "apk add --upgrade busybox=1.36.1-r10", VulnerabilityIDs: []string{"CVE-3"}},
+				{PackageManager: "dnf", PackageName: "glibc", InstalledVersion: "2.28-1", FixedVersion: "2.28-9", Command: "dnf upgrade glibc-2.28-9", VulnerabilityIDs: []string{"CVE-4"}},
+				{PackageManager: "apt", PackageName: "openssl", InstalledVersion: "1.1.1n", FixedVersion: "1.1.1t", Command: "apt-get install --only-upgrade openssl=1.1.1t", VulnerabilityIDs: []string{"CVE-1", "CVE-2"}},
+			},
+		},
+		"should compare numeric parts as numbers": {
+			vulns: []schemas.Vulnerability{
+				{ID: "CVE-1", PackageName: "zlib", PURL: "pkg:deb/debian/zlib@1.2.9", FixedVersion: "1.2.10", FixState: schemas.FixStateFixAvailable},
+				{ID: "CVE-2", PackageName: "zlib", PURL: "pkg:deb/debian/zlib@1.2.9", FixedVersion: "1.2.9.1", FixState: schemas.FixStateFixAvailable},
+			},
+			want: []schemas.Remediation{
+				{PackageManager: "apt", PackageName: "zlib", FixedVersion: "1.2.10", Command: "apt-get install --only-upgrade zlib=1.2.10", VulnerabilityIDs: []string{"CVE-1", "CVE-2"}},
+			},
+		},
+		"should skip unfixed and non-OS findings": {
+			vulns: []schemas.Vulnerability{
+				{ID: "CVE-1", PackageName: "openssl", PURL: "pkg:deb/debian/openssl@1.1.1n", FixState: schemas.FixStateNoFixAvailable},
+				{ID: "CVE-2", PackageName: "golang.org/x/net", PURL: "pkg:golang/golang.org/x/net@0.1.0", FixedVersion: "0.17.0", FixState: schemas.FixStateFixAvailable},
+				{ID: "CVE-3", PackageName: "curl", FixedVersion: "8.0", FixState: schemas.FixStateFixAvailable},
+			},
+			want: nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := schemas.BuildRemediations(tt.vulns)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("BuildRemediations() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
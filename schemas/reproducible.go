@@ -0,0 +1,41 @@
+package schemas
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// SortResults orders results by their artifact reference (host, project, repository, image, tag, then digest).
+func SortResults(results []AnalyzeResult) {
+	slices.SortStableFunc(results, func(a, b AnalyzeResult) int {
+		return cmp.Compare(a.Artifact.String(), b.Artifact.String())
+	})
+}
+
+// CompareVulnerabilities orders vulnerabilities from the most severe, then by ID, package and installed version.
+// It can be used with slices.SortFunc.
+func CompareVulnerabilities(a, b Vulnerability) int {
+	return cmp.Or(
+		CompareSeverity(b.Severity, a.Severity),
+		cmp.Compare(a.ID, b.ID),
+		cmp.Compare(a.PackageName, b.PackageName),
+		cmp.Compare(a.InstalledVersion, b.InstalledVersion),
+		cmp.Compare(a.PURL, b.PURL),
+	)
+}
+
+// Normalize makes a result independent of when and how fast it was produced, so that two scans
+// of unchanged data give identical results: its findings are sorted (see CompareVulnerabilities),
+// its scan time is set to scanTime, and the analysis duration is cleared.
+func Normalize(result *AnalyzeResult, scanTime time.Time) {
+	slices.SortStableFunc(result.Vulnerabilities, CompareVulnerabilities)
+	slices.SortStableFunc(result.Resolved, CompareVulnerabilities)
+	slices.SortStableFunc(result.Suppressed, func(a, b SuppressedFinding) int {
+		return CompareVulnerabilities(a.Vulnerability, b.Vulnerability)
+	})
+	result.ScanTime = scanTime.UTC()
+	if result.Metadata != nil {
+		result.Metadata.DurationMillis = 0
+	}
+}
@@ -0,0 +1,112 @@
+package schemas_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+func TestSortResults(t *testing.T) {
+	result := func(repository, image string) schemas.AnalyzeResult {
+		return schemas.AnalyzeResult{Artifact: schemas.ArtifactReference{
+			Host: "us-central1-docker.pkg.dev", ProjectID: "p", RepositoryID: repository, ImageName: image,
+		}}
+	}
+	results := []schemas.AnalyzeResult{result("repo", "worker"), result("other", "app"), result("repo", "api")}
+
+	schemas.SortResults(results)
+
+	got := make([]string, 0, len(results))
+	for _, r := range results {
+		got = append(got, r.Artifact.RepositoryID+"/"+r.Artifact.ImageName)
+	}
+	want := []string{"other/app", "repo/api", "repo/worker"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SortResults() order mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	scanTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("JST", 9*60*60))
+	vuln := func(id string, severity schemas.Severity, pkg string) schemas.Vulnerability {
+		return schemas.Vulnerability{ID: id, Severity: severity, PackageName: pkg}
+	}
+
+	tests := map[string]struct {
+		input schemas.AnalyzeResult
+		want  schemas.AnalyzeResult
+	}{
+		"should sort findings from the most severe, then by ID and package": {
+			input: schemas.AnalyzeResult{
+				Vulnerabilities: []schemas.Vulnerability{
+					vuln("CVE-2", schemas.SeverityHigh, "zlib"),
+					vuln("CVE-3", schemas.SeverityCritical, "openssl"),
+					vuln("CVE-1", schemas.SeverityHigh, "zlib"),
+					vuln("CVE-1", schemas.SeverityHigh, "curl"),
+				},
+				Resolved: []schemas.Vulnerability{vuln("CVE-5", schemas.SeverityLow, "a"), vuln("CVE-4", schemas.SeverityLow, "a")},
+				Suppressed: []schemas.SuppressedFinding{
+					{Vulnerability: vuln("CVE-7", schemas.SeverityLow, "a")},
+					{Vulnerability: vuln("CVE-6", schemas.SeverityMedium, "a")},
+				},
+			},
+			want: schemas.AnalyzeResult{
+				ScanTime: scanTime.UTC(),
+				Vulnerabilities: []schemas.Vulnerability{
+					vuln("CVE-3", schemas.SeverityCritical, "openssl"),
+					vuln("CVE-1", schemas.SeverityHigh, "curl"),
+					vuln("CVE-1", schemas.SeverityHigh, "zlib"),
+					vuln("CVE-2", schemas.SeverityHigh, "zlib"),
+				},
+				Resolved: []schemas.Vulnerability{vuln("CVE-4", schemas.SeverityLow, "a"), vuln("CVE-5", schemas.SeverityLow, "a")},
+				Suppressed: []schemas.SuppressedFinding{
+					{Vulnerability: vuln("CVE-6", schemas.SeverityMedium, "a")},
+					{Vulnerability: vuln("CVE-7", schemas.SeverityLow, "a")},
+				},
+			},
+		},
+		"should replace the scan time and clear the analysis duration": {
+			input: schemas.AnalyzeResult{
+				ScanTime: time.Now(),
+				Metadata: &schemas.ScanMetadata{DurationMillis: 1234, OccurrencesFetched: 3},
+			},
+			want: schemas.AnalyzeResult{
+				ScanTime: scanTime.UTC(),
+				Metadata: &schemas.ScanMetadata{OccurrencesFetched: 3},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tt.input
+			schemas.Normalize(&got, scanTime)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Normalize() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestVulnerabilitySummary_StableJSON(t *testing.T) {
+	summary := schemas.VulnerabilitySummary{CountBySeverity: map[schemas.Severity]int{
+		schemas.SeverityLow: 1, schemas.SeverityCritical: 2, schemas.SeverityHigh: 3, schemas.SeverityMedium: 4,
+	}}
+
+	first, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	for range 10 {
+		got, err := json.Marshal(summary)
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		if diff := cmp.Diff(string(first), string(got)); diff != "" {
+			t.Fatalf("json.Marshal() is not stable (-first +got):\n%s", diff)
+		}
+	}
+}
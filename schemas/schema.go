@@ -0,0 +1,47 @@
+package schemas
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// SchemaVersion is the version of the exported result format.
+// It is bumped whenever a field is removed or its meaning changes; adding optional fields does not bump it.
+const SchemaVersion = "1"
+
+// JSONSchema is the JSON Schema (draft 2020-12) describing the JSON output of drydock.
+//
+//go:embed result.schema.json
+var JSONSchema []byte
+
+// MarshalJSON adds the "schemaVersion" field so consumers can detect format changes
+func (r AnalyzeResult) MarshalJSON() ([]byte, error) {
+	type Alias AnalyzeResult
+	return json.Marshal(&struct {
+		SchemaVersion string `json:"schemaVersion"`
+		*Alias
+	}{
+		SchemaVersion: SchemaVersion,
+		Alias:         (*Alias)(&r),
+	})
+}
+
+// ReadResults reads a JSON report as written by the JSON exporter.
+// Several concatenated arrays (e.g., one per scan appended to the same file) are merged.
+func ReadResults(r io.Reader) ([]AnalyzeResult, error) {
+	dec := json.NewDecoder(r)
+	var results []AnalyzeResult
+	for {
+		var batch []AnalyzeResult
+		if err := dec.Decode(&batch); err != nil {
+			if errors.Is(err, io.EOF) {
+				return results, nil
+			}
+			return nil, fmt.Errorf("invalid report: %w", err)
+		}
+		results = append(results, batch...)
+	}
+}
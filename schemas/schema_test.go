@@ -0,0 +1,178 @@
+package schemas_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+func TestAnalyzeResult_MarshalJSON(t *testing.T) {
+	result := schemas.AnalyzeResult{
+		Artifact: schemas.ArtifactReference{Host: "h", ProjectID: "p", RepositoryID: "r", ImageName: "i"},
+		ScanTime: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.HasPrefix(string(data), `{"schemaVersion":"`+schemas.SchemaVersion+`",`) {
+		t.Errorf("Marshal() = %s, want schemaVersion as the first field", data)
+	}
+
+	var got schemas.AnalyzeResult
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if diff := cmp.Diff(result, got); diff != "" {
+		t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestJSONSchema_MatchesTypes guards against the embedded schema drifting from the Go types.
+func TestJSONSchema_MatchesTypes(t *testing.T) {
+	var doc struct {
+		Defs map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(schemas.JSONSchema, &doc); err != nil {
+		t.Fatalf("JSONSchema is not valid JSON: %v", err)
+	}
+
+	tests := map[string]struct {
+		typ   reflect.Type
+		def   string
+		extra []string // properties added by custom marshalers
+	}{
+		"should describe AnalyzeResult": {
+			typ:   reflect.TypeFor[schemas.AnalyzeResult](),
+			def:   "analyzeResult",
+			extra: []string{"schemaVersion"},
+		},
+		"should describe ArtifactReference": {
+			typ:   reflect.TypeFor[schemas.ArtifactReference](),
+			def:   "artifact",
+			extra: []string{"uri"},
+		},
+		"should describe ImageMetadata": {
+			typ: reflect.TypeFor[schemas.ImageMetadata](),
+			def: "image",
+		},
+		"should describe Vulnerability": {
+			typ:   reflect.TypeFor[schemas.Vulnerability](),
+			def:   "vulnerability",
+			extra: []string{"urls"},
+		},
+		"should describe Workload": {
+			typ: reflect.TypeFor[schemas.Workload](),
+			def: "workload",
+		},
+		"should describe SuppressedFinding": {
+			typ: reflect.TypeFor[schemas.SuppressedFinding](),
+			def: "suppressedFinding",
+		},
+		"should describe Suppression": {
+			typ: reflect.TypeFor[schemas.Suppression](),
+			def: "suppression",
+		},
+		"should describe Reference": {
+			typ: reflect.TypeFor[schemas.Reference](),
+			def: "reference",
+		},
+		"should describe CVSSDetails": {
+			typ: reflect.TypeFor[schemas.CVSSDetails](),
+			def: "cvss",
+		},
+		"should describe VulnerabilitySummary": {
+			typ: reflect.TypeFor[schemas.VulnerabilitySummary](),
+			def: "summary",
+		},
+		"should describe BaseImageAdvice": {
+			typ: reflect.TypeFor[schemas.BaseImageAdvice](),
+			def: "baseImage",
+		},
+		"should describe Remediation": {
+			typ: reflect.TypeFor[schemas.Remediation](),
+			def: "remediation",
+		},
+		"should describe ScannerInfo": {
+			typ: reflect.TypeFor[schemas.ScannerInfo](),
+			def: "scanner",
+		},
+		"should describe ScanMetadata": {
+			typ: reflect.TypeFor[schemas.ScanMetadata](),
+			def: "metadata",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			def, ok := doc.Defs[tt.def]
+			if !ok {
+				t.Fatalf("schema has no $defs/%s", tt.def)
+			}
+
+			want := slices.Clone(tt.extra)
+			for i := range tt.typ.NumField() {
+				jsonName, _, _ := strings.Cut(tt.typ.Field(i).Tag.Get("json"), ",")
+				if jsonName != "" && jsonName != "-" {
+					want = append(want, jsonName)
+				}
+			}
+			got := make([]string, 0, len(def.Properties))
+			for p := range def.Properties {
+				got = append(got, p)
+			}
+			slices.Sort(want)
+			slices.Sort(got)
+
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("$defs/%s properties mismatch (-types +schema):\n%s", tt.def, diff)
+			}
+		})
+	}
+}
+
+func TestReadResults(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		"should read a JSON array": {
+			input: `[{"schemaVersion":"1","artifact":{"imageName":"a"}},{"artifact":{"imageName":"b"}}]`,
+			want:  []string{"a", "b"},
+		},
+		"should merge concatenated arrays": {
+			input: "[{\"artifact\":{\"imageName\":\"a\"}}]\n[{\"artifact\":{\"imageName\":\"b\"}}]\n",
+			want:  []string{"a", "b"},
+		},
+		"should reject other documents": {
+			input:   `{"artifact":{}}`,
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			results, err := schemas.ReadResults(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadResults() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, r := range results {
+				got = append(got, r.Artifact.ImageName)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ReadResults() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package schemas
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SLAPolicy maps severities to the time allowed to fix a finding, counted from when it was first seen.
+// Severities without a window have no SLA.
+type SLAPolicy map[Severity]time.Duration
+
+// ParseSLAPolicy parses windows such as "CRITICAL=7d,HIGH=30d". Durations accept a "d" (days) suffix
+// in addition to the units of time.ParseDuration.
+func ParseSLAPolicy(s string) (SLAPolicy, error) {
+	policy := make(SLAPolicy)
+	for part := range strings.SplitSeq(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, window, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid SLA %q: want SEVERITY=DURATION, e.g. CRITICAL=7d", part)
+		}
+		severity, err := ParseSeverity(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		d, err := parseWindow(strings.TrimSpace(window))
+		if err != nil {
+			return nil, fmt.Errorf("invalid SLA window for %s: %w", severity, err)
+		}
+		policy[severity] = d
+	}
+	if len(policy) == 0 {
+		return nil, fmt.Errorf("empty SLA policy")
+	}
+	return policy, nil
+}
+
+// parseWindow parses a positive duration, accepting whole days such as "7d".
+func parseWindow(s string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, err
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration must be positive: %q", s)
+	}
+	return d, nil
+}
+
+// Apply sets the SLA due date of the findings of result that have a window and a first-seen time,
+// marks those past due at now as breached, and counts them in the summary.
+func (p SLAPolicy) Apply(result *AnalyzeResult, now time.Time) {
+	result.Summary.SLABreachCount = 0
+	for i := range result.Vulnerabilities {
+		v := &result.Vulnerabilities[i]
+		window, ok := p[v.Severity]
+		if !ok || v.FirstSeen.IsZero() {
+			continue
+		}
+		v.SLADue = v.FirstSeen.Add(window)
+		v.SLABreached = now.After(v.SLADue)
+		if v.SLABreached {
+			result.Summary.SLABreachCount++
+		}
+	}
+}
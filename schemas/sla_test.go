@@ -0,0 +1,80 @@
+package schemas_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+func TestParseSLAPolicy(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    schemas.SLAPolicy
+		wantErr bool
+	}{
+		"should parse days and durations": {
+			input: "CRITICAL=7d, high=30d,MEDIUM=36h",
+			want: schemas.SLAPolicy{
+				schemas.SeverityCritical: 7 * 24 * time.Hour,
+				schemas.SeverityHigh:     30 * 24 * time.Hour,
+				schemas.SeverityMedium:   36 * time.Hour,
+			},
+		},
+		"should reject unknown severities": {
+			input:   "URGENT=1d",
+			wantErr: true,
+		},
+		"should reject missing windows": {
+			input:   "CRITICAL",
+			wantErr: true,
+		},
+		"should reject non-positive windows": {
+			input:   "CRITICAL=0d",
+			wantErr: true,
+		},
+		"should reject empty policies": {
+			input:   " , ",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := schemas.ParseSLAPolicy(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSLAPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseSLAPolicy() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSLAPolicy_Apply(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	policy := schemas.SLAPolicy{schemas.SeverityCritical: 7 * 24 * time.Hour, schemas.SeverityHigh: 30 * 24 * time.Hour}
+
+	result := schemas.AnalyzeResult{Vulnerabilities: []schemas.Vulnerability{
+		{ID: "overdue", Severity: schemas.SeverityCritical, FirstSeen: now.Add(-10 * 24 * time.Hour)},
+		{ID: "within", Severity: schemas.SeverityHigh, FirstSeen: now.Add(-10 * 24 * time.Hour)},
+		{ID: "no-window", Severity: schemas.SeverityLow, FirstSeen: now.Add(-100 * 24 * time.Hour)},
+		{ID: "never-seen", Severity: schemas.SeverityCritical},
+	}}
+	policy.Apply(&result, now)
+
+	want := []schemas.Vulnerability{
+		{ID: "overdue", Severity: schemas.SeverityCritical, FirstSeen: now.Add(-10 * 24 * time.Hour), SLADue: now.Add(-3 * 24 * time.Hour), SLABreached: true},
+		{ID: "within", Severity: schemas.SeverityHigh, FirstSeen: now.Add(-10 * 24 * time.Hour), SLADue: now.Add(20 * 24 * time.Hour)},
+		{ID: "no-window", Severity: schemas.SeverityLow, FirstSeen: now.Add(-100 * 24 * time.Hour)},
+		{ID: "never-seen", Severity: schemas.SeverityCritical},
+	}
+	if diff := cmp.Diff(want, result.Vulnerabilities); diff != "" {
+		t.Errorf("Apply() vulnerabilities mismatch (-want +got):\n%s", diff)
+	}
+	if result.Summary.SLABreachCount != 1 {
+		t.Errorf("SLABreachCount = %d, want 1", result.Summary.SLABreachCount)
+	}
+}
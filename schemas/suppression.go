@@ -0,0 +1,136 @@
+package schemas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Suppression is an accepted exception for a vulnerability, such as a risk acceptance or a VEX
+// "not affected" statement. Findings it matches are not reported as vulnerabilities but listed
+// as suppressed with who accepted them, why and until when, so exceptions can be audited from the report.
+type Suppression struct {
+	// ID is the suppressed vulnerability ID (e.g., CVE-2024-1234), compared case-insensitively
+	ID string `json:"id" yaml:"id"`
+
+	// PackageName limits the suppression to one package; empty matches any package
+	PackageName string `json:"packageName,omitempty" yaml:"packageName,omitempty"`
+
+	// Reason explains why the finding is accepted
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+
+	// By is who accepted the finding
+	By string `json:"by,omitempty" yaml:"by,omitempty"`
+
+	// Until is when the suppression expires; zero means it does not expire
+	Until time.Time `json:"until,omitzero" yaml:"until,omitempty"`
+
+	// Source is where the suppression comes from (e.g., "skip-cve" or a suppressions file)
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+}
+
+// SuppressedFinding is a finding left out of the reported vulnerabilities by a suppression.
+type SuppressedFinding struct {
+	// Vulnerability is the suppressed finding
+	Vulnerability Vulnerability `json:"vulnerability" yaml:"vulnerability"`
+
+	// Suppression is the exception that matched it
+	Suppression Suppression `json:"suppression" yaml:"suppression"`
+}
+
+// Matches reports whether the suppression applies to v at now. Expired suppressions match nothing.
+func (s Suppression) Matches(v Vulnerability, now time.Time) bool {
+	if !s.Until.IsZero() && now.After(s.Until) {
+		return false
+	}
+	if !strings.EqualFold(strings.TrimSpace(s.ID), v.ID) {
+		return false
+	}
+	return s.PackageName == "" || s.PackageName == v.PackageName
+}
+
+// Suppress splits vulns into the findings to report and those suppressed at now,
+// each by the first suppression matching it.
+func Suppress(vulns []Vulnerability, suppressions []Suppression, now time.Time) ([]Vulnerability, []SuppressedFinding) {
+	if len(suppressions) == 0 {
+		return vulns, nil
+	}
+	kept := make([]Vulnerability, 0, len(vulns))
+	var suppressed []SuppressedFinding
+	for _, v := range vulns {
+		matched := false
+		for _, s := range suppressions {
+			if s.Matches(v, now) {
+				suppressed = append(suppressed, SuppressedFinding{Vulnerability: v, Suppression: s})
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			kept = append(kept, v)
+		}
+	}
+	return kept, suppressed
+}
+
+// ReadSuppressions reads a suppressions file: a JSON array of objects with an "id" and optionally
+// "packageName", "reason", "by" and "until" (a date such as 2024-12-31, or an RFC 3339 time).
+// Each suppression gets source as its Source. A date-only "until" lasts until the end of that day (UTC).
+func ReadSuppressions(r io.Reader, source string) ([]Suppression, error) {
+	var entries []struct {
+		ID          string `json:"id"`
+		PackageName string `json:"packageName"`
+		Reason      string `json:"reason"`
+		By          string `json:"by"`
+		Until       string `json:"until"`
+	}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&entries); err != nil {
+		return nil, fmt.Errorf("invalid suppressions: %w", err)
+	}
+
+	var errs []error
+	suppressions := make([]Suppression, 0, len(entries))
+	for i, e := range entries {
+		if strings.TrimSpace(e.ID) == "" {
+			errs = append(errs, fmt.Errorf("suppression %d: id is required", i))
+			continue
+		}
+		until, err := parseUntil(e.Until)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("suppression %d (%s): %w", i, e.ID, err))
+			continue
+		}
+		suppressions = append(suppressions, Suppression{
+			ID:          strings.TrimSpace(e.ID),
+			PackageName: e.PackageName,
+			Reason:      e.Reason,
+			By:          e.By,
+			Until:       until,
+			Source:      source,
+		})
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return suppressions, nil
+}
+
+// parseUntil parses an expiry given as a date (lasting the whole day, UTC) or an RFC 3339 time.
+func parseUntil(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.Parse(time.DateOnly, s); err == nil {
+		return d.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid until %q: want a date (2006-01-02) or an RFC 3339 time", s)
+	}
+	return t, nil
+}
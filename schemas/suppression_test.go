@@ -0,0 +1,110 @@
+package schemas_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+func TestSuppress(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	openssl := schemas.Vulnerability{ID: "CVE-1", PackageName: "openssl"}
+	zlib := schemas.Vulnerability{ID: "CVE-1", PackageName: "zlib"}
+	glibc := schemas.Vulnerability{ID: "CVE-2", PackageName: "glibc"}
+
+	tests := map[string]struct {
+		suppressions   []schemas.Suppression
+		wantKept       []schemas.Vulnerability
+		wantSuppressed []schemas.SuppressedFinding
+	}{
+		"should keep everything without suppressions": {
+			wantKept: []schemas.Vulnerability{openssl, zlib, glibc},
+		},
+		"should suppress an ID case-insensitively in every package": {
+			suppressions: []schemas.Suppression{{ID: "cve-1", Reason: "accepted"}},
+			wantKept:     []schemas.Vulnerability{glibc},
+			wantSuppressed: []schemas.SuppressedFinding{
+				{Vulnerability: openssl, Suppression: schemas.Suppression{ID: "cve-1", Reason: "accepted"}},
+				{Vulnerability: zlib, Suppression: schemas.Suppression{ID: "cve-1", Reason: "accepted"}},
+			},
+		},
+		"should limit a suppression to its package": {
+			suppressions: []schemas.Suppression{{ID: "CVE-1", PackageName: "zlib"}},
+			wantKept:     []schemas.Vulnerability{openssl, glibc},
+			wantSuppressed: []schemas.SuppressedFinding{
+				{Vulnerability: zlib, Suppression: schemas.Suppression{ID: "CVE-1", PackageName: "zlib"}},
+			},
+		},
+		"should ignore expired suppressions": {
+			suppressions: []schemas.Suppression{{ID: "CVE-2", Until: now.Add(-time.Second)}},
+			wantKept:     []schemas.Vulnerability{openssl, zlib, glibc},
+		},
+		"should use the first matching suppression": {
+			suppressions: []schemas.Suppression{{ID: "CVE-2", Source: "skip-cve"}, {ID: "CVE-2", Reason: "later"}},
+			wantKept:     []schemas.Vulnerability{openssl, zlib},
+			wantSuppressed: []schemas.SuppressedFinding{
+				{Vulnerability: glibc, Suppression: schemas.Suppression{ID: "CVE-2", Source: "skip-cve"}},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			kept, suppressed := schemas.Suppress([]schemas.Vulnerability{openssl, zlib, glibc}, tt.suppressions, now)
+			if diff := cmp.Diff(tt.wantKept, kept); diff != "" {
+				t.Errorf("kept mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantSuppressed, suppressed); diff != "" {
+				t.Errorf("suppressed mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadSuppressions(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    []schemas.Suppression
+		wantErr bool
+	}{
+		"should read suppressions with dates and times": {
+			input: `[
+				{"id": "CVE-1", "reason": "not reachable", "by": "alice", "until": "2024-06-30"},
+				{"id": "CVE-2", "packageName": "zlib", "until": "2024-06-30T12:00:00Z"},
+				{"id": "CVE-3"}
+			]`,
+			want: []schemas.Suppression{
+				{ID: "CVE-1", Reason: "not reachable", By: "alice", Until: time.Date(2024, 6, 30, 23, 59, 59, 999999999, time.UTC), Source: "ignore.json"},
+				{ID: "CVE-2", PackageName: "zlib", Until: time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC), Source: "ignore.json"},
+				{ID: "CVE-3", Source: "ignore.json"},
+			},
+		},
+		"should require an id": {
+			input:   `[{"reason": "no id"}]`,
+			wantErr: true,
+		},
+		"should reject invalid expiries": {
+			input:   `[{"id": "CVE-1", "until": "next week"}]`,
+			wantErr: true,
+		},
+		"should reject unknown fields": {
+			input:   `[{"id": "CVE-1", "expires": "2024-06-30"}]`,
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := schemas.ReadSuppressions(strings.NewReader(tt.input), "ignore.json")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadSuppressions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ReadSuppressions() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -1,5 +1,13 @@
 package schemas
 
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
 // ============================================================================
 // Core Domain Types
 // ============================================================================
@@ -16,6 +24,76 @@ const (
 	SeverityCritical    Severity = "CRITICAL"
 )
 
+// ParseSeverity parses a severity level case-insensitively (e.g., "high" or "HIGH").
+func ParseSeverity(s string) (Severity, error) {
+	normalized := Severity(strings.ToUpper(strings.TrimSpace(s)))
+	switch normalized {
+	case SeverityUnspecified, SeverityMinimal, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical:
+		return normalized, nil
+	default:
+		return "", fmt.Errorf("invalid severity level: %s (allowed: MINIMAL, LOW, MEDIUM, HIGH, CRITICAL)", s)
+	}
+}
+
+// String implements the flag.Value and fmt.Stringer interfaces.
+func (s Severity) String() string {
+	return string(s)
+}
+
+// Set implements the flag.Value interface.
+func (s *Severity) Set(value string) error {
+	parsed, err := ParseSeverity(value)
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// An empty value decodes to SeverityUnspecified.
+func (s *Severity) UnmarshalText(text []byte) error {
+	if len(strings.TrimSpace(string(text))) == 0 {
+		*s = SeverityUnspecified
+		return nil
+	}
+	return s.Set(string(text))
+}
+
+// Level returns the rank of the severity, from 0 (UNSPECIFIED or unknown) to 5 (CRITICAL).
+func (s Severity) Level() int {
+	switch s {
+	case SeverityMinimal:
+		return 1
+	case SeverityLow:
+		return 2
+	case SeverityMedium:
+		return 3
+	case SeverityHigh:
+		return 4
+	case SeverityCritical:
+		return 5
+	default:
+		return 0
+	}
+}
+
+// AtLeast reports whether s is as severe as or more severe than other.
+func (s Severity) AtLeast(other Severity) bool {
+	return s.Level() >= other.Level()
+}
+
+// CompareSeverity returns -1 if a is less severe than b, 1 if it is more severe, and 0 otherwise.
+// It can be used with slices.SortFunc.
+func CompareSeverity(a, b Severity) int {
+	return cmp.Compare(a.Level(), b.Level())
+}
+
 // Vulnerability represents a single vulnerability finding
 type Vulnerability struct {
 	// ID is the CVE identifier
@@ -27,12 +105,22 @@ type Vulnerability struct {
 	// PackageName is the affected package
 	PackageName string `json:"packageName" yaml:"packageName"`
 
+	// PURL is the Package URL of the affected package (e.g., "pkg:deb/debian/openssl@1.1.1n-0+deb11u3")
+	PURL string `json:"purl,omitempty" yaml:"purl,omitempty"`
+
 	// InstalledVersion is the currently installed version
 	InstalledVersion string `json:"installedVersion" yaml:"installedVersion"`
 
 	// FixedVersion is the version that fixes the vulnerability (if available)
 	FixedVersion string `json:"fixedVersion,omitempty" yaml:"fixedVersion,omitempty"`
 
+	// FixState tells whether a fix is available; use it rather than checking FixedVersion
+	FixState FixState `json:"fixState,omitempty" yaml:"fixState,omitempty"`
+
+	// DistroStatus is the status of the vulnerability in the security tracker of the package's
+	// Linux distribution (e.g., "no-dsa", "ignored", "postponed"), if looked up
+	DistroStatus string `json:"distroStatus,omitempty" yaml:"distroStatus,omitempty"`
+
 	// PackageType indicates the type/category of the vulnerability
 	PackageType string `json:"packageType" yaml:"packageType"`
 
@@ -42,8 +130,128 @@ type Vulnerability struct {
 	// CVSSScore is the CVSS score
 	CVSSScore float32 `json:"cvssScore" yaml:"cvssScore"`
 
-	// URLs contains reference links
-	URLs []string `json:"urls,omitempty" yaml:"urls,omitempty"`
+	// CVSSVersion is the major CVSS version the score is based on ("2" or "3")
+	CVSSVersion string `json:"cvssVersion,omitempty" yaml:"cvssVersion,omitempty"`
+
+	// CVSSVector is the CVSS vector string (e.g., "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H")
+	CVSSVector string `json:"cvssVector,omitempty" yaml:"cvssVector,omitempty"`
+
+	// CVSS contains the CVSS sub-scores, if available
+	CVSS *CVSSDetails `json:"cvss,omitempty" yaml:"cvss,omitempty"`
+
+	// Source is the engine that reported the vulnerability (e.g., "container-analysis", "trivy", "osv")
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+
+	// CWEs lists the weakness classes of the vulnerability (e.g., "CWE-79")
+	CWEs []string `json:"cwes,omitempty" yaml:"cwes,omitempty"`
+
+	// References contains typed reference links
+	References []Reference `json:"references,omitempty" yaml:"references,omitempty"`
+
+	// DependencyPath is the chain of language packages from the direct dependency of the image's
+	// application to the affected package, if known from an SBOM (e.g., ["github.com/foo/client@v1.2.0", "golang.org/x/net@v0.17.0"])
+	DependencyPath []string `json:"dependencyPath,omitempty" yaml:"dependencyPath,omitempty"`
+
+	// FirstSeen is when the finding was first reported for the image, if known
+	FirstSeen time.Time `json:"firstSeen,omitzero" yaml:"firstSeen,omitempty"`
+
+	// LastSeen is when the finding was last reported for the image, if tracked (see History)
+	LastSeen time.Time `json:"lastSeen,omitzero" yaml:"lastSeen,omitempty"`
+
+	// ResolvedAt is when the finding was first no longer reported, for resolved findings (see History)
+	ResolvedAt time.Time `json:"resolvedAt,omitzero" yaml:"resolvedAt,omitempty"`
+
+	// SLADue is when the finding must be fixed by, if an SLA applies (see SLAPolicy)
+	SLADue time.Time `json:"slaDue,omitzero" yaml:"slaDue,omitempty"`
+
+	// SLABreached is true when the finding is past its SLA due date
+	SLABreached bool `json:"slaBreached,omitempty" yaml:"slaBreached,omitempty"`
+}
+
+// ReferenceType classifies a reference link
+type ReferenceType string
+
+const (
+	// ReferenceTypeAdvisory is a security advisory (e.g., NVD, a distribution tracker)
+	ReferenceTypeAdvisory ReferenceType = "ADVISORY"
+	// ReferenceTypeFix points at the fix itself (e.g., a commit, patch or release)
+	ReferenceTypeFix ReferenceType = "FIX"
+	// ReferenceTypeWeb is any other web page
+	ReferenceTypeWeb ReferenceType = "WEB"
+)
+
+// Reference is a link related to a vulnerability
+type Reference struct {
+	// URL is the link target
+	URL string `json:"url" yaml:"url"`
+
+	// Type classifies the link
+	Type ReferenceType `json:"type" yaml:"type"`
+}
+
+// URLs returns the URLs of all references, in order.
+func (v Vulnerability) URLs() []string {
+	if v.References == nil {
+		return nil
+	}
+	urls := make([]string, 0, len(v.References))
+	for _, r := range v.References {
+		urls = append(urls, r.URL)
+	}
+	return urls
+}
+
+// MarshalJSON keeps the flat "urls" field of earlier outputs next to the typed "references"
+func (v Vulnerability) MarshalJSON() ([]byte, error) {
+	type Alias Vulnerability
+	return json.Marshal(&struct {
+		*Alias
+		URLs []string `json:"urls,omitempty"`
+	}{
+		Alias: (*Alias)(&v),
+		URLs:  v.URLs(),
+	})
+}
+
+// UnmarshalJSON accepts outputs with only the flat "urls" field, turning them into web references
+func (v *Vulnerability) UnmarshalJSON(data []byte) error {
+	type Alias Vulnerability
+	aux := &struct {
+		*Alias
+		URLs []string `json:"urls"`
+	}{
+		Alias: (*Alias)(v),
+	}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	if v.References == nil && aux.URLs != nil {
+		v.References = make([]Reference, 0, len(aux.URLs))
+		for _, u := range aux.URLs {
+			v.References = append(v.References, Reference{URL: u, Type: ReferenceTypeWeb})
+		}
+	}
+	return nil
+}
+
+// Known vulnerability sources
+const (
+	SourceContainerAnalysis = "container-analysis"
+	SourceTrivy             = "trivy"
+	SourceOSV               = "osv"
+)
+
+// CVSSDetails contains the CVSS base score and its sub-scores
+type CVSSDetails struct {
+	// BaseScore is the CVSS base score
+	BaseScore float32 `json:"baseScore" yaml:"baseScore"`
+
+	// ExploitabilityScore is the exploitability sub-score
+	ExploitabilityScore float32 `json:"exploitabilityScore" yaml:"exploitabilityScore"`
+
+	// ImpactScore is the impact sub-score
+	ImpactScore float32 `json:"impactScore" yaml:"impactScore"`
 }
 
 // VulnerabilitySummary provides aggregated statistics
@@ -56,4 +264,30 @@ type VulnerabilitySummary struct {
 
 	// FixableCount is the number of vulnerabilities with fixes available
 	FixableCount int `json:"fixableCount" yaml:"fixableCount"`
+
+	// CountByPackageType maps package types (e.g., "OS", "GO") to counts
+	CountByPackageType map[string]int `json:"countByPackageType" yaml:"countByPackageType"`
+
+	// CountByFixState maps fix states to counts
+	CountByFixState map[FixState]int `json:"countByFixState" yaml:"countByFixState"`
+
+	// SLABreachCount is the number of vulnerabilities past their SLA due date
+	SLABreachCount int `json:"slaBreachCount,omitempty" yaml:"slaBreachCount,omitempty"`
 }
+
+// FixState represents whether a fix is available for a vulnerability
+type FixState string
+
+const (
+	// FixStateUnknown means the data source did not report fix information
+	FixStateUnknown FixState = "UNKNOWN"
+	// FixStateFixAvailable means a fixed version of the package exists
+	FixStateFixAvailable FixState = "FIX_AVAILABLE"
+	// FixStateNoFixAvailable means no fix has been released yet
+	FixStateNoFixAvailable FixState = "NO_FIX_AVAILABLE"
+	// FixStateWontFix means the vendor does not plan to release a fix
+	FixStateWontFix FixState = "WONT_FIX"
+)
+
+// PackageTypeUnknown is the package type used in summaries for vulnerabilities without one
+const PackageTypeUnknown = "UNKNOWN"
@@ -0,0 +1,239 @@
+package schemas_test
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+func TestParseSeverity(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    schemas.Severity
+		wantErr bool
+	}{
+		"should parse MINIMAL severity": {
+			input: "MINIMAL",
+			want:  schemas.SeverityMinimal,
+		},
+		"should parse LOW severity": {
+			input: "LOW",
+			want:  schemas.SeverityLow,
+		},
+		"should parse MEDIUM severity": {
+			input: "MEDIUM",
+			want:  schemas.SeverityMedium,
+		},
+		"should parse HIGH severity": {
+			input: "HIGH",
+			want:  schemas.SeverityHigh,
+		},
+		"should parse CRITICAL severity": {
+			input: "CRITICAL",
+			want:  schemas.SeverityCritical,
+		},
+		"should parse severity case-insensitively": {
+			input: " critical ",
+			want:  schemas.SeverityCritical,
+		},
+		"should return error when severity is invalid": {
+			input:   "INVALID",
+			wantErr: true,
+		},
+		"should return error when severity is empty": {
+			input:   "",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := schemas.ParseSeverity(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSeverity() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseSeverity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeverity_FlagValue(t *testing.T) {
+	severity := schemas.SeverityHigh
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&severity, "min-severity", "")
+
+	if err := fs.Parse([]string{"--min-severity", "medium"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if severity != schemas.SeverityMedium {
+		t.Errorf("severity = %v, want %v", severity, schemas.SeverityMedium)
+	}
+
+	if err := fs.Parse([]string{"--min-severity", "urgent"}); err == nil {
+		t.Error("Parse() expected error for invalid severity")
+	}
+}
+
+func TestSeverity_JSON(t *testing.T) {
+	type config struct {
+		MinSeverity schemas.Severity         `json:"minSeverity"`
+		Counts      map[schemas.Severity]int `json:"counts"`
+	}
+
+	tests := map[string]struct {
+		input   string
+		want    config
+		wantErr bool
+	}{
+		"should decode severities case-insensitively": {
+			input: `{"minSeverity":"high","counts":{"critical":1,"LOW":2}}`,
+			want: config{
+				MinSeverity: schemas.SeverityHigh,
+				Counts:      map[schemas.Severity]int{schemas.SeverityCritical: 1, schemas.SeverityLow: 2},
+			},
+		},
+		"should decode empty severity as unspecified": {
+			input: `{"minSeverity":""}`,
+			want:  config{MinSeverity: schemas.SeverityUnspecified},
+		},
+		"should reject unknown severities": {
+			input:   `{"minSeverity":"urgent"}`,
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got config
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unmarshal() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSeverity_AtLeast(t *testing.T) {
+	tests := map[string]struct {
+		s     schemas.Severity
+		other schemas.Severity
+		want  bool
+	}{
+		"should return true when severity is higher": {
+			s:     schemas.SeverityCritical,
+			other: schemas.SeverityHigh,
+			want:  true,
+		},
+		"should return true when severities are equal": {
+			s:     schemas.SeverityMedium,
+			other: schemas.SeverityMedium,
+			want:  true,
+		},
+		"should return false when severity is lower": {
+			s:     schemas.SeverityLow,
+			other: schemas.SeverityMedium,
+			want:  false,
+		},
+		"should treat unknown severity as unspecified": {
+			s:     schemas.Severity("BOGUS"),
+			other: schemas.SeverityMinimal,
+			want:  false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.s.AtLeast(tt.other); got != tt.want {
+				t.Errorf("AtLeast() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareSeverity(t *testing.T) {
+	got := []schemas.Severity{
+		schemas.SeverityHigh,
+		schemas.SeverityUnspecified,
+		schemas.SeverityCritical,
+		schemas.SeverityLow,
+		schemas.SeverityMinimal,
+		schemas.SeverityMedium,
+	}
+	slices.SortFunc(got, schemas.CompareSeverity)
+
+	want := []schemas.Severity{
+		schemas.SeverityUnspecified,
+		schemas.SeverityMinimal,
+		schemas.SeverityLow,
+		schemas.SeverityMedium,
+		schemas.SeverityHigh,
+		schemas.SeverityCritical,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("sorted severities mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestVulnerability_JSONReferences(t *testing.T) {
+	tests := map[string]struct {
+		input    string
+		want     schemas.Vulnerability
+		wantURLs string
+	}{
+		"should decode typed references": {
+			input: `{"id":"CVE-1","references":[{"url":"https://nvd.nist.gov/x","type":"ADVISORY"}]}`,
+			want: schemas.Vulnerability{
+				ID:         "CVE-1",
+				References: []schemas.Reference{{URL: "https://nvd.nist.gov/x", Type: schemas.ReferenceTypeAdvisory}},
+			},
+			wantURLs: `"urls":["https://nvd.nist.gov/x"]`,
+		},
+		"should turn legacy urls into web references": {
+			input: `{"id":"CVE-2","urls":["https://example.com/a","https://example.com/b"]}`,
+			want: schemas.Vulnerability{
+				ID: "CVE-2",
+				References: []schemas.Reference{
+					{URL: "https://example.com/a", Type: schemas.ReferenceTypeWeb},
+					{URL: "https://example.com/b", Type: schemas.ReferenceTypeWeb},
+				},
+			},
+			wantURLs: `"urls":["https://example.com/a","https://example.com/b"]`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got schemas.Vulnerability
+			if err := json.Unmarshal([]byte(tt.input), &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unmarshal() mismatch (-want +got):\n%s", diff)
+			}
+
+			data, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if !strings.Contains(string(data), tt.wantURLs) {
+				t.Errorf("Marshal() = %s, want it to contain %s", data, tt.wantURLs)
+			}
+		})
+	}
+}
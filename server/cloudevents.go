@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/hiro-o918/drydock"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// cloudEventsSpecVersion is the only CloudEvents specification version accepted.
+	cloudEventsSpecVersion = "1.0"
+
+	// EventTypePubSubMessagePublished is the CloudEvents type Eventarc uses to deliver
+	// Pub/Sub messages, e.g. Artifact Registry notifications from the "gcr" topic.
+	EventTypePubSubMessagePublished = "google.cloud.pubsub.topic.v1.messagePublished"
+
+	// structuredContentType is the media type of CloudEvents in structured content mode.
+	structuredContentType = "application/cloudevents+json"
+
+	// maxEventSize bounds the size of accepted request bodies.
+	maxEventSize = 1 << 20
+)
+
+// Artifact Registry notification actions.
+const (
+	ArtifactActionInsert = "INSERT"
+	ArtifactActionDelete = "DELETE"
+)
+
+// ArtifactEvent is the Artifact Registry notification published to the "gcr" Pub/Sub topic.
+type ArtifactEvent struct {
+	// Action is either INSERT or DELETE
+	Action string `json:"action"`
+
+	// Digest is the full image reference with digest (e.g., us-docker.pkg.dev/p/r/img@sha256:...)
+	Digest string `json:"digest,omitempty"`
+
+	// Tag is the full image reference with tag, if the event concerns a tag
+	Tag string `json:"tag,omitempty"`
+}
+
+// ScanFunc performs a targeted scan of a single image.
+type ScanFunc func(ctx context.Context, target drydock.ImageTarget) error
+
+// CloudEventHandler receives Artifact Registry events delivered as CloudEvents over HTTP
+// (Eventarc push with a Pub/Sub trigger) and triggers a scan of the referenced digest.
+// Both binary and structured content modes are supported.
+//
+// Responses follow push delivery semantics: events that are ignored are acknowledged with
+// 204 No Content, malformed events are rejected with 400 Bad Request, and failed scans return
+// 500 Internal Server Error so that the event is redelivered.
+type CloudEventHandler struct {
+	scan ScanFunc
+}
+
+// NewCloudEventHandler creates a handler that calls scan for each pushed image.
+func NewCloudEventHandler(scan ScanFunc) *CloudEventHandler {
+	return &CloudEventHandler{scan: scan}
+}
+
+// cloudEvent holds the CloudEvents attributes drydock relies on, plus the event data.
+type cloudEvent struct {
+	SpecVersion string          `json:"specversion"`
+	ID          string          `json:"id"`
+	Source      string          `json:"source"`
+	Type        string          `json:"type"`
+	Data        json.RawMessage `json:"data,omitempty"`
+}
+
+// messagePublishedData is the data of a google.cloud.pubsub.topic.v1.messagePublished event.
+type messagePublishedData struct {
+	Message struct {
+		Data      []byte `json:"data"` // base64 in JSON
+		MessageID string `json:"messageId"`
+	} `json:"message"`
+	Subscription string `json:"subscription"`
+}
+
+// ServeHTTP implements http.Handler.
+func (h *CloudEventHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	log := zerolog.Ctx(r.Context())
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	event, err := parseCloudEvent(r)
+	if err != nil {
+		log.Warn().Err(err).Msg("Rejected invalid CloudEvent")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	target, ok, err := targetFromEvent(event)
+	if err != nil {
+		log.Warn().Err(err).Str("event_id", event.ID).Msg("Rejected invalid Artifact Registry event")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !ok {
+		log.Debug().Str("event_id", event.ID).Msg("Ignoring event that does not reference a new image")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	log.Info().Str("event_id", event.ID).Str("image", target.URI).Msg("Scanning image from event")
+	if err := h.scan(r.Context(), target); err != nil {
+		log.Error().Err(err).Str("event_id", event.ID).Str("image", target.URI).Msg("Scan triggered by event failed")
+		http.Error(w, "scan failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseCloudEvent decodes an HTTP request in binary or structured content mode and validates
+// the required CloudEvents attributes.
+func parseCloudEvent(r *http.Request) (cloudEvent, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxEventSize+1))
+	if err != nil {
+		return cloudEvent{}, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if len(body) > maxEventSize {
+		return cloudEvent{}, fmt.Errorf("event exceeds %d bytes", maxEventSize)
+	}
+
+	var event cloudEvent
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == structuredContentType {
+		if err := json.Unmarshal(body, &event); err != nil {
+			return cloudEvent{}, fmt.Errorf("invalid structured CloudEvent: %w", err)
+		}
+	} else {
+		event = cloudEvent{
+			SpecVersion: r.Header.Get("Ce-Specversion"),
+			ID:          r.Header.Get("Ce-Id"),
+			Source:      r.Header.Get("Ce-Source"),
+			Type:        r.Header.Get("Ce-Type"),
+			Data:        body,
+		}
+	}
+
+	var missing []string
+	for _, attr := range []struct{ name, value string }{
+		{"specversion", event.SpecVersion},
+		{"id", event.ID},
+		{"source", event.Source},
+		{"type", event.Type},
+	} {
+		if attr.value == "" {
+			missing = append(missing, attr.name)
+		}
+	}
+	if len(missing) > 0 {
+		return cloudEvent{}, fmt.Errorf("missing required CloudEvents attributes: %s", strings.Join(missing, ", "))
+	}
+	if event.SpecVersion != cloudEventsSpecVersion {
+		return cloudEvent{}, fmt.Errorf("unsupported CloudEvents specversion: %s", event.SpecVersion)
+	}
+	return event, nil
+}
+
+// targetFromEvent extracts the image to scan. It reports false when the event is valid
+// but does not call for a scan (e.g., a DELETE action).
+func targetFromEvent(event cloudEvent) (drydock.ImageTarget, bool, error) {
+	if event.Type != EventTypePubSubMessagePublished {
+		return drydock.ImageTarget{}, false, fmt.Errorf("unsupported event type: %s", event.Type)
+	}
+
+	var data messagePublishedData
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return drydock.ImageTarget{}, false, fmt.Errorf("invalid Pub/Sub message data: %w", err)
+	}
+
+	var artifactEvent ArtifactEvent
+	if err := json.Unmarshal(data.Message.Data, &artifactEvent); err != nil {
+		return drydock.ImageTarget{}, false, fmt.Errorf("invalid Artifact Registry notification: %w", err)
+	}
+
+	if artifactEvent.Action != ArtifactActionInsert {
+		return drydock.ImageTarget{}, false, nil
+	}
+	if artifactEvent.Digest == "" {
+		return drydock.ImageTarget{}, false, errors.New("artifact registry notification has no digest")
+	}
+
+	ref, err := drydock.ParseArtifactURI(artifactEvent.Digest)
+	if err != nil {
+		return drydock.ImageTarget{}, false, err
+	}
+	if ref.Digest == nil {
+		return drydock.ImageTarget{}, false, fmt.Errorf("image reference has no digest: %s", artifactEvent.Digest)
+	}
+
+	return drydock.ImageTarget{
+		Artifact: ref,
+		URI:      artifactEvent.Digest,
+		Location: strings.TrimSuffix(ref.Host, "-docker.pkg.dev"),
+	}, true, nil
+}
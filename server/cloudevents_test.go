@@ -0,0 +1,131 @@
+package server_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/server"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestCloudEventHandler_ServeHTTP(t *testing.T) {
+	const digest = "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	const imageURI = "us-east1-docker.pkg.dev/my-project/my-repo/app@" + digest
+
+	pubsubData := func(notification string) string {
+		encoded := base64.StdEncoding.EncodeToString([]byte(notification))
+		return `{"message":{"data":"` + encoded + `","messageId":"1"},"subscription":"projects/p/subscriptions/s"}`
+	}
+	binaryHeaders := map[string]string{
+		"Content-Type":   "application/json",
+		"Ce-Specversion": "1.0",
+		"Ce-Id":          "event-1",
+		"Ce-Source":      "//pubsub.googleapis.com/projects/my-project/topics/gcr",
+		"Ce-Type":        server.EventTypePubSubMessagePublished,
+	}
+	wantTarget := drydock.ImageTarget{
+		Artifact: schemas.ArtifactReference{
+			Host:         "us-east1-docker.pkg.dev",
+			ProjectID:    "my-project",
+			RepositoryID: "my-repo",
+			ImageName:    "app",
+			Digest:       utils.Ptr(digest),
+		},
+		URI:      imageURI,
+		Location: "us-east1",
+	}
+
+	tests := map[string]struct {
+		method     string
+		headers    map[string]string
+		body       string
+		scanErr    error
+		wantStatus int
+		wantTarget *drydock.ImageTarget
+	}{
+		"should scan the pushed digest when a binary-mode event is received": {
+			headers:    binaryHeaders,
+			body:       pubsubData(`{"action":"INSERT","digest":"` + imageURI + `"}`),
+			wantStatus: http.StatusNoContent,
+			wantTarget: &wantTarget,
+		},
+		"should scan the pushed digest when a structured-mode event is received": {
+			headers: map[string]string{"Content-Type": "application/cloudevents+json; charset=utf-8"},
+			body: `{"specversion":"1.0","id":"event-1","source":"//pubsub.googleapis.com/projects/my-project/topics/gcr",` +
+				`"type":"` + server.EventTypePubSubMessagePublished + `","data":` +
+				pubsubData(`{"action":"INSERT","digest":"`+imageURI+`","tag":"us-east1-docker.pkg.dev/my-project/my-repo/app:v1"}`) + `}`,
+			wantStatus: http.StatusNoContent,
+			wantTarget: &wantTarget,
+		},
+		"should acknowledge without scanning when the image was deleted": {
+			headers:    binaryHeaders,
+			body:       pubsubData(`{"action":"DELETE","digest":"` + imageURI + `"}`),
+			wantStatus: http.StatusNoContent,
+		},
+		"should reject events missing required attributes": {
+			headers:    map[string]string{"Content-Type": "application/json", "Ce-Specversion": "1.0"},
+			body:       pubsubData(`{"action":"INSERT","digest":"` + imageURI + `"}`),
+			wantStatus: http.StatusBadRequest,
+		},
+		"should reject unsupported event types": {
+			headers: map[string]string{
+				"Ce-Specversion": "1.0", "Ce-Id": "1", "Ce-Source": "s", "Ce-Type": "com.example.other",
+			},
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		"should reject notifications without a digest": {
+			headers:    binaryHeaders,
+			body:       pubsubData(`{"action":"INSERT","tag":"us-east1-docker.pkg.dev/my-project/my-repo/app:v1"}`),
+			wantStatus: http.StatusBadRequest,
+		},
+		"should return server error so the event is redelivered when the scan fails": {
+			headers:    binaryHeaders,
+			body:       pubsubData(`{"action":"INSERT","digest":"` + imageURI + `"}`),
+			scanErr:    errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantTarget: &wantTarget,
+		},
+		"should reject non-POST requests": {
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got *drydock.ImageTarget
+			handler := server.NewCloudEventHandler(func(ctx context.Context, target drydock.ImageTarget) error {
+				got = &target
+				return tt.scanErr
+			})
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "/", strings.NewReader(tt.body))
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if diff := cmp.Diff(tt.wantTarget, got); diff != "" {
+				t.Errorf("scanned target mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
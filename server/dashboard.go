@@ -0,0 +1,363 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/exporter"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/rs/zerolog"
+)
+
+// DefaultTrendDays is the number of days charted by the dashboard.
+const DefaultTrendDays = 30
+
+// dashboardSeverities are the severities shown by the dashboard, most severe first.
+var dashboardSeverities = []schemas.Severity{
+	schemas.SeverityCritical,
+	schemas.SeverityHigh,
+	schemas.SeverityMedium,
+	schemas.SeverityLow,
+	schemas.SeverityMinimal,
+	schemas.SeverityUnspecified,
+}
+
+//go:embed dashboard.html
+var dashboardHTML string
+
+var dashboardTemplate = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"lower": func(s schemas.Severity) string { return strings.ToLower(string(s)) },
+	"date":  func(t time.Time) string { return t.Format(time.DateOnly) },
+	"time": func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.UTC().Format("2006-01-02 15:04 UTC")
+	},
+}).Parse(dashboardHTML))
+
+// ImageStatus is the latest scan of an image known to the dashboard.
+type ImageStatus struct {
+	// Image is the image reference, without tag and digest
+	Image string `json:"image"`
+
+	// Result is the latest successful scan of the image, if any
+	Result *schemas.AnalyzeResult `json:"result,omitempty"`
+
+	// Error is the error of the latest scan, if it failed
+	Error string `json:"error,omitempty"`
+
+	// UpdatedAt is when the latest scan completed or failed
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Dashboard is a web UI of the scans of a long-running server: the latest scan status of each
+// image with its findings, and the trend of open findings from the history store.
+// Their findings are also served as an Atom feed at feed.atom.
+//
+// Dashboard is also a drydock.Exporter, so that it sees the results as the scanner exports them.
+// Scans are kept in memory, so the dashboard starts empty on each start of the server.
+type Dashboard struct {
+	history drydock.HistoryStore
+	mux     *http.ServeMux
+
+	mu     sync.RWMutex
+	images map[string]*ImageStatus
+}
+
+var (
+	_ drydock.Exporter = (*Dashboard)(nil)
+	_ http.Handler     = (*Dashboard)(nil)
+)
+
+// NewDashboard creates a dashboard charting the trend of the findings in history.
+// history may be nil, in which case no trend is shown.
+func NewDashboard(history drydock.HistoryStore) *Dashboard {
+	d := &Dashboard{
+		history: history,
+		mux:     http.NewServeMux(),
+		images:  make(map[string]*ImageStatus),
+	}
+	d.mux.HandleFunc("GET /{$}", d.serveIndex)
+	d.mux.HandleFunc("GET /image", d.serveImage)
+	d.mux.HandleFunc("GET /api/images", d.serveImagesAPI)
+	d.mux.HandleFunc("GET /api/trend", d.serveTrendAPI)
+	d.mux.HandleFunc("GET /feed.atom", d.serveFeed)
+	return d
+}
+
+// Export records the results as the latest scans of their images.
+func (d *Dashboard) Export(_ context.Context, results []schemas.AnalyzeResult) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, r := range results {
+		status := d.status(r.Artifact)
+		status.Result = &r
+		status.Error = ""
+		status.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// RecordFailure records that the latest scan of the image failed. The previous result, if any, is kept.
+func (d *Dashboard) RecordFailure(image schemas.ArtifactReference, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	status := d.status(image)
+	status.Error = err.Error()
+	status.UpdatedAt = time.Now()
+}
+
+// Images returns the latest scan of each image, worst first.
+func (d *Dashboard) Images() []ImageStatus {
+	d.mu.RLock()
+	images := make([]ImageStatus, 0, len(d.images))
+	for _, status := range d.images {
+		images = append(images, *status)
+	}
+	d.mu.RUnlock()
+
+	slices.SortFunc(images, func(a, b ImageStatus) int {
+		for _, severity := range dashboardSeverities {
+			if c := cmp.Compare(countOf(b, severity), countOf(a, severity)); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(a.Image, b.Image)
+	})
+	return images
+}
+
+// status returns the status of the image, creating it if needed. d.mu must be held.
+func (d *Dashboard) status(image schemas.ArtifactReference) *ImageStatus {
+	name := imageName(image)
+	status, ok := d.images[name]
+	if !ok {
+		status = &ImageStatus{Image: name}
+		d.images[name] = status
+	}
+	return status
+}
+
+// ServeHTTP implements http.Handler. The dashboard links are relative, so that it can be
+// mounted under a prefix with http.StripPrefix (e.g. "/dashboard/").
+func (d *Dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mux.ServeHTTP(w, r)
+}
+
+// severityCount is a bar of a severity chart.
+type severityCount struct {
+	Severity schemas.Severity
+	Count    int
+	Percent  float64
+}
+
+// trendLine is the polyline of a severity in the trend chart.
+type trendLine struct {
+	Severity schemas.Severity
+	Points   string
+	Last     int
+}
+
+// trendChart is the SVG chart of open findings over time.
+type trendChart struct {
+	Width, Height int
+	Max           int
+	From, Until   time.Time
+	Lines         []trendLine
+}
+
+// indexPage is the data of the dashboard overview.
+type indexPage struct {
+	Images []imageRow
+	Totals []severityCount
+	Failed int
+	Trend  *trendChart
+}
+
+// imageRow is an image of the overview.
+type imageRow struct {
+	ImageStatus
+	Counts []severityCount
+}
+
+func (d *Dashboard) serveIndex(w http.ResponseWriter, r *http.Request) {
+	page := indexPage{}
+	totals := make(map[schemas.Severity]int)
+	for _, status := range d.Images() {
+		if status.Error != "" {
+			page.Failed++
+		}
+		row := imageRow{ImageStatus: status}
+		for _, severity := range dashboardSeverities {
+			if n := countOf(status, severity); n > 0 {
+				row.Counts = append(row.Counts, severityCount{Severity: severity, Count: n})
+				totals[severity] += n
+			}
+		}
+		page.Images = append(page.Images, row)
+	}
+	page.Totals = severityBars(totals)
+
+	if d.history != nil {
+		history, err := d.history.Load(r.Context())
+		if err != nil {
+			zerolog.Ctx(r.Context()).Warn().Err(err).Msg("Failed to load history for the dashboard")
+		} else {
+			page.Trend = newTrendChart(history.Trend(time.Now(), DefaultTrendDays))
+		}
+	}
+	d.render(w, r, "index", page)
+}
+
+// imagePage is the data of the findings of an image.
+type imagePage struct {
+	ImageStatus
+	Totals          []severityCount
+	Vulnerabilities []schemas.Vulnerability
+}
+
+func (d *Dashboard) serveImage(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	d.mu.RLock()
+	status, ok := d.images[name]
+	var page imagePage
+	if ok {
+		page.ImageStatus = *status
+	}
+	d.mu.RUnlock()
+	if !ok {
+		http.Error(w, fmt.Sprintf("image not scanned yet: %s", name), http.StatusNotFound)
+		return
+	}
+
+	if page.Result != nil {
+		page.Vulnerabilities = slices.Clone(page.Result.Vulnerabilities)
+		slices.SortStableFunc(page.Vulnerabilities, func(a, b schemas.Vulnerability) int {
+			return cmp.Or(schemas.CompareSeverity(b.Severity, a.Severity), cmp.Compare(a.ID, b.ID))
+		})
+		page.Totals = severityBars(page.Result.Summary.CountBySeverity)
+	}
+	d.render(w, r, "image", page)
+}
+
+func (d *Dashboard) serveImagesAPI(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, r, d.Images())
+}
+
+func (d *Dashboard) serveTrendAPI(w http.ResponseWriter, r *http.Request) {
+	if d.history == nil {
+		http.Error(w, "no history store configured", http.StatusNotFound)
+		return
+	}
+	history, err := d.history.Load(r.Context())
+	if err != nil {
+		zerolog.Ctx(r.Context()).Error().Err(err).Msg("Failed to load history for the dashboard")
+		http.Error(w, "failed to load history", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, r, history.Trend(time.Now(), DefaultTrendDays))
+}
+
+// serveFeed serves the findings of the latest scans as an Atom feed, for feed readers.
+func (d *Dashboard) serveFeed(w http.ResponseWriter, r *http.Request) {
+	var results []schemas.AnalyzeResult
+	for _, status := range d.Images() {
+		if status.Result != nil {
+			results = append(results, *status.Result)
+		}
+	}
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	if err := exporter.NewAtomExporter(w).Export(r.Context(), results); err != nil {
+		zerolog.Ctx(r.Context()).Error().Err(err).Msg("Failed to write dashboard feed")
+	}
+}
+
+func (d *Dashboard) render(w http.ResponseWriter, r *http.Request, name string, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := dashboardTemplate.ExecuteTemplate(w, name, data); err != nil {
+		zerolog.Ctx(r.Context()).Error().Err(err).Str("page", name).Msg("Failed to render dashboard")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		zerolog.Ctx(r.Context()).Error().Err(err).Msg("Failed to write dashboard response")
+	}
+}
+
+// imageName returns the image of a reference, without tag and digest.
+func imageName(a schemas.ArtifactReference) string {
+	a.Tag, a.Digest = nil, nil
+	return a.String()
+}
+
+// countOf returns the number of findings of the severity in the latest result of the image.
+func countOf(status ImageStatus, severity schemas.Severity) int {
+	if status.Result == nil {
+		return 0
+	}
+	return status.Result.Summary.CountBySeverity[severity]
+}
+
+// severityBars returns the bars of the severities with findings, most severe first.
+func severityBars(counts map[schemas.Severity]int) []severityCount {
+	peak := 0
+	for _, n := range counts {
+		peak = max(peak, n)
+	}
+	var bars []severityCount
+	for _, severity := range dashboardSeverities {
+		if n := counts[severity]; n > 0 {
+			bars = append(bars, severityCount{Severity: severity, Count: n, Percent: 100 * float64(n) / float64(peak)})
+		}
+	}
+	return bars
+}
+
+// newTrendChart lays out the trend of open findings as one line per severity.
+func newTrendChart(points []schemas.TrendPoint) *trendChart {
+	if len(points) == 0 {
+		return nil
+	}
+	chart := &trendChart{Width: 600, Height: 160, From: points[0].Date, Until: points[len(points)-1].Date}
+	for _, p := range points {
+		for _, n := range p.CountBySeverity {
+			chart.Max = max(chart.Max, n)
+		}
+	}
+	step := float64(chart.Width)
+	if len(points) > 1 {
+		step /= float64(len(points) - 1)
+	}
+	for _, severity := range dashboardSeverities {
+		if !slices.ContainsFunc(points, func(p schemas.TrendPoint) bool { return p.CountBySeverity[severity] > 0 }) {
+			continue
+		}
+		coords := make([]string, len(points))
+		for i, p := range points {
+			y := float64(chart.Height)
+			if chart.Max > 0 {
+				y -= float64(chart.Height) * float64(p.CountBySeverity[severity]) / float64(chart.Max)
+			}
+			coords[i] = fmt.Sprintf("%.1f,%.1f", float64(i)*step, y)
+		}
+		chart.Lines = append(chart.Lines, trendLine{
+			Severity: severity,
+			Points:   strings.Join(coords, " "),
+			Last:     points[len(points)-1].CountBySeverity[severity],
+		})
+	}
+	return chart
+}
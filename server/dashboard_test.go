@@ -0,0 +1,156 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+	"github.com/hiro-o918/drydock/server"
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestDashboard(t *testing.T) {
+	ctx := context.Background()
+	artifact := func(image string) schemas.ArtifactReference {
+		return schemas.ArtifactReference{
+			Host: "us-docker.pkg.dev", ProjectID: "p", RepositoryID: "r", ImageName: image,
+			Digest: utils.Ptr("sha256:" + image),
+		}
+	}
+	result := func(image string, vulns ...schemas.Vulnerability) schemas.AnalyzeResult {
+		summary := schemas.VulnerabilitySummary{TotalCount: len(vulns), CountBySeverity: map[schemas.Severity]int{}}
+		for _, v := range vulns {
+			summary.CountBySeverity[v.Severity]++
+		}
+		return schemas.AnalyzeResult{Artifact: artifact(image), Vulnerabilities: vulns, Summary: summary}
+	}
+	openssl := schemas.Vulnerability{ID: "CVE-2024-0001", PackageName: "openssl", Severity: schemas.SeverityHigh, FixedVersion: "3.0.14"}
+	glibc := schemas.Vulnerability{ID: "CVE-2024-0002", PackageName: "glibc", Severity: schemas.SeverityCritical}
+
+	store := drydock.NewFileHistoryStore(filepath.Join(t.TempDir(), "history.json"))
+	history := &schemas.History{}
+	observed := result("api", openssl)
+	history.Observe(&observed, time.Now())
+	if err := store.Save(ctx, history); err != nil {
+		t.Fatal(err)
+	}
+
+	dashboard := server.NewDashboard(store)
+	if err := dashboard.Export(ctx, []schemas.AnalyzeResult{result("web"), result("api", openssl), result("worker", glibc)}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	dashboard.RecordFailure(artifact("web"), errors.New("quota exceeded"))
+
+	srv := httptest.NewServer(http.StripPrefix("/dashboard", dashboard))
+	defer srv.Close()
+
+	get := func(t *testing.T, path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(body)
+	}
+
+	t.Run("should list images worst first", func(t *testing.T) {
+		var got []string
+		for _, status := range dashboard.Images() {
+			got = append(got, status.Image)
+		}
+		want := []string{"us-docker.pkg.dev/p/r/worker", "us-docker.pkg.dev/p/r/api", "us-docker.pkg.dev/p/r/web"}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Images() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	tests := map[string]struct {
+		path         string
+		wantStatus   int
+		wantContains []string
+	}{
+		"should show the overview with failures and trends": {
+			path:       "/dashboard/",
+			wantStatus: http.StatusOK,
+			wantContains: []string{
+				`<a href="image?name=us-docker.pkg.dev%2fp%2fr%2fapi">us-docker.pkg.dev/p/r/api</a>`,
+				`<span class="error">1 failed</span>`,
+				"<polyline class=\"high\"",
+			},
+		},
+		"should show the findings of an image": {
+			path:         "/dashboard/image?name=us-docker.pkg.dev/p/r/api",
+			wantStatus:   http.StatusOK,
+			wantContains: []string{"CVE-2024-0001", "openssl", "3.0.14"},
+		},
+		"should show the error of a failed scan": {
+			path:         "/dashboard/image?name=us-docker.pkg.dev/p/r/web",
+			wantStatus:   http.StatusOK,
+			wantContains: []string{"quota exceeded"},
+		},
+		"should return not found for unknown images": {
+			path:       "/dashboard/image?name=us-docker.pkg.dev/p/r/unknown",
+			wantStatus: http.StatusNotFound,
+		},
+		"should serve the findings as an Atom feed": {
+			path:         "/dashboard/feed.atom",
+			wantStatus:   http.StatusOK,
+			wantContains: []string{"<title>HIGH CVE-2024-0001 in openssl (us-docker.pkg.dev/p/r/api)</title>", "CVE-2024-0002"},
+		},
+		"should return the trend as JSON": {
+			path:         "/dashboard/api/trend",
+			wantStatus:   http.StatusOK,
+			wantContains: []string{`"countBySeverity":{"HIGH":1}`},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			status, body := get(t, tt.path)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", status, tt.wantStatus, body)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(body, want) {
+					t.Errorf("body does not contain %q:\n%s", want, body)
+				}
+			}
+		})
+	}
+
+	t.Run("should return the images as JSON", func(t *testing.T) {
+		status, body := get(t, "/dashboard/api/images")
+		if status != http.StatusOK {
+			t.Fatalf("status = %d, want %d", status, http.StatusOK)
+		}
+		var got []server.ImageStatus
+		if err := json.Unmarshal([]byte(body), &got); err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 3 || got[2].Error != "quota exceeded" || got[2].Result == nil {
+			t.Errorf("unexpected images: %s", body)
+		}
+	})
+
+	t.Run("should not serve trends without a history store", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		server.NewDashboard(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/trend", nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package drydock
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// resultSpill keeps results in a temporary file, as JSON lines, so that a scan holds at most a
+// bounded number of them in memory until they are exported (see WithResultSpill).
+type resultSpill struct {
+	file  *os.File
+	w     *bufio.Writer
+	count int
+}
+
+// newResultSpill creates the temporary file of a spill in dir, or in the default directory of
+// temporary files if dir is empty.
+func newResultSpill(dir string) (*resultSpill, error) {
+	file, err := os.CreateTemp(dir, "drydock-results-*.jsonl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spill file: %w", err)
+	}
+	return &resultSpill{file: file, w: bufio.NewWriter(file)}, nil
+}
+
+// write appends the results to the file.
+func (s *resultSpill) write(results []schemas.AnalyzeResult) error {
+	enc := json.NewEncoder(s.w)
+	for _, r := range results {
+		if err := enc.Encode(r); err != nil {
+			return fmt.Errorf("failed to spill result of %s: %w", r.Artifact, err)
+		}
+		s.count++
+	}
+	return nil
+}
+
+// each reads the spilled results back, in the order they were written.
+func (s *resultSpill) each(fn func(schemas.AnalyzeResult) error) error {
+	if err := s.w.Flush(); err != nil {
+		return fmt.Errorf("failed to write spill file: %w", err)
+	}
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read spill file: %w", err)
+	}
+	dec := json.NewDecoder(bufio.NewReader(s.file))
+	for {
+		var r schemas.AnalyzeResult
+		if err := dec.Decode(&r); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to read spill file: %w", err)
+		}
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+}
+
+// Close removes the file.
+func (s *resultSpill) Close() error {
+	return errors.Join(s.file.Close(), os.Remove(s.file.Name()))
+}
@@ -0,0 +1,96 @@
+package drydock
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+// worstImagesInSummary is the number of images listed in ScanSummary.Worst.
+const worstImagesInSummary = 5
+
+// ScanSummary sums up a scan, so that operators know how it went without reading the report.
+// It covers every analyzed image, including those left out of the report by an exporter.
+type ScanSummary struct {
+	// Images is the number of images analyzed
+	Images int
+
+	// Failed is the number of images that could not be resolved or analyzed
+	Failed int
+
+	// NotAnalyzed is the number of images without a finished analysis by their scanner (see schemas.ScanStatus),
+	// whose findings may be missing
+	NotAnalyzed int
+
+	// Vulnerabilities is the number of findings of all images
+	Vulnerabilities int
+
+	// CountBySeverity counts the findings of all images by severity
+	CountBySeverity map[schemas.Severity]int
+
+	// Fixable is the number of findings with a fix available
+	Fixable int
+
+	// Duration is how long the scan took, export included
+	Duration time.Duration
+
+	// Worst lists the images with the most severe findings, worst first (at most 5)
+	Worst []ImageSummary
+
+	// Interrupted is true when the scan stopped before all images were analyzed
+	Interrupted bool
+}
+
+// ImageSummary is the summary of the findings of one image.
+type ImageSummary struct {
+	// Image is the image reference
+	Image string
+
+	// Summary is the summary of the findings of the image
+	Summary schemas.VulnerabilitySummary
+}
+
+// SummaryFunc receives the summary at the end of a scan (see WithSummary).
+type SummaryFunc func(ScanSummary)
+
+// add counts the findings of res in the summary.
+func (s *ScanSummary) add(res *schemas.AnalyzeResult) {
+	if s.CountBySeverity == nil {
+		s.CountBySeverity = make(map[schemas.Severity]int)
+	}
+	s.Images++
+	if res.ScanStatus != "" && res.ScanStatus != schemas.ScanStatusFinished {
+		s.NotAnalyzed++
+	}
+	s.Vulnerabilities += res.Summary.TotalCount
+	s.Fixable += res.Summary.FixableCount
+	for severity, n := range res.Summary.CountBySeverity {
+		s.CountBySeverity[severity] += n
+	}
+
+	if res.Summary.TotalCount == 0 {
+		return
+	}
+	image := ImageSummary{Image: res.Artifact.String(), Summary: res.Summary}
+	i, _ := slices.BinarySearchFunc(s.Worst, image, compareWorst)
+	if i < worstImagesInSummary {
+		s.Worst = slices.Insert(s.Worst, i, image)
+		s.Worst = s.Worst[:min(len(s.Worst), worstImagesInSummary)]
+	}
+}
+
+// compareWorst orders images by their number of critical findings, then of high ones, and so on,
+// from the worst; ties are broken by image reference.
+func compareWorst(a, b ImageSummary) int {
+	for _, severity := range []schemas.Severity{
+		schemas.SeverityCritical, schemas.SeverityHigh, schemas.SeverityMedium,
+		schemas.SeverityLow, schemas.SeverityMinimal, schemas.SeverityUnspecified,
+	} {
+		if c := cmp.Compare(b.Summary.CountBySeverity[severity], a.Summary.CountBySeverity[severity]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(a.Image, b.Image)
+}
@@ -0,0 +1,102 @@
+package drydock_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hiro-o918/drydock"
+	"github.com/hiro-o918/drydock/schemas"
+)
+
+func TestScanSummary_Add(t *testing.T) {
+	result := func(image string, counts map[schemas.Severity]int, fixable int) schemas.AnalyzeResult {
+		total := 0
+		for _, n := range counts {
+			total += n
+		}
+		return schemas.AnalyzeResult{
+			Artifact: schemas.ArtifactReference{Host: "us-central1-docker.pkg.dev", ProjectID: "p", RepositoryID: "repo", ImageName: image},
+			Summary:  schemas.VulnerabilitySummary{TotalCount: total, CountBySeverity: counts, FixableCount: fixable},
+		}
+	}
+	image := func(name string) string {
+		return "us-central1-docker.pkg.dev/p/repo/" + name
+	}
+
+	tests := map[string]struct {
+		results   []schemas.AnalyzeResult
+		want      drydock.ScanSummary
+		wantWorst []string
+	}{
+		"should total the findings of every image": {
+			results: []schemas.AnalyzeResult{
+				result("api", map[schemas.Severity]int{schemas.SeverityCritical: 1, schemas.SeverityLow: 2}, 1),
+				result("web", map[schemas.Severity]int{schemas.SeverityLow: 3}, 2),
+				result("clean", map[schemas.Severity]int{}, 0),
+			},
+			want: drydock.ScanSummary{
+				Images:          3,
+				Vulnerabilities: 6,
+				CountBySeverity: map[schemas.Severity]int{schemas.SeverityCritical: 1, schemas.SeverityLow: 5},
+				Fixable:         3,
+			},
+			wantWorst: []string{image("api"), image("web")},
+		},
+		"should keep the five images with the most severe findings": {
+			results: []schemas.AnalyzeResult{
+				result("a", map[schemas.Severity]int{schemas.SeverityLow: 9}, 0),
+				result("b", map[schemas.Severity]int{schemas.SeverityHigh: 1}, 0),
+				result("c", map[schemas.Severity]int{schemas.SeverityCritical: 1}, 0),
+				result("d", map[schemas.Severity]int{schemas.SeverityHigh: 2}, 0),
+				result("e", map[schemas.Severity]int{schemas.SeverityMedium: 1}, 0),
+				result("f", map[schemas.Severity]int{schemas.SeverityCritical: 1, schemas.SeverityHigh: 1}, 0),
+				result("g", map[schemas.Severity]int{schemas.SeverityHigh: 1}, 0),
+			},
+			want: drydock.ScanSummary{
+				Images:          7,
+				Vulnerabilities: 18,
+				CountBySeverity: map[schemas.Severity]int{
+					schemas.SeverityCritical: 2, schemas.SeverityHigh: 5, schemas.SeverityMedium: 1, schemas.SeverityLow: 9,
+				},
+			},
+			wantWorst: []string{image("f"), image("c"), image("d"), image("b"), image("g")},
+		},
+		"should count the images that were not analyzed": {
+			results: func() []schemas.AnalyzeResult {
+				finished := result("api", map[schemas.Severity]int{}, 0)
+				finished.ScanStatus = schemas.ScanStatusFinished
+				pending := result("web", map[schemas.Severity]int{}, 0)
+				pending.ScanStatus = schemas.ScanStatusPending
+				notScanned := result("old", map[schemas.Severity]int{}, 0)
+				notScanned.ScanStatus = schemas.ScanStatusNotScanned
+				return []schemas.AnalyzeResult{finished, pending, notScanned, result("unknown", map[schemas.Severity]int{}, 0)}
+			}(),
+			want: drydock.ScanSummary{
+				Images:          4,
+				NotAnalyzed:     2,
+				CountBySeverity: map[schemas.Severity]int{},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got drydock.ScanSummary
+			for _, r := range tt.results {
+				drydock.ExportSummaryAdd(&got, &r)
+			}
+
+			var worst []string
+			for _, w := range got.Worst {
+				worst = append(worst, w.Image)
+			}
+			if diff := cmp.Diff(tt.wantWorst, worst); diff != "" {
+				t.Errorf("ScanSummary.Worst mismatch (-want +got):\n%s", diff)
+			}
+			got.Worst = nil
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ScanSummary mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -1,23 +1,54 @@
+// Package drydock scans Google Artifact Registry images for vulnerabilities.
+//
+// The domain types it produces (ArtifactReference, Vulnerability, AnalyzeResult, ...) are defined
+// once, in the schemas package; this package only adds the components that create and export them.
 package drydock
 
 import (
 	"context"
 	"fmt"
-	"strings"
+	"iter"
 
 	"github.com/hiro-o918/drydock/schemas"
 )
 
+// ============================================================================
+// Resolver Component
+// ============================================================================
+
+// Resolver finds the images to scan. ImageResolver implements it with Artifact Registry;
+// the drydocktest package has a fake for tests.
+type Resolver interface {
+	// AllLatestImages yields the best digest of every image in the project and location
+	AllLatestImages(ctx context.Context, projectID, location string, opts ...ResolveOption) iter.Seq2[ImageTarget, error]
+
+	// AllImages yields every digest of every image in the project and location
+	AllImages(ctx context.Context, projectID, location string, opts ...ResolveOption) iter.Seq2[ImageTarget, error]
+
+	// ResolveImage resolves a single image reference, by digest or tag
+	ResolveImage(ctx context.Context, ref schemas.ArtifactReference) (ImageTarget, error)
+
+	// Close releases the resources of the resolver
+	Close() error
+}
+
 // ============================================================================
 // Analyzer Component
 // ============================================================================
 
-// Analyzer fetches and processes vulnerability data
+// Analyzer fetches and processes vulnerability data. ArtifactRegistryAnalyzer implements it
+// with Container Analysis; the Scanner also closes analyzers implementing io.Closer.
 type Analyzer interface {
 	// Analyze retrieves vulnerabilities for the specified image
 	Analyze(ctx context.Context, req AnalyzeRequest) (*schemas.AnalyzeResult, error)
 }
 
+// SBOMFetcher is implemented by analyzers that can download the SBOM of an image (see WithSBOMDownload)
+type SBOMFetcher interface {
+	// FetchSBOM downloads the SBOM of the image, given by digest
+	FetchSBOM(ctx context.Context, ref schemas.ArtifactReference) (*SBOMDocument, error)
+}
+
 // AnalyzeRequest contains parameters for vulnerability analysis
 type AnalyzeRequest struct {
 	// Artifact is the image reference to analyze
@@ -31,6 +62,25 @@ type AnalyzeRequest struct {
 
 	// FixableOnly filters for vulnerabilities that have a fix available
 	FixableOnly bool
+
+	// OnlyIDs, if non-empty, keeps only vulnerabilities with one of these IDs (e.g., CVE-2024-1234)
+	OnlyIDs []string
+
+	// SkipIDs suppresses vulnerabilities with one of these IDs; they are listed in AnalyzeResult.Suppressed
+	SkipIDs []string
+
+	// Suppressions are accepted exceptions; matching findings are listed in AnalyzeResult.Suppressed
+	Suppressions []schemas.Suppression
+
+	// DistroTrackers annotate findings with the status their distro gives them, before Filter runs
+	DistroTrackers []DistroTracker
+
+	// IgnoreUnlikelyFixed suppresses findings their vendor or distro will not fix (wont-fix, or a
+	// distro status such as "ignored"); they are listed in AnalyzeResult.Suppressed
+	IgnoreUnlikelyFixed bool
+
+	// Filter, if set, keeps only vulnerabilities matching its CEL expression
+	Filter *VulnerabilityFilter
 }
 
 // ============================================================================
@@ -43,6 +93,33 @@ const (
 	OutputFormatJSON OutputFormat = "json"
 	OutputFormatCSV  OutputFormat = "csv"
 	OutputFormatTSV  OutputFormat = "tsv"
+
+	// OutputFormatMarkdown writes a Markdown report with a section per image, e.g. for pull request comments
+	OutputFormatMarkdown OutputFormat = "markdown"
+
+	// OutputFormatInToto writes one unsigned in-toto vulnerability attestation per image, to be signed with cosign
+	OutputFormatInToto OutputFormat = "intoto"
+
+	// OutputFormatRemediations prints the package upgrade commands of each image
+	OutputFormatRemediations OutputFormat = "remediations"
+
+	// OutputFormatBadge writes a shields.io endpoint badge summarizing the vulnerabilities of all images
+	OutputFormatBadge OutputFormat = "badge"
+
+	// OutputFormatAtom writes an Atom feed with one entry per finding on an image
+	OutputFormatAtom OutputFormat = "atom"
+
+	// OutputFormatBackstage writes the findings grouped by Backstage entity, for catalog plugins
+	OutputFormatBackstage OutputFormat = "backstage"
+
+	// OutputFormatSARIF writes a SARIF 2.1.0 log with one rule per vulnerability ID, for GitHub code scanning
+	OutputFormatSARIF OutputFormat = "sarif"
+
+	// OutputFormatCycloneDX writes a CycloneDX 1.5 document of the images, their affected packages and vulnerabilities, e.g. for Dependency-Track
+	OutputFormatCycloneDX OutputFormat = "cyclonedx"
+
+	// OutputFormatGitHub appends a Markdown report to the GitHub Actions step summary and annotates critical findings
+	OutputFormatGitHub OutputFormat = "github"
 )
 
 // String implements the flag.Value interface.
@@ -52,15 +129,14 @@ func (f *OutputFormat) String() string {
 
 // Set implements the flag.Value interface.
 // ここでパース時にバリデーションが行われます。
+// Any format added with RegisterFormat is accepted.
 func (f *OutputFormat) Set(value string) error {
-	normalized := OutputFormat(strings.ToLower(strings.TrimSpace(value)))
-	switch normalized {
-	case OutputFormatJSON, OutputFormatCSV, OutputFormatTSV:
-		*f = normalized
-		return nil
-	default:
-		return fmt.Errorf("invalid output format: %s (allowed: json, csv, tsv)", value)
+	normalized := normalizeFormat(value)
+	if _, ok := lookupFormat(normalized); !ok {
+		return fmt.Errorf("invalid output format: %s (allowed: %s)", value, formatList())
 	}
+	*f = normalized
+	return nil
 }
 
 // Exporter defines the interface for exporting analysis results
@@ -68,3 +144,19 @@ type Exporter interface {
 	// Export outputs the analysis results to the configured destination
 	Export(ctx context.Context, results []schemas.AnalyzeResult) error
 }
+
+// StreamExporter is an Exporter that can also write results incrementally,
+// so that the scanner does not have to keep every result in memory before exporting.
+// The output produced by Begin, ExportOne (repeated) and End must be equivalent to a single Export call.
+type StreamExporter interface {
+	Exporter
+
+	// Begin writes any leading output (e.g., a header row or an opening bracket)
+	Begin(ctx context.Context) error
+
+	// ExportOne writes a single analysis result
+	ExportOne(ctx context.Context, result schemas.AnalyzeResult) error
+
+	// End writes any trailing output and flushes buffered data
+	End(ctx context.Context) error
+}
@@ -0,0 +1,8 @@
+package utils
+
+// SetCachedProjectID replaces the cached project ID for tests.
+func SetCachedProjectID(projectID string) {
+	projectIDCache.Lock()
+	defer projectIDCache.Unlock()
+	projectIDCache.projectID = projectID
+}
@@ -1,10 +1,18 @@
 package utils
 
+// IsZero reports whether v is the zero value of its type.
 func IsZero[T comparable](v T) bool {
 	var zero T // zero will hold the zero value for type T
 	return v == zero
 }
 
+// Ptr returns a pointer to v, whatever its value, e.g. to tell "present but empty" from absent.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// ToPtr returns a pointer to v, or nil if v is the zero value, for optional values whose zero
+// value means absent (e.g., an unset flag). Use Ptr to keep zero values.
 func ToPtr[T comparable](v T) *T {
 	if IsZero(v) {
 		return nil
@@ -135,3 +135,39 @@ func TestToPtr(t *testing.T) {
 		t.Run(tt.name, tt.run)
 	}
 }
+
+func TestPtr(t *testing.T) {
+	tests := map[string]struct {
+		run func(t *testing.T)
+	}{
+		"should keep empty strings": {
+			run: func(t *testing.T) {
+				got := utils.Ptr("")
+				if got == nil || *got != "" {
+					t.Errorf("Ptr(\"\") = %v, want a pointer to \"\"", got)
+				}
+			},
+		},
+		"should keep zero numbers": {
+			run: func(t *testing.T) {
+				got := utils.Ptr(0)
+				if got == nil || *got != 0 {
+					t.Errorf("Ptr(0) = %v, want a pointer to 0", got)
+				}
+			},
+		},
+		"should accept non-comparable types": {
+			run: func(t *testing.T) {
+				v := []string{"a"}
+				got := utils.Ptr(v)
+				if diff := cmp.Diff(&v, got); diff != "" {
+					t.Errorf("mismatch (-want +got):\n%s", diff)
+				}
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, tt.run)
+	}
+}
@@ -4,18 +4,46 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 
 	"cloud.google.com/go/compute/metadata"
 	"golang.org/x/oauth2/google"
 )
 
+// ProjectIDOption configures GetProjectID.
+type ProjectIDOption func(*projectIDConfig)
+
+type projectIDConfig struct {
+	skipMetadataServer bool
+}
+
+// WithoutMetadataServer skips probing the GCP metadata server, which can take
+// several seconds to time out when not running on GCP.
+func WithoutMetadataServer() ProjectIDOption {
+	return func(c *projectIDConfig) {
+		c.skipMetadataServer = true
+	}
+}
+
+// projectIDCache holds the first successfully detected project ID of the process.
+var projectIDCache struct {
+	sync.Mutex
+	projectID string
+}
+
 // GetProjectID attempts to determine the GCP project ID from the environment.
 // It checks in the following order:
 // 1. Environment variables (GOOGLE_CLOUD_PROJECT, GCLOUD_PROJECT)
 // 2. Application Default Credentials (ADC)
 // 3. GCP metadata server (if running on GCP)
 // Returns an error if no project ID could be determined.
-func GetProjectID(ctx context.Context) (string, error) {
+// The first successful ADC or metadata server lookup is cached for the lifetime of the process.
+func GetProjectID(ctx context.Context, opts ...ProjectIDOption) (string, error) {
+	cfg := &projectIDConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	// 1. Check environment variables
 	if p := os.Getenv("GOOGLE_CLOUD_PROJECT"); p != "" {
 		return p, nil
@@ -24,16 +52,24 @@ func GetProjectID(ctx context.Context) (string, error) {
 		return p, nil
 	}
 
+	projectIDCache.Lock()
+	defer projectIDCache.Unlock()
+	if projectIDCache.projectID != "" {
+		return projectIDCache.projectID, nil
+	}
+
 	// 2. Check Application Default Credentials (ADC)
 	creds, err := google.FindDefaultCredentials(ctx, "https://www.googleapis.com/auth/cloud-platform")
 	if err == nil && creds.ProjectID != "" {
+		projectIDCache.projectID = creds.ProjectID
 		return creds.ProjectID, nil
 	}
 
 	// 3. Check metadata server (only on GCP)
-	if metadata.OnGCE() {
+	if !cfg.skipMetadataServer && metadata.OnGCEWithContext(ctx) {
 		p, err := metadata.ProjectIDWithContext(ctx)
 		if err == nil && p != "" {
+			projectIDCache.projectID = p
 			return p, nil
 		}
 	}
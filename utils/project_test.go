@@ -0,0 +1,49 @@
+package utils_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hiro-o918/drydock/utils"
+)
+
+func TestGetProjectID(t *testing.T) {
+	tests := map[string]struct {
+		env    map[string]string
+		cached string
+		want   string
+	}{
+		"should prefer GOOGLE_CLOUD_PROJECT": {
+			env:    map[string]string{"GOOGLE_CLOUD_PROJECT": "env-project", "GCLOUD_PROJECT": "gcloud-project"},
+			cached: "cached-project",
+			want:   "env-project",
+		},
+		"should fall back to GCLOUD_PROJECT": {
+			env:  map[string]string{"GOOGLE_CLOUD_PROJECT": "", "GCLOUD_PROJECT": "gcloud-project"},
+			want: "gcloud-project",
+		},
+		"should return the cached project without looking it up again": {
+			env:    map[string]string{"GOOGLE_CLOUD_PROJECT": "", "GCLOUD_PROJECT": ""},
+			cached: "cached-project",
+			want:   "cached-project",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			utils.SetCachedProjectID(tt.cached)
+			t.Cleanup(func() { utils.SetCachedProjectID("") })
+
+			got, err := utils.GetProjectID(context.Background(), utils.WithoutMetadataServer())
+			if err != nil {
+				t.Fatalf("GetProjectID() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetProjectID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}